@@ -0,0 +1,237 @@
+// Copyright 2018 The gcchain authors
+
+// Package external implements an account backend of the accounts held by a remote signer
+// daemon, see package signer, reachable over IPC or HTTP.
+package external
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+
+	"/gcchain/chain"
+	"github.com/gcchains/chain/accounts"
+	"github.com/gcchains/chain/api/rpc"
+	"github.com/gcchains/chain/consensus"
+	"github.com/gcchains/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// ExternalScheme is the protocol scheme prefixing the URLs of remote signers.
+const ExternalScheme = "extapi"
+
+// callTimeout is the timeout of a request to the remote signer
+const callTimeout = 10 * time.Second
+
+// ExternalBackend is an account backend of a remote signer, holding a single wallet.
+type ExternalBackend struct {
+	signers []accounts.Wallet
+}
+
+// NewExternalBackend connects to the remote signer at the endpoint, an IPC path or an HTTP
+// URL, authenticating to it with auth if it is not nil.
+func NewExternalBackend(endpoint string, auth rpc.AuthProvider) (*ExternalBackend, error) {
+	signer, err := NewExternalSigner(endpoint, auth)
+	if err != nil {
+		return nil, err
+	}
+	return &ExternalBackend{
+		signers: []accounts.Wallet{signer},
+	}, nil
+}
+
+// Wallets implements accounts.Backend, returning the remote signer.
+func (eb *ExternalBackend) Wallets() []accounts.Wallet {
+	return eb.signers
+}
+
+// Subscribe implements accounts.Backend, the remote signer never arrives or departs.
+func (eb *ExternalBackend) Subscribe(sink chan<- accounts.WalletEvent) event.Subscription {
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		<-quit
+		return nil
+	})
+}
+
+// ExternalSigner is a wallet of the accounts held by a remote signer. It implements
+// backend.ConsensusSigner, sending the headers and macs to sign for the signer to check
+// them, bare hashes are refused by the signer.
+type ExternalSigner struct {
+	client   *rpc.Client
+	endpoint string
+
+	lock  sync.RWMutex
+	cache []accounts.Account // accounts of the signer, fetched on open
+}
+
+// NewExternalSigner connects to the remote signer at the endpoint.
+func NewExternalSigner(endpoint string, auth rpc.AuthProvider) (*ExternalSigner, error) {
+	client, err := rpc.DialContextWithAuth(context.Background(), endpoint, auth)
+	if err != nil {
+		return nil, err
+	}
+	signer := &ExternalSigner{
+		client:   client,
+		endpoint: endpoint,
+	}
+	if _, err := signer.fetchAccounts(); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return signer, nil
+}
+
+func (s *ExternalSigner) call(result interface{}, method string, args ...interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout)
+	defer cancel()
+
+	return s.client.CallContext(ctx, result, "signer_"+method, args...)
+}
+
+func (s *ExternalSigner) fetchAccounts() ([]accounts.Account, error) {
+	var addresses []common.Address
+	if err := s.call(&addresses, "accounts"); err != nil {
+		return nil, err
+	}
+	accs := make([]accounts.Account, 0, len(addresses))
+	for _, addr := range addresses {
+		accs = append(accs, accounts.Account{Address: addr, URL: s.URL()})
+	}
+
+	s.lock.Lock()
+	s.cache = accs
+	s.lock.Unlock()
+	return accs, nil
+}
+
+// URL implements accounts.Wallet, returning the endpoint of the signer.
+func (s *ExternalSigner) URL() accounts.URL {
+	return accounts.URL{Scheme: ExternalScheme, Path: s.endpoint}
+}
+
+// Status implements accounts.Wallet, returning whether the signer is reachable.
+func (s *ExternalSigner) Status() (string, error) {
+	if _, err := s.fetchAccounts(); err != nil {
+		return "Unreachable", err
+	}
+	return "Ok", nil
+}
+
+// Open implements accounts.Wallet, refreshing the accounts of the signer.
+func (s *ExternalSigner) Open(passphrase string) error {
+	_, err := s.fetchAccounts()
+	return err
+}
+
+// Close implements accounts.Wallet, but is a noop as the connection is kept for the node.
+func (s *ExternalSigner) Close() error { return nil }
+
+// Accounts implements accounts.Wallet, returning the accounts of the signer.
+func (s *ExternalSigner) Accounts() []accounts.Account {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return append([]accounts.Account(nil), s.cache...)
+}
+
+// Contains implements accounts.Wallet, returning whether the signer holds the account.
+func (s *ExternalSigner) Contains(account accounts.Account) bool {
+	if account.URL != (accounts.URL{}) && account.URL != s.URL() {
+		return false
+	}
+	for _, a := range s.Accounts() {
+		if a.Address == account.Address {
+			return true
+		}
+	}
+	return false
+}
+
+// Derive implements accounts.Wallet, but is not supported by the signer.
+func (s *ExternalSigner) Derive(path accounts.DerivationPath, pin bool) (accounts.Account, error) {
+	return accounts.Account{}, accounts.ErrNotSupported
+}
+
+// SelfDerive implements accounts.Wallet, but is a noop for the signer.
+func (s *ExternalSigner) SelfDerive(base accounts.DerivationPath, chain gcchain.ChainStateReader) {}
+
+// SignHash implements accounts.Wallet and backend.SignFn, the signer refuses it unless the
+// content of the hash can be checked, sign the content with SignHeader or SignMac instead.
+func (s *ExternalSigner) SignHash(account accounts.Account, hash []byte) ([]byte, error) {
+	var sig hexutil.Bytes
+	if err := s.call(&sig, "signHash", account.Address, hexutil.Bytes(hash)); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// SignTx implements accounts.Wallet, the signer only signs transactions to whitelisted
+// contracts.
+func (s *ExternalSigner) SignTx(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	data, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return nil, err
+	}
+	var signed hexutil.Bytes
+	if err := s.call(&signed, "signTransaction", account.Address, hexutil.Bytes(data), (*hexutil.Big)(chainID)); err != nil {
+		return nil, err
+	}
+	result := new(types.Transaction)
+	if err := rlp.DecodeBytes(signed, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SignHashWithPassphrase implements accounts.Wallet, but is not supported as the signer
+// holds its accounts unlocked.
+func (s *ExternalSigner) SignHashWithPassphrase(account accounts.Account, passphrase string, hash []byte) ([]byte, error) {
+	return nil, accounts.ErrNotSupported
+}
+
+// SignTxWithPassphrase implements accounts.Wallet, but is not supported as the signer
+// holds its accounts unlocked.
+func (s *ExternalSigner) SignTxWithPassphrase(account accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return nil, accounts.ErrNotSupported
+}
+
+// DecryptWithEcies implements accounts.Wallet, but is not supported by the signer.
+func (s *ExternalSigner) DecryptWithEcies(account accounts.Account, cipherText []byte) ([]byte, error) {
+	return nil, accounts.ErrNotSupported
+}
+
+// PublicKey implements accounts.Wallet, returning the public key of the account.
+func (s *ExternalSigner) PublicKey(account accounts.Account) ([]byte, error) {
+	var pubkey hexutil.Bytes
+	if err := s.call(&pubkey, "publicKey", account.Address); err != nil {
+		return nil, err
+	}
+	return pubkey, nil
+}
+
+// SignHeader implements backend.ConsensusSigner, the signer never signs two different
+// headers at the same height in the same state.
+func (s *ExternalSigner) SignHeader(account accounts.Account, header *types.Header, state consensus.State) ([]byte, error) {
+	data, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		return nil, err
+	}
+	var sig hexutil.Bytes
+	if err := s.call(&sig, "signHeader", account.Address, hexutil.Bytes(data), state); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// SignMac implements backend.ConsensusSigner.
+func (s *ExternalSigner) SignMac(account accounts.Account, mac string) ([]byte, error) {
+	var sig hexutil.Bytes
+	if err := s.call(&sig, "signMac", account.Address, mac); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
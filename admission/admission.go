@@ -5,12 +5,12 @@ import (
 	"errors"
 	"math"
 	"math/big"
-	"net"
 	"reflect"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/gcchains/chain/accounts"
 	"github.com/gcchains/chain/accounts/abi/bind"
 	"github.com/gcchains/chain/accounts/keystore"
 	"github.com/gcchains/chain/api/gcclient"
@@ -20,8 +20,8 @@ import (
 	"github.com/gcchains/chain/contracts/dpos/admission"
 	campaign "github.com/gcchains/chain/contracts/dpos/campaign"
 	contracts "github.com/gcchains/chain/contracts/dpos/campaign/tests"
-	"github.com/gcchains/chain/contracts/dpos/network"
 	rnode "github.com/gcchains/chain/contracts/dpos/rnode"
+	"github.com/gcchains/chain/types"
 	"github.com/ethereum/go-ethereum/common"
 )
 
@@ -55,6 +55,7 @@ var (
 	errLockedPeriod   = errors.New("the period is locked, cannot invest now")
 	errNoEnoughMoney  = errors.New("money is not enough to become RNode")
 	errBadNetwork     = errors.New("now the network status is bad")
+	errMissingPowWork = errors.New("cpu and memory proofs are required to claim campaign")
 )
 
 // AdmissionControl implements admission control functionality.
@@ -62,22 +63,23 @@ type AdmissionControl struct {
 	address               common.Address
 	chain                 consensus.ChainReader
 	key                   *keystore.Key
+	wallet                accounts.Wallet // signs the txs if there is no key, e.g. an external signer
 	contractBackend       contracts.Backend
 	admissionContractAddr common.Address
 	campaignContractAddr  common.Address
 	rNodeContractAddr     common.Address
 	networkContractAddr   common.Address
+	plotDir               string // directory storing the plots of storage proofs
 
 	checkNetworkStatus bool
 
-	mutex      sync.RWMutex
-	wg         *sync.WaitGroup
-	cpuWork    ProofWork
-	memoryWork ProofWork
-	status     workStatus
-	err        error
-	abort      chan interface{}
-	done       chan interface{}
+	mutex  sync.RWMutex
+	wg     *sync.WaitGroup
+	works  map[string]ProofWork
+	status workStatus
+	err    error
+	abort  chan interface{}
+	done   chan interface{}
 
 	sendingFund int32
 }
@@ -107,113 +109,35 @@ func (ac *AdmissionControl) IgnoreNetworkCheck() {
 	ac.checkNetworkStatus = false
 }
 
+// CheckNetworkStatus runs the network check, it returns true if the proof succeeds or is not required.
 func (ac *AdmissionControl) CheckNetworkStatus() bool {
 	ac.mutex.RLock()
 	localCheck := ac.checkNetworkStatus
+	ctx := ac.proofContext()
 	ac.mutex.RUnlock()
 
-	// TODO: read parameters from contract
-	host := "www.microsoft.com:443"
-	count := 4
-	timeout := 300
-	check := true
-	gap := 100
-
-	// create contract instance
-	networkCheckerInstance, err := network.NewNetwork(ac.networkContractAddr, ac.contractBackend)
-	if err != nil {
-		log.Error("failed to create new network checker instance", "err", err)
-		return false
-	}
-
-	chck, err := networkCheckerInstance.Open(nil)
-	if err != nil {
-		log.Error("failed to get dial open flag from network checker instance", "err", err)
-		return false
-	}
-	check = chck
-
-	// if check flag is false, return true
-	if !check || !localCheck {
+	if !localCheck {
 		return true
 	}
 
-	// read parameters from contract
-	host, err = networkCheckerInstance.Host(nil)
-	if err != nil {
-		log.Error("failed to get host from network checker instance", "err", err)
-		return false
-	}
-
-	cnt, err := networkCheckerInstance.Count(nil)
-	if err != nil {
-		log.Error("failed to get dial count from network checker instance", "err", err)
-		return false
-	}
-
-	count = int(cnt.Uint64())
-
-	tmout, err := networkCheckerInstance.Timeout(nil)
-	if err != nil {
-		log.Error("failed to get dial count from network checker instance", "err", err)
-		return false
+	work, err := GetProofType(Network).NewWork(ctx)
+	if err == errProofNotRequired {
+		return true
 	}
-	timeout = int(tmout.Uint64())
-
-	gp, err := networkCheckerInstance.Gap(nil)
 	if err != nil {
-		log.Error("failed to get dial gap from network checker instance", "err", err)
-		return false
-	}
-	gap = int(gp.Uint64())
-
-	// do the dial
-	if ok, err := checkNetworkStatus(host, count, timeout, gap); !ok {
-		log.Warn("Failed to check network status, try to dial", "host", host, "count", count, "timeout", timeout, "gap", gap, "err", err)
+		log.Error("failed to read network check parameters from network contract", "err", err)
 		return false
 	}
 
-	return true
-}
-
-func checkNetworkStatus(host string, count int, timeout int, gap int) (bool, error) {
-
-	timeoutD := time.Duration(time.Duration(timeout) * time.Millisecond)
-	gapD := time.Duration(time.Duration(gap) * time.Millisecond)
-
-	// dial first, do not count this time as result
-	conn, err := net.DialTimeout("tcp", host, timeoutD*10)
-	if err != nil {
-		log.Debug("failed to dial remote host", "err", err)
-	}
-	log.Debug("dialed host to check network status", "host", host, "count", count, "timeout", timeout, "gap", gap)
-	if conn != nil {
-		conn.Close()
-	}
-
-	for i := 0; i < count; i++ {
-		conn, err := net.DialTimeout("tcp", host, timeoutD)
-		if err != nil {
-			return false, err
-		}
-		log.Debug("dialed host to check network status", "host", host, "count", count, "timeout", timeout, "gap", gap)
-		if conn != nil {
-			conn.Close()
-		}
-		time.Sleep(gapD)
-	}
-	return true, nil
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+	work.prove(make(chan interface{}), wg)
+	return work.error() == nil
 }
 
 // Campaign starts running all the proof work to generate the campaign information and waits all proof work done, send msg
 func (ac *AdmissionControl) Campaign(terms uint64) error {
 
-	// check network status before continue, this is not a hard restriction
-	// one can ignore this by setting `IgnoreNetworkStatusCheck' == true in configs/general.go
-	if !ac.CheckNetworkStatus() {
-		return errBadNetwork
-	}
-
 	log.Info("Start campaign for dpos proposers committee")
 	ac.mutex.Lock()
 	defer ac.mutex.Unlock()
@@ -231,11 +155,15 @@ func (ac *AdmissionControl) Campaign(terms uint64) error {
 		return errNotRNode
 	}
 
+	// the network status is checked by the network proof type among the works
+	if err := ac.buildWorks(); err != nil {
+		return err
+	}
+
 	ac.status = AcRunning
 	ac.err = nil
 	ac.done = make(chan interface{})
 	ac.abort = make(chan interface{})
-	ac.buildWorks()
 	ac.wg = new(sync.WaitGroup)
 	ac.wg.Add(len(ac.getWorks()))
 	for _, work := range ac.getWorks() {
@@ -301,7 +229,7 @@ func (ac *AdmissionControl) FundForRNode() error {
 
 	balance, _ := ac.contractBackend.BalanceAt(context.Background(), ac.address, nil)
 	if balance.Cmp(minRnodeFund) >= 0 {
-		transactOpts := bind.NewKeyedTransactor(ac.key.PrivateKey)
+		transactOpts := ac.newTransactor()
 		transactOpts.Value = minRnodeFund
 		tx, err := rNodeContract.JoinRnode(
 			transactOpts,
@@ -391,6 +319,39 @@ func (ac *AdmissionControl) SetAdmissionKey(key *keystore.Key) {
 	ac.key = key
 }
 
+// SetAdmissionWallet sets the wallet signing the txs to participate campaign if there is no
+// admission key, e.g. an external signer holding the key of the node.
+func (ac *AdmissionControl) SetAdmissionWallet(wallet accounts.Wallet) {
+	ac.mutex.Lock()
+	defer ac.mutex.Unlock()
+
+	ac.wallet = wallet
+}
+
+// SetPlotDir sets the directory storing the plots of storage proofs.
+func (ac *AdmissionControl) SetPlotDir(dir string) {
+	ac.mutex.Lock()
+	defer ac.mutex.Unlock()
+
+	ac.plotDir = dir
+}
+
+// newTransactor returns the opts signing the txs with the admission key, or with the
+// admission wallet if there is no key.
+func (ac *AdmissionControl) newTransactor() *bind.TransactOpts {
+	if ac.key != nil || ac.wallet == nil {
+		return bind.NewKeyedTransactor(ac.key.PrivateKey)
+	}
+
+	wallet := ac.wallet
+	return &bind.TransactOpts{
+		From: ac.address,
+		Signer: func(signer types.Signer, address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			return wallet.SignTx(accounts.Account{Address: address}, tx, configs.ChainConfigInfo().ChainID)
+		},
+	}
+}
+
 // GetStatus gets status of campaign
 func (ac *AdmissionControl) GetStatus() (workStatus, error) {
 	ac.mutex.RLock()
@@ -436,7 +397,7 @@ func (ac *AdmissionControl) sendCampaignResult(terms uint64) {
 		return
 	}
 
-	transactOpts := bind.NewKeyedTransactor(ac.key.PrivateKey)
+	transactOpts := ac.newTransactor()
 	// this is an *empirical* estimate of the possible largest gas needed by the claimCampaign smartcontract call.
 	// @liusw for this number.
 	transactOpts.GasLimit = 2300000
@@ -451,8 +412,22 @@ func (ac *AdmissionControl) sendCampaignResult(terms uint64) {
 		return
 	}
 
-	cpuResult := ac.cpuWork.result()
-	memResult := ac.memoryWork.result()
+	ac.mutex.RLock()
+	cpuResult := ac.works[Cpu].result()
+	memResult := ac.works[Memory].result()
+	storageWork, proveStorage := ac.works[Storage]
+	ac.mutex.RUnlock()
+
+	// the admission contract records the storage proof, then checks it when verifying the claim
+	if proveStorage {
+		if err := ac.submitStorageProof(storageWork.result()); err != nil {
+			ac.mutex.Lock()
+			ac.err = err
+			ac.mutex.Unlock()
+			log.Warn("Error in submitting storage proof", "error", err)
+			return
+		}
+	}
 
 	log.Info("ready to claim campaign",
 		"terms", terms,
@@ -484,6 +459,21 @@ func (ac *AdmissionControl) sendCampaignResult(terms uint64) {
 		"MemPowResult", memResult.Nonce, "CpuBlockNumber", cpuResult.BlockNumber, "MemBlockNumber", memResult.BlockNumber)
 }
 
+// submitStorageProof sends the storage proof to admission contract, it is sent right before the
+// campaign claim so that the claim is processed after it.
+func (ac *AdmissionControl) submitStorageProof(result Result) error {
+	instance, err := admission.NewAdmission(ac.admissionContractAddr, ac.contractBackend)
+	if err != nil {
+		return err
+	}
+	_, err = instance.SubmitStorageProof(ac.newTransactor(), result.Nonce, new(big.Int).SetInt64(result.BlockNumber))
+	if err != nil {
+		return err
+	}
+	log.Info("Submitted storage proof", "nonce", result.Nonce, "challenge number", result.BlockNumber)
+	return nil
+}
+
 func (ac *AdmissionControl) setClientBackend(client *gcclient.Client) {
 	ac.mutex.Lock()
 	defer ac.mutex.Unlock()
@@ -498,58 +488,44 @@ func (ac *AdmissionControl) SetSimulateBackend(contractBackend contracts.Backend
 	ac.contractBackend = contractBackend
 }
 
-// buildWorks creates proof works required by admission
-func (ac *AdmissionControl) buildWorks() {
-	ac.cpuWork = ac.buildCpuProofWork()
-	ac.memoryWork = ac.buildMemoryProofWork()
-}
-
-func (ac *AdmissionControl) buildCpuProofWork() ProofWork {
-	client := ac.contractBackend
-	instance, err := admission.NewAdmission(ac.admissionContractAddr, client)
-	if err != nil {
-		log.Fatal("NewAdmissionCaller is error", "error is", err)
+func (ac *AdmissionControl) proofContext() *ProofContext {
+	return &ProofContext{
+		Chain:                 ac.chain,
+		Backend:               ac.contractBackend,
+		Address:               ac.address,
+		AdmissionContractAddr: ac.admissionContractAddr,
+		NetworkContractAddr:   ac.networkContractAddr,
+		PlotDir:               ac.plotDir,
 	}
-	cd, _, clt, _, err := instance.GetAdmissionParameters(nil)
-	if err != nil {
-		log.Fatal("GetAdmissionParameters is error", "error is ", err)
-	}
-	cpuDifficulty := cd.Uint64()
-	cpuLifeTime := time.Duration(time.Duration(clt.Int64()) * time.Second)
-
-	// must use current block number - 1, because solidity cannot get hash of current block
-	blockNum := ac.chain.CurrentHeader().Number.Uint64()
-	if blockNum > 0 {
-		blockNum = blockNum - 1
-	}
-	return newWork(cpuDifficulty, cpuLifeTime, ac.address, ac.chain.GetHeaderByNumber(blockNum), sha256Func)
 }
 
-func (ac *AdmissionControl) buildMemoryProofWork() ProofWork {
-	client := ac.contractBackend
-	instance, err := admission.NewAdmission(ac.admissionContractAddr, client)
-	if err != nil {
-		log.Fatal("NewAdmissionCaller is error", "error is", err)
-	}
-	_, md, _, mct, err := instance.GetAdmissionParameters(nil)
-	if err != nil {
-		log.Fatal("GetDifficultyParameter is error", "error is", err)
+// buildWorks creates proof works of all the registered proof types required by admission
+func (ac *AdmissionControl) buildWorks() error {
+	ctx := ac.proofContext()
+	works := make(map[string]ProofWork)
+	for _, proofType := range ProofTypes() {
+		// one can ignore the network check by setting `IgnoreNetworkStatusCheck' == true in configs/general.go
+		if proofType.Name() == Network && !ac.checkNetworkStatus {
+			continue
+		}
+		work, err := proofType.NewWork(ctx)
+		if err == errProofNotRequired {
+			continue
+		}
+		if err != nil {
+			log.Error("failed to build proof work", "type", proofType.Name(), "err", err)
+			return err
+		}
+		works[proofType.Name()] = work
 	}
-	memoryDifficulty := md.Uint64()
-	memoryCpuLifeTime := time.Duration(time.Duration(mct.Int64()) * time.Second)
-
-	// must use current block number - 1, because solidity cannot get hash of current block
-	blockNum := ac.chain.CurrentHeader().Number.Uint64()
-	if blockNum > 0 {
-		blockNum = blockNum - 1
+	if works[Cpu] == nil || works[Memory] == nil {
+		return errMissingPowWork
 	}
-	return newWork(memoryDifficulty, memoryCpuLifeTime, ac.address, ac.chain.GetHeaderByNumber(blockNum), scryptFunc)
+	ac.works = works
+	return nil
 }
 
-// registerProofWork returns all proof work
+// getWorks returns all proof work
 func (ac *AdmissionControl) getWorks() map[string]ProofWork {
-	proofWorks := make(map[string]ProofWork)
-	proofWorks[Cpu] = ac.cpuWork
-	proofWorks[Memory] = ac.memoryWork
-	return proofWorks
+	return ac.works
 }
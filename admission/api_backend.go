@@ -3,6 +3,7 @@
 package admission
 
 import (
+	"github.com/gcchains/chain/accounts"
 	"github.com/gcchains/chain/accounts/keystore"
 	"github.com/gcchains/chain/api/gcclient"
 	"github.com/gcchains/chain/api/rpc"
@@ -74,6 +75,18 @@ func (b *AdmissionApiBackend) AdmissionKey() *keystore.Key {
 	return b.admissionControl.key
 }
 
+func (b *AdmissionApiBackend) SetAdmissionWallet(wallet accounts.Wallet) {
+	b.admissionControl.SetAdmissionWallet(wallet)
+}
+
+func (b *AdmissionApiBackend) AdmissionWallet() accounts.Wallet {
+	return b.admissionControl.wallet
+}
+
+func (b *AdmissionApiBackend) SetPlotDir(dir string) {
+	b.admissionControl.SetPlotDir(dir)
+}
+
 // RegisterInProcHandler registers the rpc.Server, handles RPC request to process the API requests in process
 func (b *AdmissionApiBackend) RegisterInProcHandler(localRPCServer *rpc.Server) {
 	client := rpc.DialInProc(localRPCServer)
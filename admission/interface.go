@@ -5,6 +5,7 @@ package admission
 import (
 	"sync"
 
+	"github.com/gcchains/chain/accounts"
 	"github.com/gcchains/chain/accounts/keystore"
 	"github.com/gcchains/chain/api/rpc"
 	contracts "github.com/gcchains/chain/contracts/dpos/campaign/tests"
@@ -39,6 +40,15 @@ type ApiBackend interface {
 	// AdmissionKey returns keystore key
 	AdmissionKey() *keystore.Key
 
+	// SetAdmissionWallet sets the wallet signing the txs to participate campaign if there is no key
+	SetAdmissionWallet(wallet accounts.Wallet)
+
+	// AdmissionWallet returns the wallet signing the txs if there is no key
+	AdmissionWallet() accounts.Wallet
+
+	// SetPlotDir sets the directory storing the plots of storage proofs
+	SetPlotDir(dir string)
+
 	// RegisterInProcHandler registers the rpc.Server, handles RPC request to process the API requests in process
 	RegisterInProcHandler(localRPCServer *rpc.Server)
 
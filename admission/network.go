@@ -0,0 +1,132 @@
+// Copyright 2018 The gcchain authors
+
+package admission
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/gcchains/chain/commons/log"
+	"github.com/gcchains/chain/contracts/dpos/network"
+	"github.com/gcchains/chain/types"
+)
+
+const Network = "network"
+
+// networkProofType checks the network status of the node by dialing the host set in the network contract
+// several times, each within the timeout. It proves the host is reachable, not the bandwidth, and is not
+// verifiable on chain.
+type networkProofType struct{}
+
+func (b *networkProofType) Name() string {
+	return Network
+}
+
+// NewWork reads open, host, count, timeout and gap from the network contract.
+func (b *networkProofType) NewWork(ctx *ProofContext) (ProofWork, error) {
+	instance, err := network.NewNetwork(ctx.NetworkContractAddr, ctx.Backend)
+	if err != nil {
+		return nil, err
+	}
+	open, err := instance.Open(nil)
+	if err != nil {
+		return nil, err
+	}
+	if !open {
+		return nil, errProofNotRequired
+	}
+
+	host, err := instance.Host(nil)
+	if err != nil {
+		return nil, err
+	}
+	count, err := instance.Count(nil)
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := instance.Timeout(nil)
+	if err != nil {
+		return nil, err
+	}
+	gap, err := instance.Gap(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &networkWork{
+		host:    host,
+		count:   int(count.Uint64()),
+		timeout: time.Duration(timeout.Int64()) * time.Millisecond,
+		gap:     time.Duration(gap.Int64()) * time.Millisecond,
+		header:  ctx.Chain.CurrentHeader(),
+	}, nil
+}
+
+type networkWork struct {
+	host    string
+	count   int
+	timeout time.Duration
+	gap     time.Duration
+	header  *types.Header
+	err     error
+	mutex   sync.RWMutex
+}
+
+// result returns the result, there is no nonce for the network check.
+func (w *networkWork) result() Result {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	return Result{
+		BlockNumber: w.header.Number.Int64(),
+		Success:     w.err == nil,
+	}
+}
+
+// error returns the work error
+func (w *networkWork) error() error {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	return w.err
+}
+
+// prove dials the host count times with gap in between.
+func (w *networkWork) prove(abort <-chan interface{}, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	err := w.dial(abort)
+	if err != nil {
+		log.Warn("Failed to check network status, try to dial", "host", w.host, "count", w.count, "timeout", w.timeout, "gap", w.gap, "err", err)
+	}
+	w.mutex.Lock()
+	w.err = err
+	w.mutex.Unlock()
+}
+
+func (w *networkWork) dial(abort <-chan interface{}) error {
+	// dial first, do not count this time as result
+	conn, err := net.DialTimeout("tcp", w.host, w.timeout*10)
+	if err != nil {
+		log.Debug("failed to dial remote host", "err", err)
+	}
+	if conn != nil {
+		conn.Close()
+	}
+
+	for i := 0; i < w.count; i++ {
+		select {
+		case <-abort:
+			return ErrPowAbort
+		default:
+		}
+		conn, err := net.DialTimeout("tcp", w.host, w.timeout)
+		if err != nil {
+			return err
+		}
+		log.Debug("dialed host to check network status", "host", w.host, "count", w.count, "timeout", w.timeout, "gap", w.gap)
+		conn.Close()
+		time.Sleep(w.gap)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+// Copyright 2018 The gcchain authors
+
+package admission
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gcchains/chain/accounts/abi"
+	"github.com/gcchains/chain/accounts/abi/bind"
+	"github.com/gcchains/chain/consensus"
+	"github.com/gcchains/chain/contracts/dpos/admission"
+	contracts "github.com/gcchains/chain/contracts/dpos/campaign/tests"
+	"github.com/gcchains/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	// errProofNotRequired is returned by ProofType.NewWork if the contracts do not require the proof.
+	errProofNotRequired = errors.New("proof is not required")
+	errProofTypeExists  = errors.New("proof type already registered")
+)
+
+// ProofContext contains what a proof type needs to read its parameters and build its work.
+type ProofContext struct {
+	Chain                 consensus.ChainReader
+	Backend               contracts.Backend
+	Address               common.Address
+	AdmissionContractAddr common.Address
+	NetworkContractAddr   common.Address
+	PlotDir               string // directory storing the plots of storage proofs
+}
+
+// header returns the header proof works are based on. It is the parent of the current block,
+// because solidity cannot get hash of current block.
+func (ctx *ProofContext) header() *types.Header {
+	blockNum := ctx.Chain.CurrentHeader().Number.Uint64()
+	if blockNum > 0 {
+		blockNum = blockNum - 1
+	}
+	return ctx.Chain.GetHeaderByNumber(blockNum)
+}
+
+// callUint reads an uint256 parameter by calling the constant method of the contract with given ABI.
+func (ctx *ProofContext) callUint(contractAddr common.Address, contractABI string, method string) (*big.Int, error) {
+	parsed, err := abi.JSON(strings.NewReader(contractABI))
+	if err != nil {
+		return nil, err
+	}
+	var (
+		ret     = new(*big.Int)
+		bounded = bind.NewBoundContract(contractAddr, parsed, ctx.Backend, nil, nil)
+	)
+	if err := bounded.Call(nil, ret, method); err != nil {
+		return nil, err
+	}
+	return *ret, nil
+}
+
+// ProofType is a kind of proof a node does to be admitted to campaign. New kinds of proof are added by
+// RegisterProofType, all the registered proofs must succeed before the node claims campaign.
+type ProofType interface {
+	// Name returns the unique name of the proof type, which is the key of its result.
+	Name() string
+
+	// NewWork reads the difficulty parameters of the proof from contracts and creates the proof work.
+	// It returns errProofNotRequired if the contracts do not require the proof.
+	NewWork(ctx *ProofContext) (ProofWork, error)
+}
+
+var (
+	proofTypes     = make(map[string]ProofType)
+	proofTypeNames []string
+	proofTypesLock sync.RWMutex
+)
+
+// RegisterProofType registers a proof type required by admission control.
+func RegisterProofType(proofType ProofType) error {
+	proofTypesLock.Lock()
+	defer proofTypesLock.Unlock()
+
+	name := proofType.Name()
+	if _, ok := proofTypes[name]; ok {
+		return fmt.Errorf("%v: %v", errProofTypeExists, name)
+	}
+	proofTypes[name] = proofType
+	proofTypeNames = append(proofTypeNames, name)
+	return nil
+}
+
+// ProofTypes returns all the registered proof types in order of registration.
+func ProofTypes() []ProofType {
+	proofTypesLock.RLock()
+	defer proofTypesLock.RUnlock()
+
+	registered := make([]ProofType, 0, len(proofTypeNames))
+	for _, name := range proofTypeNames {
+		registered = append(registered, proofTypes[name])
+	}
+	return registered
+}
+
+// GetProofType returns the registered proof type with name, or nil if no such type.
+func GetProofType(name string) ProofType {
+	proofTypesLock.RLock()
+	defer proofTypesLock.RUnlock()
+
+	return proofTypes[name]
+}
+
+func init() {
+	for _, proofType := range []ProofType{
+		&powProofType{name: Cpu, hashfn: sha256Func},
+		&powProofType{name: Memory, hashfn: scryptFunc},
+		&storageProofType{},
+		&networkProofType{},
+	} {
+		if err := RegisterProofType(proofType); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// powProofType is a proof of work searching a nonce whose hash is below the target, the cpu and memory proofs
+// differ in the hash function.
+type powProofType struct {
+	name   string
+	hashfn hashFn
+}
+
+func (p *powProofType) Name() string {
+	return p.name
+}
+
+// NewWork reads the difficulty and timeout of the proof from getAdmissionParameters of the admission contract.
+func (p *powProofType) NewWork(ctx *ProofContext) (ProofWork, error) {
+	instance, err := admission.NewAdmission(ctx.AdmissionContractAddr, ctx.Backend)
+	if err != nil {
+		return nil, err
+	}
+	cpuDifficulty, memDifficulty, cpuTimeout, memTimeout, err := instance.GetAdmissionParameters(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	difficulty, timeout := cpuDifficulty, cpuTimeout
+	if p.name == Memory {
+		difficulty, timeout = memDifficulty, memTimeout
+	}
+	lifeTime := time.Duration(timeout.Int64()) * time.Second
+	return newWork(difficulty.Uint64(), lifeTime, ctx.Address, ctx.header(), p.hashfn), nil
+}
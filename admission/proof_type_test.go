@@ -0,0 +1,139 @@
+package admission
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+type fakeProofType struct{ name string }
+
+func (f *fakeProofType) Name() string { return f.name }
+
+func (f *fakeProofType) NewWork(ctx *ProofContext) (ProofWork, error) {
+	return nil, errProofNotRequired
+}
+
+func TestRegisterProofType(t *testing.T) {
+	names := []string{Cpu, Memory, Storage, Network}
+	for i, proofType := range ProofTypes() {
+		if i < len(names) && proofType.Name() != names[i] {
+			t.Errorf("ProofTypes()[%d] = %v, want %v", i, proofType.Name(), names[i])
+		}
+	}
+
+	if err := RegisterProofType(&fakeProofType{name: Cpu}); err == nil {
+		t.Errorf("RegisterProofType() of existing type succeeded")
+	}
+	if err := RegisterProofType(&fakeProofType{name: "fake"}); err != nil {
+		t.Fatalf("RegisterProofType() error = %v", err)
+	}
+	if got := GetProofType("fake"); got == nil || got.Name() != "fake" {
+		t.Errorf("GetProofType(fake) = %v, want the registered type", got)
+	}
+}
+
+func TestStorageProof(t *testing.T) {
+	var (
+		difficulty uint64 = 12
+		sender            = common.HexToAddress("0x96216849c49358b10257cb55b28ea603c874b05e")
+	)
+	dir, err := ioutil.TempDir("", "plot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// spread the entries into several buckets
+	defer func(entries uint64) { plotBucketEntries = entries }(plotBucketEntries)
+	plotBucketEntries = 1 << 8
+
+	plot, err := ensurePlot(dir, sender, difficulty, nil)
+	if err != nil {
+		t.Fatalf("ensurePlot() error = %v", err)
+	}
+	defer plot.Close()
+	if plot.entries != 1<<difficulty {
+		t.Fatalf("plot entries = %v, want %v", plot.entries, 1<<difficulty)
+	}
+	var last uint64
+	for i := uint64(0); i < plot.entries; i++ {
+		prefix, index, err := plot.entry(i)
+		if err != nil {
+			t.Fatalf("entry(%d) error = %v", i, err)
+		}
+		if prefix < last {
+			t.Fatalf("entry(%d) is not sorted", i)
+		}
+		if want := binary.BigEndian.Uint64(storageEntry(sender, index)); prefix != want {
+			t.Fatalf("entry(%d) prefix = %x, want %x", i, prefix, want)
+		}
+		last = prefix
+	}
+
+	found := 0
+	for i := 0; i < 32; i++ {
+		challenge := crypto.Keccak256([]byte{byte(i)})
+		nonce, err := plot.lookup(sender, challenge, difficulty)
+		if err == errStorageProofNotFound {
+			continue
+		}
+		if err != nil {
+			t.Fatalf("lookup() error = %v", err)
+		}
+		found++
+
+		if !ValidateStorage(sender, challenge, nonce, difficulty) {
+			t.Errorf("ValidateStorage() of answer %v = false, want true", nonce)
+		}
+		if ValidateStorage(common.Address{1}, challenge, nonce, difficulty) {
+			t.Errorf("ValidateStorage() of other sender = true, want false")
+		}
+		if ValidateStorage(sender, challenge, nonce+1<<difficulty, difficulty) {
+			t.Errorf("ValidateStorage() of nonce out of plot = true, want false")
+		}
+	}
+	// a full plot answers about 98% of challenges
+	if found < 24 {
+		t.Errorf("answered %v of 32 challenges, want most of them", found)
+	}
+
+	// the stored plot is reused without plotting again
+	abort := make(chan interface{})
+	close(abort)
+	reused, err := ensurePlot(dir, sender, difficulty, abort)
+	if err != nil {
+		t.Fatalf("ensurePlot() of stored plot error = %v", err)
+	}
+	reused.Close()
+}
+
+func TestStorageProofAbort(t *testing.T) {
+	dir, err := ioutil.TempDir("", "plot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	abort := make(chan interface{})
+	close(abort)
+	w := newStorageWork(20, 0, common.Address{}, nil, dir)
+
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+	w.prove(abort, wg)
+	if err := w.error(); err != ErrPowAbort {
+		t.Errorf("error() = %v, want %v", err, ErrPowAbort)
+	}
+	if w.result().Success {
+		t.Errorf("result() of aborted work succeeded")
+	}
+	if _, err := openPlot(plotPath(dir, common.Address{}, 20), 20); err == nil {
+		t.Errorf("aborted plotting stored a plot")
+	}
+}
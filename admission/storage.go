@@ -0,0 +1,425 @@
+// Copyright 2018 The gcchain authors
+
+package admission
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gcchains/chain/commons/log"
+	"github.com/gcchains/chain/consensus"
+	"github.com/gcchains/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	Storage = "storage"
+
+	// maxStorageDifficulty is the largest number of plot bits, a plot of it takes 64GB on disk.
+	maxStorageDifficulty = 32
+
+	// plotEntrySize is the size of a plot entry on disk, the prefix of its hash followed by its index.
+	plotEntrySize = 16
+
+	// storageSlack is how many bits the common prefix of the answer and the challenge may be shorter than
+	// the plot bits. A full plot contains such an entry with probability about 1-e^-4.
+	storageSlack = 2
+
+	// challengePollInterval is the interval to check whether the challenge block arrives.
+	challengePollInterval = 500 * time.Millisecond
+)
+
+// storageParametersABI declares the getters of the storage proof parameters in the admission contract,
+// admission contracts without them do not require the proof.
+const storageParametersABI = `[{"constant":true,"inputs":[],"name":"storageDifficulty","outputs":[{"name":"","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[],"name":"storageWorkTimeout","outputs":[{"name":"","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"}]`
+
+// plotBucketEntries is the most entries sorted in memory at once when plotting, the entries are spread
+// into buckets by the leading bits of their prefixes and the plot is written bucket by bucket.
+var plotBucketEntries uint64 = 1 << 24
+
+var (
+	errStorageDifficulty    = errors.New("storage difficulty out of range")
+	errStorageProofNotFound = errors.New("no plot entry matches the storage challenge")
+	errNoPlotDir            = errors.New("no directory to store the plot")
+)
+
+// storageProofType proves the storage capacity of RNodes. A node plots 2^difficulty entries derived from
+// its address once and stores them on disk sorted. It answers the challenge, which is the hash of a block
+// mined after the proof starts, with the entry sharing the longest prefix with it. Nodes without the plot
+// stored have to redo all the hashing within the timeout, while verifying an answer takes one hash.
+type storageProofType struct{}
+
+func (s *storageProofType) Name() string {
+	return Storage
+}
+
+// NewWork reads storageDifficulty and storageWorkTimeout from the admission contract.
+func (s *storageProofType) NewWork(ctx *ProofContext) (ProofWork, error) {
+	difficulty, err := ctx.callUint(ctx.AdmissionContractAddr, storageParametersABI, "storageDifficulty")
+	if err != nil {
+		log.Debug("admission contract does not declare storage difficulty", "err", err)
+		return nil, errProofNotRequired
+	}
+	if difficulty.Sign() == 0 {
+		return nil, errProofNotRequired
+	}
+	if difficulty.Cmp(big.NewInt(maxStorageDifficulty)) > 0 {
+		return nil, errStorageDifficulty
+	}
+	if ctx.PlotDir == "" {
+		return nil, errNoPlotDir
+	}
+	timeout, err := ctx.callUint(ctx.AdmissionContractAddr, storageParametersABI, "storageWorkTimeout")
+	if err != nil {
+		return nil, err
+	}
+	lifeTime := time.Duration(timeout.Int64()) * time.Second
+	return newStorageWork(difficulty.Uint64(), lifeTime, ctx.Address, ctx.Chain, ctx.PlotDir), nil
+}
+
+type storageWork struct {
+	difficulty  uint64
+	nonce       uint64
+	timeout     time.Duration
+	coinbase    common.Address
+	chain       consensus.ChainReader
+	plotDir     string
+	blockNumber int64 // number of the challenge block
+	err         error
+	mutex       sync.RWMutex
+}
+
+// newStorageWork returns a new storage work with the plot stored in plotDir.
+func newStorageWork(difficulty uint64, timeout time.Duration, address common.Address, chain consensus.ChainReader, plotDir string) *storageWork {
+	return &storageWork{
+		difficulty: difficulty,
+		timeout:    timeout,
+		coinbase:   address,
+		chain:      chain,
+		plotDir:    plotDir,
+	}
+}
+
+// result returns the result, nonce is the index of the answered plot entry and block number is the
+// number of the challenge block.
+func (w *storageWork) result() Result {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	var nonce uint64 = 0
+	if w.err == nil {
+		nonce = w.nonce
+	}
+	return Result{
+		BlockNumber: w.blockNumber,
+		Nonce:       nonce,
+		Success:     w.err == nil,
+	}
+}
+
+// error returns the work error
+func (w *storageWork) error() error {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	return w.err
+}
+
+func (w *storageWork) setErr(err error) {
+	w.mutex.Lock()
+	w.err = err
+	w.mutex.Unlock()
+}
+
+// prove plots if the plot is not stored yet, waits for the challenge block and looks up the answer.
+// The timeout starts after plotting, the first proof after changing the difficulty may take long.
+func (w *storageWork) prove(abort <-chan interface{}, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	plot, err := ensurePlot(w.plotDir, w.coinbase, w.difficulty, abort)
+	if err != nil {
+		w.setErr(err)
+		return
+	}
+	defer plot.Close()
+
+	start := time.Now()
+	deadline := time.NewTimer(w.timeout)
+	defer deadline.Stop()
+
+	ticker := time.NewTicker(challengePollInterval)
+	defer ticker.Stop()
+	var (
+		number    = w.chain.CurrentHeader().Number.Uint64() + 1
+		challenge *types.Header
+	)
+	for challenge == nil {
+		select {
+		case <-abort:
+			w.setErr(ErrPowAbort)
+			return
+		case <-deadline.C:
+			w.setErr(ErrPowTimeout)
+			return
+		case <-ticker.C:
+			challenge = w.chain.GetHeaderByNumber(number)
+		}
+	}
+
+	nonce, err := plot.lookup(w.coinbase, challenge.Hash().Bytes(), w.difficulty)
+	if err != nil {
+		w.setErr(err)
+		return
+	}
+	w.mutex.Lock()
+	w.nonce = nonce
+	w.blockNumber = challenge.Number.Int64()
+	w.mutex.Unlock()
+	log.Info("found storage proof", "challenge number", number, "difficulty", w.difficulty,
+		"sender", w.coinbase.Hex(), "nonce", nonce, "timeCost(s)", time.Since(start).Seconds())
+}
+
+// plotFile is a plot stored on disk, entries sorted by their prefixes.
+type plotFile struct {
+	file    *os.File
+	entries uint64
+}
+
+// plotPath returns the path of the plot of the sender with the difficulty.
+func plotPath(dir string, sender common.Address, difficulty uint64) string {
+	return filepath.Join(dir, fmt.Sprintf("%x-%d.plot", sender.Bytes(), difficulty))
+}
+
+// ensurePlot opens the plot stored in dir, or plots and stores it if there is none.
+func ensurePlot(dir string, sender common.Address, difficulty uint64, abort <-chan interface{}) (*plotFile, error) {
+	path := plotPath(dir, sender, difficulty)
+	if plot, err := openPlot(path, difficulty); err == nil {
+		return plot, nil
+	}
+	log.Info("plotting for storage proof", "path", path, "difficulty", difficulty)
+	start := time.Now()
+	if err := createPlot(path, sender, difficulty, abort); err != nil {
+		return nil, err
+	}
+	log.Info("plotted for storage proof", "path", path, "difficulty", difficulty, "timeCost(s)", time.Since(start).Seconds())
+	return openPlot(path, difficulty)
+}
+
+// openPlot opens the plot at path, the plot is complete if its size matches the difficulty.
+func openPlot(path string, difficulty uint64) (*plotFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	entries := uint64(1) << difficulty
+	if stat, err := file.Stat(); err != nil || uint64(stat.Size()) != entries*plotEntrySize {
+		file.Close()
+		return nil, fmt.Errorf("incomplete plot %v", path)
+	}
+	return &plotFile{file: file, entries: entries}, nil
+}
+
+// createPlot computes all the plot entries and stores them at path sorted. The entries are spread into
+// bucket files by the leading bits of their prefixes, then each bucket is sorted in memory and appended.
+func createPlot(path string, sender common.Address, difficulty uint64, abort <-chan interface{}) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmpDir := path + ".buckets"
+	if err := os.RemoveAll(tmpDir); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(tmpDir, 0700); err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	var (
+		entries    = uint64(1) << difficulty
+		bucketBits = uint(0)
+	)
+	for entries>>bucketBits > plotBucketEntries {
+		bucketBits++
+	}
+	buckets := make([]*os.File, 1<<bucketBits)
+	writers := make([]*bufio.Writer, len(buckets))
+	defer func() {
+		for _, bucket := range buckets {
+			if bucket != nil {
+				bucket.Close()
+			}
+		}
+	}()
+	for i := range buckets {
+		bucket, err := os.Create(filepath.Join(tmpDir, fmt.Sprintf("%d", i)))
+		if err != nil {
+			return err
+		}
+		buckets[i], writers[i] = bucket, bufio.NewWriter(bucket)
+	}
+
+	entry := make([]byte, plotEntrySize)
+	for i := uint64(0); i < entries; i++ {
+		if i%4096 == 0 {
+			select {
+			case <-abort:
+				return ErrPowAbort
+			default:
+			}
+		}
+		prefix := binary.BigEndian.Uint64(storageEntry(sender, i))
+		binary.BigEndian.PutUint64(entry[:8], prefix)
+		binary.BigEndian.PutUint64(entry[8:], i)
+		if _, err := writers[prefix>>(64-bucketBits)%uint64(len(buckets))].Write(entry); err != nil {
+			return err
+		}
+	}
+
+	tmp := path + ".tmp"
+	plot, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+	defer plot.Close()
+
+	out := bufio.NewWriter(plot)
+	for i, bucket := range buckets {
+		select {
+		case <-abort:
+			return ErrPowAbort
+		default:
+		}
+		if err := writers[i].Flush(); err != nil {
+			return err
+		}
+		if _, err := bucket.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		data, err := ioutil.ReadAll(bucket)
+		if err != nil {
+			return err
+		}
+		sort.Sort(plotEntries(data))
+		if _, err := out.Write(data); err != nil {
+			return err
+		}
+	}
+	if err := out.Flush(); err != nil {
+		return err
+	}
+	if err := plot.Sync(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// plotEntries sorts the encoded entries of a bucket by their prefixes.
+type plotEntries []byte
+
+func (p plotEntries) Len() int { return len(p) / plotEntrySize }
+
+func (p plotEntries) Less(i, j int) bool {
+	return binary.BigEndian.Uint64(p[i*plotEntrySize:]) < binary.BigEndian.Uint64(p[j*plotEntrySize:])
+}
+
+func (p plotEntries) Swap(i, j int) {
+	var tmp [plotEntrySize]byte
+	a, b := p[i*plotEntrySize:(i+1)*plotEntrySize], p[j*plotEntrySize:(j+1)*plotEntrySize]
+	copy(tmp[:], a)
+	copy(a, b)
+	copy(b, tmp[:])
+}
+
+// entry reads the prefix and the index of the i-th entry.
+func (p *plotFile) entry(i uint64) (prefix uint64, index uint64, err error) {
+	buf := make([]byte, plotEntrySize)
+	if _, err := p.file.ReadAt(buf, int64(i*plotEntrySize)); err != nil {
+		return 0, 0, err
+	}
+	return binary.BigEndian.Uint64(buf[:8]), binary.BigEndian.Uint64(buf[8:]), nil
+}
+
+// lookup returns the index of the entry sharing the longest prefix with the challenge, which is one of
+// the neighbours of the challenge in the sorted plot.
+func (p *plotFile) lookup(sender common.Address, challengeHash []byte, difficulty uint64) (uint64, error) {
+	var (
+		target  = binary.BigEndian.Uint64(challengeHash)
+		readErr error
+	)
+	i := sort.Search(int(p.entries), func(i int) bool {
+		prefix, _, err := p.entry(uint64(i))
+		if err != nil && readErr == nil {
+			readErr = err
+		}
+		return prefix >= target
+	})
+	if readErr != nil {
+		return 0, readErr
+	}
+
+	best, bestBits := uint64(0), -1
+	for _, j := range []int{i - 1, i} {
+		if j < 0 || j >= int(p.entries) {
+			continue
+		}
+		prefix, index, err := p.entry(uint64(j))
+		if err != nil {
+			return 0, err
+		}
+		if n := bits.LeadingZeros64(prefix ^ target); n > bestBits {
+			best, bestBits = index, n
+		}
+	}
+	if bestBits < 0 || !ValidateStorage(sender, challengeHash, best, difficulty) {
+		return 0, errStorageProofNotFound
+	}
+	return best, nil
+}
+
+// Close closes the plot file.
+func (p *plotFile) Close() error {
+	return p.file.Close()
+}
+
+// storageEntry returns the plot entry of the index.
+func storageEntry(sender common.Address, index uint64) []byte {
+	indexBytes := make([]byte, 8)
+	binary.LittleEndian.PutUint64(indexBytes, index)
+	return crypto.Keccak256(sender.Bytes(), indexBytes)
+}
+
+// commonPrefixBits returns the number of leading bits a and b share.
+func commonPrefixBits(a, b []byte) int {
+	n := 0
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if x := a[i] ^ b[i]; x != 0 {
+			return n + bits.LeadingZeros8(x)
+		}
+		n += 8
+	}
+	return n
+}
+
+// ValidateStorage verifies the storage proof of the sender, nonce is the index of the answered plot entry.
+func ValidateStorage(sender common.Address, challengeHash []byte, nonce uint64, difficulty uint64) bool {
+	if difficulty == 0 || difficulty > maxStorageDifficulty || nonce >= uint64(1)<<difficulty {
+		return false
+	}
+	required := 0
+	if difficulty > storageSlack {
+		required = int(difficulty - storageSlack)
+	}
+	return commonPrefixBits(storageEntry(sender, nonce), challengeHash) >= required
+}
@@ -33,7 +33,17 @@ func Dial(rawurl string) (*Client, error) {
 }
 
 func DialContext(ctx context.Context, rawurl string) (*Client, error) {
-	c, err := rpc.DialContext(ctx, rawurl)
+	return DialContextWithAuth(ctx, rawurl, nil)
+}
+
+// DialWithAuth connects a client to the given URL, authenticating with the credentials of auth.
+func DialWithAuth(rawurl string, auth rpc.AuthProvider) (*Client, error) {
+	return DialContextWithAuth(context.Background(), rawurl, auth)
+}
+
+// DialContextWithAuth connects a client to the given URL, authenticating with the credentials of auth.
+func DialContextWithAuth(ctx context.Context, rawurl string, auth rpc.AuthProvider) (*Client, error) {
+	c, err := rpc.DialContextWithAuth(ctx, rawurl, auth)
 	if err != nil {
 		return nil, err
 	}
@@ -172,6 +182,8 @@ type txExtraInfo struct {
 	BlockNumber *string         `json:"blockNumber,omitempty"`
 	BlockHash   *common.Hash    `json:"blockHash,omitempty"`
 	From        *common.Address `json:"from,omitempty"`
+
+	TransactionIndex *hexutil.Uint `json:"transactionIndex,omitempty"`
 }
 
 func (tx *rpcTransaction) UnmarshalJSON(msg []byte) error {
@@ -260,6 +272,48 @@ func (c *Client) TransactionReceipt(ctx context.Context, txHash common.Hash) (*t
 	return r, err
 }
 
+// AddressTransaction is a transaction of an address along with its position in the chain.
+type AddressTransaction struct {
+	Tx          *types.Transaction
+	BlockHash   common.Hash
+	BlockNumber uint64
+	Index       uint
+}
+
+// TransactionsByAddress returns a page of the transactions sent from, sent to or creating the
+// address between fromBlock and toBlock, nil meaning the latest indexed block. The returned cursor
+// is non-nil if there are further transactions, which are returned by passing it to the next call.
+// The node must maintain the address index.
+func (c *Client) TransactionsByAddress(ctx context.Context, address common.Address, fromBlock, toBlock *big.Int, cursor *uint64) ([]AddressTransaction, *uint64, error) {
+	var result struct {
+		Transactions []*rpcTransaction `json:"transactions"`
+		Cursor       *hexutil.Uint64   `json:"cursor"`
+	}
+	var arg *hexutil.Uint64
+	if cursor != nil {
+		arg = (*hexutil.Uint64)(cursor)
+	}
+	err := c.c.CallContext(ctx, &result, "gcc_getTransactionsByAddress", address, toBlockNumArg(fromBlock), toBlockNumArg(toBlock), arg)
+	if err != nil {
+		return nil, nil, err
+	}
+	txs := make([]AddressTransaction, 0, len(result.Transactions))
+	for _, json := range result.Transactions {
+		if json.BlockHash == nil || json.BlockNumber == nil || json.TransactionIndex == nil {
+			return nil, nil, fmt.Errorf("server returned transaction without block")
+		}
+		number, err := hexutil.DecodeUint64(*json.BlockNumber)
+		if err != nil {
+			return nil, nil, err
+		}
+		if json.From != nil {
+			setSenderFromServer(json.tx, *json.From, *json.BlockHash)
+		}
+		txs = append(txs, AddressTransaction{Tx: json.tx, BlockHash: *json.BlockHash, BlockNumber: number, Index: uint(*json.TransactionIndex)})
+	}
+	return txs, (*uint64)(result.Cursor), nil
+}
+
 func toBlockNumArg(number *big.Int) string {
 	if number == nil {
 		return "latest"
@@ -466,6 +520,68 @@ func toFilterArg(q gcchain.FilterQuery) interface{} {
 	return arg
 }
 
+// SubscribePendingTransactions subscribes to the full transactions matching the query as
+// they enter the transaction pool.
+func (c *Client) SubscribePendingTransactions(ctx context.Context, q gcchain.PendingTxQuery, ch chan<- *types.Transaction) (gcchain.Subscription, error) {
+	return c.c.EthSubscribe(ctx, ch, "newFullPendingTransactions", toPendingTxArg(q))
+}
+
+func toPendingTxArg(q gcchain.PendingTxQuery) interface{} {
+	methods := make([]hexutil.Bytes, len(q.Methods))
+	for i := range q.Methods {
+		methods[i] = q.Methods[i][:]
+	}
+	return map[string]interface{}{
+		"from":    q.From,
+		"to":      q.To,
+		"methods": methods,
+	}
+}
+
+// TransactionInclusion is the inclusion of a transaction in a block with the
+// required confirmations, or its removal by a chain reorg if Removed is true.
+type TransactionInclusion struct {
+	TxHash        common.Hash
+	BlockHash     common.Hash
+	BlockNumber   uint64
+	Index         uint
+	Confirmations uint64
+	Receipt       *types.Receipt
+	Removed       bool
+}
+
+func (ti *TransactionInclusion) UnmarshalJSON(msg []byte) error {
+	var dec struct {
+		TxHash           common.Hash    `json:"transactionHash"`
+		BlockHash        common.Hash    `json:"blockHash"`
+		BlockNumber      hexutil.Uint64 `json:"blockNumber"`
+		TransactionIndex hexutil.Uint64 `json:"transactionIndex"`
+		Confirmations    hexutil.Uint64 `json:"confirmations"`
+		Receipt          *types.Receipt `json:"receipt"`
+		Removed          bool           `json:"removed"`
+	}
+	if err := json.Unmarshal(msg, &dec); err != nil {
+		return err
+	}
+	*ti = TransactionInclusion{
+		TxHash:        dec.TxHash,
+		BlockHash:     dec.BlockHash,
+		BlockNumber:   uint64(dec.BlockNumber),
+		Index:         uint(dec.TransactionIndex),
+		Confirmations: uint64(dec.Confirmations),
+		Receipt:       dec.Receipt,
+		Removed:       dec.Removed,
+	}
+	return nil
+}
+
+// SubscribeTransactionIncluded subscribes to the inclusion of the transaction once its
+// block has the given number of confirmations, and to the removal of the inclusion by
+// chain reorgs. A confirmations of 0 or 1 is notified as soon as the block is imported.
+func (c *Client) SubscribeTransactionIncluded(ctx context.Context, txHash common.Hash, confirmations uint64, ch chan<- *TransactionInclusion) (gcchain.Subscription, error) {
+	return c.c.EthSubscribe(ctx, ch, "transactionIncluded", txHash, hexutil.Uint64(confirmations))
+}
+
 // Pending State
 
 // PendingBalanceAt returns the wei balance of the given account in the pending state.
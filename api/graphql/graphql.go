@@ -0,0 +1,698 @@
+// Copyright 2018 The gcchain authors
+
+// Package graphql serves chain data, accounts and the DPoS data of blocks over GraphQL.
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/gcchains/chain/api/rpc"
+	"github.com/gcchains/chain/core/rawdb"
+	"github.com/gcchains/chain/core/state"
+	"github.com/gcchains/chain/internal/gccapi"
+	"github.com/gcchains/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// maxBlockRange is the number of blocks returned by a blocks query at most.
+	maxBlockRange = 1000
+	// maxLogsBlockRange is the number of blocks searched by a logs query at most.
+	maxLogsBlockRange = 10000
+)
+
+var errBlockNotFound = errors.New("block not found")
+
+// Account is an account at a block.
+type Account struct {
+	backend gccapi.Backend
+	address common.Address
+	number  rpc.BlockNumber
+}
+
+func (a *Account) getState(ctx context.Context) (*state.StateDB, error) {
+	state, _, err := a.backend.StateAndHeaderByNumber(ctx, a.number, false)
+	if state == nil && err == nil {
+		err = errBlockNotFound
+	}
+	return state, err
+}
+
+func (a *Account) Address(ctx context.Context) Address {
+	return Address(a.address)
+}
+
+func (a *Account) Balance(ctx context.Context) (BigInt, error) {
+	state, err := a.getState(ctx)
+	if err != nil {
+		return BigInt{}, err
+	}
+	return newBigInt(state.GetBalance(a.address)), nil
+}
+
+func (a *Account) TransactionCount(ctx context.Context) (Long, error) {
+	state, err := a.getState(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return Long(state.GetNonce(a.address)), nil
+}
+
+func (a *Account) Code(ctx context.Context) (Bytes, error) {
+	state, err := a.getState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return Bytes(state.GetCode(a.address)), nil
+}
+
+func (a *Account) Storage(ctx context.Context, args struct{ Slot Bytes32 }) (Bytes32, error) {
+	state, err := a.getState(ctx)
+	if err != nil {
+		return Bytes32{}, err
+	}
+	return Bytes32(state.GetState(a.address, common.Hash(args.Slot))), nil
+}
+
+// Log is a log emitted by a transaction.
+type Log struct {
+	tx  *Transaction
+	log *types.Log
+}
+
+func (l *Log) Index(ctx context.Context) int32 {
+	return int32(l.log.Index)
+}
+
+func (l *Log) Account(ctx context.Context) *Account {
+	return &Account{backend: l.tx.backend, address: l.log.Address, number: rpc.BlockNumber(l.log.BlockNumber)}
+}
+
+func (l *Log) Topics(ctx context.Context) []Bytes32 {
+	topics := make([]Bytes32, len(l.log.Topics))
+	for i, topic := range l.log.Topics {
+		topics[i] = Bytes32(topic)
+	}
+	return topics
+}
+
+func (l *Log) Data(ctx context.Context) Bytes {
+	return Bytes(l.log.Data)
+}
+
+func (l *Log) Transaction(ctx context.Context) *Transaction {
+	return l.tx
+}
+
+// Receipt is the receipt of an included transaction.
+type Receipt struct {
+	tx      *Transaction
+	receipt *types.Receipt
+}
+
+func (r *Receipt) Status(ctx context.Context) Long {
+	return Long(r.receipt.Status)
+}
+
+func (r *Receipt) GasUsed(ctx context.Context) Long {
+	return Long(r.receipt.GasUsed)
+}
+
+func (r *Receipt) CumulativeGasUsed(ctx context.Context) Long {
+	return Long(r.receipt.CumulativeGasUsed)
+}
+
+func (r *Receipt) CreatedContract(ctx context.Context) *Account {
+	if r.tx.tx.To() != nil || r.receipt.ContractAddress == (common.Address{}) {
+		return nil
+	}
+	return &Account{backend: r.tx.backend, address: r.receipt.ContractAddress, number: r.tx.number()}
+}
+
+func (r *Receipt) Logs(ctx context.Context) []*Log {
+	logs := make([]*Log, len(r.receipt.Logs))
+	for i, log := range r.receipt.Logs {
+		logs[i] = &Log{tx: r.tx, log: log}
+	}
+	return logs
+}
+
+func (r *Receipt) LogsBloom(ctx context.Context) Bytes {
+	return Bytes(r.receipt.Bloom.Bytes())
+}
+
+// Transaction is a transaction included in a block, or a pending one if block is nil.
+type Transaction struct {
+	backend gccapi.Backend
+	tx      *types.Transaction
+	block   *types.Block
+	index   uint64
+}
+
+// number returns the number of the block of the transaction, the latest block if it is pending.
+func (t *Transaction) number() rpc.BlockNumber {
+	if t.block == nil {
+		return rpc.LatestBlockNumber
+	}
+	return rpc.BlockNumber(t.block.NumberU64())
+}
+
+// receipt returns the receipt of the transaction, nil if it is pending.
+func (t *Transaction) receipt(ctx context.Context) (*types.Receipt, error) {
+	if t.block == nil {
+		return nil, nil
+	}
+	receipts, err := t.backend.GetReceipts(ctx, t.block.Hash())
+	if err != nil {
+		return nil, err
+	}
+	return gccapi.ReceiptOf(ctx, t.backend, t.tx, receipts, t.index), nil
+}
+
+func (t *Transaction) Hash(ctx context.Context) Bytes32 {
+	return Bytes32(t.tx.Hash())
+}
+
+func (t *Transaction) Nonce(ctx context.Context) Long {
+	return Long(t.tx.Nonce())
+}
+
+func (t *Transaction) Index(ctx context.Context) *int32 {
+	if t.block == nil {
+		return nil
+	}
+	index := int32(t.index)
+	return &index
+}
+
+func (t *Transaction) From(ctx context.Context) (*Account, error) {
+	var signer types.Signer = types.FrontierSigner{}
+	if t.tx.Protected() {
+		signer = types.NewCep1Signer(t.tx.ChainId())
+	}
+	from, err := types.Sender(signer, t.tx)
+	if err != nil {
+		return nil, err
+	}
+	return &Account{backend: t.backend, address: from, number: t.number()}, nil
+}
+
+func (t *Transaction) To(ctx context.Context) *Account {
+	to := t.tx.To()
+	if to == nil {
+		return nil
+	}
+	return &Account{backend: t.backend, address: *to, number: t.number()}
+}
+
+func (t *Transaction) Value(ctx context.Context) BigInt {
+	return newBigInt(t.tx.Value())
+}
+
+func (t *Transaction) GasPrice(ctx context.Context) BigInt {
+	return newBigInt(t.tx.GasPrice())
+}
+
+func (t *Transaction) Gas(ctx context.Context) Long {
+	return Long(t.tx.Gas())
+}
+
+func (t *Transaction) InputData(ctx context.Context) Bytes {
+	return Bytes(t.tx.Data())
+}
+
+func (t *Transaction) Private(ctx context.Context) bool {
+	return t.tx.IsPrivate()
+}
+
+func (t *Transaction) Block(ctx context.Context) *Block {
+	if t.block == nil {
+		return nil
+	}
+	return &Block{backend: t.backend, block: t.block}
+}
+
+func (t *Transaction) Receipt(ctx context.Context) (*Receipt, error) {
+	receipt, err := t.receipt(ctx)
+	if receipt == nil || err != nil {
+		return nil, err
+	}
+	return &Receipt{tx: t, receipt: receipt}, nil
+}
+
+// Dpos is the DPoS data of a block.
+type Dpos struct {
+	backend gccapi.Backend
+	block   *types.Block
+}
+
+func (d *Dpos) number() rpc.BlockNumber {
+	return rpc.BlockNumber(d.block.NumberU64())
+}
+
+// Term returns the term of the block, the genesis block is in term 0.
+func (d *Dpos) Term(ctx context.Context) Long {
+	number := d.block.NumberU64()
+	if number == 0 {
+		return 0
+	}
+	return Long((number - 1) / (d.backend.ViewLen() * d.backend.TermLen()))
+}
+
+// View returns the view of the block in its term.
+func (d *Dpos) View(ctx context.Context) Long {
+	number := d.block.NumberU64()
+	if number == 0 {
+		return 0
+	}
+	vl, tl := d.backend.ViewLen(), d.backend.TermLen()
+	return Long(((number - 1) % (vl * tl)) / tl)
+}
+
+// Proposer returns the proposer scheduled for the block, the genesis block has none.
+func (d *Dpos) Proposer(ctx context.Context) (Address, error) {
+	if d.block.NumberU64() == 0 {
+		return Address{}, nil
+	}
+	proposer, err := d.backend.ProposerOf(d.number())
+	return Address(proposer), err
+}
+
+func (d *Dpos) Proposers(ctx context.Context) ([]Address, error) {
+	proposers, err := d.backend.Proposers(d.number())
+	return newAddresses(proposers), err
+}
+
+func (d *Dpos) Validators(ctx context.Context) ([]Address, error) {
+	validators, err := d.backend.Validators(d.number())
+	return newAddresses(validators), err
+}
+
+func (d *Dpos) Impeachment(ctx context.Context) bool {
+	return d.block.Impeachment()
+}
+
+func (d *Dpos) Reward(ctx context.Context) BigInt {
+	return newBigInt(d.backend.BlockReward(d.number()))
+}
+
+func newAddresses(addresses []common.Address) []Address {
+	result := make([]Address, len(addresses))
+	for i, address := range addresses {
+		result[i] = Address(address)
+	}
+	return result
+}
+
+// Block is a block of the chain.
+type Block struct {
+	backend gccapi.Backend
+	block   *types.Block
+}
+
+func (b *Block) Number(ctx context.Context) Long {
+	return Long(b.block.NumberU64())
+}
+
+func (b *Block) Hash(ctx context.Context) Bytes32 {
+	return Bytes32(b.block.Hash())
+}
+
+func (b *Block) Parent(ctx context.Context) (*Block, error) {
+	if b.block.NumberU64() == 0 {
+		return nil, nil
+	}
+	parent, err := b.backend.GetBlock(ctx, b.block.ParentHash())
+	if parent == nil || err != nil {
+		return nil, err
+	}
+	return &Block{backend: b.backend, block: parent}, nil
+}
+
+func (b *Block) Miner(ctx context.Context) *Account {
+	return b.Account(ctx, struct{ Address Address }{Address(b.block.Coinbase())})
+}
+
+func (b *Block) StateRoot(ctx context.Context) Bytes32 {
+	return Bytes32(b.block.StateRoot())
+}
+
+func (b *Block) TransactionsRoot(ctx context.Context) Bytes32 {
+	return Bytes32(b.block.TxsRoot())
+}
+
+func (b *Block) ReceiptsRoot(ctx context.Context) Bytes32 {
+	return Bytes32(b.block.ReceiptsRoot())
+}
+
+func (b *Block) LogsBloom(ctx context.Context) Bytes {
+	return Bytes(b.block.LogsBloom().Bytes())
+}
+
+func (b *Block) GasLimit(ctx context.Context) Long {
+	return Long(b.block.GasLimit())
+}
+
+func (b *Block) GasUsed(ctx context.Context) Long {
+	return Long(b.block.GasUsed())
+}
+
+func (b *Block) Timestamp(ctx context.Context) Long {
+	return Long(b.block.Time().Uint64())
+}
+
+func (b *Block) ExtraData(ctx context.Context) Bytes {
+	return Bytes(b.block.Extra())
+}
+
+func (b *Block) TransactionCount(ctx context.Context) int32 {
+	return int32(len(b.block.Transactions()))
+}
+
+func (b *Block) Transactions(ctx context.Context) []*Transaction {
+	txs := make([]*Transaction, len(b.block.Transactions()))
+	for i, tx := range b.block.Transactions() {
+		txs[i] = &Transaction{backend: b.backend, tx: tx, block: b.block, index: uint64(i)}
+	}
+	return txs
+}
+
+func (b *Block) TransactionAt(ctx context.Context, args struct{ Index int32 }) *Transaction {
+	txs := b.block.Transactions()
+	if args.Index < 0 || int(args.Index) >= len(txs) {
+		return nil
+	}
+	return &Transaction{backend: b.backend, tx: txs[args.Index], block: b.block, index: uint64(args.Index)}
+}
+
+// BlockFilterCriteria selects the logs of a block.
+type BlockFilterCriteria struct {
+	Addresses *[]Address
+	Topics    *[][]Bytes32
+}
+
+func (b *Block) Logs(ctx context.Context, args struct{ Filter BlockFilterCriteria }) ([]*Log, error) {
+	return b.filterLogs(ctx, newLogFilter(args.Filter.Addresses, args.Filter.Topics))
+}
+
+// filterLogs returns the logs of the block matching the filter.
+func (b *Block) filterLogs(ctx context.Context, filter *logFilter) ([]*Log, error) {
+	var logs []*Log
+	for _, tx := range b.Transactions(ctx) {
+		receipt, err := tx.receipt(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if receipt == nil {
+			continue
+		}
+		for _, log := range receipt.Logs {
+			if filter.matches(log) {
+				logs = append(logs, &Log{tx: tx, log: log})
+			}
+		}
+	}
+	return logs, nil
+}
+
+func (b *Block) Account(ctx context.Context, args struct{ Address Address }) *Account {
+	return &Account{backend: b.backend, address: common.Address(args.Address), number: rpc.BlockNumber(b.block.NumberU64())}
+}
+
+func (b *Block) Dpos(ctx context.Context) *Dpos {
+	return &Dpos{backend: b.backend, block: b.block}
+}
+
+// logFilter matches logs by the address of their contract and their topics.
+type logFilter struct {
+	addresses []common.Address
+	topics    [][]common.Hash
+}
+
+func newLogFilter(addresses *[]Address, topics *[][]Bytes32) *logFilter {
+	filter := new(logFilter)
+	if addresses != nil {
+		for _, address := range *addresses {
+			filter.addresses = append(filter.addresses, common.Address(address))
+		}
+	}
+	if topics != nil {
+		filter.topics = make([][]common.Hash, len(*topics))
+		for i, alternatives := range *topics {
+			for _, topic := range alternatives {
+				filter.topics[i] = append(filter.topics[i], common.Hash(topic))
+			}
+		}
+	}
+	return filter
+}
+
+// matches returns whether the log matches the filter.
+func (f *logFilter) matches(log *types.Log) bool {
+	if len(f.addresses) > 0 && !containsAddress(f.addresses, log.Address) {
+		return false
+	}
+	if len(f.topics) > len(log.Topics) {
+		return false
+	}
+	for i, alternatives := range f.topics {
+		if len(alternatives) > 0 && !containsHash(alternatives, log.Topics[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// mayMatch returns whether the bloom filter of a block may hold logs matching the filter.
+func (f *logFilter) mayMatch(bloom types.Bloom) bool {
+	if len(f.addresses) > 0 {
+		found := false
+		for _, address := range f.addresses {
+			if types.BloomLookup(bloom, address) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for _, alternatives := range f.topics {
+		found := len(alternatives) == 0
+		for _, topic := range alternatives {
+			if types.BloomLookup(bloom, topic) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func containsAddress(addresses []common.Address, address common.Address) bool {
+	for _, a := range addresses {
+		if a == address {
+			return true
+		}
+	}
+	return false
+}
+
+func containsHash(hashes []common.Hash, hash common.Hash) bool {
+	for _, h := range hashes {
+		if h == hash {
+			return true
+		}
+	}
+	return false
+}
+
+// RNode is a reputation node at the latest block.
+type RNode struct {
+	address   common.Address
+	rpt       int64
+	committee bool
+}
+
+func (r *RNode) Address(ctx context.Context) Address {
+	return Address(r.address)
+}
+
+func (r *RNode) Rpt(ctx context.Context) BigInt {
+	return BigInt(*new(big.Int).SetInt64(r.rpt))
+}
+
+func (r *RNode) Committee(ctx context.Context) bool {
+	return r.committee
+}
+
+// Resolver is the root resolver of the queries.
+type Resolver struct {
+	backend gccapi.Backend
+}
+
+// newBlock returns the resolver of the block, nil if it is nil.
+func (r *Resolver) newBlock(block *types.Block) *Block {
+	if block == nil {
+		return nil
+	}
+	return &Block{backend: r.backend, block: block}
+}
+
+func (r *Resolver) Block(ctx context.Context, args struct {
+	Number *Long
+	Hash   *Bytes32
+}) (*Block, error) {
+	var (
+		block *types.Block
+		err   error
+	)
+	switch {
+	case args.Hash != nil:
+		block, err = r.backend.GetBlock(ctx, common.Hash(*args.Hash))
+	case args.Number != nil:
+		block, err = r.backend.BlockByNumber(ctx, rpc.BlockNumber(*args.Number))
+	default:
+		block, err = r.backend.BlockByNumber(ctx, rpc.LatestBlockNumber)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.newBlock(block), nil
+}
+
+// blockRange returns the range of blocks between from and to, which default to the latest block.
+func (r *Resolver) blockRange(from, to *Long, max uint64) (uint64, uint64, error) {
+	head := r.backend.CurrentBlock().NumberU64()
+	first, last := head, head
+	if from != nil {
+		first = uint64(*from)
+	}
+	if to != nil && uint64(*to) < head {
+		last = uint64(*to)
+	}
+	if first > last {
+		return first, last, nil
+	}
+	if last-first >= max {
+		return 0, 0, fmt.Errorf("block range %d-%d exceeds %d blocks", first, last, max)
+	}
+	return first, last, nil
+}
+
+func (r *Resolver) Blocks(ctx context.Context, args struct {
+	From Long
+	To   *Long
+}) ([]*Block, error) {
+	first, last, err := r.blockRange(&args.From, args.To, maxBlockRange)
+	if err != nil {
+		return nil, err
+	}
+	var blocks []*Block
+	for number := first; number <= last; number++ {
+		block, err := r.backend.BlockByNumber(ctx, rpc.BlockNumber(number))
+		if err != nil {
+			return nil, err
+		}
+		if block == nil {
+			break
+		}
+		blocks = append(blocks, r.newBlock(block))
+	}
+	return blocks, nil
+}
+
+func (r *Resolver) Transaction(ctx context.Context, args struct{ Hash Bytes32 }) (*Transaction, error) {
+	hash := common.Hash(args.Hash)
+	if tx, blockHash, _, index := rawdb.ReadTransaction(r.backend.ChainDb(), hash); tx != nil {
+		block, err := r.backend.GetBlock(ctx, blockHash)
+		if err != nil {
+			return nil, err
+		}
+		if block != nil {
+			return &Transaction{backend: r.backend, tx: tx, block: block, index: index}, nil
+		}
+	}
+	if tx := r.backend.GetPoolTransaction(hash); tx != nil {
+		return &Transaction{backend: r.backend, tx: tx}, nil
+	}
+	return nil, nil
+}
+
+func (r *Resolver) Account(ctx context.Context, args struct {
+	Address Address
+	Block   *Long
+}) *Account {
+	number := rpc.LatestBlockNumber
+	if args.Block != nil {
+		number = rpc.BlockNumber(*args.Block)
+	}
+	return &Account{backend: r.backend, address: common.Address(args.Address), number: number}
+}
+
+// FilterCriteria selects the logs of a range of blocks.
+type FilterCriteria struct {
+	FromBlock *Long
+	ToBlock   *Long
+	Addresses *[]Address
+	Topics    *[][]Bytes32
+}
+
+func (r *Resolver) Logs(ctx context.Context, args struct{ Filter FilterCriteria }) ([]*Log, error) {
+	first, last, err := r.blockRange(args.Filter.FromBlock, args.Filter.ToBlock, maxLogsBlockRange)
+	if err != nil {
+		return nil, err
+	}
+	filter := newLogFilter(args.Filter.Addresses, args.Filter.Topics)
+
+	var logs []*Log
+	for number := first; number <= last; number++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		header, err := r.backend.HeaderByNumber(ctx, rpc.BlockNumber(number))
+		if err != nil {
+			return nil, err
+		}
+		if header == nil {
+			break
+		}
+		if !filter.mayMatch(header.LogsBloom) {
+			continue
+		}
+		block, err := r.backend.GetBlock(ctx, header.Hash())
+		if err != nil {
+			return nil, err
+		}
+		if block == nil {
+			return nil, errBlockNotFound
+		}
+		blockLogs, err := r.newBlock(block).filterLogs(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, blockLogs...)
+	}
+	return logs, nil
+}
+
+func (r *Resolver) RNodes(ctx context.Context) []*RNode {
+	addresses, number := r.backend.RNode()
+	committee := r.backend.CommitteMember()
+
+	rnodes := make([]*RNode, len(addresses))
+	for i, address := range addresses {
+		rnodes[i] = &RNode{
+			address:   address,
+			rpt:       r.backend.CalcRptInfo(address, addresses, number),
+			committee: gccapi.IsCommittee(address, committee),
+		}
+	}
+	return rnodes
+}
@@ -0,0 +1,396 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gcchains/chain/api/rpc"
+	"github.com/gcchains/chain/configs"
+	"github.com/gcchains/chain/consensus/dpos"
+	"github.com/gcchains/chain/core"
+	"github.com/gcchains/chain/core/state"
+	"github.com/gcchains/chain/core/vm"
+	"github.com/gcchains/chain/database"
+	"github.com/gcchains/chain/internal/gccapi"
+	"github.com/gcchains/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	testKey, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	testAddress = crypto.PubkeyToAddress(testKey.PublicKey)
+	testTopic   = common.HexToHash("0x0102030405060708091011121314151617181920212223242526272829303132")
+	testGenesis = &core.Genesis{
+		Config:   configs.TestChainConfig,
+		GasLimit: 3141592,
+		Alloc:    core.GenesisAlloc{testAddress: {Balance: big.NewInt(1000000000)}},
+	}
+	// testLogCode is the init code of a contract emitting a log with testTopic and data 42
+	testLogCode = common.FromHex("602a600052" + "7f" + testTopic.Hex()[2:] + "60206000a100")
+)
+
+// testBackend implements the methods of gccapi.Backend used by the resolvers with a chain.
+type testBackend struct {
+	gccapi.Backend
+	chain *core.BlockChain
+	db    database.Database
+}
+
+func (b *testBackend) ChainDb() database.Database { return b.db }
+
+func (b *testBackend) CurrentBlock() *types.Block { return b.chain.CurrentBlock() }
+
+func (b *testBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	if number == rpc.LatestBlockNumber {
+		return b.chain.CurrentHeader(), nil
+	}
+	return b.chain.GetHeaderByNumber(uint64(number)), nil
+}
+
+func (b *testBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Block, error) {
+	if number == rpc.LatestBlockNumber {
+		return b.chain.CurrentBlock(), nil
+	}
+	return b.chain.GetBlockByNumber(uint64(number)), nil
+}
+
+func (b *testBackend) StateAndHeaderByNumber(ctx context.Context, number rpc.BlockNumber, isPrivate bool) (*state.StateDB, *types.Header, error) {
+	header, _ := b.HeaderByNumber(ctx, number)
+	if header == nil {
+		return nil, nil, nil
+	}
+	state, err := b.chain.StateAt(header.StateRoot)
+	return state, header, err
+}
+
+func (b *testBackend) GetBlock(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	return b.chain.GetBlockByHash(hash), nil
+}
+
+func (b *testBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
+	return b.chain.GetReceiptsByHash(hash), nil
+}
+
+func (b *testBackend) GetPoolTransaction(hash common.Hash) *types.Transaction { return nil }
+
+func (b *testBackend) ViewLen() uint64 { return 3 }
+
+func (b *testBackend) TermLen() uint64 { return 4 }
+
+func (b *testBackend) Proposers(number rpc.BlockNumber) ([]common.Address, error) {
+	return []common.Address{{1}, {2}, {3}, {4}}, nil
+}
+
+func (b *testBackend) Validators(number rpc.BlockNumber) ([]common.Address, error) {
+	return []common.Address{{5}}, nil
+}
+
+func (b *testBackend) ProposerOf(number rpc.BlockNumber) (common.Address, error) {
+	proposers, _ := b.Proposers(number)
+	return proposers[(uint64(number)-1)%b.TermLen()], nil
+}
+
+func (b *testBackend) BlockReward(number rpc.BlockNumber) *big.Int { return big.NewInt(5) }
+
+func (b *testBackend) RNode() ([]common.Address, uint64) {
+	return []common.Address{{1}, {6}}, b.chain.CurrentBlock().NumberU64()
+}
+
+func (b *testBackend) CommitteMember() []common.Address { return []common.Address{{1}} }
+
+func (b *testBackend) CalcRptInfo(address common.Address, addresses []common.Address, number uint64) int64 {
+	return int64(address[0]) * 10
+}
+
+// newTestBackend creates a chain of 6 blocks, block 1 holds a transfer and block 2 the creation of
+// a contract emitting a log.
+func newTestBackend(t *testing.T) *testBackend {
+	db := database.NewMemDatabase()
+	genesis := testGenesis.MustCommit(db)
+	engine := dpos.NewFaker(configs.ChainConfigInfo().Dpos, db)
+	remoteDB := database.NewIpfsDbWithAdapter(database.NewFakeIpfsAdapter())
+	signer := types.NewCep1Signer(testGenesis.Config.ChainID)
+
+	blocks, _ := core.GenerateChain(testGenesis.Config, genesis, engine, db, remoteDB, 6, func(i int, gen *core.BlockGen) {
+		var tx *types.Transaction
+		switch i {
+		case 0:
+			tx = types.NewTransaction(gen.TxNonce(testAddress), common.Address{1}, big.NewInt(1000), configs.TxGas, nil, nil)
+		case 1:
+			tx = types.NewContractCreation(gen.TxNonce(testAddress), new(big.Int), 100000, nil, testLogCode)
+		default:
+			return
+		}
+		signed, err := types.SignTx(tx, signer, testKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gen.AddTx(signed)
+	})
+	chain, err := core.NewBlockChain(db, nil, testGenesis.Config, engine, vm.Config{}, remoteDB, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	return &testBackend{chain: chain, db: db}
+}
+
+// query executes the query and decodes its data into result.
+func query(t *testing.T, h *handler, q string, result interface{}) {
+	response := h.schema.Exec(context.Background(), q, "", nil)
+	if len(response.Errors) > 0 {
+		t.Fatalf("query %s failed: %v", q, response.Errors)
+	}
+	if err := json.Unmarshal(response.Data, result); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBlockQuery(t *testing.T) {
+	backend := newTestBackend(t)
+	defer backend.chain.Stop()
+	h, err := newHandler(backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var result struct {
+		Block struct {
+			Number       uint64
+			Hash         common.Hash
+			Parent       struct{ Number uint64 }
+			Transactions []struct {
+				Hash  common.Hash
+				Index int
+				From  struct {
+					Address common.Address
+					Balance string
+				}
+				To      struct{ Address common.Address }
+				Value   string
+				Receipt struct {
+					Status  uint64
+					GasUsed uint64
+				}
+			}
+			Dpos struct {
+				Term        uint64
+				View        uint64
+				Proposer    common.Address
+				Validators  []common.Address
+				Impeachment bool
+				Reward      string
+			}
+			Account struct{ Balance string }
+		}
+	}
+	query(t, h, fmt.Sprintf(`{ block(number: 1) { number hash parent { number } transactions { hash index from { address balance } to { address } value receipt { status gasUsed } }
+		dpos { term view proposer validators impeachment reward } account(address: "%s") { balance } } }`, common.Address{1}.Hex()), &result)
+
+	block := backend.chain.GetBlockByNumber(1)
+	if result.Block.Number != 1 || result.Block.Hash != block.Hash() || result.Block.Parent.Number != 0 {
+		t.Errorf("block = %d %x with parent %d, want 1 %x with parent 0", result.Block.Number, result.Block.Hash, result.Block.Parent.Number, block.Hash())
+	}
+	if len(result.Block.Transactions) != 1 {
+		t.Fatalf("block has %d transactions, want 1", len(result.Block.Transactions))
+	}
+	tx := result.Block.Transactions[0]
+	if tx.Hash != block.Transactions()[0].Hash() || tx.From.Address != testAddress || tx.To.Address != (common.Address{1}) || tx.Value != "0x3e8" {
+		t.Errorf("transaction = %+v", tx)
+	}
+	if tx.Receipt.Status != types.ReceiptStatusSuccessful || tx.Receipt.GasUsed != configs.TxGas {
+		t.Errorf("receipt = %+v", tx.Receipt)
+	}
+	if result.Block.Account.Balance != "0x3e8" {
+		t.Errorf("balance at block 1 = %s, want 0x3e8", result.Block.Account.Balance)
+	}
+	dpos := result.Block.Dpos
+	if dpos.Term != 0 || dpos.View != 0 || dpos.Proposer != (common.Address{1}) || len(dpos.Validators) != 1 || dpos.Impeachment != block.Impeachment() || dpos.Reward != "0x5" {
+		t.Errorf("dpos = %+v", dpos)
+	}
+
+	// Terms and views follow the view and term lengths of the backend
+	var later struct {
+		Block struct {
+			Dpos struct {
+				Term     uint64
+				View     uint64
+				Proposer common.Address
+			}
+		}
+	}
+	query(t, h, `{ block(number: 6) { dpos { term view proposer } } }`, &later)
+	if d := later.Block.Dpos; d.Term != 0 || d.View != 1 || d.Proposer != (common.Address{2}) {
+		t.Errorf("dpos of block 6 = %+v, want term 0, view 1, proposer 2", d)
+	}
+
+	var blocks struct{ Blocks []struct{ Number uint64 } }
+	query(t, h, `{ blocks(from: 2) { number } }`, &blocks)
+	if len(blocks.Blocks) != 5 || blocks.Blocks[4].Number != 6 {
+		t.Errorf("blocks from 2 = %v, want 2-6", blocks.Blocks)
+	}
+}
+
+func TestTransactionAndLogsQuery(t *testing.T) {
+	backend := newTestBackend(t)
+	defer backend.chain.Stop()
+	h, err := newHandler(backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	creation := backend.chain.GetBlockByNumber(2).Transactions()[0]
+	contract := crypto.CreateAddress(testAddress, creation.Nonce())
+
+	var result struct {
+		Transaction struct {
+			Block   struct{ Number uint64 }
+			Receipt struct {
+				CreatedContract struct {
+					Address common.Address
+					Code    string
+				}
+				Logs []struct {
+					Topics []common.Hash
+					Data   string
+				}
+			}
+		}
+	}
+	query(t, h, fmt.Sprintf(`{ transaction(hash: "%s") { block { number } receipt { createdContract { address code } logs { topics data } } } }`, creation.Hash().Hex()), &result)
+	receipt := result.Transaction.Receipt
+	if result.Transaction.Block.Number != 2 || receipt.CreatedContract.Address != contract {
+		t.Errorf("transaction in block %d created %x, want block 2 and %x", result.Transaction.Block.Number, receipt.CreatedContract.Address, contract)
+	}
+	if len(receipt.Logs) != 1 || receipt.Logs[0].Topics[0] != testTopic {
+		t.Fatalf("logs = %+v, want one with the test topic", receipt.Logs)
+	}
+
+	tests := []struct {
+		filter string
+		logs   int
+	}{
+		{fmt.Sprintf(`{ fromBlock: 0, addresses: ["%s"] }`, contract.Hex()), 1},
+		{fmt.Sprintf(`{ fromBlock: 3, addresses: ["%s"] }`, contract.Hex()), 0},
+		{fmt.Sprintf(`{ fromBlock: 0, topics: [["%s"]] }`, testTopic.Hex()), 1},
+		{fmt.Sprintf(`{ fromBlock: 0, topics: [[], ["%s"]] }`, testTopic.Hex()), 0},
+		{fmt.Sprintf(`{ fromBlock: 0, addresses: ["%s"] }`, testAddress.Hex()), 0},
+	}
+	for _, tt := range tests {
+		var logs struct {
+			Logs []struct {
+				Account struct{ Address common.Address }
+			}
+		}
+		query(t, h, fmt.Sprintf(`{ logs(filter: %s) { account { address } } }`, tt.filter), &logs)
+		if len(logs.Logs) != tt.logs {
+			t.Errorf("logs of %s = %d, want %d", tt.filter, len(logs.Logs), tt.logs)
+		}
+		for _, log := range logs.Logs {
+			if log.Account.Address != contract {
+				t.Errorf("log of %x, want %x", log.Account.Address, contract)
+			}
+		}
+	}
+
+	var missing struct{ Transaction *struct{ Hash common.Hash } }
+	query(t, h, fmt.Sprintf(`{ transaction(hash: "%s") { hash } }`, common.Hash{1}.Hex()), &missing)
+	if missing.Transaction != nil {
+		t.Errorf("unknown transaction = %v, want null", missing.Transaction)
+	}
+
+	var rnodes struct {
+		RNodes []struct {
+			Address   common.Address
+			Rpt       string
+			Committee bool
+		}
+	}
+	query(t, h, `{ rnodes { address rpt committee } }`, &rnodes)
+	if len(rnodes.RNodes) != 2 || !rnodes.RNodes[0].Committee || rnodes.RNodes[1].Committee || rnodes.RNodes[1].Rpt != "0x3c" {
+		t.Errorf("rnodes = %+v", rnodes.RNodes)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	backend := newTestBackend(t)
+	defer backend.chain.Stop()
+	service, err := New(backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(service.HTTPHandlers()[0].Handler)
+	defer server.Close()
+
+	check := func(resp *http.Response, err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var result struct {
+			Data struct {
+				Account struct{ TransactionCount uint64 }
+			}
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatal(err)
+		}
+		if result.Data.Account.TransactionCount != 2 {
+			t.Errorf("transaction count = %d, want 2", result.Data.Account.TransactionCount)
+		}
+	}
+	q := `query($address: Address!) { account(address: $address) { transactionCount } }`
+	variables := fmt.Sprintf(`{"address": "%s"}`, testAddress.Hex())
+	body := fmt.Sprintf(`{"query": %q, "variables": %s}`, q, variables)
+	check(http.Post(server.URL, "application/json", strings.NewReader(body)))
+	check(http.Get(server.URL + "?query=" + url.QueryEscape(q) + "&variables=" + url.QueryEscape(variables)))
+
+	req, _ := http.NewRequest(http.MethodPut, server.URL, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("PUT status = %d, want %d", resp.StatusCode, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestQueryDepth(t *testing.T) {
+	backend := newTestBackend(t)
+	defer backend.chain.Stop()
+	h, err := newHandler(backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// nested returns a query of the depth, blocks at odd and transactions at even depths
+	nested := func(depth int) string {
+		q := "hash"
+		if depth%2 == 0 {
+			q = "number"
+		}
+		for i := depth - 1; i > 1; i-- {
+			if i%2 == 0 {
+				q = "transactions { " + q + " }"
+			} else {
+				q = "block { " + q + " }"
+			}
+		}
+		return "{ block(number: 1) { " + q + " } }"
+	}
+	if response := h.schema.Exec(context.Background(), nested(maxQueryDepth), "", nil); len(response.Errors) > 0 {
+		t.Errorf("query of depth %d failed: %v", maxQueryDepth, response.Errors)
+	}
+	if response := h.schema.Exec(context.Background(), nested(maxQueryDepth+1), "", nil); len(response.Errors) == 0 {
+		t.Errorf("query of depth %d succeeded, want an error", maxQueryDepth+1)
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright 2018 The gcchain authors
+
+package graphql
+
+const schema string = `
+    # Bytes32 is a 32 byte binary string, represented as 0x-prefixed hexadecimal.
+    scalar Bytes32
+    # Address is a 20 byte account address, represented as 0x-prefixed hexadecimal.
+    scalar Address
+    # Bytes is an arbitrary length binary string, represented as 0x-prefixed hexadecimal.
+    # An empty byte string is represented as '0x'.
+    scalar Bytes
+    # BigInt is a large integer. Input is accepted as either a JSON number or as a string.
+    # Strings may be either decimal or 0x-prefixed hexadecimal. Output values are all
+    # 0x-prefixed hexadecimal.
+    scalar BigInt
+    # Long is a 64 bit unsigned integer. Input is accepted as either a JSON number or as a
+    # string, output values are JSON numbers.
+    scalar Long
+
+    schema {
+        query: Query
+    }
+
+    # Account is an account at a particular block.
+    type Account {
+        # Address is the address owning the account.
+        address: Address!
+        # Balance is the balance of the account, in wei.
+        balance: BigInt!
+        # TransactionCount is the number of transactions sent from this account,
+        # or in the case of a contract, the number of contracts created.
+        transactionCount: Long!
+        # Code contains the smart contract code for this account, if the account
+        # is a (non-self-destructed) contract.
+        code: Bytes!
+        # Storage provides access to the storage of a contract account, indexed
+        # by its 32 byte slot identifier.
+        storage(slot: Bytes32!): Bytes32!
+    }
+
+    # Log is a log entry emitted by a contract event.
+    type Log {
+        # Index is the index of this log in the block.
+        index: Int!
+        # Account is the account which generated this log, it is resolved at
+        # the block containing the log.
+        account: Account!
+        # Topics is a list of 0-4 indexed topics for the log.
+        topics: [Bytes32!]!
+        # Data is unindexed data for this log.
+        data: Bytes!
+        # Transaction is the transaction that generated this log entry.
+        transaction: Transaction!
+    }
+
+    # Receipt is the outcome of a transaction included in a block.
+    type Receipt {
+        # Status is the return status of the transaction, 1 if it succeeded and
+        # 0 if it failed.
+        status: Long!
+        # GasUsed is the amount of gas used by the transaction.
+        gasUsed: Long!
+        # CumulativeGasUsed is the total gas used in the block up to and
+        # including this transaction.
+        cumulativeGasUsed: Long!
+        # CreatedContract is the account created by the transaction, if it was
+        # a contract creation.
+        createdContract: Account
+        # Logs is the list of logs emitted by the transaction.
+        logs: [Log!]!
+        # LogsBloom is the bloom filter of the logs.
+        logsBloom: Bytes!
+    }
+
+    # Transaction is a transaction, included in a block or pending.
+    type Transaction {
+        # Hash is the hash of this transaction.
+        hash: Bytes32!
+        # Nonce is the nonce of the account this transaction was generated with.
+        nonce: Long!
+        # Index is the index of this transaction in the parent block. This will
+        # be null if the transaction has not yet been mined.
+        index: Int
+        # From is the account that sent this transaction, resolved at the block
+        # containing it or the latest block if it is pending.
+        from: Account!
+        # To is the account the transaction was sent to. This is null for
+        # contract-creating transactions.
+        to: Account
+        # Value is the value, in wei, sent along with this transaction.
+        value: BigInt!
+        # GasPrice is the price offered to miners for gas, in wei per unit.
+        gasPrice: BigInt!
+        # Gas is the maximum amount of gas this transaction can consume.
+        gas: Long!
+        # InputData is the data supplied to the target of the transaction.
+        inputData: Bytes!
+        # Private is true if the payload of the transaction is private.
+        private: Boolean!
+        # Block is the block this transaction was mined in. This will be null if
+        # the transaction has not yet been mined.
+        block: Block
+        # Receipt is the receipt of the transaction, the private receipt of a
+        # private transaction this node participates in. This will be null if the
+        # transaction has not yet been mined.
+        receipt: Receipt
+    }
+
+    # RNode is a reputation node, a candidate for the committee of proposers.
+    type RNode {
+        # Address is the address of the node.
+        address: Address!
+        # Rpt is the reputation of the node at the latest block.
+        rpt: BigInt!
+        # Committee is true if the node is in the current committee.
+        committee: Boolean!
+    }
+
+    # Dpos holds the DPoS consensus data of a block.
+    type Dpos {
+        # Term is the term the block was proposed in.
+        term: Long!
+        # View is the view of the term the block was proposed in.
+        view: Long!
+        # Proposer is the proposer scheduled for the block.
+        proposer: Address!
+        # Proposers is the committee of proposers of the term.
+        proposers: [Address!]!
+        # Validators is the list of validators of the block.
+        validators: [Address!]!
+        # Impeachment is true if the block was produced by the validators
+        # impeaching a faulty proposer.
+        impeachment: Boolean!
+        # Reward is the block reward of the proposer, in wei.
+        reward: BigInt!
+    }
+
+    # Block is a block of the chain.
+    type Block {
+        # Number is the number of this block, starting at 0 for the genesis block.
+        number: Long!
+        # Hash is the block hash of this block.
+        hash: Bytes32!
+        # Parent is the parent block of this block. This will be null for the
+        # genesis block.
+        parent: Block
+        # Miner is the account that proposed this block, resolved at this block.
+        miner: Account!
+        # StateRoot is the hash of the state trie after this block was processed.
+        stateRoot: Bytes32!
+        # TransactionsRoot is the hash of the root of the trie of transactions in this block.
+        transactionsRoot: Bytes32!
+        # ReceiptsRoot is the hash of the trie of transaction receipts in this block.
+        receiptsRoot: Bytes32!
+        # LogsBloom is a bloom filter that can be used to check if a block may
+        # contain log entries matching a filter.
+        logsBloom: Bytes!
+        # GasLimit is the maximum amount of gas that was available to transactions in this block.
+        gasLimit: Long!
+        # GasUsed is the amount of gas that was used executing transactions in this block.
+        gasUsed: Long!
+        # Timestamp is the time this block was proposed, in milliseconds since the unix epoch.
+        timestamp: Long!
+        # ExtraData is an arbitrary data field supplied by the proposer.
+        extraData: Bytes!
+        # TransactionCount is the number of transactions in this block.
+        transactionCount: Int!
+        # Transactions is a list of transactions associated with this block.
+        transactions: [Transaction!]!
+        # TransactionAt returns the transaction at the specified index. This will
+        # be null if the index is out of range.
+        transactionAt(index: Int!): Transaction
+        # Logs returns the logs of this block matching the filter.
+        logs(filter: BlockFilterCriteria!): [Log!]!
+        # Account fetches a contract or user account at this block.
+        account(address: Address!): Account!
+        # Dpos is the DPoS consensus data of this block.
+        dpos: Dpos!
+    }
+
+    # BlockFilterCriteria encapsulates log filter criteria for searching a single block.
+    input BlockFilterCriteria {
+        # Addresses is list of addresses that are of interest. If this list is
+        # empty, results will not be filtered by address.
+        addresses: [Address!]
+        # Topics list restricts matches to particular event topics. Each event has a list
+        # of topics. Topics matches a prefix of that list. An empty element array matches any
+        # topic. Non-empty elements represent an alternative that matches any of the
+        # contained topics.
+        topics: [[Bytes32!]!]
+    }
+
+    # FilterCriteria encapsulates log filter criteria for a range of blocks.
+    input FilterCriteria {
+        # FromBlock is the first block to search, the latest block if null.
+        fromBlock: Long
+        # ToBlock is the last block to search, the latest block if null.
+        toBlock: Long
+        # Addresses is a list of addresses that are of interest.
+        addresses: [Address!]
+        # Topics list restricts matches to particular event topics, as in BlockFilterCriteria.
+        topics: [[Bytes32!]!]
+    }
+
+    type Query {
+        # Block fetches a block by number or by hash. If neither is supplied, the
+        # most recent known block is returned.
+        block(number: Long, hash: Bytes32): Block
+        # Blocks returns the blocks from one block number to another, inclusive.
+        # If to is not supplied, it defaults to the most recent known block.
+        blocks(from: Long!, to: Long): [Block!]!
+        # Transaction returns a transaction specified by its hash, included or pending.
+        transaction(hash: Bytes32!): Transaction
+        # Account fetches an account at a block number, the most recent known
+        # block if it is not supplied.
+        account(address: Address!, block: Long): Account!
+        # Logs returns the logs matching the filter.
+        logs(filter: FilterCriteria!): [Log!]!
+        # RNodes returns the reputation nodes at the most recent known block.
+        rnodes: [RNode!]!
+    }
+`
@@ -0,0 +1,113 @@
+// Copyright 2018 The gcchain authors
+
+package graphql
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gcchains/chain/api/rpc"
+	"github.com/gcchains/chain/internal/gccapi"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/graph-gophers/graphql-go"
+)
+
+const (
+	// Path is the path of the GraphQL handler on the HTTP RPC endpoint.
+	Path = "/graphql"
+
+	// Namespace is the namespace the GraphQL clients are authorized and rate limited by, queries
+	// are calls of its "query" method.
+	Namespace = "graphql"
+
+	// maxRequestContentLength is the size of a query request at most.
+	maxRequestContentLength = 1024 * 512
+
+	// maxQueryDepth is the field nesting depth of a query at most, the range limits alone do not
+	// bound queries nesting blocks and transactions in each other.
+	maxQueryDepth = 8
+)
+
+// Service serves GraphQL queries on the HTTP RPC endpoint of the node.
+type Service struct {
+	handler http.Handler
+}
+
+// New creates a GraphQL service resolving the queries with the backend.
+func New(backend gccapi.Backend) (*Service, error) {
+	handler, err := newHandler(backend)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{handler: handler}, nil
+}
+
+// Protocols implements node.Service, returning the P2P network protocols used by the service.
+func (s *Service) Protocols() []p2p.Protocol { return nil }
+
+// APIs implements node.Service, returning the RPC API endpoints provided by the service.
+func (s *Service) APIs() []rpc.API { return nil }
+
+// Start implements node.Service, the handler is served by the HTTP RPC endpoint.
+func (s *Service) Start(server *p2p.Server) error { return nil }
+
+// Stop implements node.Service.
+func (s *Service) Stop() error { return nil }
+
+// HTTPHandlers implements node.HTTPService, returning the GraphQL handler.
+func (s *Service) HTTPHandlers() []rpc.HTTPHandler {
+	return []rpc.HTTPHandler{{Namespace: Namespace, Method: "query", Path: Path, Handler: s.handler}}
+}
+
+// handler executes the GraphQL queries of POST requests, or of the query parameter of GET requests.
+type handler struct {
+	schema *graphql.Schema
+}
+
+// newHandler parses the schema and creates a handler resolving it with the backend.
+func newHandler(backend gccapi.Backend) (*handler, error) {
+	parsed, err := graphql.ParseSchema(schema, &Resolver{backend: backend}, graphql.MaxDepth(maxQueryDepth))
+	if err != nil {
+		return nil, err
+	}
+	return &handler{schema: parsed}, nil
+}
+
+// request is a GraphQL query request.
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req request
+	switch r.Method {
+	case http.MethodGet:
+		req.Query = r.URL.Query().Get("query")
+		req.OperationName = r.URL.Query().Get("operationName")
+		if variables := r.URL.Query().Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+	case http.MethodPost:
+		if err := json.NewDecoder(io.LimitReader(r.Body, maxRequestContentLength)).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	response := h.schema.Exec(r.Context(), req.Query, req.OperationName, req.Variables)
+	data, err := json.Marshal(response)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
@@ -0,0 +1,138 @@
+// Copyright 2018 The gcchain authors
+
+package graphql
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Bytes32 is the Bytes32 scalar.
+type Bytes32 common.Hash
+
+func (Bytes32) ImplementsGraphQLType(name string) bool { return name == "Bytes32" }
+
+func (b *Bytes32) UnmarshalGraphQL(input interface{}) error {
+	s, ok := input.(string)
+	if !ok {
+		return fmt.Errorf("unexpected type %T for Bytes32", input)
+	}
+	return (*common.Hash)(b).UnmarshalText([]byte(s))
+}
+
+func (b Bytes32) MarshalText() ([]byte, error) { return common.Hash(b).MarshalText() }
+
+// Address is the Address scalar.
+type Address common.Address
+
+func (Address) ImplementsGraphQLType(name string) bool { return name == "Address" }
+
+func (a *Address) UnmarshalGraphQL(input interface{}) error {
+	s, ok := input.(string)
+	if !ok {
+		return fmt.Errorf("unexpected type %T for Address", input)
+	}
+	return (*common.Address)(a).UnmarshalText([]byte(s))
+}
+
+func (a Address) MarshalText() ([]byte, error) { return common.Address(a).MarshalText() }
+
+// Bytes is the Bytes scalar.
+type Bytes []byte
+
+func (Bytes) ImplementsGraphQLType(name string) bool { return name == "Bytes" }
+
+func (b *Bytes) UnmarshalGraphQL(input interface{}) error {
+	s, ok := input.(string)
+	if !ok {
+		return fmt.Errorf("unexpected type %T for Bytes", input)
+	}
+	return (*hexutil.Bytes)(b).UnmarshalText([]byte(s))
+}
+
+func (b Bytes) MarshalText() ([]byte, error) { return hexutil.Bytes(b).MarshalText() }
+
+// BigInt is the BigInt scalar.
+type BigInt big.Int
+
+func (BigInt) ImplementsGraphQLType(name string) bool { return name == "BigInt" }
+
+func (b *BigInt) UnmarshalGraphQL(input interface{}) error {
+	switch input := input.(type) {
+	case string:
+		value, ok := new(big.Int), false
+		if strings.HasPrefix(input, "0x") {
+			decoded, err := hexutil.DecodeBig(input)
+			value, ok = decoded, err == nil
+		} else {
+			value, ok = value.SetString(input, 10)
+		}
+		if !ok {
+			return fmt.Errorf("invalid BigInt %q", input)
+		}
+		*b = BigInt(*value)
+	case int32:
+		*b = BigInt(*big.NewInt(int64(input)))
+	case float64:
+		value, accuracy := big.NewFloat(input).Int(nil)
+		if accuracy != big.Exact {
+			return fmt.Errorf("invalid BigInt %v", input)
+		}
+		*b = BigInt(*value)
+	default:
+		return fmt.Errorf("unexpected type %T for BigInt", input)
+	}
+	return nil
+}
+
+func (b BigInt) MarshalText() ([]byte, error) { return (*hexutil.Big)(&b).MarshalText() }
+
+// Long is the Long scalar.
+type Long uint64
+
+func (Long) ImplementsGraphQLType(name string) bool { return name == "Long" }
+
+func (l *Long) UnmarshalGraphQL(input interface{}) error {
+	switch input := input.(type) {
+	case string:
+		var (
+			value uint64
+			err   error
+		)
+		if strings.HasPrefix(input, "0x") {
+			value, err = hexutil.DecodeUint64(input)
+		} else {
+			value, err = strconv.ParseUint(input, 10, 64)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid Long %q", input)
+		}
+		*l = Long(value)
+	case int32:
+		if input < 0 {
+			return fmt.Errorf("negative Long %d", input)
+		}
+		*l = Long(input)
+	case float64:
+		if input < 0 || input != float64(uint64(input)) {
+			return fmt.Errorf("invalid Long %v", input)
+		}
+		*l = Long(input)
+	default:
+		return fmt.Errorf("unexpected type %T for Long", input)
+	}
+	return nil
+}
+
+// newBigInt returns the BigInt of value, which is zero if value is nil.
+func newBigInt(value *big.Int) BigInt {
+	if value == nil {
+		return BigInt{}
+	}
+	return BigInt(*value)
+}
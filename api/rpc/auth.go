@@ -0,0 +1,335 @@
+// Copyright 2018 The gcchain authors
+
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+const (
+	// authSchemeBearer carries a JWT signed with HS256 by the shared secret.
+	authSchemeBearer = "Bearer"
+	// authSchemeHMAC carries a unix timestamp, a random nonce and the HMAC-SHA256 by the shared secret of
+	// them and the hash of the request body, as "<time>:<nonce>:<hex mac>".
+	authSchemeHMAC = "HMAC"
+
+	// hmacSubject is the subject of clients authenticated with the HMAC scheme.
+	hmacSubject = "hmac"
+
+	// authClockSkew is how far the issue time of HMAC credentials may be from the server time, it is
+	// also the lifetime of the JWTs signed by NewJWTAuth.
+	authClockSkew = 60 * time.Second
+
+	// hmacNonceSize is the number of random bytes of the nonce of HMAC credentials.
+	hmacNonceSize = 16
+
+	// wildcard allows all namespaces or methods in a policy.
+	wildcard = "*"
+)
+
+var (
+	errAuthScheme      = errors.New("unsupported authorization scheme")
+	errAuthStale       = errors.New("stale authorization token")
+	errAuthHMACInvalid = errors.New("invalid hmac authorization")
+	errAuthNoExpiry    = errors.New("authorization token without expiry")
+	errAuthReplayed    = errors.New("replayed authorization")
+)
+
+// AuthPolicy lists the namespaces and methods (e.g. "personal_unlockAccount") a role may call.
+type AuthPolicy struct {
+	Namespaces []string `toml:",omitempty"`
+	Methods    []string `toml:",omitempty"`
+}
+
+// allows returns whether the policy allows the method.
+func (p AuthPolicy) allows(namespace, method string) bool {
+	for _, ns := range p.Namespaces {
+		if ns == wildcard || ns == namespace {
+			return true
+		}
+	}
+	name := namespace + serviceMethodSeparator + method
+	for _, m := range p.Methods {
+		if m == wildcard || m == name {
+			return true
+		}
+	}
+	return false
+}
+
+// AuthConfig configures the authentication of the HTTP and websocket RPC interfaces.
+//
+// Clients authenticate with a JWT signed with HS256 by the shared secret in SecretFile, which must
+// expire, or with an HMAC of the current time, a nonce used once and the request body by the secret.
+// The "role" claim of a JWT selects its policy in Policies,
+// tokens without a role and HMAC clients are allowed to call everything as they hold the secret.
+// Namespaces in Public are callable without credentials.
+type AuthConfig struct {
+	SecretFile string
+	Public     []string              `toml:",omitempty"`
+	Policies   map[string]AuthPolicy `toml:",omitempty"`
+}
+
+// AuthClaims are the claims of a JWT accepted by the RPC interfaces.
+type AuthClaims struct {
+	Role string `json:"role,omitempty"`
+	jwt.StandardClaims
+}
+
+// Authenticator verifies the credentials of clients and authorizes their calls.
+type Authenticator struct {
+	secret   []byte
+	public   AuthPolicy
+	policies map[string]AuthPolicy
+
+	nonces       map[string]time.Time // nonces of accepted HMAC credentials and when they go stale
+	noncesPruned time.Time
+	nonceLock    sync.Mutex
+}
+
+// ReadAuthSecret reads a shared secret from a file, which holds it hex encoded or verbatim.
+func ReadAuthSecret(path string) ([]byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	text := strings.TrimSpace(string(data))
+	if secret, err := hex.DecodeString(strings.TrimPrefix(text, "0x")); err == nil && len(secret) > 0 {
+		return secret, nil
+	}
+	if len(text) == 0 {
+		return nil, fmt.Errorf("empty secret in %s", path)
+	}
+	return []byte(text), nil
+}
+
+// NewAuthenticator creates an authenticator with the secret and policies of config.
+func NewAuthenticator(config AuthConfig) (*Authenticator, error) {
+	secret, err := ReadAuthSecret(config.SecretFile)
+	if err != nil {
+		return nil, err
+	}
+	return &Authenticator{
+		secret:       secret,
+		public:       AuthPolicy{Namespaces: config.Public},
+		policies:     config.Policies,
+		nonces:       make(map[string]time.Time),
+		noncesPruned: time.Now(),
+	}, nil
+}
+
+// authenticate verifies the credentials of the request, requests without credentials are anonymous.
+func (a *Authenticator) authenticate(r *http.Request) (*ClientInfo, error) {
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return &ClientInfo{}, nil
+	}
+	parts := strings.SplitN(header, " ", 2)
+	if len(parts) != 2 {
+		return nil, errAuthScheme
+	}
+	credentials := strings.TrimSpace(parts[1])
+	switch {
+	case strings.EqualFold(parts[0], authSchemeBearer):
+		claims, err := a.verifyToken(credentials)
+		if err != nil {
+			return nil, err
+		}
+		return &ClientInfo{Subject: claims.Subject, Role: claims.Role, Authenticated: true}, nil
+	case strings.EqualFold(parts[0], authSchemeHMAC):
+		body, err := readBody(r)
+		if err != nil {
+			return nil, err
+		}
+		if err := a.verifyHMAC(credentials, body); err != nil {
+			return nil, err
+		}
+		return &ClientInfo{Subject: hmacSubject, Authenticated: true}, nil
+	}
+	return nil, errAuthScheme
+}
+
+// verifyToken verifies the JWT, which is valid until its expiry. Tokens without expiry are rejected
+// as they could be replayed forever.
+func (a *Authenticator) verifyToken(token string) (*AuthClaims, error) {
+	claims := new(AuthClaims)
+	parser := &jwt.Parser{ValidMethods: []string{jwt.SigningMethodHS256.Alg()}, SkipClaimsValidation: true}
+	if _, err := parser.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) { return a.secret, nil }); err != nil {
+		return nil, err
+	}
+	if claims.ExpiresAt == 0 {
+		return nil, errAuthNoExpiry
+	}
+	now := time.Now()
+	if now.After(time.Unix(claims.ExpiresAt, 0)) {
+		return nil, errAuthStale
+	}
+	if claims.NotBefore != 0 && now.Add(authClockSkew).Before(time.Unix(claims.NotBefore, 0)) {
+		return nil, errAuthStale
+	}
+	return claims, nil
+}
+
+// verifyHMAC verifies the "<time>:<nonce>:<hex mac>" credentials of the body, each nonce is accepted
+// once while the credentials are fresh.
+func (a *Authenticator) verifyHMAC(credentials string, body []byte) error {
+	parts := strings.SplitN(credentials, ":", 3)
+	if len(parts) != 3 || parts[1] == "" {
+		return errAuthHMACInvalid
+	}
+	issued, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return errAuthHMACInvalid
+	}
+	mac, err := hex.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(mac, hmacSign(a.secret, hmacMessage(parts[0], parts[1], body))) {
+		return errAuthHMACInvalid
+	}
+	now := time.Now()
+	if !fresh(time.Unix(issued, 0), now) {
+		return errAuthStale
+	}
+	return a.useNonce(parts[1], time.Unix(issued, 0).Add(authClockSkew), now)
+}
+
+// useNonce records the nonce until it goes stale, it fails if the nonce is recorded already.
+func (a *Authenticator) useNonce(nonce string, stale, now time.Time) error {
+	a.nonceLock.Lock()
+	defer a.nonceLock.Unlock()
+
+	if now.Sub(a.noncesPruned) >= authClockSkew {
+		for n, expiry := range a.nonces {
+			if now.After(expiry) {
+				delete(a.nonces, n)
+			}
+		}
+		a.noncesPruned = now
+	}
+	if _, ok := a.nonces[nonce]; ok {
+		return errAuthReplayed
+	}
+	a.nonces[nonce] = stale
+	return nil
+}
+
+// readBody reads the body of the request and puts it back for the handlers.
+func readBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil {
+		return nil, nil
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxRequestContentLength))
+	if err != nil {
+		return nil, err
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// allowed returns whether the client of the request may call the method.
+func (a *Authenticator) allowed(ctx context.Context, namespace, method string) bool {
+	info, ok := ClientInfoFromContext(ctx)
+	if !ok {
+		// in-process and IPC clients are trusted
+		return true
+	}
+	if a.public.allows(namespace, method) {
+		return true
+	}
+	if !info.Authenticated {
+		return false
+	}
+	if info.Role == "" {
+		return true
+	}
+	policy, ok := a.policies[info.Role]
+	return ok && policy.allows(namespace, method)
+}
+
+// unauthorizedError is returned if a client is not allowed to call a method.
+type unauthorizedError struct{ method string }
+
+func (e *unauthorizedError) ErrorCode() int { return -32001 }
+
+func (e *unauthorizedError) Error() string { return "unauthorized to call " + e.method }
+
+// fresh returns whether the issue time is within the clock skew of now.
+func fresh(issued, now time.Time) bool {
+	return issued.After(now.Add(-authClockSkew)) && issued.Before(now.Add(authClockSkew))
+}
+
+// hmacMessage returns the message authenticated by HMAC credentials.
+func hmacMessage(issued, nonce string, body []byte) string {
+	hash := sha256.Sum256(body)
+	return issued + ":" + nonce + ":" + hex.EncodeToString(hash[:])
+}
+
+func hmacSign(secret []byte, message string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(message))
+	return mac.Sum(nil)
+}
+
+// AuthProvider sets the credentials of an outgoing request with the body, which is nil for
+// websocket handshakes.
+type AuthProvider func(header http.Header, body []byte) error
+
+// NewJWTAuth returns credentials signing a fresh JWT with the shared secret for every request, role
+// selects the policy of the server and may be empty to call everything.
+func NewJWTAuth(secret []byte, subject, role string) AuthProvider {
+	return func(header http.Header, body []byte) error {
+		now := time.Now()
+		claims := &AuthClaims{Role: role, StandardClaims: jwt.StandardClaims{Subject: subject, IssuedAt: now.Unix(), ExpiresAt: now.Add(authClockSkew).Unix()}}
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+		if err != nil {
+			return err
+		}
+		header.Set("Authorization", authSchemeBearer+" "+token)
+		return nil
+	}
+}
+
+// NewTokenAuth returns credentials sending a JWT issued by the node operator.
+func NewTokenAuth(token string) AuthProvider {
+	return func(header http.Header, body []byte) error {
+		header.Set("Authorization", authSchemeBearer+" "+token)
+		return nil
+	}
+}
+
+// NewHMACAuth returns credentials signing the current time, a random nonce and the body with the
+// shared secret for every request.
+func NewHMACAuth(secret []byte) AuthProvider {
+	return func(header http.Header, body []byte) error {
+		var nonce [hmacNonceSize]byte
+		if _, err := rand.Read(nonce[:]); err != nil {
+			return err
+		}
+		now, n := strconv.FormatInt(time.Now().Unix(), 10), hex.EncodeToString(nonce[:])
+		mac := hmacSign(secret, hmacMessage(now, n, body))
+		header.Set("Authorization", authSchemeHMAC+" "+now+":"+n+":"+hex.EncodeToString(mac))
+		return nil
+	}
+}
+
+// IssueToken issues a JWT for the role valid for the duration, for clients not holding the secret.
+func IssueToken(secret []byte, subject, role string, valid time.Duration) (string, error) {
+	now := time.Now()
+	claims := &AuthClaims{Role: role, StandardClaims: jwt.StandardClaims{Subject: subject, IssuedAt: now.Unix(), ExpiresAt: now.Add(valid).Unix()}}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+}
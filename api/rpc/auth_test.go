@@ -0,0 +1,270 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+var testAuthSecret = []byte("secret shared by the node and its clients")
+
+// newTestAuthenticator creates an authenticator with the test secret, allowing anonymous clients
+// to call test_rets and the "reader" role to call test_echo.
+func newTestAuthenticator(t *testing.T) *Authenticator {
+	dir, err := ioutil.TempDir("", "rpc-auth-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "secret")
+	if err := ioutil.WriteFile(path, testAuthSecret, 0600); err != nil {
+		t.Fatal(err)
+	}
+	auth, err := NewAuthenticator(AuthConfig{
+		SecretFile: path,
+		Public:     []string{"web3"},
+		Policies: map[string]AuthPolicy{
+			"reader": {Methods: []string{"test_echo"}},
+			"admin":  {Namespaces: []string{"*"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return auth
+}
+
+func TestAuthenticatorCredentials(t *testing.T) {
+	auth := newTestAuthenticator(t)
+	expired, _ := IssueToken(testAuthSecret, "client", "", -time.Minute)
+	stale, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, &AuthClaims{StandardClaims: jwt.StandardClaims{IssuedAt: time.Now().Add(-time.Hour).Unix()}}).SignedString(testAuthSecret)
+	noExpiry, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, &AuthClaims{StandardClaims: jwt.StandardClaims{IssuedAt: time.Now().Unix()}}).SignedString(testAuthSecret)
+	forged, _ := IssueToken([]byte("other secret"), "client", "", time.Minute)
+	unsigned, _ := jwt.NewWithClaims(jwt.SigningMethodNone, &AuthClaims{}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+
+	tests := []struct {
+		provider AuthProvider
+		header   string
+		subject  string
+		role     string
+		ok       bool
+	}{
+		{provider: NewJWTAuth(testAuthSecret, "client", "reader"), subject: "client", role: "reader", ok: true},
+		{provider: NewHMACAuth(testAuthSecret), subject: hmacSubject, ok: true},
+		{provider: NewTokenAuth(mustIssueToken(t, "client", "admin")), subject: "client", role: "admin", ok: true},
+		{header: "", ok: true},
+		{provider: NewJWTAuth([]byte("other secret"), "client", ""), ok: false},
+		{provider: NewHMACAuth([]byte("other secret")), ok: false},
+		{provider: NewTokenAuth(expired), ok: false},
+		{provider: NewTokenAuth(stale), ok: false},
+		{provider: NewTokenAuth(noExpiry), ok: false},
+		{provider: NewTokenAuth(forged), ok: false},
+		{provider: NewTokenAuth(unsigned), ok: false},
+		{header: "HMAC 1:00", ok: false},
+		{header: "HMAC 1::00", ok: false},
+		{header: "Basic dXNlcjpwYXNz", ok: false},
+	}
+	for i, tt := range tests {
+		r := httptest.NewRequest(http.MethodPost, "/", nil)
+		if tt.provider != nil {
+			if err := tt.provider(r.Header, nil); err != nil {
+				t.Fatalf("test %d: provider error = %v", i, err)
+			}
+		} else if tt.header != "" {
+			r.Header.Set("Authorization", tt.header)
+		}
+		info, err := auth.authenticate(r)
+		if (err == nil) != tt.ok {
+			t.Errorf("test %d: authenticate() error = %v, want ok %v", i, err, tt.ok)
+			continue
+		}
+		if err == nil && (info.Subject != tt.subject || info.Role != tt.role) {
+			t.Errorf("test %d: authenticated as %q/%q, want %q/%q", i, info.Subject, info.Role, tt.subject, tt.role)
+		}
+	}
+}
+
+func TestAuthenticatorHMACReplay(t *testing.T) {
+	auth := newTestAuthenticator(t)
+	body := []byte(`{"jsonrpc":"2.0","id":1,"method":"test_echo","params":[]}`)
+
+	header := make(http.Header)
+	if err := NewHMACAuth(testAuthSecret)(header, body); err != nil {
+		t.Fatal(err)
+	}
+	request := func(body []byte) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+		r.Header.Set("Authorization", header.Get("Authorization"))
+		return r
+	}
+
+	// credentials of another body are invalid
+	if _, err := auth.authenticate(request([]byte(`{}`))); err != errAuthHMACInvalid {
+		t.Errorf("authenticate() of other body error = %v, want %v", err, errAuthHMACInvalid)
+	}
+	r := request(body)
+	if _, err := auth.authenticate(r); err != nil {
+		t.Fatalf("authenticate() error = %v", err)
+	}
+	if read, _ := ioutil.ReadAll(r.Body); !bytes.Equal(read, body) {
+		t.Errorf("body after authentication = %s, want %s", read, body)
+	}
+	if _, err := auth.authenticate(request(body)); err != errAuthReplayed {
+		t.Errorf("authenticate() of replayed credentials error = %v, want %v", err, errAuthReplayed)
+	}
+}
+
+func mustIssueToken(t *testing.T, subject, role string) string {
+	token, err := IssueToken(testAuthSecret, subject, role, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestAuthenticatorPolicies(t *testing.T) {
+	auth := newTestAuthenticator(t)
+	client := func(role string, authenticated bool) context.Context {
+		return context.WithValue(context.Background(), clientInfoKey{}, &ClientInfo{Role: role, Authenticated: authenticated})
+	}
+	tests := []struct {
+		ctx       context.Context
+		namespace string
+		method    string
+		want      bool
+	}{
+		{client("", false), "web3", "clientVersion", true},
+		{client("", false), "test", "echo", false},
+		{client("", true), "test", "echo", true},
+		{client("reader", true), "test", "echo", true},
+		{client("reader", true), "test", "rets", false},
+		{client("admin", true), "personal", "unlockAccount", true},
+		{client("unknown", true), "test", "echo", false},
+		// local clients are always allowed
+		{context.Background(), "personal", "unlockAccount", true},
+	}
+	for i, tt := range tests {
+		if got := auth.allowed(tt.ctx, tt.namespace, tt.method); got != tt.want {
+			t.Errorf("test %d: allowed(%s_%s) = %v, want %v", i, tt.namespace, tt.method, got, tt.want)
+		}
+	}
+}
+
+func TestHTTPAuth(t *testing.T) {
+	server := NewServer()
+	server.SetAuthenticator(newTestAuthenticator(t))
+	if err := server.RegisterName("test", new(Service)); err != nil {
+		t.Fatal(err)
+	}
+	hs := httptest.NewServer(server)
+	defer hs.Close()
+
+	var result Result
+	reader, err := DialHTTPWithAuth(hs.URL, NewJWTAuth(testAuthSecret, "client", "reader"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	if err := reader.Call(&result, "test_echo", "hello", 10, &Args{"world"}); err != nil {
+		t.Errorf("allowed call error = %v", err)
+	}
+	err = reader.Call(nil, "test_rets")
+	if rpcErr, ok := err.(Error); !ok || rpcErr.ErrorCode() != -32001 {
+		t.Errorf("call outside policy error = %v, want unauthorized error", err)
+	}
+
+	anonymous, err := DialHTTP(hs.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer anonymous.Close()
+	if err := anonymous.Call(&result, "test_echo", "hello", 10, &Args{"world"}); err == nil {
+		t.Error("anonymous call succeeded")
+	}
+
+	forged, err := DialHTTPWithAuth(hs.URL, NewHMACAuth([]byte("other secret")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer forged.Close()
+	err = forged.Call(&result, "test_echo", "hello", 10, &Args{"world"})
+	if err == nil || !strings.HasPrefix(err.Error(), "401") {
+		t.Errorf("call with invalid credentials error = %v, want 401", err)
+	}
+}
+
+func TestHTTPHandlerAuth(t *testing.T) {
+	server := NewServer()
+	server.SetAuthenticator(newTestAuthenticator(t))
+	handler := server.serveHandler(HTTPHandler{
+		Namespace: "test",
+		Method:    "echo",
+		Path:      "/echo",
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			info, _ := ClientInfoFromContext(r.Context())
+			w.Write([]byte(info.Subject))
+		}),
+	})
+	tests := []struct {
+		provider AuthProvider
+		code     int
+	}{
+		{provider: NewJWTAuth(testAuthSecret, "client", "reader"), code: http.StatusOK},
+		{provider: NewJWTAuth(testAuthSecret, "client", "other"), code: http.StatusForbidden},
+		{provider: NewHMACAuth([]byte("other secret")), code: http.StatusUnauthorized},
+		{code: http.StatusForbidden},
+	}
+	for i, tt := range tests {
+		r := httptest.NewRequest(http.MethodPost, "/echo", nil)
+		if tt.provider != nil {
+			if err := tt.provider(r.Header, nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != tt.code {
+			t.Errorf("test %d: status = %d, want %d", i, w.Code, tt.code)
+		}
+		if tt.code == http.StatusOK && w.Body.String() != "client" {
+			t.Errorf("test %d: handler saw subject %q, want client", i, w.Body.String())
+		}
+	}
+}
+
+func TestWebsocketAuth(t *testing.T) {
+	server := NewServer()
+	server.SetAuthenticator(newTestAuthenticator(t))
+	if err := server.RegisterName("test", new(Service)); err != nil {
+		t.Fatal(err)
+	}
+	hs := httptest.NewServer(server.WebsocketHandler([]string{"*"}))
+	defer hs.Close()
+	endpoint := "ws" + strings.TrimPrefix(hs.URL, "http")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := DialWebsocketWithAuth(ctx, endpoint, "", NewHMACAuth(testAuthSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	if err := client.CallContext(ctx, nil, "test_rets"); err != nil {
+		t.Errorf("authenticated call error = %v", err)
+	}
+
+	if _, err := DialWebsocketWithAuth(ctx, endpoint, "", NewTokenAuth("invalid")); err == nil {
+		t.Error("dial with invalid credentials succeeded")
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"net/http"
 	"net/url"
 	"os"
 	"reflect"
@@ -149,15 +150,21 @@ func Dial(rawurl string) (*Client, error) {
 // The context is used to cancel or time out the initial connection establishment. It does
 // not affect subsequent interactions with the client.
 func DialContext(ctx context.Context, rawurl string) (*Client, error) {
+	return DialContextWithAuth(ctx, rawurl, nil)
+}
+
+// DialContextWithAuth creates a new RPC client like DialContext, sending the credentials of
+// auth over HTTP and websocket. IPC and stdio clients need no credentials.
+func DialContextWithAuth(ctx context.Context, rawurl string, auth AuthProvider) (*Client, error) {
 	u, err := url.Parse(rawurl)
 	if err != nil {
 		return nil, err
 	}
 	switch u.Scheme {
 	case "http", "https":
-		return DialHTTP(rawurl)
+		return dialHTTP(rawurl, new(http.Client), auth)
 	case "ws", "wss":
-		return DialWebsocket(ctx, rawurl, "")
+		return DialWebsocketWithAuth(ctx, rawurl, "", auth)
 	case "stdio":
 		return DialStdIO(ctx)
 	case "":
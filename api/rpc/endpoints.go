@@ -4,12 +4,15 @@ package rpc
 
 import (
 	"net"
+	"net/http"
 
 	"github.com/gcchains/chain/commons/log"
 )
 
-// StartHTTPEndpoint starts the HTTP RPC endpoint, configured with cors/vhosts/modules
-func StartHTTPEndpoint(endpoint string, apis []API, modules []string, cors []string, vhosts []string) (net.Listener, *Server, error) {
+// StartHTTPEndpoint starts the HTTP RPC endpoint, configured with cors/vhosts/modules, requests are
+// rate limited by limiter and authorized by auth if they are not nil. The handlers are served under
+// their paths, all other paths serve the JSON-RPC APIs.
+func StartHTTPEndpoint(endpoint string, apis []API, handlers []HTTPHandler, modules []string, cors []string, vhosts []string, limiter *Limiter, auth *Authenticator) (net.Listener, *Server, error) {
 	// Generate the whitelist based on the allowed modules
 	whitelist := make(map[string]bool)
 	for _, module := range modules {
@@ -17,6 +20,8 @@ func StartHTTPEndpoint(endpoint string, apis []API, modules []string, cors []str
 	}
 	// Register all the APIs exposed by the services
 	handler := NewServer()
+	handler.SetLimiter(limiter)
+	handler.SetAuthenticator(auth)
 	for _, api := range apis {
 		if whitelist[api.Namespace] || (len(whitelist) == 0 && api.Public) {
 			if err := handler.RegisterName(api.Namespace, api.Service); err != nil {
@@ -33,12 +38,23 @@ func StartHTTPEndpoint(endpoint string, apis []API, modules []string, cors []str
 	if listener, err = net.Listen("tcp", endpoint); err != nil {
 		return nil, nil, err
 	}
-	go NewHTTPServer(cors, vhosts, handler).Serve(listener)
+	var served http.Handler = handler
+	if len(handlers) > 0 {
+		mux := http.NewServeMux()
+		mux.Handle("/", handler)
+		for _, h := range handlers {
+			mux.Handle(h.Path, handler.serveHandler(h))
+			log.Debug("HTTP handler registered", "path", h.Path, "namespace", h.Namespace)
+		}
+		served = mux
+	}
+	go newHTTPServer(cors, vhosts, served).Serve(listener)
 	return listener, handler, err
 }
 
-// StartWSEndpoint starts a websocket endpoint
-func StartWSEndpoint(endpoint string, apis []API, modules []string, wsOrigins []string, exposeAll bool) (net.Listener, *Server, error) {
+// StartWSEndpoint starts a websocket endpoint, requests are rate limited by limiter and authorized by
+// auth if they are not nil
+func StartWSEndpoint(endpoint string, apis []API, modules []string, wsOrigins []string, exposeAll bool, limiter *Limiter, auth *Authenticator) (net.Listener, *Server, error) {
 
 	// Generate the whitelist based on the allowed modules
 	whitelist := make(map[string]bool)
@@ -47,6 +63,8 @@ func StartWSEndpoint(endpoint string, apis []API, modules []string, wsOrigins []
 	}
 	// Register all the APIs exposed by the services
 	handler := NewServer()
+	handler.SetLimiter(limiter)
+	handler.SetAuthenticator(auth)
 	for _, api := range apis {
 		if exposeAll || whitelist[api.Namespace] || (len(whitelist) == 0 && api.Public) {
 			if err := handler.RegisterName(api.Namespace, api.Service); err != nil {
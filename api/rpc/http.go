@@ -30,6 +30,7 @@ var nullAddr, _ = net.ResolveTCPAddr("tcp", "127.0.0.1:0")
 type httpConn struct {
 	client    *http.Client
 	req       *http.Request
+	auth      AuthProvider
 	closeOnce sync.Once
 	closed    chan struct{}
 }
@@ -55,6 +56,16 @@ func (hc *httpConn) Close() error {
 // DialHTTPWithClient creates a new RPC client that connects to an RPC server over HTTP
 // using the provided HTTP Client.
 func DialHTTPWithClient(endpoint string, client *http.Client) (*Client, error) {
+	return dialHTTP(endpoint, client, nil)
+}
+
+// DialHTTPWithAuth creates a new RPC client that connects to an RPC server over HTTP,
+// sending the credentials of auth with every request.
+func DialHTTPWithAuth(endpoint string, auth AuthProvider) (*Client, error) {
+	return dialHTTP(endpoint, new(http.Client), auth)
+}
+
+func dialHTTP(endpoint string, client *http.Client, auth AuthProvider) (*Client, error) {
 	req, err := http.NewRequest(http.MethodPost, endpoint, nil)
 	if err != nil {
 		return nil, err
@@ -64,7 +75,7 @@ func DialHTTPWithClient(endpoint string, client *http.Client) (*Client, error) {
 
 	initctx := context.Background()
 	return newClient(initctx, func(context.Context) (net.Conn, error) {
-		return &httpConn{client: client, req: req, closed: make(chan struct{})}, nil
+		return &httpConn{client: client, req: req, auth: auth, closed: make(chan struct{})}, nil
 	})
 }
 
@@ -122,6 +133,15 @@ func (hc *httpConn) doRequest(ctx context.Context, msg interface{}) (io.ReadClos
 	req := hc.req.WithContext(ctx)
 	req.Body = ioutil.NopCloser(bytes.NewReader(body))
 	req.ContentLength = int64(len(body))
+	if hc.auth != nil {
+		req.Header = make(http.Header, len(hc.req.Header)+1)
+		for key, values := range hc.req.Header {
+			req.Header[key] = values
+		}
+		if err := hc.auth(req.Header, body); err != nil {
+			return nil, err
+		}
+	}
 
 	resp, err := hc.client.Do(req)
 	if err != nil {
@@ -148,6 +168,11 @@ func (t *httpReadWriteNopCloser) Close() error {
 //
 // Deprecated: Server implements http.Handler
 func NewHTTPServer(cors []string, vhosts []string, srv *Server) *http.Server {
+	return newHTTPServer(cors, vhosts, srv)
+}
+
+// newHTTPServer creates a new HTTP server around the handler, with the CORS and virtual host checks.
+func newHTTPServer(cors []string, vhosts []string, srv http.Handler) *http.Server {
 	// Wrap the CORS-handler within a host-handler
 	handler := newCorsHandler(srv, cors)
 	handler = newVHostHandler(vhosts, handler)
@@ -176,6 +201,11 @@ func (srv *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ctx = context.WithValue(ctx, "remote", r.RemoteAddr)
 	ctx = context.WithValue(ctx, "scheme", r.Proto)
 	ctx = context.WithValue(ctx, "local", r.Host)
+	ctx, err := srv.clientContext(ctx, r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
 
 	body := io.LimitReader(r.Body, maxRequestContentLength)
 	codec := NewJSONCodec(&httpReadWriteNopCloser{body, w})
@@ -185,6 +215,37 @@ func (srv *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	srv.ServeSingleRequest(ctx, codec, OptionMethodInvocation)
 }
 
+// HTTPHandler is served on the HTTP RPC endpoint under Path besides the JSON-RPC APIs. Its clients
+// are authenticated and rate limited as if they called Method of Namespace.
+type HTTPHandler struct {
+	Namespace string
+	Method    string
+	Path      string
+	Handler   http.Handler
+}
+
+// serveHandler returns the handler of h, which rejects clients the server does not allow to call
+// its method.
+func (srv *Server) serveHandler(h HTTPHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.clientContext(r.Context(), r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		name := h.Namespace + serviceMethodSeparator + h.Method
+		if srv.auth != nil && !srv.auth.allowed(ctx, h.Namespace, h.Method) {
+			http.Error(w, (&unauthorizedError{name}).Error(), http.StatusForbidden)
+			return
+		}
+		if srv.limiter != nil && !srv.limiter.Allow(ctx, h.Namespace, h.Method) {
+			http.Error(w, (&limitExceededError{name}).Error(), http.StatusTooManyRequests)
+			return
+		}
+		h.Handler.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // validateRequest returns a non-zero response code and error message if the
 // request is invalid.
 func validateRequest(r *http.Request) (int, error) {
@@ -203,7 +264,7 @@ func validateRequest(r *http.Request) (int, error) {
 	return 0, nil
 }
 
-func newCorsHandler(srv *Server, allowedOrigins []string) http.Handler {
+func newCorsHandler(srv http.Handler, allowedOrigins []string) http.Handler {
 	// disable CORS support if user has not specified a custom CORS configuration
 	if len(allowedOrigins) == 0 {
 		return srv
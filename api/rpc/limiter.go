@@ -0,0 +1,220 @@
+// Copyright 2018 The gcchain authors
+
+package rpc
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+const (
+	// KeyByRemote limits clients by their remote IP address.
+	KeyByRemote = "remote"
+	// KeyByAPIKey limits clients by the API key header if it is a configured key, then by the subject of
+	// their authenticated token. Other clients are limited by remote address.
+	KeyByAPIKey = "apikey"
+	// KeyBySubject limits clients by the subject of their authenticated token, clients without one
+	// are limited by remote address.
+	KeyBySubject = "subject"
+
+	// APIKeyHeader is the HTTP header carrying the API key of a client.
+	APIKeyHeader = "X-Api-Key"
+
+	// bucketExpiry is how long the bucket of an idle client is kept.
+	bucketExpiry = 10 * time.Minute
+)
+
+var (
+	limiterAllowedMeter  = metrics.NewRegisteredMeter("rpc/limiter/allowed", nil)
+	limiterRejectedMeter = metrics.NewRegisteredMeter("rpc/limiter/rejected", nil)
+	limiterClientsGauge  = metrics.NewRegisteredGauge("rpc/limiter/clients", nil)
+)
+
+// RateLimit is the budget of a client, Rate requests per second are allowed with bursts up to Burst.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// LimiterConfig configures the rate limits of the RPC server. Budgets are looked up by the full method
+// name (e.g. "debug_traceChain"), then by the namespace, then the default. A zero budget is unlimited.
+// APIKeys are the keys clients are limited by with KeyByAPIKey, unknown keys are ignored.
+type LimiterConfig struct {
+	KeyBy      string               `toml:",omitempty"`
+	APIKeys    []string             `toml:",omitempty"`
+	Default    RateLimit            `toml:",omitempty"`
+	Namespaces map[string]RateLimit `toml:",omitempty"`
+	Methods    map[string]RateLimit `toml:",omitempty"`
+}
+
+// clientInfoKey is the context key of the client information.
+type clientInfoKey struct{}
+
+// ClientInfo identifies the client of a request, Subject and Role are set for authenticated clients.
+type ClientInfo struct {
+	RemoteAddr    string
+	APIKey        string
+	Subject       string
+	Role          string
+	Authenticated bool
+}
+
+// ClientInfoFromContext returns the information of the client sending the request, it is only
+// available for requests over HTTP and websocket.
+func ClientInfoFromContext(ctx context.Context) (*ClientInfo, bool) {
+	info, ok := ctx.Value(clientInfoKey{}).(*ClientInfo)
+	return info, ok
+}
+
+// clientContext returns a copy of ctx with the client information of the http request, it fails
+// if the request carries invalid credentials.
+func (s *Server) clientContext(ctx context.Context, r *http.Request) (context.Context, error) {
+	info := new(ClientInfo)
+	if s.auth != nil {
+		var err error
+		if info, err = s.auth.authenticate(r); err != nil {
+			return nil, err
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	info.RemoteAddr, info.APIKey = host, r.Header.Get(APIKeyHeader)
+	return context.WithValue(ctx, clientInfoKey{}, info), nil
+}
+
+// limitExceededError is returned if a client exceeds its budget.
+type limitExceededError struct{ method string }
+
+func (e *limitExceededError) ErrorCode() int { return -32005 }
+
+func (e *limitExceededError) Error() string { return "request rate limit exceeded for " + e.method }
+
+// tokenBucket holds the tokens of a client for a budget.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Limiter rate limits requests per client against the budgets of the methods.
+type Limiter struct {
+	config  LimiterConfig
+	apiKeys map[string]struct{}
+	buckets map[string]*tokenBucket
+	pruned  time.Time
+	lock    sync.Mutex
+}
+
+// NewLimiter creates a limiter with the budgets in config.
+func NewLimiter(config LimiterConfig) *Limiter {
+	if config.KeyBy == "" {
+		config.KeyBy = KeyByRemote
+	}
+	apiKeys := make(map[string]struct{})
+	for _, key := range config.APIKeys {
+		apiKeys[key] = struct{}{}
+	}
+	return &Limiter{
+		config:  config,
+		apiKeys: apiKeys,
+		buckets: make(map[string]*tokenBucket),
+		pruned:  time.Now(),
+	}
+}
+
+// budget returns the budget of the method and the scope sharing it.
+func (l *Limiter) budget(namespace, method string) (RateLimit, string) {
+	name := namespace + serviceMethodSeparator + method
+	if limit, ok := l.config.Methods[name]; ok {
+		return limit, name
+	}
+	if limit, ok := l.config.Namespaces[namespace]; ok {
+		return limit, namespace
+	}
+	return l.config.Default, ""
+}
+
+// clientKey returns the key the client of the request is limited by, local clients are not limited.
+// Clients can not choose their own keys, so only configured API keys and authenticated subjects are
+// trusted, anything else is limited by the remote address.
+func (l *Limiter) clientKey(ctx context.Context) (string, bool) {
+	info, ok := ClientInfoFromContext(ctx)
+	if !ok {
+		return "", false
+	}
+	if l.config.KeyBy == KeyByAPIKey {
+		if _, known := l.apiKeys[info.APIKey]; known && info.APIKey != "" {
+			return "key:" + info.APIKey, true
+		}
+	}
+	if l.config.KeyBy == KeyByAPIKey || l.config.KeyBy == KeyBySubject {
+		if info.Authenticated && info.Subject != "" {
+			return "sub:" + info.Subject, true
+		}
+	}
+	return "addr:" + info.RemoteAddr, true
+}
+
+// Allow takes a token of the client for the method, it returns false if the client exceeds the budget.
+func (l *Limiter) Allow(ctx context.Context, namespace, method string) bool {
+	key, ok := l.clientKey(ctx)
+	if !ok {
+		return true
+	}
+	limit, scope := l.budget(namespace, method)
+	if limit.Rate <= 0 {
+		return true
+	}
+	key += "/" + scope
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := time.Now()
+	l.prune(now)
+
+	burst := float64(limit.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: burst, last: now}
+		l.buckets[key] = bucket
+		limiterClientsGauge.Update(int64(len(l.buckets)))
+	}
+	bucket.tokens += now.Sub(bucket.last).Seconds() * limit.Rate
+	if bucket.tokens > burst {
+		bucket.tokens = burst
+	}
+	bucket.last = now
+
+	if bucket.tokens < 1 {
+		limiterRejectedMeter.Mark(1)
+		metrics.GetOrRegisterMeter("rpc/limiter/rejected/"+namespace+serviceMethodSeparator+method, nil).Mark(1)
+		return false
+	}
+	bucket.tokens--
+	limiterAllowedMeter.Mark(1)
+	return true
+}
+
+// prune drops the buckets of clients idle for bucketExpiry.
+func (l *Limiter) prune(now time.Time) {
+	if now.Sub(l.pruned) < bucketExpiry {
+		return
+	}
+	for key, bucket := range l.buckets {
+		if now.Sub(bucket.last) > bucketExpiry {
+			delete(l.buckets, key)
+		}
+	}
+	l.pruned = now
+	limiterClientsGauge.Update(int64(len(l.buckets)))
+}
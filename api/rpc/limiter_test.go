@@ -0,0 +1,93 @@
+package rpc
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+)
+
+func clientContext(remote, apiKey string) context.Context {
+	return context.WithValue(context.Background(), clientInfoKey{}, &ClientInfo{RemoteAddr: remote, APIKey: apiKey})
+}
+
+func subjectContext(remote, subject string) context.Context {
+	info := &ClientInfo{RemoteAddr: remote, Subject: subject, Authenticated: true}
+	return context.WithValue(context.Background(), clientInfoKey{}, info)
+}
+
+func TestLimiterBudgets(t *testing.T) {
+	limiter := NewLimiter(LimiterConfig{
+		KeyBy:      KeyByAPIKey,
+		APIKeys:    []string{"key"},
+		Default:    RateLimit{Rate: 0.001, Burst: 3},
+		Namespaces: map[string]RateLimit{"debug": {Rate: 0.001, Burst: 1}},
+		Methods:    map[string]RateLimit{"eth_call": {Rate: 0.001, Burst: 2}, "eth_blockNumber": {}},
+	})
+
+	tests := []struct {
+		ctx       context.Context
+		namespace string
+		method    string
+		want      int
+	}{
+		{clientContext("1.1.1.1", ""), "eth", "call", 2},
+		{clientContext("1.1.1.1", ""), "eth", "getBalance", 3},
+		{clientContext("1.1.1.1", ""), "eth", "blockNumber", 10},
+		// methods of a namespace share the budget
+		{clientContext("1.1.1.1", ""), "debug", "traceChain", 1},
+		{clientContext("1.1.1.1", ""), "debug", "traceBlock", 0},
+		// clients with api keys are limited separately from their address
+		{clientContext("1.1.1.1", "key"), "eth", "call", 2},
+		{clientContext("2.2.2.2", "key"), "eth", "call", 0},
+		{clientContext("2.2.2.2", ""), "eth", "call", 2},
+		// unknown api keys do not escape the budget of the address
+		{clientContext("2.2.2.2", "forged"), "eth", "call", 0},
+		// authenticated clients without api keys are limited by their subjects
+		{subjectContext("2.2.2.2", "alice"), "eth", "call", 2},
+		{subjectContext("3.3.3.3", "alice"), "eth", "call", 0},
+		{clientContext("3.3.3.3", ""), "eth", "call", 2},
+		// local clients are never limited
+		{context.Background(), "eth", "call", 10},
+	}
+	for i, tt := range tests {
+		allowed := 0
+		for n := 0; n < 10; n++ {
+			if limiter.Allow(tt.ctx, tt.namespace, tt.method) {
+				allowed++
+			}
+		}
+		if allowed != tt.want {
+			t.Errorf("test %d: allowed %v calls of %s_%s, want %v", i, allowed, tt.namespace, tt.method, tt.want)
+		}
+	}
+}
+
+func TestHTTPRateLimit(t *testing.T) {
+	server := NewServer()
+	server.SetLimiter(NewLimiter(LimiterConfig{Methods: map[string]RateLimit{"test_echo": {Rate: 0.001, Burst: 2}}}))
+	if err := server.RegisterName("test", new(Service)); err != nil {
+		t.Fatal(err)
+	}
+	hs := httptest.NewServer(server)
+	defer hs.Close()
+
+	client, err := DialHTTP(hs.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	var result Result
+	for i := 0; i < 2; i++ {
+		if err := client.Call(&result, "test_echo", "hello", 10, &Args{"world"}); err != nil {
+			t.Fatalf("call %d error = %v", i, err)
+		}
+	}
+	err = client.Call(&result, "test_echo", "hello", 10, &Args{"world"})
+	if rpcErr, ok := err.(Error); !ok || rpcErr.ErrorCode() != -32005 {
+		t.Fatalf("call over budget error = %v, want limit exceeded error", err)
+	}
+	if err := client.Call(nil, "test_rets"); err != nil {
+		t.Errorf("call of unlimited method error = %v", err)
+	}
+}
@@ -59,6 +59,17 @@ func (s *RPCService) Modules() map[string]string {
 	return modules
 }
 
+// SetLimiter sets the limiter rate limiting method calls and subscriptions of remote clients.
+func (s *Server) SetLimiter(limiter *Limiter) {
+	s.limiter = limiter
+}
+
+// SetAuthenticator sets the authenticator verifying the credentials of remote clients and authorizing
+// their calls and subscriptions.
+func (s *Server) SetAuthenticator(auth *Authenticator) {
+	s.auth = auth
+}
+
 // RegisterName will create a service for the given rcvr type under the given name. When no methods on the given rcvr
 // match the criteria to be either a RPC method or a subscription an error is returned. Otherwise a new service is
 // created and added to the service collection this server instance serves.
@@ -263,6 +274,13 @@ func (s *Server) handle(ctx context.Context, codec ServerCodec, req *serverReque
 		return codec.CreateErrorResponse(&req.id, &invalidParamsError{"Expected subscription id as first argument"}), nil
 	}
 
+	if s.auth != nil && !s.auth.allowed(ctx, req.svcname, req.method) {
+		return codec.CreateErrorResponse(&req.id, &unauthorizedError{req.svcname + serviceMethodSeparator + req.method}), nil
+	}
+	if s.limiter != nil && !s.limiter.Allow(ctx, req.svcname, req.method) {
+		return codec.CreateErrorResponse(&req.id, &limitExceededError{req.svcname + serviceMethodSeparator + req.method}), nil
+	}
+
 	if req.callb.isSubscribe {
 		subid, err := s.createSubscription(ctx, codec, req)
 		if err != nil {
@@ -396,7 +414,7 @@ func (s *Server) readRequest(codec ServerCodec) ([]*serverRequest, bool, Error)
 
 		if r.isPubSub { // eth_subscribe, r.method contains the subscription method name
 			if callb, ok := svc.subscriptions[r.method]; ok {
-				requests[i] = &serverRequest{id: r.id, svcname: svc.name, callb: callb}
+				requests[i] = &serverRequest{id: r.id, svcname: svc.name, method: "subscribe", callb: callb}
 				if r.params != nil && len(callb.argTypes) > 0 {
 					argTypes := []reflect.Type{reflect.TypeOf("")}
 					argTypes = append(argTypes, callb.argTypes...)
@@ -413,7 +431,7 @@ func (s *Server) readRequest(codec ServerCodec) ([]*serverRequest, bool, Error)
 		}
 
 		if callb, ok := svc.callbacks[r.method]; ok { // lookup RPC method
-			requests[i] = &serverRequest{id: r.id, svcname: svc.name, callb: callb}
+			requests[i] = &serverRequest{id: r.id, svcname: svc.name, method: r.method, callb: callb}
 			if r.params != nil && len(callb.argTypes) > 0 {
 				if args, err := codec.ParseRequestArguments(callb.argTypes, r.params); err == nil {
 					requests[i].args = args
@@ -38,9 +38,10 @@ type notifierKey struct{}
 // Server callbacks use the notifier to send notifications.
 type Notifier struct {
 	codec    ServerCodec
-	subMu    sync.RWMutex // guards active and inactive maps
+	subMu    sync.RWMutex // guards active, inactive and buffered maps
 	active   map[ID]*Subscription
 	inactive map[ID]*Subscription
+	buffered map[ID][]interface{} // notifications of inactive subscriptions, sent once activated
 }
 
 // newNotifier creates a new notifier that can be used to send subscription
@@ -50,6 +51,7 @@ func newNotifier(codec ServerCodec) *Notifier {
 		codec:    codec,
 		active:   make(map[ID]*Subscription),
 		inactive: make(map[ID]*Subscription),
+		buffered: make(map[ID][]interface{}),
 	}
 }
 
@@ -61,7 +63,7 @@ func NotifierFromContext(ctx context.Context) (*Notifier, bool) {
 
 // CreateSubscription returns a new subscription that is coupled to the
 // RPC connection. By default subscriptions are inactive and notifications
-// are dropped until the subscription is marked as active. This is done
+// are buffered until the subscription is marked as active. This is done
 // by the RPC server after the subscription ID is send to the client.
 func (n *Notifier) CreateSubscription() *Subscription {
 	s := &Subscription{ID: NewID(), err: make(chan error)}
@@ -73,17 +75,32 @@ func (n *Notifier) CreateSubscription() *Subscription {
 
 // Notify sends a notification to the client with the given data as payload.
 // If an error occurs the RPC connection is closed and the error is returned.
+// Notifications of an inactive subscription are sent once it is activated.
 func (n *Notifier) Notify(id ID, data interface{}) error {
 	n.subMu.RLock()
-	defer n.subMu.RUnlock()
-
 	sub, active := n.active[id]
 	if active {
-		notification := n.codec.CreateNotification(string(id), sub.namespace, data)
-		if err := n.codec.Write(notification); err != nil {
-			n.codec.Close()
-			return err
-		}
+		defer n.subMu.RUnlock()
+		return n.send(sub, data)
+	}
+	n.subMu.RUnlock()
+
+	n.subMu.Lock()
+	defer n.subMu.Unlock()
+	if sub, active := n.active[id]; active {
+		return n.send(sub, data)
+	}
+	if _, found := n.inactive[id]; found {
+		n.buffered[id] = append(n.buffered[id], data)
+	}
+	return nil
+}
+
+func (n *Notifier) send(sub *Subscription, data interface{}) error {
+	notification := n.codec.CreateNotification(string(sub.ID), sub.namespace, data)
+	if err := n.codec.Write(notification); err != nil {
+		n.codec.Close()
+		return err
 	}
 	return nil
 }
@@ -107,7 +124,7 @@ func (n *Notifier) unsubscribe(id ID) error {
 }
 
 // activate enables a subscription. Until a subscription is enabled all
-// notifications are dropped. This method is called by the RPC server after
+// notifications are buffered. This method is called by the RPC server after
 // the subscription ID was sent to client. This prevents notifications being
 // send to the client before the subscription ID is send to the client.
 func (n *Notifier) activate(id ID, namespace string) {
@@ -117,5 +134,13 @@ func (n *Notifier) activate(id ID, namespace string) {
 		sub.namespace = namespace
 		n.active[id] = sub
 		delete(n.inactive, id)
+
+		buffered := n.buffered[id]
+		delete(n.buffered, id)
+		for _, data := range buffered {
+			if err := n.send(sub, data); err != nil {
+				return
+			}
+		}
 	}
 }
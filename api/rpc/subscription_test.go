@@ -91,6 +91,19 @@ func (s *NotificationTestService) HangSubscription(ctx context.Context, val int)
 	return subscription, nil
 }
 
+// ImmediateSubscription notifies val before the subscription is activated.
+func (s *NotificationTestService) ImmediateSubscription(ctx context.Context, val int) (*Subscription, error) {
+	notifier, supported := NotifierFromContext(ctx)
+	if !supported {
+		return nil, ErrNotificationsUnsupported
+	}
+	subscription := notifier.CreateSubscription()
+	if err := notifier.Notify(subscription.ID, val); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
 func TestNotifications(t *testing.T) {
 	server := NewServer()
 	service := &NotificationTestService{}
@@ -150,6 +163,55 @@ func TestNotifications(t *testing.T) {
 	}
 }
 
+// TestNotificationsBeforeActivation ensures that notifications sent before the subscription is
+// activated are delivered after the subscription id.
+func TestNotificationsBeforeActivation(t *testing.T) {
+	server := NewServer()
+	if err := server.RegisterName("eth", new(NotificationTestService)); err != nil {
+		t.Fatalf("unable to register test service %v", err)
+	}
+
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	clientConn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	go server.ServeCodec(NewJSONCodec(serverConn), OptionMethodInvocation|OptionSubscriptions)
+
+	out := json.NewEncoder(clientConn)
+	in := json.NewDecoder(clientConn)
+
+	val := 12345
+	request := map[string]interface{}{
+		"id":      1,
+		"method":  "eth_subscribe",
+		"version": "2.0",
+		"params":  []interface{}{"immediateSubscription", val},
+	}
+	if err := out.Encode(request); err != nil {
+		t.Fatal(err)
+	}
+
+	var response jsonSuccessResponse
+	if err := in.Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	subid, ok := response.Result.(string)
+	if !ok {
+		t.Fatalf("expected subscription id, got %T", response.Result)
+	}
+
+	var notification jsonNotification
+	if err := in.Decode(&notification); err != nil {
+		t.Fatal(err)
+	}
+	if notification.Params.Subscription != subid {
+		t.Fatalf("expected subscription %s, got %s", subid, notification.Params.Subscription)
+	}
+	if int(notification.Params.Result.(float64)) != val {
+		t.Fatalf("expected %d, got %v", val, notification.Params.Result)
+	}
+}
+
 func waitForMessages(t *testing.T, in *json.Decoder, successes chan<- jsonSuccessResponse,
 	failures chan<- jsonErrResponse, notifications chan<- jsonNotification, errors chan<- error) {
 
@@ -43,6 +43,7 @@ type service struct {
 type serverRequest struct {
 	id            interface{}
 	svcname       string
+	method        string
 	callb         *callback
 	args          []reflect.Value
 	isUnsubscribe bool
@@ -56,6 +57,8 @@ type subscriptions map[string]*callback  // collection of subscription callbacks
 // Server represents a RPC server
 type Server struct {
 	services serviceRegistry
+	limiter  *Limiter
+	auth     *Authenticator
 
 	run      int32
 	codecsMu sync.Mutex
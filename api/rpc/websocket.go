@@ -42,9 +42,26 @@ var websocketJSONCodec = websocket.Codec{
 // allowedOrigins should be a comma-separated list of allowed origin URLs.
 // To allow connections with any origin, pass "*".
 func (srv *Server) WebsocketHandler(allowedOrigins []string) http.Handler {
+	validateOrigin := wsHandshakeValidator(allowedOrigins)
 	return websocket.Server{
-		Handshake: wsHandshakeValidator(allowedOrigins),
+		Handshake: func(cfg *websocket.Config, req *http.Request) error {
+			if err := validateOrigin(cfg, req); err != nil {
+				return err
+			}
+			// credentials are used once, the handler gets the client information from the request
+			ctx, err := srv.clientContext(context.Background(), req)
+			if err != nil {
+				return err
+			}
+			*req = *req.WithContext(ctx)
+			return nil
+		},
 		Handler: func(conn *websocket.Conn) {
+			ctx := conn.Request().Context()
+			if _, ok := ClientInfoFromContext(ctx); !ok {
+				conn.Close()
+				return
+			}
 			// Create a custom encode/decode pair to enforce payload size and number encoding
 			conn.MaxPayloadBytes = maxRequestContentLength
 
@@ -54,7 +71,9 @@ func (srv *Server) WebsocketHandler(allowedOrigins []string) http.Handler {
 			decoder := func(v interface{}) error {
 				return websocketJSONCodec.Receive(conn, v)
 			}
-			srv.ServeCodec(NewCodec(conn, encoder, decoder), OptionMethodInvocation|OptionSubscriptions)
+			codec := NewCodec(conn, encoder, decoder)
+			defer codec.Close()
+			srv.serveRequest(ctx, codec, false, OptionMethodInvocation|OptionSubscriptions)
 		},
 	}
 }
@@ -110,6 +129,12 @@ func wsHandshakeValidator(allowedOrigins []string) func(*websocket.Config, *http
 // The context is used for the initial connection establishment. It does not
 // affect subsequent interactions with the client.
 func DialWebsocket(ctx context.Context, endpoint, origin string) (*Client, error) {
+	return DialWebsocketWithAuth(ctx, endpoint, origin, nil)
+}
+
+// DialWebsocketWithAuth creates a new RPC client like DialWebsocket, authenticating with the
+// credentials of auth when connecting.
+func DialWebsocketWithAuth(ctx context.Context, endpoint, origin string, auth AuthProvider) (*Client, error) {
 	if origin == "" {
 		var err error
 		if origin, err = os.Hostname(); err != nil {
@@ -127,6 +152,12 @@ func DialWebsocket(ctx context.Context, endpoint, origin string) (*Client, error
 	}
 
 	return newClient(ctx, func(ctx context.Context) (net.Conn, error) {
+		if auth != nil {
+			config.Header = make(http.Header)
+			if err := auth(config.Header, nil); err != nil {
+				return nil, err
+			}
+		}
 		return wsDialContext(ctx, config)
 	})
 }
@@ -168,8 +168,6 @@ func buildFlags(env build.Environment) (flags []string) {
 	}
 	ld = append(ld, "-X", "github.com/gcchains/chain/configs.Version="+version)
 
-	supportPrivateTx := fmt.Sprint(env.SupportPrivateTx)
-	ld = append(ld, "-X", "github.com/gcchains/chain/private.SupportPrivateTx="+supportPrivateTx)
 	if runtime.GOOS == "darwin" {
 		ld = append(ld, "-s")
 	}
@@ -7,14 +7,20 @@ import (
 
 	"github.com/gcchains/chain/accounts/keystore"
 	"github.com/gcchains/chain/api/gcclient"
+	"github.com/gcchains/chain/api/rpc"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
 // NewCpcClient new a gcc.client
 func NewCpcClient(ep string, kspath string, password string) (*gcclient.Client, *ecdsa.PrivateKey, *ecdsa.PublicKey, common.Address, error) {
+	return NewCpcClientWithAuth(ep, nil, kspath, password)
+}
+
+// NewCpcClientWithAuth new a gcc.client authenticating with auth
+func NewCpcClientWithAuth(ep string, auth rpc.AuthProvider, kspath string, password string) (*gcclient.Client, *ecdsa.PrivateKey, *ecdsa.PublicKey, common.Address, error) {
 	// Create client.
-	client, err := gcclient.Dial(ep)
+	client, err := gcclient.DialWithAuth(ep, auth)
 	if err != nil {
 		return nil, nil, nil, [20]byte{}, err
 	}
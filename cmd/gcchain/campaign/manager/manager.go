@@ -21,6 +21,7 @@ import (
 // Console manage apis
 type Console struct {
 	rpc    string
+	auth   rpc.AuthProvider
 	output cm.Output
 	ctx    *context.Context
 	client *gcclient.Client
@@ -53,18 +54,24 @@ func SetRunMode(rm configs.RunMode) {
 
 // NewConsole build a console
 func NewConsole(ctx *context.Context, rpc string, keystore string, passwordFile string, output cm.Output) (*Console, error) {
+	return NewConsoleWithAuth(ctx, rpc, nil, keystore, passwordFile, output)
+}
+
+// NewConsoleWithAuth build a console authenticating to the rpc endpoint with auth
+func NewConsoleWithAuth(ctx *context.Context, rpc string, auth rpc.AuthProvider, keystore string, passwordFile string, output cm.Output) (*Console, error) {
 	password, err := cc.ReadPasswordByFile(passwordFile)
 	if err != nil {
 		output.Fatal(err.Error())
 		return nil, err
 	}
-	client, prvkey, pubkey, fromAddress, err := cm.NewCpcClient(rpc, keystore, *password)
+	client, prvkey, pubkey, fromAddress, err := cm.NewCpcClientWithAuth(rpc, auth, keystore, *password)
 	if err != nil {
 		output.Fatal(err.Error())
 		return nil, err
 	}
 	console := Console{
 		rpc,
+		auth,
 		output,
 		ctx,
 		client,
@@ -76,7 +83,7 @@ func NewConsole(ctx *context.Context, rpc string, keystore string, passwordFile
 }
 
 func (c *Console) isMining() bool {
-	client, err := rpc.DialContext(*c.ctx, c.rpc)
+	client, err := rpc.DialContextWithAuth(*c.ctx, c.rpc, c.auth)
 	if err != nil {
 		c.output.Error(err.Error())
 	}
@@ -139,7 +146,7 @@ func (c *Console) StartMining() error {
 		c.output.Info("You are not rnode yet ,you will spend 200000 gcc to be rnode first")
 	}
 	c.output.Info("Start Mining...")
-	client, err := rpc.DialContext(*c.ctx, c.rpc)
+	client, err := rpc.DialContextWithAuth(*c.ctx, c.rpc, c.auth)
 	if err != nil {
 		return err
 	}
@@ -172,7 +179,7 @@ func (c *Console) StopMining() error {
 		} else {
 			// miner stop
 			c.output.Info("Stop Mining...")
-			client, err := rpc.DialContext(*c.ctx, c.rpc)
+			client, err := rpc.DialContextWithAuth(*c.ctx, c.rpc, c.auth)
 			if err != nil {
 				return err
 			}
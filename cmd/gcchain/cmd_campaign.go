@@ -40,8 +40,13 @@ func build(ctx *cli.Context) (*manager.Console, common.Output, context.CancelFun
 	manager.SetGasConfig(price, limit)
 	manager.SetRunMode(configs.GetRunMode())
 
+	auth, err := flags.RpcAuth(ctx)
+	if err != nil {
+		return nil, &out, nil, err
+	}
+
 	_ctx, cancel := context.WithCancel(context.Background())
-	console, err := manager.NewConsole(&_ctx, rpc, kspath, pwdfile, &out)
+	console, err := manager.NewConsoleWithAuth(&_ctx, rpc, auth, kspath, pwdfile, &out)
 	if err != nil {
 		out.Fatal(err.Error())
 	}
@@ -19,13 +19,13 @@ import (
 	"github.com/gcchains/chain/configs"
 	"github.com/gcchains/chain/contracts/dpos/primitive_register"
 	"github.com/gcchains/chain/core"
+	"github.com/gcchains/chain/core/chainexport"
 	"github.com/gcchains/chain/core/state"
 	"github.com/gcchains/chain/database"
 	"github.com/gcchains/chain/types"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/trie"
 	"github.com/naoina/toml"
-	"github.com/syndtr/goleveldb/leveldb/util"
 	"github.com/urfave/cli"
 )
 
@@ -41,6 +41,7 @@ var chainCommand = cli.Command{
 			Flags: append([]cli.Flag{
 				flags.GetByName(flags.DataDirFlagName),
 				flags.GetByName(flags.RunModeFlagName),
+				flags.GetByName(flags.DBEngineFlagName),
 			}, flags.LogFlags...),
 			Action:    initChain,
 			ArgsUsage: "[/path/to/genesis.toml]",
@@ -60,36 +61,57 @@ If no genesis file is found, the initialization is aborted.`, defaultGenesisPath
 		{
 			Action:    importChain,
 			Name:      "import",
-			Usage:     "Import a blockchain file",
-			ArgsUsage: "<filename>",
+			Usage:     "Import a blockchain file or segmented export",
+			ArgsUsage: "<filename | directory>",
 			Flags: append([]cli.Flag{
 				flags.GetByName(flags.DataDirFlagName),
 				flags.GetByName(flags.NoCompactionFlagName),
 				flags.GetByName(flags.CacheFlagName),
 				flags.GetByName(flags.CacheDatabaseFlagName),
 				flags.GetByName(flags.CacheGCFlagName),
+				cli.StringSliceFlag{
+					Name:  "checkpoint",
+					Usage: "Trusted block of a segmented import as <number>:<hash>, or the path of a trusted manifest",
+				},
 			}, flags.LogFlags...),
 			Description: `The import command imports blocks from an RLP-encoded form. The form can be one file
 with several RLP-encoded blocks, or several files can be used.
 
 If only one file is used, import error will result in failure. If several files are used,
-processing will proceed even if an individual RLP-file import failure occurs.`,
+processing will proceed even if an individual RLP-file import failure occurs.
+
+A directory is imported as a segmented RLP export. All segments are verified against the
+manifest and the --checkpoint blocks before any block is inserted, segments already in
+the chain are skipped, so an interrupted import resumes.`,
 		},
 		{
 			Action:    exportChain,
 			Name:      "export",
 			Usage:     "Export blockchain into file",
-			ArgsUsage: "<output file> [blockNumFirst blockNumLast]",
+			ArgsUsage: "<output file | directory> [blockNumFirst blockNumLast]",
 			Flags: append([]cli.Flag{
 				flags.GetByName(flags.DataDirFlagName),
 				flags.GetByName(flags.CacheFlagName),
 				flags.GetByName(flags.CacheDatabaseFlagName),
 				flags.GetByName(flags.CacheGCFlagName),
+				cli.StringFlag{
+					Name:  "format",
+					Usage: fmt.Sprintf("Export segments in this format (%s) into the output directory", strings.Join(chainexport.Formats, "|")),
+				},
+				cli.Uint64Flag{
+					Name:  "segment-size",
+					Usage: fmt.Sprintf("Export segments of this many blocks into the output directory (default: %d)", chainexport.DefaultSegmentSize),
+				},
 			}, flags.LogFlags...),
 			Description: `Requires a first argument of the file to write to.
 Optional second and third arguments control the first and
 last block to write. In this mode, the file will be appended
-if already existing.`,
+if already existing.
+
+With --format or --segment-size the blocks are exported into gzip compressed segment
+files listed with their hashes in a manifest.json in the output directory. Exporting
+into the directory again resumes after its last segment. The jsonl format writes one
+JSON object per block with its transactions, receipts and DPoS snapshot.`,
 		},
 		{
 			Action:    importPreimages,
@@ -131,6 +153,44 @@ if already existing.`,
 			Description: `The arguments are interpreted as block numbers or hashes.
 Use "gcchain chain dump 0" to dump the genesis block.`,
 		},
+		{
+			Action:    pruneState,
+			Name:      "prune-state",
+			Usage:     "Delete states of old blocks",
+			ArgsUsage: " ",
+			Flags: append([]cli.Flag{
+				flags.GetByName(flags.DataDirFlagName),
+				flags.GetByName(flags.NoCompactionFlagName),
+				flags.GetByName(flags.CacheFlagName),
+				flags.GetByName(flags.CacheDatabaseFlagName),
+				flags.GetByName(flags.StateRetentionFlagName),
+			}, flags.LogFlags...),
+			Description: fmt.Sprintf(`The prune-state command deletes states of blocks older than the recent ones.
+States of the genesis block and term checkpoints are always kept, as well as private
+states of retained blocks. The number of recent blocks is set by --%v, which is %v by default.
+
+The node must not be running while pruning.`, flags.StateRetentionFlagName, defaultPruneRetention),
+		},
+		{
+			Action:    migrateDB,
+			Name:      "migrate-db",
+			Usage:     "Copy the chain database to another engine",
+			ArgsUsage: " ",
+			Flags: append([]cli.Flag{
+				flags.GetByName(flags.DataDirFlagName),
+				flags.GetByName(flags.CacheFlagName),
+				flags.GetByName(flags.CacheDatabaseFlagName),
+				cli.StringFlag{
+					Name:  "to",
+					Usage: fmt.Sprintf("Engine to copy the database to (%s)", strings.Join(database.Engines, "|")),
+				},
+			}, flags.LogFlags...),
+			Description: `The migrate-db command copies the chain database to a new database of the engine given by --to
+and verifies that the copy has the same head block. The copy replaces the database, which is kept as
+a backup named after its engine next to it, e.g. chaindata.leveldb.
+
+The node must not be running while migrating.`,
+		},
 	},
 }
 
@@ -237,7 +297,16 @@ func importChain(ctx *cli.Context) error {
 	// Import the chain
 	start := time.Now()
 
-	if err := commons.ImportChain(chain, ctx.Args().First()); err != nil {
+	var err error
+	if fi, serr := os.Stat(ctx.Args().First()); serr == nil && fi.IsDir() {
+		err = commons.ImportChainSegments(chain, ctx.Args().First(), ctx.StringSlice("checkpoint"))
+	} else {
+		if ctx.IsSet("checkpoint") {
+			log.Fatalf("Checkpoints are only verified for segmented imports")
+		}
+		err = commons.ImportChain(chain, ctx.Args().First())
+	}
+	if err != nil {
 		log.Error("Import error", "err", err)
 	}
 	// flush the caches
@@ -246,19 +315,7 @@ func importChain(ctx *cli.Context) error {
 	fmt.Printf("Import done in %v.\n\n", time.Since(start))
 
 	// Output pre-compaction stats mostly to see the import trashing
-	db := chainDb.(*database.LDBDatabase)
-
-	stats, err := db.LDB().GetProperty("leveldb.stats")
-	if err != nil {
-		log.Fatalf("Failed to read database stats: %v", err)
-	}
-	fmt.Println(stats)
-
-	ioStats, err := db.LDB().GetProperty("leveldb.iostats")
-	if err != nil {
-		log.Fatalf("Failed to read database iostats: %v", err)
-	}
-	fmt.Println(ioStats)
+	printDatabaseStats(chainDb)
 
 	fmt.Printf("Trie cache misses:  %d\n", trie.CacheMisses())
 	fmt.Printf("Trie cache unloads: %d\n\n", trie.CacheUnloads())
@@ -277,26 +334,42 @@ func importChain(ctx *cli.Context) error {
 	}
 
 	// Compact the entire database to more accurately measure disk io and print the stats
-	start = time.Now()
-	fmt.Println("Compacting entire database...")
-	if err = db.LDB().CompactRange(util.Range{}); err != nil {
-		log.Fatalf("Compaction failed: %v", err)
-	}
-	fmt.Printf("Compaction done in %v.\n\n", time.Since(start))
+	compactDatabase(chainDb)
+	printDatabaseStats(chainDb)
+
+	return nil
+}
 
-	stats, err = db.LDB().GetProperty("leveldb.stats")
+// printDatabaseStats prints the engine statistics of the database, and its io statistics
+// if the engine has them.
+func printDatabaseStats(db database.Database) {
+	stater, ok := db.(database.Stater)
+	if !ok {
+		return
+	}
+	stats, err := stater.Stat("stats")
 	if err != nil {
 		log.Fatalf("Failed to read database stats: %v", err)
 	}
 	fmt.Println(stats)
 
-	ioStats, err = db.LDB().GetProperty("leveldb.iostats")
-	if err != nil {
-		log.Fatalf("Failed to read database iostats: %v", err)
+	if ioStats, err := stater.Stat("iostats"); err == nil {
+		fmt.Println(ioStats)
 	}
-	fmt.Println(ioStats)
+}
 
-	return nil
+// compactDatabase compacts the entire database if the engine compacts.
+func compactDatabase(db database.Database) {
+	compacter, ok := db.(database.Compacter)
+	if !ok {
+		return
+	}
+	start := time.Now()
+	fmt.Println("Compacting entire database...")
+	if err := compacter.Compact(nil, nil); err != nil {
+		log.Fatalf("Compaction failed: %v", err)
+	}
+	fmt.Printf("Compaction done in %v.\n\n", time.Since(start))
 }
 
 func exportChain(ctx *cli.Context) error {
@@ -311,20 +384,29 @@ func exportChain(ctx *cli.Context) error {
 
 	var err error
 	fp := ctx.Args().First()
+	first, last := uint64(0), chain.CurrentBlock().NumberU64()
 
-	if argcnt == 1 {
-		err = commons.ExportChainN(chain, fp, uint64(0), chain.CurrentBlock().NumberU64())
-	} else {
+	if argcnt == 3 {
 		// This can be improved to allow for numbers larger than 9223372036854775807
-		first, ferr := strconv.ParseInt(ctx.Args().Get(1), 10, 64)
-		last, lerr := strconv.ParseInt(ctx.Args().Get(2), 10, 64)
+		f, ferr := strconv.ParseInt(ctx.Args().Get(1), 10, 64)
+		l, lerr := strconv.ParseInt(ctx.Args().Get(2), 10, 64)
 		if ferr != nil || lerr != nil {
 			log.Fatal("Export error in parsing parameters: block number not an integer")
 		}
-		if first < 0 || last < 0 {
+		if f < 0 || l < 0 {
 			log.Fatal("Export error: block number must be greater than 0")
 		}
-		err = commons.ExportChainN(chain, fp, uint64(first), uint64(last))
+		first, last = uint64(f), uint64(l)
+	}
+	if ctx.IsSet("format") || ctx.IsSet("segment-size") {
+		err = commons.ExportChainSegments(chain, fp, chainexport.ExportConfig{
+			Format:      ctx.String("format"),
+			SegmentSize: ctx.Uint64("segment-size"),
+			First:       first,
+			Last:        last,
+		})
+	} else {
+		err = commons.ExportChainN(chain, fp, first, last)
 	}
 
 	if err != nil {
@@ -340,7 +422,7 @@ func importPreimages(ctx *cli.Context) error {
 		log.Fatalf("This command requires an argument.")
 	}
 	cfg, stack := newConfigNode(ctx)
-	diskdb := commons.MakeChainDatabase(ctx, stack, cfg.Gcc.DatabaseCache).(*database.LDBDatabase)
+	diskdb := commons.MakeChainDatabase(ctx, stack, cfg.Gcc.DatabaseCache)
 
 	start := time.Now()
 	if err := commons.ImportPreimages(diskdb, ctx.Args().First()); err != nil {
@@ -356,7 +438,7 @@ func exportPreimages(ctx *cli.Context) error {
 		log.Fatal("This command requires an argument.")
 	}
 	cfg, stack := newConfigNode(ctx)
-	diskdb := commons.MakeChainDatabase(ctx, stack, cfg.Gcc.DatabaseCache).(*database.LDBDatabase)
+	diskdb := commons.MakeChainDatabase(ctx, stack, cfg.Gcc.DatabaseCache)
 
 	start := time.Now()
 	if err := commons.ExportPreimages(diskdb, ctx.Args().First()); err != nil {
@@ -397,6 +479,63 @@ func dump(ctx *cli.Context) error {
 	return nil
 }
 
+// defaultPruneRetention is the number of recent blocks whose state is kept by prune-state if not specified.
+const defaultPruneRetention = 128
+
+// pruneState deletes states of old blocks except the recent ones and checkpoints.
+func pruneState(ctx *cli.Context) error {
+	retention := uint64(defaultPruneRetention)
+	if ctx.IsSet(flags.StateRetentionFlagName) {
+		retention = ctx.Uint64(flags.StateRetentionFlagName)
+	}
+	if retention == 0 {
+		log.Fatalf("--%v must be positive", flags.StateRetentionFlagName)
+	}
+
+	cfg, stack := newConfigNode(ctx)
+	chain, chainDb := commons.OpenChain(ctx, stack, &cfg.Gcc)
+	defer chainDb.Close()
+
+	start := time.Now()
+	deleted, err := chain.PruneState(retention)
+	// flush the caches
+	chain.Stop()
+	if err != nil {
+		log.Fatalf("Prune error: %v", err)
+	}
+	fmt.Printf("Pruned %d state entries in %v.\n", deleted, time.Since(start))
+
+	if ctx.IsSet(flags.NoCompactionFlagName) {
+		return nil
+	}
+	compactDatabase(chainDb)
+	return nil
+}
+
+// migrateDB copies the chain database to the engine given by --to.
+func migrateDB(ctx *cli.Context) error {
+	engine := ctx.String("to")
+	if engine == "" {
+		log.Fatalf("--to is required, supported engines are %v", database.Engines)
+	}
+	if err := database.ValidateEngine(engine); err != nil {
+		log.Fatalf("%v", err)
+	}
+	cfg, stack := newConfigNode(ctx)
+	dir := stack.ResolvePath(configs.DatabaseName)
+	if dir == "" {
+		log.Fatalf("Migration requires a data directory")
+	}
+
+	start := time.Now()
+	copied, backup, err := commons.MigrateChainDatabase(dir, engine, cfg.Gcc.DatabaseCache)
+	if err != nil {
+		log.Fatalf("Migration failed: %v", err)
+	}
+	fmt.Printf("Copied %d entries to %s in %v, the original database is kept at %s.\n", copied, engine, time.Since(start), backup)
+	return nil
+}
+
 // hashish returns true for strings that look like hashes.
 func hashish(x string) bool {
 	_, err := strconv.Atoi(x)
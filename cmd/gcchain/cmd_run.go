@@ -12,6 +12,7 @@ import (
 	"github.com/gcchains/chain/accounts"
 	"github.com/gcchains/chain/accounts/keystore"
 	"github.com/gcchains/chain/api/gcclient"
+	"github.com/gcchains/chain/api/graphql"
 	"github.com/gcchains/chain/cmd/gcchain/flags"
 	"github.com/gcchains/chain/commons/chainmetrics"
 	"github.com/gcchains/chain/commons/log"
@@ -19,9 +20,11 @@ import (
 	"github.com/gcchains/chain/configs"
 	"github.com/gcchains/chain/consensus/dpos"
 	"github.com/gcchains/chain/contracts/dpos/primitive_register"
+	"github.com/gcchains/chain/internal/gccapi"
 	"github.com/gcchains/chain/internal/profile"
 	"github.com/gcchains/chain/node"
 	"github.com/gcchains/chain/protocols/gcc"
+	"github.com/gcchains/chain/protocols/les"
 	"github.com/urfave/cli"
 )
 
@@ -99,6 +102,16 @@ func run(ctx *cli.Context) error {
 		log.Fatalf("A node cannot be both miner and validator.")
 	}
 
+	if ctx.Bool(flags.LightFlagName) {
+		if ctx.IsSet(flags.MineFlagName) || ctx.IsSet(flags.ValidatorFlagName) || ctx.IsSet(flags.LightServeFlagName) {
+			log.Fatalf("A light client cannot mine, validate or serve light clients.")
+		}
+		n := createLightNode(ctx)
+		bootstrapLight(ctx, n)
+		n.Wait()
+		return nil
+	}
+
 	n := createNode(ctx)
 	bootstrap(ctx, n)
 	n.Wait()
@@ -107,9 +120,13 @@ func run(ctx *cli.Context) error {
 
 // Register chain services for a *full* node.
 func registerChainService(cfg *gcc.Config, n *node.Node, cliCtx *cli.Context) {
+	var backend gccapi.Backend
 	err := n.Register(func(ctx *node.ServiceContext) (node.Service, error) {
 		fullNode, err := gcc.New(ctx, cfg)
 		primitive_register.RegisterPrimitiveContracts()
+		if err == nil {
+			backend = fullNode.APIBackend
+		}
 
 		if cliCtx.Bool(flags.MineFlagName) {
 			fullNode.SetAsMiner(true)
@@ -117,12 +134,47 @@ func registerChainService(cfg *gcc.Config, n *node.Node, cliCtx *cli.Context) {
 
 		if cliCtx.Bool(flags.ValidatorFlagName) {
 			fullNode.SetAsValidator()
+
+			if path := cliCtx.String(flags.LBFTTraceFlagName); path != "" {
+				if err := fullNode.TraceLBFT2(path); err != nil {
+					log.Fatalf("Failed to open the lbft trace file: %v", err)
+				}
+			}
+		}
+
+		if err == nil && cliCtx.Bool(flags.LightServeFlagName) {
+			fullNode.AddLesServer(les.NewServer(fullNode.BlockChain(), cfg.NetworkId))
 		}
 		return fullNode, err
 	})
 	if err != nil {
 		log.Fatalf("Failed to register the chain service: %v", err)
 	}
+
+	if cliCtx.Bool(flags.GraphQLFlagName) {
+		// services are constructed in order, the backend is set when the GraphQL service is constructed
+		err := n.Register(func(ctx *node.ServiceContext) (node.Service, error) {
+			return graphql.New(backend)
+		})
+		if err != nil {
+			log.Fatalf("Failed to register the GraphQL service: %v", err)
+		}
+	}
+}
+
+// Register chain services for a *light* node.
+func registerLightService(cfg *gcc.Config, n *node.Node) {
+	err := n.Register(func(ctx *node.ServiceContext) (node.Service, error) {
+		return les.New(ctx, &les.Config{
+			Genesis:         cfg.Genesis,
+			NetworkId:       cfg.NetworkId,
+			DatabaseCache:   cfg.DatabaseCache,
+			DatabaseHandles: cfg.DatabaseHandles,
+		})
+	})
+	if err != nil {
+		log.Fatalf("Failed to register the light service: %v", err)
+	}
 }
 
 // Creates a node with chain services registered
@@ -132,6 +184,13 @@ func createNode(ctx *cli.Context) *node.Node {
 	return n
 }
 
+// Creates a node with light client services registered
+func createLightNode(ctx *cli.Context) *node.Node {
+	cfg, n := newConfigNode(ctx)
+	registerLightService(&cfg.Gcc, n)
+	return n
+}
+
 // Starts up the node
 func startNode(n *node.Node) {
 	// launch the node itself
@@ -238,6 +297,14 @@ func setupMining(ctx *cli.Context, n *node.Node, key *keystore.Key) {
 	}
 
 	gcchainService.AdmissionApiBackend.SetAdmissionKey(key)
+	if key == nil && ctx.IsSet(flags.SignerFlagName) {
+		// the external signer holds the key, signing the campaign txs
+		if wallet, err := findCoinbaseWallet(n, gcchainService); err != nil {
+			log.Warn("Coinbase is not held by the external signer", "err", err)
+		} else {
+			gcchainService.AdmissionApiBackend.SetAdmissionWallet(wallet)
+		}
+	}
 	if configs.IgnoreNetworkStatusCheck {
 		gcchainService.AdmissionApiBackend.IgnoreNetworkCheck()
 	}
@@ -255,6 +322,15 @@ func setupMining(ctx *cli.Context, n *node.Node, key *keystore.Key) {
 	}
 }
 
+// findCoinbaseWallet returns the wallet holding the coinbase of the node.
+func findCoinbaseWallet(n *node.Node, gcchainService *gcc.gcchainService) (accounts.Wallet, error) {
+	coinbase, err := gcchainService.Coinbase()
+	if err != nil {
+		return nil, err
+	}
+	return n.AccountManager().Find(accounts.Account{Address: coinbase})
+}
+
 func handleInterrupt(n *node.Node) {
 	sigc := make(chan os.Signal, 1)
 	signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
@@ -299,6 +375,9 @@ func bootstrap(ctx *cli.Context, n *node.Node) {
 	if ctx.IsSet(flags.MetricGatewayFlagName) {
 		chainmetrics.InitMetrics(ctx.String(flags.PortFlagName), ctx.String(flags.MetricGatewayFlagName))
 	}
+	if ctx.Bool(flags.MetricsFlagName) {
+		chainmetrics.StartServer(ctx.String(flags.MetricsAddressFlagName))
+	}
 
 	startNode(n)
 	key := unlockAccounts(ctx, n)
@@ -307,3 +386,31 @@ func bootstrap(ctx *cli.Context, n *node.Node) {
 	// handle user interrupt
 	go handleInterrupt(n)
 }
+
+// bootstrapLight starts a light node, which has no miner or validator to set up.
+func bootstrapLight(ctx *cli.Context, n *node.Node) {
+	if ctx.IsSet(flags.ProfileFlagName) {
+		if err := profile.Start(ctx); err != nil {
+			log.Fatalf("start profiling failed: %v\n", err)
+		}
+	}
+
+	startNode(n)
+	handleWallet(n)
+
+	go func() {
+		sigc := make(chan os.Signal, 1)
+		signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+		defer signal.Stop(sigc)
+
+		<-sigc
+		log.Info("Got interrupt, shutting down...")
+		go n.Stop()
+		for i := 10; i > 0; i-- {
+			<-sigc
+			if i > 1 {
+				log.Warn("Already shutting down, interrupt more to panic.", "times", i-1)
+			}
+		}
+	}()
+}
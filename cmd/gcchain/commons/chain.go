@@ -8,14 +8,18 @@ import (
 	"io"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strings"
 	"syscall"
+	"time"
 
+	"github.com/gcchains/chain/cmd/gcchain/flags"
 	"github.com/gcchains/chain/commons/log"
 	"github.com/gcchains/chain/configs"
 	"github.com/gcchains/chain/consensus"
 	"github.com/gcchains/chain/consensus/dpos"
 	"github.com/gcchains/chain/core"
+	"github.com/gcchains/chain/core/chainexport"
 	"github.com/gcchains/chain/core/rawdb"
 	"github.com/gcchains/chain/core/vm"
 	"github.com/gcchains/chain/database"
@@ -40,7 +44,8 @@ func MakeChainDatabase(ctx *cli.Context, n *node.Node, databaseCache int) databa
 	name := "chaindata"
 	handles := makeDatabaseHandles()
 
-	chainDb, err := n.OpenDatabase(name, databaseCache, handles)
+	// blocks are only moved to the freezer by a running node
+	chainDb, err := n.OpenDatabaseWithFreezer(name, databaseCache, handles, ctx.String(flags.AncientDirFlagName), 0)
 	if err != nil {
 		log.Fatalf("Could not open database: %v", err)
 	}
@@ -229,8 +234,61 @@ func ExportChainN(blockchain *core.BlockChain, fn string, first, last uint64) er
 	return nil
 }
 
+// ExportChainSegments exports a range of the blockchain into segments in the directory,
+// resuming a previous export into it. Ctrl-C stops the export after the current segment.
+func ExportChainSegments(blockchain *core.BlockChain, dir string, config chainexport.ExportConfig) error {
+	log.Info("Exporting blockchain segments", "dir", dir, "first", config.First, "last", config.Last)
+
+	stop, release := interruptChannel("export")
+	defer release()
+	manifest, err := chainexport.Export(blockchain, dir, config, stop)
+	if err != nil {
+		return err
+	}
+	log.Info("Exported blockchain segments", "dir", dir, "segments", len(manifest.Segments))
+	return nil
+}
+
+// ImportChainSegments imports the segments exported into the directory, verified against
+// the checkpoints. Ctrl-C stops the import at the next segment or batch.
+func ImportChainSegments(chain *core.BlockChain, dir string, checkpoints []string) error {
+	var trusted []chainexport.Checkpoint
+	for _, s := range checkpoints {
+		parsed, err := chainexport.ParseCheckpoint(s)
+		if err != nil {
+			return err
+		}
+		trusted = append(trusted, parsed...)
+	}
+	log.Info("Importing blockchain segments", "dir", dir, "checkpoints", len(trusted))
+
+	stop, release := interruptChannel("import")
+	defer release()
+	imported, err := chainexport.Import(chain, dir, trusted, stop)
+	log.Info("Imported blockchain segments", "blocks", imported)
+	return err
+}
+
+// interruptChannel returns a channel closed on SIGINT or SIGTERM and a function
+// releasing the signal handler.
+func interruptChannel(operation string) (<-chan struct{}, func()) {
+	interrupt := make(chan os.Signal, 1)
+	stop := make(chan struct{})
+	signal.Notify(interrupt, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		if _, ok := <-interrupt; ok {
+			log.Info("Interrupted, stopping at the next segment", "operation", operation)
+		}
+		close(stop)
+	}()
+	return stop, func() {
+		signal.Stop(interrupt)
+		close(interrupt)
+	}
+}
+
 // ImportPreimages imports a batch of exported hash preimages into the database.
-func ImportPreimages(db *database.LDBDatabase, fn string) error {
+func ImportPreimages(db database.Database, fn string) error {
 	log.Info("Importing preimages", "file", fn)
 
 	// Open the file handle and potentially unwrap the gzip stream
@@ -277,7 +335,7 @@ func ImportPreimages(db *database.LDBDatabase, fn string) error {
 
 // ExportPreimages exports all known hash preimages into the specified file,
 // truncating any data already present in the file.
-func ExportPreimages(db *database.LDBDatabase, fn string) error {
+func ExportPreimages(db database.Database, fn string) error {
 	log.Info("Exporting preimages", "file", fn)
 
 	// Open the file handle and potentially wrap with a gzip stream
@@ -293,14 +351,122 @@ func ExportPreimages(db *database.LDBDatabase, fn string) error {
 		defer writer.(*gzip.Writer).Close()
 	}
 	// Iterate over the preimages and export them
-	it := db.NewIteratorWithPrefix([]byte("secure-key-"))
+	it := db.NewIterator([]byte("secure-key-"), nil)
+	defer it.Release()
+
 	for it.Next() {
 		if err := rlp.Encode(writer, it.Value()); err != nil {
 			return err
 		}
 	}
+	if err := it.Error(); err != nil {
+		return err
+	}
 	log.Info("Exported preimages", "file", fn)
 	return nil
 }
 
+// MigrateChainDatabase copies the database in dir to a new one with the engine and verifies that
+// the copy has the same head block. The new database takes the place of the original, which is
+// kept as a backup next to it. The node must not be running.
+func MigrateChainDatabase(dir string, engine string, cache int) (copied int, backup string, err error) {
+	from := database.DetectEngine(dir)
+	if from == "" {
+		return 0, "", fmt.Errorf("no database in %s", dir)
+	}
+	if from == engine {
+		return 0, "", fmt.Errorf("database %s already uses engine %s", dir, engine)
+	}
+	backup = dir + "." + from
+	if common.FileExist(backup) {
+		return 0, "", fmt.Errorf("backup %s already exists", backup)
+	}
+	// Remove the leftovers of an interrupted migration
+	tmp := dir + ".migrating"
+	if err := os.RemoveAll(tmp); err != nil {
+		return 0, "", err
+	}
+	src, err := database.Open(dir, from, cache, makeDatabaseHandles())
+	if err != nil {
+		return 0, "", err
+	}
+	dst, err := database.Open(tmp, engine, cache, makeDatabaseHandles())
+	if err != nil {
+		src.Close()
+		return 0, "", err
+	}
+	copied, err = copyDatabase(dst, src)
+	if err == nil {
+		err = verifyDatabaseHead(dst, src)
+	}
+	src.Close()
+	dst.Close()
+	if err != nil {
+		return copied, "", err
+	}
+	// The freezer is not part of the key-value store, it moves along unchanged
+	if ancient := filepath.Join(dir, node.DefaultFreezerDir); common.FileExist(ancient) {
+		if err := os.Rename(ancient, filepath.Join(tmp, node.DefaultFreezerDir)); err != nil {
+			return copied, "", err
+		}
+	}
+	if err := os.Rename(dir, backup); err != nil {
+		return copied, "", err
+	}
+	return copied, backup, os.Rename(tmp, dir)
+}
 
+// copyDatabase copies all entries of src to dst in batches.
+func copyDatabase(dst, src database.Database) (int, error) {
+	it := src.NewIterator(nil, nil)
+	defer it.Release()
+
+	var (
+		batch  = dst.NewBatch()
+		copied int
+		logged = time.Now()
+	)
+	for it.Next() {
+		if err := batch.Put(it.Key(), it.Value()); err != nil {
+			return copied, err
+		}
+		copied++
+		if batch.ValueSize() >= database.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return copied, err
+			}
+			batch.Reset()
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Copying database", "entries", copied, "key", common.Bytes2Hex(it.Key()))
+			logged = time.Now()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return copied, err
+	}
+	return copied, batch.Write()
+}
+
+// verifyDatabaseHead checks that dst has the head header and block of src.
+func verifyDatabaseHead(dst, src database.Database) error {
+	if head := rawdb.ReadHeadHeaderHash(src); rawdb.ReadHeadHeaderHash(dst) != head {
+		return fmt.Errorf("head header mismatch, have %x, want %x", rawdb.ReadHeadHeaderHash(dst), head)
+	}
+	head := rawdb.ReadHeadBlockHash(src)
+	if rawdb.ReadHeadBlockHash(dst) != head {
+		return fmt.Errorf("head block mismatch, have %x, want %x", rawdb.ReadHeadBlockHash(dst), head)
+	}
+	if head == (common.Hash{}) {
+		return nil
+	}
+	number := rawdb.ReadHeaderNumber(dst, head)
+	if number == nil {
+		return fmt.Errorf("number of head block %x missing", head)
+	}
+	if block := rawdb.ReadBlock(dst, head, *number); block == nil || block.Hash() != head {
+		return fmt.Errorf("head block #%d [%x] missing", *number, head)
+	}
+	log.Info("Verified database head", "number", *number, "hash", head)
+	return nil
+}
@@ -14,7 +14,7 @@ import (
 	"github.com/gcchains/chain/protocols/gcc/syncer"
 
 	"github.com/gcchains/chain/accounts"
-	"github.com/gcchains/chain/accounts/keystore"
+	"github.com/gcchains/chain/api/rpc"
 	"github.com/gcchains/chain/cmd/gcchain/flags"
 	"github.com/gcchains/chain/commons/log"
 	"github.com/gcchains/chain/configs"
@@ -186,6 +186,14 @@ func updateRpcConfig(ctx *cli.Context, cfg *node.Config) {
 	if ctx.IsSet(flags.RpcCorsDomainFlagName) {
 		cfg.HTTPCors = strings.Split(ctx.String(flags.RpcCorsDomainFlagName), ",")
 	}
+
+	// authentication, keeping the policies of the config file
+	if ctx.IsSet(flags.RpcAuthSecretFlagName) {
+		if cfg.RPCAuth == nil {
+			cfg.RPCAuth = new(rpc.AuthConfig)
+		}
+		cfg.RPCAuth.SecretFile = ctx.String(flags.RpcAuthSecretFlagName)
+	}
 }
 
 func updateNodeConfig(ctx *cli.Context, cfg *node.Config) {
@@ -196,12 +204,19 @@ func updateNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	if ctx.IsSet(flags.LightKdfFlagName) {
 		cfg.UseLightweightKDF = ctx.Bool(flags.LightKdfFlagName)
 	}
+	if ctx.IsSet(flags.SignerFlagName) {
+		cfg.ExternalSigner = ctx.String(flags.SignerFlagName)
+		cfg.ExternalSignerSecret = ctx.String(flags.SignerSecretFlagName)
+	}
+	if ctx.IsSet(flags.DBEngineFlagName) {
+		cfg.DBEngine = ctx.String(flags.DBEngineFlagName)
+	}
 }
 
 // begin chain configs
 
 // Updates the account for cfg.Coinbase
-func updateBaseAccount(ctx *cli.Context, ks *keystore.KeyStore, cfg *gcc.Config) {
+func updateBaseAccount(ctx *cli.Context, am *accounts.Manager, cfg *gcc.Config) {
 	if ctx.IsSet("account") {
 		val := ctx.String("account")
 		if !common.IsHexAddress(val) {
@@ -211,8 +226,11 @@ func updateBaseAccount(ctx *cli.Context, ks *keystore.KeyStore, cfg *gcc.Config)
 		cfg.Gccbase = account.Address
 	} else {
 		isRunCommand := ctx.Command.Name == runCommand.Name
-		// fall back on the first account
-		accs := ks.Accounts()
+		// fall back on the first account, the wallets of an external signer sort first
+		var accs []accounts.Account
+		for _, wallet := range am.Wallets() {
+			accs = append(accs, wallet.Accounts()...)
+		}
 		if len(accs) > 0 {
 			account := accs[0].Address
 			cfg.Gccbase = account
@@ -237,6 +255,9 @@ func updateTxPool(ctx *cli.Context, cfg *core.TxPoolConfig) {
 		cfg.IsFifoTxQueue = ctx.Bool(flags.FifoTxPoolQueue)
 
 	}
+	if ctx.IsSet(flags.TxPoolQuotaFlagName) {
+		cfg.AccountQuota = ctx.Uint64(flags.TxPoolQuotaFlagName)
+	}
 }
 
 func updateChainGeneralConfig(ctx *cli.Context, cfg *gcc.Config) {
@@ -268,12 +289,17 @@ func updateChainGeneralConfig(ctx *cli.Context, cfg *gcc.Config) {
 func updateChainConfig(ctx *cli.Context, cfg *gcc.Config, n *node.Node) {
 	updateChainGeneralConfig(ctx, cfg)
 	// passing in a node, all for this.  a pity.
-	ks := n.AccountManager().Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
-	updateBaseAccount(ctx, ks, cfg)
+	updateBaseAccount(ctx, n.AccountManager(), cfg)
 	// setGPO(ctx, &cfg.GPO)
 	updateTxPool(ctx, &cfg.TxPool)
+	if ctx.IsSet(flags.TxReplaceFeeCapFlagName) {
+		cfg.TxReplaceFeeCap = ctx.Float64(flags.TxReplaceFeeCapFlagName)
+	}
 	updateDatabaseCache(ctx, cfg)
 	updateTrieCache(ctx, cfg)
+	updateStateRetention(ctx, cfg)
+	updateAddressIndex(ctx, cfg)
+	updateAncient(ctx, cfg)
 }
 
 // updateDatabaseCache updates database cache.
@@ -290,11 +316,38 @@ func updateTrieCache(ctx *cli.Context, cfg *gcc.Config) {
 	}
 }
 
+// updateStateRetention updates the number of recent blocks whose state is kept.
+func updateStateRetention(ctx *cli.Context, cfg *gcc.Config) {
+	if ctx.IsSet(flags.StateRetentionFlagName) {
+		cfg.StateRetention = ctx.Uint64(flags.StateRetentionFlagName)
+	}
+}
+
+// updateAddressIndex enables the address index of transactions.
+func updateAddressIndex(ctx *cli.Context, cfg *gcc.Config) {
+	if ctx.IsSet(flags.AddressIndexFlagName) {
+		cfg.AddressIndex = ctx.Bool(flags.AddressIndexFlagName)
+	}
+}
+
+// updateAncient updates the freezer of ancient blocks.
+func updateAncient(ctx *cli.Context, cfg *gcc.Config) {
+	if ctx.IsSet(flags.AncientThresholdFlagName) {
+		cfg.AncientThreshold = ctx.Uint64(flags.AncientThresholdFlagName)
+	}
+	if ctx.IsSet(flags.AncientDirFlagName) {
+		cfg.DatabaseFreezer = ctx.String(flags.AncientDirFlagName)
+	}
+}
+
 // updateTrieCache updates trie cache.
 func updateSyncModeFlag(ctx *cli.Context, cfg *gcc.Config) {
 	if ctx.IsSet(flags.FastSyncFlagName) {
 		cfg.SyncMode = syncer.FastSync
 	}
+	if ctx.IsSet(flags.SnapshotSyncFlagName) {
+		cfg.SyncMode = syncer.SnapshotSync
+	}
 }
 
 // Updates config from --config file
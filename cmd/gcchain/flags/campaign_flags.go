@@ -3,6 +3,7 @@ package flags
 import (
 	"errors"
 
+	"github.com/gcchains/chain/api/rpc"
 	"github.com/gcchains/chain/configs"
 	"github.com/gcchains/chain/tools/utility"
 	"github.com/urfave/cli"
@@ -24,6 +25,14 @@ var RPCFlags = []cli.Flag{
 		Usage: "Set the APIs offered over the HTTP-RPC interface",
 		Value: "http://127.0.0.1:8501",
 	},
+	cli.StringFlag{
+		Name:  RpcAuthSecretFlagName,
+		Usage: "Shared secret file to authenticate to the RPC interface with",
+	},
+	cli.StringFlag{
+		Name:  RpcAuthTokenFlagName,
+		Usage: "Token issued by the node operator to authenticate to the RPC interface with",
+	},
 }
 
 // campaignAccountFlags include account params
@@ -81,3 +90,18 @@ func Validator(ctx *cli.Context) (string, string, string, error) {
 	}
 	return rpc, kspath, pwdfile, nil
 }
+
+// RpcAuth returns the credentials to authenticate to the RPC interface with, or nil if none are set.
+func RpcAuth(ctx *cli.Context) (rpc.AuthProvider, error) {
+	if token := ctx.String(RpcAuthTokenFlagName); token != "" {
+		return rpc.NewTokenAuth(token), nil
+	}
+	if path := ctx.String(RpcAuthSecretFlagName); path != "" {
+		secret, err := rpc.ReadAuthSecret(path)
+		if err != nil {
+			return nil, err
+		}
+		return rpc.NewHMACAuth(secret), nil
+	}
+	return nil, nil
+}
@@ -4,8 +4,10 @@ package flags
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/gcchains/chain/commons/log"
+	"github.com/gcchains/chain/database"
 	"github.com/gcchains/chain/node"
 	"github.com/urfave/cli"
 )
@@ -80,9 +82,11 @@ var GeneralFlags = []cli.Flag{
 }
 
 const (
-	PasswordFlagName = "password"
-	LightKdfFlagName = "lightkdf"
-	UnlockFlagName   = "unlock"
+	PasswordFlagName     = "password"
+	LightKdfFlagName     = "lightkdf"
+	UnlockFlagName       = "unlock"
+	SignerFlagName       = "signer"
+	SignerSecretFlagName = "signer.secret"
 )
 
 var AccountFlags = []cli.Flag{
@@ -101,16 +105,31 @@ var AccountFlags = []cli.Flag{
 		Usage: "Comma separated list of accounts to unlock",
 		Value: "",
 	},
+	cli.StringFlag{
+		Name:  SignerFlagName,
+		Usage: "External signer holding the keys of the node, an IPC path or an HTTP URL, see tools/signer",
+	},
+	cli.StringFlag{
+		Name:  SignerSecretFlagName,
+		Usage: "Shared secret file authenticating the node to the external signer over HTTP",
+	},
 }
 
 const (
-	NetworkIDFlagName     = "networkid"
-	NoCompactionFlagName  = "nocompaction"
-	CacheFlagName         = "cache"
-	CacheDatabaseFlagName = "cache.database"
-	CacheGCFlagName       = "cache.gc"
-	MaxTxMapSizeFlagName  = "txpoolsize"
-	FifoTxPoolQueue       = "fifotxpool"
+	NetworkIDFlagName        = "networkid"
+	NoCompactionFlagName     = "nocompaction"
+	CacheFlagName            = "cache"
+	CacheDatabaseFlagName    = "cache.database"
+	CacheGCFlagName          = "cache.gc"
+	StateRetentionFlagName   = "state.retention"
+	AddressIndexFlagName     = "addrindex"
+	AncientThresholdFlagName = "ancient.threshold"
+	AncientDirFlagName       = "ancient.dir"
+	DBEngineFlagName         = "dbengine"
+	MaxTxMapSizeFlagName     = "txpoolsize"
+	FifoTxPoolQueue          = "fifotxpool"
+	TxPoolQuotaFlagName      = "txpoolquota"
+	TxReplaceFeeCapFlagName  = "txreplacefeecap"
 )
 
 var ChainFlags = []cli.Flag{
@@ -141,6 +160,26 @@ var ChainFlags = []cli.Flag{
 		Usage: "Percentage of cache memory allowance to use for trie pruning",
 		Value: 25,
 	},
+	cli.Uint64Flag{
+		Name:  StateRetentionFlagName,
+		Usage: "Number of recent blocks whose state is kept, older states except term checkpoints are pruned (0 = keep all)",
+	},
+	cli.StringFlag{
+		Name:  DBEngineFlagName,
+		Usage: fmt.Sprintf("Engine of the chain database created by init (%s), existing databases keep theirs", strings.Join(database.Engines, "|")),
+	},
+	cli.BoolFlag{
+		Name:  AddressIndexFlagName,
+		Usage: "Maintain an index of the transactions of every address for gcc_getTransactionsByAddress",
+	},
+	cli.Uint64Flag{
+		Name:  AncientThresholdFlagName,
+		Usage: "Number of recent blocks kept in the chain database, older blocks are moved to the freezer (0 = disabled)",
+	},
+	cli.StringFlag{
+		Name:  AncientDirFlagName,
+		Usage: "Directory of the freezer for ancient blocks (default = inside the chain database)",
+	},
 	cli.IntFlag{
 		Name:  MaxTxMapSizeFlagName,
 		Usage: "Maximum number of pending transactions",
@@ -150,11 +189,22 @@ var ChainFlags = []cli.Flag{
 		Name:  FifoTxPoolQueue,
 		Usage: "Use FIFO tx pool queue",
 	},
+	cli.Uint64Flag{
+		Name:  TxPoolQuotaFlagName,
+		Usage: "Maximum number of pending and queued transactions of a remote account (0 = no quota)",
+		Value: 2048,
+	},
+	cli.Float64Flag{
+		Name:  TxReplaceFeeCapFlagName,
+		Usage: "Maximum fee in gcc of a transaction replaced by txpool_replace (0 = no cap)",
+		Value: 1,
+	},
 }
 
 const (
 	MineFlagName      = "mine"
 	ValidatorFlagName = "validator"
+	LBFTTraceFlagName = "lbfttrace"
 )
 
 var MinerFlags = []cli.Flag{
@@ -166,10 +216,17 @@ var MinerFlags = []cli.Flag{
 		Name:  ValidatorFlagName,
 		Usage: "Enable validator",
 	},
+	cli.StringFlag{
+		Name:  LBFTTraceFlagName,
+		Usage: "Record the steps of the lbft state machine of the validator to the file, to be checked against tlaplus/lbft.tla by lbfttrace",
+	},
 }
 
 const (
-	FastSyncFlagName = "fast"
+	FastSyncFlagName     = "fast"
+	SnapshotSyncFlagName = "snapshot"
+	LightFlagName        = "light"
+	LightServeFlagName   = "lightserve"
 )
 
 var SyncFlags = []cli.Flag{
@@ -177,6 +234,18 @@ var SyncFlags = []cli.Flag{
 		Name:  FastSyncFlagName,
 		Usage: "Enable fast sync",
 	},
+	cli.BoolFlag{
+		Name:  SnapshotSyncFlagName,
+		Usage: "Enable snapshot sync, which downloads the state snapshot at a term checkpoint",
+	},
+	cli.BoolFlag{
+		Name:  LightFlagName,
+		Usage: "Run as a light client, which syncs headers verified by committee signatures and retrieves state on demand",
+	},
+	cli.BoolFlag{
+		Name:  LightServeFlagName,
+		Usage: "Serve light clients with headers and state proofs",
+	},
 }
 
 const (
@@ -185,6 +254,9 @@ const (
 	// these two flags should be removed in the future
 	RpcCorsDomainFlagName = "rpccorsdomain"
 	RpcApiFlagName        = "rpcapi"
+	RpcAuthSecretFlagName = "rpcauthsecret"
+	RpcAuthTokenFlagName  = "rpcauthtoken"
+	GraphQLFlagName       = "graphql"
 )
 
 // TODO @sangh adjust these
@@ -207,6 +279,14 @@ var RpcFlags = []cli.Flag{
 		Name:  RpcCorsDomainFlagName,
 		Usage: "Comma separated list of domains from which to accept cross origin requests (browser enforced)",
 	},
+	cli.StringFlag{
+		Name:  RpcAuthSecretFlagName,
+		Usage: "Shared secret file authenticating the clients of the HTTP and websocket RPC interfaces",
+	},
+	cli.BoolFlag{
+		Name:  GraphQLFlagName,
+		Usage: "Serve GraphQL queries of chain data on the HTTP-RPC interface under /graphql",
+	},
 }
 
 const (
@@ -256,6 +336,8 @@ const (
 	ProfileFlagName        = "profile"
 	ProfileAddressFlagName = "profileaddr"
 	MetricGatewayFlagName  = "metricgateway"
+	MetricsFlagName        = "metrics"
+	MetricsAddressFlagName = "metricsaddr"
 )
 
 var NodeFlags = []cli.Flag{
@@ -276,6 +358,15 @@ var NodeFlags = []cli.Flag{
 		Usage: "Metric Gateway Address",
 		Value: "",
 	},
+	cli.BoolFlag{
+		Name:  MetricsFlagName,
+		Usage: "Serve metrics at http://metricsaddr/metrics for Prometheus, go-ethereum meters are collected only if the flag is given as --metrics",
+	},
+	cli.StringFlag{
+		Name:  MetricsAddressFlagName,
+		Usage: "Metrics http service address",
+		Value: "localhost:6060",
+	},
 }
 
 var MiscFlags = []cli.Flag{}
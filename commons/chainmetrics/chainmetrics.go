@@ -36,8 +36,9 @@ func InitMetrics(port, gatewayURL string) {
 	log.Debug("InitMetrics", "chainId", chainId, "gatewayAddress", gatewayAddress)
 }
 
+// NeedMetrics returns whether the gauges are pushed to the Pushgateway or served at the /metrics endpoint.
 func NeedMetrics() bool {
-	return gatewayAddress != ""
+	return gatewayAddress != "" || Serving()
 }
 
 func ReportBlockNumberGauge(exportedJob string, blockNumber float64) {
@@ -56,6 +57,9 @@ func ReportInsertionElapsedTime(exportedJob string, elapsed float64) {
 }
 
 func reportGauge(monitorURL, exportedJob, host string, gauge prometheus.Gauge) {
+	if monitorURL == "" {
+		return
+	}
 	if err := push.New(monitorURL, exportedJob).
 		Collector(gauge).
 		Grouping("host", host).
@@ -0,0 +1,123 @@
+package chainmetrics
+
+import (
+	"net/http"
+	"regexp"
+	"sync/atomic"
+	"time"
+
+	"github.com/gcchains/chain/commons/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry is the registry of the metrics exported at the /metrics endpoint. The gauges pushed to the Pushgateway and
+// the metrics of go-ethereum/metrics.DefaultRegistry are exported too.
+var Registry = prometheus.NewRegistry()
+
+// serving is 1 once the /metrics endpoint is started
+var serving int32
+
+// quantiles of the timers and histograms of go-ethereum/metrics exported as summaries
+var quantiles = []float64{0.5, 0.75, 0.95, 0.99}
+
+func init() {
+	Registry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		blockNumberCounter,
+		txsNumberCounter,
+		insertionElapsedTime,
+		NewGethCollector(metrics.DefaultRegistry),
+	)
+}
+
+// Register registers the collector to the Registry, logging instead of panicking if it fails, as collectors of the same
+// metrics are registered more than once if several nodes run in a process.
+func Register(c prometheus.Collector) {
+	if err := Registry.Register(c); err != nil {
+		log.Debug("failed to register metrics collector", "err", err)
+	}
+}
+
+// Unregister unregisters the collector from the Registry.
+func Unregister(c prometheus.Collector) {
+	Registry.Unregister(c)
+}
+
+// Serving returns whether the /metrics endpoint is started.
+func Serving() bool {
+	return atomic.LoadInt32(&serving) == 1
+}
+
+// StartServer starts to serve the metrics of the Registry at http://address/metrics.
+//
+// The meters and timers of go-ethereum/metrics are only collected if the node is started with --metrics, the flag
+// go-ethereum/metrics looks for in os.Args.
+func StartServer(address string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(Registry, promhttp.HandlerOpts{}))
+	atomic.StoreInt32(&serving, 1)
+
+	log.Info("Starting metrics server", "addr", "http://"+address+"/metrics", "go-ethereum metrics", metrics.Enabled)
+	go func() {
+		if err := http.ListenAndServe(address, mux); err != nil {
+			log.Error("Failure in running metrics server", "err", err)
+		}
+	}()
+}
+
+var invalidNameChars = regexp.MustCompile("[^a-zA-Z0-9_]")
+
+// GethCollector exports the metrics of a go-ethereum/metrics registry: counters and meters as counters, gauges as
+// gauges, timers and histograms as summaries. Timers are in seconds.
+type GethCollector struct {
+	registry metrics.Registry
+}
+
+// NewGethCollector returns a collector of the metrics in registry.
+func NewGethCollector(registry metrics.Registry) *GethCollector {
+	return &GethCollector{registry: registry}
+}
+
+// Describe implements prometheus.Collector, the metrics are unchecked as they are registered at runtime.
+func (c *GethCollector) Describe(ch chan<- *prometheus.Desc) {}
+
+// Collect implements prometheus.Collector
+func (c *GethCollector) Collect(ch chan<- prometheus.Metric) {
+	c.registry.Each(func(name string, i interface{}) {
+		name = invalidNameChars.ReplaceAllString(name, "_")
+
+		switch m := i.(type) {
+		case metrics.Counter:
+			ch <- constMetric(name, prometheus.CounterValue, float64(m.Count()))
+		case metrics.Meter:
+			ch <- constMetric(name, prometheus.CounterValue, float64(m.Snapshot().Count()))
+		case metrics.Gauge:
+			ch <- constMetric(name, prometheus.GaugeValue, float64(m.Value()))
+		case metrics.GaugeFloat64:
+			ch <- constMetric(name, prometheus.GaugeValue, m.Value())
+		case metrics.Timer:
+			t := m.Snapshot()
+			ch <- constSummary(name+"_seconds", t.Count(), float64(t.Sum())/float64(time.Second), t.Percentiles(quantiles), float64(time.Second))
+		case metrics.Histogram:
+			h := m.Snapshot()
+			ch <- constSummary(name, h.Count(), float64(h.Sum()), h.Percentiles(quantiles), 1)
+		}
+	})
+}
+
+func constMetric(name string, valueType prometheus.ValueType, value float64) prometheus.Metric {
+	desc := prometheus.NewDesc(name, name+" of go-ethereum/metrics", nil, nil)
+	return prometheus.MustNewConstMetric(desc, valueType, value)
+}
+
+func constSummary(name string, count int64, sum float64, percentiles []float64, unit float64) prometheus.Metric {
+	desc := prometheus.NewDesc(name, name+" of go-ethereum/metrics", nil, nil)
+	values := make(map[float64]float64, len(quantiles))
+	for i, q := range quantiles {
+		values[q] = percentiles[i] / unit
+	}
+	return prometheus.MustNewConstSummary(desc, uint64(count), sum, values)
+}
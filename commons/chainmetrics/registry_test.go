@@ -0,0 +1,49 @@
+package chainmetrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestGethCollector(t *testing.T) {
+	enabled := metrics.Enabled
+	metrics.Enabled = true
+	defer func() { metrics.Enabled = enabled }()
+
+	r := metrics.NewRegistry()
+	metrics.NewRegisteredCounter("eth/counter", r).Inc(3)
+	metrics.NewRegisteredGauge("eth/gauge", r).Update(7)
+	timer := metrics.NewRegisteredTimer("eth/timer", r)
+	timer.Update(2 * time.Second)
+	timer.Update(4 * time.Second)
+
+	expected := `
+# HELP eth_counter eth_counter of go-ethereum/metrics
+# TYPE eth_counter counter
+eth_counter 3
+# HELP eth_gauge eth_gauge of go-ethereum/metrics
+# TYPE eth_gauge gauge
+eth_gauge 7
+`
+	if err := testutil.CollectAndCompare(NewGethCollector(r), strings.NewReader(expected), "eth_counter", "eth_gauge"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected = `
+# HELP eth_timer_seconds eth_timer_seconds of go-ethereum/metrics
+# TYPE eth_timer_seconds summary
+eth_timer_seconds{quantile="0.5"} 3
+eth_timer_seconds{quantile="0.75"} 4
+eth_timer_seconds{quantile="0.95"} 4
+eth_timer_seconds{quantile="0.99"} 4
+eth_timer_seconds_sum 6
+eth_timer_seconds_count 2
+`
+	if err := testutil.CollectAndCompare(NewGethCollector(r), strings.NewReader(expected), "eth_timer_seconds"); err != nil {
+		t.Fatal(err)
+	}
+}
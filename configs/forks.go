@@ -0,0 +1,170 @@
+package configs
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// Fork is the name of a protocol upgrade activated at a block number
+type Fork string
+
+// Those are known forks
+const (
+	// Cep2 enables CHAINID and SELFBALANCE instructions, reprices non-zero tx data bytes
+	// and requires a fixed length of extra data in dpos headers
+	Cep2 Fork = "cep2"
+
+	// PrivateTx enables private transactions, their payloads are sealed for participants and
+	// executed against the private state of each participant
+	PrivateTx Fork = "privateTx"
+
+	// StorageProof enables the primitive contract at 0x6c validating storage proofs of admission
+	StorageProof Fork = "storageProof"
+)
+
+// KnownForks are all forks the node supports, in the order of activation
+var KnownForks = []Fork{Cep2, PrivateTx, StorageProof}
+
+// ForkSchedule maps forks to their activation block numbers, a fork not in the schedule is never activated
+type ForkSchedule map[Fork]uint64
+
+// UnknownForkError is returned if a fork schedule contains a fork the node does not support
+type UnknownForkError struct {
+	Name Fork
+}
+
+func (e *UnknownForkError) Error() string {
+	return fmt.Sprintf("unknown fork %q in fork schedule", e.Name)
+}
+
+// Validate checks that all scheduled forks are known
+func (s ForkSchedule) Validate() error {
+	for fork := range s {
+		if !isKnownFork(fork) {
+			return &UnknownForkError{Name: fork}
+		}
+	}
+	return nil
+}
+
+// blockOf returns the activation block of a fork, nil if the fork is not scheduled
+func (s ForkSchedule) blockOf(fork Fork) *big.Int {
+	if num, ok := s[fork]; ok {
+		return new(big.Int).SetUint64(num)
+	}
+	return nil
+}
+
+func isKnownFork(fork Fork) bool {
+	for _, f := range KnownForks {
+		if f == fork {
+			return true
+		}
+	}
+	return false
+}
+
+// IsForked returns whether the fork is active at the given block number
+func (c *ChainConfig) IsForked(fork Fork, num *big.Int) bool {
+	if c == nil || num == nil {
+		return false
+	}
+	block := c.Forks.blockOf(fork)
+	return block != nil && block.Cmp(num) <= 0
+}
+
+// IsCep2 returns whether num is either equal to the Cep2 fork block or greater.
+func (c *ChainConfig) IsCep2(num *big.Int) bool {
+	return c.IsForked(Cep2, num)
+}
+
+// IsPrivateTx returns whether num is either equal to the PrivateTx fork block or greater.
+func (c *ChainConfig) IsPrivateTx(num *big.Int) bool {
+	return c.IsForked(PrivateTx, num)
+}
+
+// IsStorageProof returns whether num is either equal to the StorageProof fork block or greater.
+func (c *ChainConfig) IsStorageProof(num *big.Int) bool {
+	return c.IsForked(StorageProof, num)
+}
+
+// CheckCompatible checks whether scheduled fork changes have been applied to a chain whose head is at height.
+// It returns an error if a fork activated at or below height is added, removed or moved.
+func (c *ChainConfig) CheckCompatible(newcfg *ChainConfig, height uint64) *ConfigCompatError {
+	head := new(big.Int).SetUint64(height)
+
+	var err *ConfigCompatError
+	for _, fork := range allForks(c.Forks, newcfg.Forks) {
+		stored, next := c.Forks.blockOf(fork), newcfg.Forks.blockOf(fork)
+		if !isForkIncompatible(stored, next, head) {
+			continue
+		}
+
+		// report the earliest incompatible fork, the chain must be rewound before it
+		compat := newCompatError(fmt.Sprintf("%s fork block", fork), stored, next)
+		if err == nil || compat.RewindTo < err.RewindTo {
+			err = compat
+		}
+	}
+	return err
+}
+
+// allForks returns forks in both schedules in a deterministic order
+func allForks(a, b ForkSchedule) []Fork {
+	set := make(map[Fork]struct{})
+	for fork := range a {
+		set[fork] = struct{}{}
+	}
+	for fork := range b {
+		set[fork] = struct{}{}
+	}
+
+	forks := make([]Fork, 0, len(set))
+	for fork := range set {
+		forks = append(forks, fork)
+	}
+	sort.Slice(forks, func(i, j int) bool { return forks[i] < forks[j] })
+	return forks
+}
+
+// isForkIncompatible returns true if a fork scheduled at s1 cannot be rescheduled to
+// block s2 because head is already past the fork.
+func isForkIncompatible(s1, s2, head *big.Int) bool {
+	return (isForked(s1, head) || isForked(s2, head)) && !configNumEqual(s1, s2)
+}
+
+// isForked returns whether a fork scheduled at block s is active at the given head block.
+func isForked(s, head *big.Int) bool {
+	if s == nil || head == nil {
+		return false
+	}
+	return s.Cmp(head) <= 0
+}
+
+func configNumEqual(x, y *big.Int) bool {
+	if x == nil {
+		return y == nil
+	}
+	if y == nil {
+		return x == nil
+	}
+	return x.Cmp(y) == 0
+}
+
+func newCompatError(what string, storedblock, newblock *big.Int) *ConfigCompatError {
+	var rew *big.Int
+	switch {
+	case storedblock == nil:
+		rew = newblock
+	case newblock == nil || storedblock.Cmp(newblock) < 0:
+		rew = storedblock
+	default:
+		rew = newblock
+	}
+	err := &ConfigCompatError{What: what, StoredConfig: storedblock, NewConfig: newblock, RewindTo: 0}
+	if rew != nil && rew.Sign() > 0 {
+		err.RewindTo = rew.Uint64() - 1
+	}
+	return err
+}
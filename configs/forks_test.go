@@ -0,0 +1,106 @@
+package configs
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestChainConfig_IsCep2(t *testing.T) {
+	config := &ChainConfig{Forks: ForkSchedule{Cep2: 10}}
+
+	tests := []struct {
+		config *ChainConfig
+		num    *big.Int
+		want   bool
+	}{
+		{config, big.NewInt(9), false},
+		{config, big.NewInt(10), true},
+		{config, big.NewInt(11), true},
+		{config, nil, false},
+		{&ChainConfig{}, big.NewInt(10), false},
+		{nil, big.NewInt(10), false},
+	}
+	for _, tt := range tests {
+		if got := tt.config.IsCep2(tt.num); got != tt.want {
+			t.Errorf("IsCep2(%v) = %v, want %v", tt.num, got, tt.want)
+		}
+	}
+
+	if rules := config.Rules(big.NewInt(10)); !rules.IsCep2 {
+		t.Errorf("Rules(%v).IsCep2 = false, want true", 10)
+	}
+}
+
+func TestForkSchedule_Validate(t *testing.T) {
+	if err := (ForkSchedule{Cep2: 1}).Validate(); err != nil {
+		t.Errorf("Validate() error = %v, want nil", err)
+	}
+	if err, ok := (ForkSchedule{"unknown": 1}).Validate().(*UnknownForkError); !ok || err.Name != "unknown" {
+		t.Errorf("Validate() error = %v, want an UnknownForkError", err)
+	}
+}
+
+func TestChainConfig_CheckCompatible(t *testing.T) {
+	tests := []struct {
+		stored, new *ChainConfig
+		head        uint64
+		wantErr     *ConfigCompatError
+	}{
+		{stored: &ChainConfig{}, new: &ChainConfig{}, head: 0, wantErr: nil},
+		{stored: &ChainConfig{}, new: &ChainConfig{}, head: 100, wantErr: nil},
+		{
+			// scheduling a future fork is compatible
+			stored:  &ChainConfig{},
+			new:     &ChainConfig{Forks: ForkSchedule{Cep2: 20}},
+			head:    10,
+			wantErr: nil,
+		},
+		{
+			// moving a fork which is not activated yet is compatible
+			stored:  &ChainConfig{Forks: ForkSchedule{Cep2: 20}},
+			new:     &ChainConfig{Forks: ForkSchedule{Cep2: 30}},
+			head:    10,
+			wantErr: nil,
+		},
+		{
+			stored: &ChainConfig{},
+			new:    &ChainConfig{Forks: ForkSchedule{Cep2: 20}},
+			head:   25,
+			wantErr: &ConfigCompatError{
+				What:         "cep2 fork block",
+				StoredConfig: nil,
+				NewConfig:    big.NewInt(20),
+				RewindTo:     19,
+			},
+		},
+		{
+			stored: &ChainConfig{Forks: ForkSchedule{Cep2: 20}},
+			new:    &ChainConfig{Forks: ForkSchedule{Cep2: 30}},
+			head:   25,
+			wantErr: &ConfigCompatError{
+				What:         "cep2 fork block",
+				StoredConfig: big.NewInt(20),
+				NewConfig:    big.NewInt(30),
+				RewindTo:     19,
+			},
+		},
+		{
+			stored: &ChainConfig{Forks: ForkSchedule{Cep2: 20}},
+			new:    &ChainConfig{},
+			head:   25,
+			wantErr: &ConfigCompatError{
+				What:         "cep2 fork block",
+				StoredConfig: big.NewInt(20),
+				NewConfig:    nil,
+				RewindTo:     19,
+			},
+		},
+	}
+	for _, tt := range tests {
+		err := tt.stored.CheckCompatible(tt.new, tt.head)
+		if !reflect.DeepEqual(err, tt.wantErr) {
+			t.Errorf("CheckCompatible(%v, %d) = %v, want %v", tt.new.Forks, tt.head, err, tt.wantErr)
+		}
+	}
+}
@@ -111,6 +111,8 @@ const (
 	ContractAdmission = "admission" // address of admission
 	ContractRnode     = "rnode"     // address of rnode
 	ContractNetwork   = "network"   // address of network
+
+	ContractGovernance = "governance" // address of governance, adopting parameters by votes of rnodes
 )
 
 // some version numbers
@@ -171,7 +173,7 @@ var (
 
 var (
 	// just for test
-	TestChainConfig = &ChainConfig{big.NewInt(DevChainId), &DposConfig{Period: 0, TermLen: 4}}
+	TestChainConfig = &ChainConfig{ChainID: big.NewInt(DevChainId), Dpos: &DposConfig{Period: 0, TermLen: 4}}
 )
 
 // this contains all the changes we have made to the gcchain protocol.
@@ -289,6 +291,8 @@ type ChainConfig struct {
 
 	// Various consensus engines
 	Dpos *DposConfig `json:"dpos,omitempty" toml:"dpos,omitempty"`
+
+	Forks ForkSchedule `json:"forks,omitempty" toml:"forks,omitempty"` // Activation block numbers of protocol upgrades
 }
 
 // DposConfig is the consensus engine configs for proof-of-authority based sealing.
@@ -301,6 +305,16 @@ type DposConfig struct {
 	Contracts             map[string]common.Address `json:"contracts"             toml:"contracts"`
 	ProxyContractRegister common.Address            `json:"proxyContractRegister" toml:"proxyContractRegister"`
 	ImpeachTimeout        time.Duration             `json:"impeachTimeout" toml:"impeachTimeout"`
+
+	// ElectionStrategies schedules election strategies by block range, the rpt strategy is used if it is empty
+	ElectionStrategies []ElectionStrategyConfig `json:"electionStrategies,omitempty" toml:"electionStrategies,omitempty"`
+}
+
+// ElectionStrategyConfig selects the election strategy used from a block number on,
+// until the FromBlock of the next item
+type ElectionStrategyConfig struct {
+	FromBlock uint64 `json:"fromBlock" toml:"fromBlock"`
+	Strategy  string `json:"strategy"  toml:"strategy"`
 }
 
 // String implements the stringer interface, returning the consensus engine details.
@@ -343,6 +357,22 @@ func (c *DposConfig) BlockDelay() time.Duration {
 	return time.Duration(0)
 }
 
+// ElectionStrategyAt returns the name of election strategy used at the given block number,
+// an empty string means the default one
+func (c *DposConfig) ElectionStrategyAt(number uint64) string {
+	if c == nil {
+		return ""
+	}
+
+	strategy, from := "", uint64(0)
+	for _, s := range c.ElectionStrategies {
+		if s.FromBlock <= number && s.FromBlock >= from {
+			strategy, from = s.Strategy, s.FromBlock
+		}
+	}
+	return strategy
+}
+
 // String implements the fmt.Stringer interface.
 func (c *ChainConfig) String() string {
 	var engine interface{}
@@ -400,6 +430,7 @@ func (err *ConfigCompatError) Error() string {
 type Rules struct {
 	ChainID   *big.Int
 	Isgcchain bool
+	IsCep2    bool
 }
 
 // Rules ensures c's ChainID is not nil.
@@ -408,5 +439,5 @@ func (c *ChainConfig) Rules(num *big.Int) Rules {
 	if chainID == nil {
 		chainID = new(big.Int)
 	}
-	return Rules{ChainID: new(big.Int).Set(chainID), Isgcchain: c.Isgcchain()}
+	return Rules{ChainID: new(big.Int).Set(chainID), Isgcchain: c.Isgcchain(), IsCep2: c.IsCep2(num)}
 }
@@ -24,6 +24,23 @@ func TestDposConfig(t *testing.T) {
 	fmt.Println("s:", string(s))
 }
 
+func TestDposConfig_ElectionStrategyAt(t *testing.T) {
+	dc := &DposConfig{
+		ElectionStrategies: []ElectionStrategyConfig{
+			{FromBlock: 100, Strategy: "stake"},
+			{FromBlock: 10, Strategy: "roundRobin"},
+		},
+	}
+
+	assert.Equal(t, "", dc.ElectionStrategyAt(9))
+	assert.Equal(t, "roundRobin", dc.ElectionStrategyAt(10))
+	assert.Equal(t, "roundRobin", dc.ElectionStrategyAt(99))
+	assert.Equal(t, "stake", dc.ElectionStrategyAt(100))
+
+	var nilConfig *DposConfig
+	assert.Equal(t, "", nilConfig.ElectionStrategyAt(100))
+}
+
 func TestCandidates(t *testing.T) {
 	SetRunMode(Dev)
 	addr := Candidates()
@@ -44,6 +44,8 @@ const (
 	MemoryGas        uint64 = 3     // Times the address of the (highest referenced byte in memory + 1). NOTE: referencing happens on read, write and in instructions such as RETURN and CALL.
 	TxDataNonZeroGas uint64 = 68    // Per byte of data attached to a transaction that is not equal to zero. NOTE: Not payable on data of calls between transactions.
 
+	TxDataNonZeroGasCep2 uint64 = 16 // Per byte of non zero data attached to a transaction after Cep2.
+
 	MaxCodeSize = 24576 // Maximum bytecode to permit for a contract
 
 	// Precompiled contract gas prices
@@ -62,8 +64,9 @@ const (
 	Bn256PairingPerPointGas uint64 = 80000  // Per-point price for an elliptic curve pairing check
 
 	// gcchain primitives
-	CpuPowValidateGas uint64 = 200 // Gas needed for CpuPowValidate, involving hash
-	MemPowValidateGas uint64 = 200 // Gas needed for MemPowValidate, involving hash
+	CpuPowValidateGas  uint64 = 200 // Gas needed for CpuPowValidate, involving hash
+	MemPowValidateGas  uint64 = 200 // Gas needed for MemPowValidate, involving hash
+	StorageValidateGas uint64 = 200 // Gas needed for StorageValidate, involving hash
 )
 
 const (
@@ -3,6 +3,7 @@ package dpos
 import (
 	"github.com/gcchains/chain/api/rpc"
 	"github.com/gcchains/chain/consensus"
+	"github.com/gcchains/chain/consensus/dpos/evidence"
 	"github.com/gcchains/chain/types"
 	"github.com/ethereum/go-ethereum/common"
 )
@@ -65,3 +66,24 @@ func (api *API) GetValidators(number rpc.BlockNumber) ([]common.Address, error)
 func (api *API) GetRNodes() ([]common.Address, error) {
 	return api.dpos.GetRNodes()
 }
+
+// GetEvidence retrieves known equivocation evidence, only the ones of given offender if it is not nil.
+func (api *API) GetEvidence(offender *common.Address) ([]*evidence.Evidence, error) {
+	pool := api.dpos.EvidencePool()
+	if pool == nil {
+		return nil, errDposProtocolNotWorking
+	}
+
+	all := pool.Evidence()
+	if offender == nil {
+		return all, nil
+	}
+
+	found := make([]*evidence.Evidence, 0)
+	for _, ev := range all {
+		if ev.Offender == *offender {
+			found = append(found, ev)
+		}
+	}
+	return found, nil
+}
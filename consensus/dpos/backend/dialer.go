@@ -373,6 +373,15 @@ func (d *Dialer) ValidatorsOfTerm(term uint64) map[common.Address]*RemoteValidat
 	return validators
 }
 
+// ConnectedOfTerm returns the numbers of connected proposers and validators of given term,
+// zeros if the dialer is not set up with a dpos service
+func (d *Dialer) ConnectedOfTerm(term uint64) (proposers int, validators int) {
+	if d.dpos == nil {
+		return 0, 0
+	}
+	return len(d.ProposersOfTerm(term)), len(d.ValidatorsOfTerm(term))
+}
+
 // IsDefaultValidator checks if a validator is a default validator
 func IsDefaultValidator(nodeID string, defaultValidators []string) bool {
 	for _, dv := range defaultValidators {
@@ -0,0 +1,101 @@
+package backend
+
+import (
+	"github.com/gcchains/chain/commons/log"
+	"github.com/gcchains/chain/consensus"
+	"github.com/gcchains/chain/consensus/dpos/evidence"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/p2p"
+)
+
+// handleEvidenceMsg handles an equivocation evidence gossiped by remote peer
+func (h *Handler) handleEvidenceMsg(msg p2p.Msg, p *RemoteSigner) error {
+	var ev *evidence.Evidence
+	if err := msg.Decode(&ev); err != nil {
+		return errResp(ErrDecode, "msg %v: %v", msg, err)
+	}
+
+	if h.dpos == nil || h.evidencePool.Has(ev.Hash()) {
+		return nil
+	}
+
+	added, err := h.evidencePool.Add(ev, h.dpos)
+	switch err {
+	case nil:
+
+	case evidence.ErrFutureEvidence, evidence.ErrStaleEvidence, evidence.ErrTooManyEvidence:
+		// the committee of the height is not known locally or the pool is full, it is not the fault of the peer
+		log.Debug("ignored evidence", "evidence", ev, "remote peer", p.Coinbase().Hex(), "err", err)
+		return nil
+
+	default:
+		// drop the peer sending invalid evidence, otherwise it floods the committee
+		log.Debug("received invalid evidence", "evidence", ev, "remote peer", p.Coinbase().Hex(), "err", err)
+		return errResp(ErrInvalidEvidence, "%v: %v", ev, err)
+	}
+
+	if added {
+		go h.BroadcastEvidence(ev, p.Coinbase())
+	}
+	return nil
+}
+
+// detectEquivocation observes the input of fsm before it drops conflicting msgs,
+// and broadcasts the evidence found
+func (h *Handler) detectEquivocation(input *BlockOrHeader, msgCode MsgCode) {
+	if h.detector == nil {
+		return
+	}
+
+	var found []*evidence.Evidence
+	switch msgCode {
+	case PreprepareMsgCode:
+		if input.IsBlock() {
+			found = h.detector.ObserveSeal(input.block.Header())
+		}
+
+	case PrepareMsgCode:
+		if input.IsHeader() {
+			found = h.detector.ObserveSigs(input.header, consensus.Prepare)
+		}
+
+	case CommitMsgCode:
+		if input.IsHeader() {
+			found = h.detector.ObserveSigs(input.header, consensus.Commit)
+		}
+
+	case ValidateMsgCode:
+		if input.IsBlock() {
+			found = append(h.detector.ObserveSeal(input.block.Header()), h.detector.ObserveSigs(input.block.Header(), consensus.Commit)...)
+		}
+	}
+
+	for _, ev := range found {
+		go h.BroadcastEvidence(ev, common.Address{})
+	}
+}
+
+// BroadcastEvidence broadcasts an evidence to remote proposers and validators except the given one
+func (h *Handler) BroadcastEvidence(ev *evidence.Evidence, except common.Address) {
+
+	log.Debug("broadcasting evidence", "evidence", ev)
+
+	term := h.dpos.TermOf(ev.Number)
+
+	signers := make(map[common.Address]*RemoteSigner)
+	for addr, proposer := range h.dialer.ProposersOfTerm(term) {
+		signers[addr] = proposer.RemoteSigner
+	}
+	for addr, validator := range h.dialer.ValidatorsOfTerm(term) {
+		signers[addr] = validator.RemoteSigner
+	}
+
+	for addr, signer := range signers {
+		if addr == except {
+			continue
+		}
+		if err := signer.SendEvidence(ev); err != nil {
+			log.Debug("failed to send evidence", "remote peer", addr.Hex(), "err", err)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/gcchains/chain/commons/log"
 	"github.com/gcchains/chain/configs"
 	"github.com/gcchains/chain/consensus"
+	"github.com/gcchains/chain/consensus/dpos/evidence"
 	"github.com/gcchains/chain/database"
 	"github.com/gcchains/chain/types"
 	"github.com/ethereum/go-ethereum/common"
@@ -53,6 +54,9 @@ type Handler struct {
 
 	broadcastRecord   *broadcastRecord
 	impeachmentRecord *impeachmentRecord
+
+	evidencePool *evidence.Pool
+	detector     *evidence.Detector
 }
 
 // NewHandler creates a new Handler
@@ -70,6 +74,7 @@ func NewHandler(config *configs.DposConfig, coinbase common.Address, db database
 		quitCh:                make(chan struct{}),
 		broadcastRecord:       newBroadcastRecord(),
 		impeachmentRecord:     newImpeachmentRecord(),
+		evidencePool:          evidence.NewPool(db),
 	}
 
 	// h.mode = LBFTMode
@@ -186,6 +191,10 @@ func (h *Handler) handleMsg(p *RemoteSigner, msg p2p.Msg) error {
 		return nil
 	}
 
+	if msg.Code == EvidenceMsg {
+		return h.handleEvidenceMsg(msg, p)
+	}
+
 	switch h.mode {
 	case LBFTMode:
 		return h.handleLBFTMsg(msg, p)
@@ -201,10 +210,21 @@ func (h *Handler) SetServer(server *p2p.Server) {
 	h.dialer.SetServer(server)
 }
 
+// Dialer returns the dialer of the handler
+func (h *Handler) Dialer() *Dialer {
+	return h.dialer
+}
+
 // SetDposService sets dpos service to handler
 func (h *Handler) SetDposService(dpos DposService) {
 	h.dpos = dpos
 	h.dialer.SetDposService(dpos)
+	h.detector = evidence.NewDetector(dpos, h.evidencePool)
+}
+
+// EvidencePool returns the pool of equivocation evidence
+func (h *Handler) EvidencePool() *evidence.Pool {
+	return h.evidencePool
 }
 
 // SetDposStateMachine sets dpos state machine
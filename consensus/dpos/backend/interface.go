@@ -35,6 +35,21 @@ const (
 	BroadcastAndInsertBlockAction
 )
 
+var (
+	actionName = map[Action]string{
+		NoAction:                      "NoAction",
+		BroadcastMsgAction:            "BroadcastMsgAction",
+		BroadcastAndInsertBlockAction: "BroadcastAndInsertBlockAction",
+	}
+)
+
+func (a Action) String() string {
+	if name, ok := actionName[a]; ok {
+		return name
+	}
+	return "Unknown Action"
+}
+
 // MsgCode is type enumerator for FSM message type
 type MsgCode uint8
 
@@ -128,6 +143,16 @@ type VerifyBlockFn func(block *types.Block) error
 // backing account.
 type SignFn func(accounts.Account, []byte) ([]byte, error)
 
+// ConsensusSigner signs the consensus msgs themselves rather than their hashes, so that a
+// signer holding the key out of the node, e.g. a remote signer, knows what it signs.
+type ConsensusSigner interface {
+	// SignHeader signs the header in the state, a proposer seals a header in idle state
+	SignHeader(account accounts.Account, header *types.Header, state consensus.State) ([]byte, error)
+
+	// SignMac signs the mac of a handshake with remote validators
+	SignMac(account accounts.Account, mac string) ([]byte, error)
+}
+
 // HandleGeneratedImpeachBlock handles generated impeach block
 type HandleGeneratedImpeachBlock func(block *types.Block) error
 
@@ -24,6 +24,7 @@ var (
 	ErrMsgTooOld           = errors.New("The msg is outdated")
 	ErrInvalidBlockFormat  = errors.New("The block format is invalid")
 	ErrInvalidHeaderFormat = errors.New("The header format is invalid")
+	ErrConflictingBlock    = errors.New("Already signed a different block at the same height")
 )
 
 // LBFT2 is a state machine used for consensus protocol for validators msg processing
@@ -42,6 +43,10 @@ type LBFT2 struct {
 	prepareSignatures *signaturesForBlockCaches
 	commitSignatures  *signaturesForBlockCaches
 
+	journal *lbft2Journal // write-ahead log of state transitions and signed blocks
+	tracer  *Tracer       // records the steps of the fsm, nil if not traced
+	metrics *lbft2Metrics // records the metrics of the rounds
+
 	handleImpeachBlock         HandleGeneratedImpeachBlock
 	handleFailbackImpeachBlock HandleGeneratedImpeachBlock
 
@@ -62,8 +67,11 @@ func NewLBFT2(faulty uint64, dpos DposService, handleImpeachBlock HandleGenerate
 		dpos:   dpos,
 
 		blockCache:        NewRecentBlocks(db),
-		prepareSignatures: newSignaturesForBlockCaches(db),
-		commitSignatures:  newSignaturesForBlockCaches(db),
+		prepareSignatures: newSignaturesForBlockCaches(db, prepareSignaturesPrefix),
+		commitSignatures:  newSignaturesForBlockCaches(db, commitSignaturesPrefix),
+
+		journal: newLBFT2Journal(db),
+		metrics: newLBFT2Metrics(),
 
 		handleImpeachBlock:         handleImpeachBlock,
 		handleFailbackImpeachBlock: handleFailbackImpeachBlock,
@@ -71,6 +79,9 @@ func NewLBFT2(faulty uint64, dpos DposService, handleImpeachBlock HandleGenerate
 		validateMsgMap: validateMap,
 	}
 
+	// resume the round from journal if reboot
+	lbft.replayJournal()
+
 	// try to failback if reboot
 	lbft.tryToImpeachFailback()
 
@@ -135,11 +146,20 @@ func (p *LBFT2) FSM(input *BlockOrHeader, msgCode MsgCode) ([]*BlockOrHeader, Ac
 
 	log.Debug("current status", "state", state, "number", number, "msg code", msgCode.String(), "input number", input.Number())
 
+	step := p.newTraceStep(input, msgCode)
+
 	output, action, msgCode, state, err := p.realFSM(input, msgCode, state)
 
+	p.traceStep(step, action, msgCode, state)
+
 	if output != nil && action != NoAction && msgCode != NoMsgCode && err == nil {
 		p.state = state
 		p.number = output[0].Number()
+
+		// journal the transition before the output msgs are sent out
+		if err := p.journal.writeStatus(p.number, output[0].Hash(), p.state); err != nil {
+			log.Warn("failed to write lbft2 status to journal", "number", p.number, "state", p.state, "err", err)
+		}
 	}
 
 	log.Debug("result state", "state", state, "number", number, "msg code", msgCode.String(), "action", action)
@@ -154,11 +174,14 @@ func (p *LBFT2) FSM(input *BlockOrHeader, msgCode MsgCode) ([]*BlockOrHeader, Ac
 		p.tryToImpeach()
 	}
 
+	observeLBFT2Status(p.number, p.state)
+
 	switch err {
 	case ErrBlockAlreadyInChain,
 		ErrMsgTooOld,
 		ErrInvalidBlockFormat,
-		ErrInvalidHeaderFormat:
+		ErrInvalidHeaderFormat,
+		ErrConflictingBlock:
 
 		return output, action, msgCode, nil
 
@@ -230,6 +253,7 @@ func (p *LBFT2) IdleHandler(input *BlockOrHeader, msgCode MsgCode, state consens
 		log.Debug("IdleHandler to call handlePreprepareMsg")
 
 		p.preprepareReceiveTimestamp = time.Now()
+		p.metrics.startRound(input.Number())
 
 		return p.handlePreprepareMsg(input, state, func(block *types.Block) error {
 
@@ -314,6 +338,7 @@ func (p *LBFT2) ImpeachHandler(input *BlockOrHeader, msgCode MsgCode, state cons
 		log.Debug("ImpeachHandler to call handleImpeachPreprepareMsg")
 
 		p.preprepareReceiveTimestamp = time.Now()
+		p.metrics.startRound(input.Number())
 
 		return p.handleImpeachPreprepareMsg(input, state, func(block *types.Block) error {
 
@@ -458,12 +483,18 @@ func (p *LBFT2) handlePreprepareMsg(input *BlockOrHeader, state consensus.State,
 
 		bi := NewBlockIdentifier(number, hash)
 
+		// never sign a conflicting block at the same height, even across restarts
+		if p.journal.conflicts(number, hash) {
+			log.Warn("already signed a different block at the height, refuse to sign", "number", number, "hash", hash.Hex())
+			return nil, NoAction, NoMsgCode, state, ErrConflictingBlock
+		}
+
 		// compose prepare msg
 		prepareHeader, _ := p.composePrepareMsg(block)
 
 		// if prepare certificate is satisfied
 		if p.prepareCertificate(bi) {
-			return p.oncePrepareCertificateSatisfied(prepareHeader)
+			return p.oncePrepareCertificateSatisfied(prepareHeader, state)
 		}
 
 		// prepare certificate is not satisfied, broadcast prepare msg
@@ -572,6 +603,10 @@ func (p *LBFT2) composePrepareMsg(block *types.Block) (*types.Header, error) {
 
 		_ = p.refreshSignatures(header, consensus.Prepare)
 
+		if err := p.journal.markAsSigned(number, hash); err != nil {
+			log.Warn("failed to journal the signed block", "number", number, "hash", hash.Hex(), "err", err)
+		}
+
 		log.Debug("succeed to sign the proposed block", "number", number, "hash", hash.Hex())
 
 		return header, nil
@@ -633,7 +668,7 @@ func (p *LBFT2) handlePrepareMsg(input *BlockOrHeader, state consensus.State) ([
 
 	// if prepare certificate is satisfied
 	if p.prepareCertificate(bi) {
-		return p.oncePrepareCertificateSatisfied(header)
+		return p.oncePrepareCertificateSatisfied(header, state)
 	}
 
 	log.Debug("prepare certificate is not satisfied now, waiting...", "number", number, "hash", hash.Hex(), "count", p.prepareSignatures.getSignaturesCountOf(bi))
@@ -685,6 +720,12 @@ func (p *LBFT2) composeCommitMsg(h *types.Header) (*types.Header, error) {
 		hash   = header.Hash()
 	)
 
+	// never sign a conflicting block at the same height, even across restarts
+	if p.journal.conflicts(number, hash) {
+		log.Warn("already signed a different block at the height, refuse to commit", "number", number, "hash", hash.Hex())
+		return header, ErrConflictingBlock
+	}
+
 	// prepare certificate is satisfied, sign the block with commit state
 	switch err := p.dpos.SignHeader(header, consensus.Commit); err {
 	case nil:
@@ -692,6 +733,10 @@ func (p *LBFT2) composeCommitMsg(h *types.Header) (*types.Header, error) {
 		// refresh signatures both in the header and local signatures cache
 		_ = p.refreshSignatures(header, consensus.Commit)
 
+		if err := p.journal.markAsSigned(number, hash); err != nil {
+			log.Warn("failed to journal the signed block", "number", number, "hash", hash.Hex(), "err", err)
+		}
+
 		log.Debug("succeed to sign the header with commit state, broadcasting commit msg...", "number", number, "hash", hash.Hex())
 
 		return header, nil
@@ -861,6 +906,7 @@ func (p *LBFT2) handleValidateMsg(input *BlockOrHeader, state consensus.State) (
 		go p.dpos.BroadcastBlock(block, true)
 
 		log.Debug("finished lbft2 consensus about the block", "number", block.NumberU64(), "hash", block.Hash().Hex(), "elapsed", common.PrettyDuration(time.Since(p.preprepareReceiveTimestamp)))
+		p.metrics.finishRound(number, roundNormal)
 
 		return []*BlockOrHeader{NewBOHFromBlock(block)}, BroadcastMsgAction, ValidateMsgCode, consensus.Idle, nil
 	}
@@ -904,6 +950,8 @@ func (p *LBFT2) handleImpeachValidateMsg(input *BlockOrHeader, state consensus.S
 		go p.dpos.BroadcastBlock(block, true)
 
 		log.Debug("finished lbft2 consensus about the impeach block", "number", block.NumberU64(), "hash", block.Hash().Hex(), "elapsed", common.PrettyDuration(time.Since(p.preprepareReceiveTimestamp)))
+		p.metrics.finishRound(number, roundImpeach)
+		observeImpeachment(impeachFinished)
 
 		return []*BlockOrHeader{NewBOHFromBlock(block)}, BroadcastMsgAction, ImpeachValidateMsgCode, consensus.Idle, nil
 	}
@@ -926,6 +974,7 @@ func (p *LBFT2) refreshSignatures(header *types.Header, state consensus.State) e
 		log.Debug("err when getting validators of header", "err", err, "number", header.Number.Uint64(), "hash", header.Hash().Hex())
 		return err
 	}
+	p.metrics.observeSignatures(header.Number.Uint64(), state, signers, validators)
 
 	switch state {
 	case consensus.Prepare, consensus.ImpeachPrepare:
@@ -965,7 +1014,7 @@ func (p *LBFT2) refreshSignatures(header *types.Header, state consensus.State) e
 }
 
 // oncePrepareCertificateSatisfied returns msgs and actions once prepare certificate is satisfied
-func (p *LBFT2) oncePrepareCertificateSatisfied(prepareHeader *types.Header) ([]*BlockOrHeader, Action, MsgCode, consensus.State, error) {
+func (p *LBFT2) oncePrepareCertificateSatisfied(prepareHeader *types.Header, state consensus.State) ([]*BlockOrHeader, Action, MsgCode, consensus.State, error) {
 
 	bi := BlockIdentifier{
 		hash:   prepareHeader.Hash(),
@@ -974,7 +1023,12 @@ func (p *LBFT2) oncePrepareCertificateSatisfied(prepareHeader *types.Header) ([]
 
 	// compose commit msg
 	commitHeader := types.CopyHeader(prepareHeader)
-	commitHeader, _ = p.composeCommitMsg(commitHeader)
+	commitHeader, err := p.composeCommitMsg(commitHeader)
+
+	// already signed a different block at the height, stay in current state
+	if err == ErrConflictingBlock {
+		return nil, NoAction, NoMsgCode, state, err
+	}
 
 	// if commit certificate is satisfied
 	if p.commitCertificate(bi) {
@@ -1081,6 +1135,29 @@ func (p *LBFT2) unknownAncestorBlockHandler(block *types.Block) {
 	}
 }
 
+// replayJournal restores the state of the round from journal, it is called once when creating
+// the state machine so that a validator restarted in the middle of a round resumes from where it stopped
+func (p *LBFT2) replayJournal() {
+	status, err := p.journal.readStatus()
+	if err != nil {
+		log.Debug("no lbft2 journal to replay", "err", err)
+		return
+	}
+
+	// the journaled round is finished or outdated
+	if status.State == consensus.Idle || status.Number != p.number || p.dpos.HasBlockInChain(status.Hash, status.Number) {
+		log.Debug("lbft2 journal is outdated, skip replaying", "journal.number", status.Number, "journal.state", status.State, "number", p.number)
+		return
+	}
+
+	bi := NewBlockIdentifier(status.Number, status.Hash)
+
+	log.Info("resuming lbft2 round from journal", "number", status.Number, "hash", status.Hash.Hex(), "state", status.State,
+		"prepare sigs", p.prepareSignatures.getSignaturesCountOf(bi), "commit sigs", p.commitSignatures.getSignaturesCountOf(bi))
+
+	p.state = status.State
+}
+
 func (p *LBFT2) tryToImpeach() {
 	log.Debug("try to start impeachment process")
 
@@ -1097,6 +1174,7 @@ func (p *LBFT2) tryToImpeach() {
 			func() {
 				currentBlock := p.dpos.GetCurrentBlock()
 				if currentBlock != nil && impeachBlock.NumberU64() > currentBlock.NumberU64() {
+					observeImpeachment(impeachProposed)
 					p.handleImpeachBlock(impeachBlock)
 				}
 			})
@@ -1120,6 +1198,7 @@ func (p *LBFT2) tryToImpeachFailback() {
 			func() {
 				currentBlock := p.dpos.GetCurrentBlock()
 				if currentBlock != nil && firstImpeach.NumberU64() > currentBlock.NumberU64() {
+					observeImpeachment(impeachFailback)
 					p.handleFailbackImpeachBlock(firstImpeach)
 				}
 			})
@@ -1129,6 +1208,7 @@ func (p *LBFT2) tryToImpeachFailback() {
 			func() {
 				currentBlock := p.dpos.GetCurrentBlock()
 				if currentBlock != nil && secondImpeach.NumberU64() > currentBlock.NumberU64() {
+					observeImpeachment(impeachFailback)
 					p.handleFailbackImpeachBlock(secondImpeach)
 				}
 			})
@@ -0,0 +1,144 @@
+package backend
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+
+	"github.com/gcchains/chain/commons/log"
+	"github.com/gcchains/chain/consensus"
+	"github.com/gcchains/chain/database"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const (
+	// journalSignedWindow is the number of recent heights whose signed hash is kept in journal
+	journalSignedWindow = 1024
+)
+
+// The fields below define the database schema used by lbft2 journal.
+var (
+	// journalStatusKey tracks the latest state transition of lbft2 fsm.
+	journalStatusKey = []byte("lbft2-journal-status")
+
+	journalSignedPrefix     = []byte("lbft2-journal-signed-") // journalSignedPrefix + (num % journalSignedWindow) (uint64 big endian) -> signed record
+	prepareSignaturesPrefix = []byte("lbft2-prepare-sigs-")   // prepareSignaturesPrefix + hash -> prepare signatures of the block
+	commitSignaturesPrefix  = []byte("lbft2-commit-sigs-")    // commitSignaturesPrefix + hash -> commit signatures of the block
+)
+
+var (
+	errNoJournalStatus = errors.New("no lbft2 journal status")
+)
+
+// journalStatus is a state transition of lbft2 fsm persisted in journal
+type journalStatus struct {
+	Number uint64
+	Hash   common.Hash
+	State  consensus.State
+}
+
+// journalSigned records the hash of the block signed by local validator at a height
+type journalSigned struct {
+	Number uint64
+	Hash   common.Hash
+}
+
+// lbft2Journal is a write-ahead log of lbft2 fsm, it persists every state transition
+// and the blocks signed by local validator, then replays them after a restart.
+type lbft2Journal struct {
+	db   database.Database
+	lock sync.RWMutex
+}
+
+func newLBFT2Journal(db database.Database) *lbft2Journal {
+	return &lbft2Journal{
+		db: db,
+	}
+}
+
+// writeStatus persists a state transition of lbft2 fsm
+func (j *lbft2Journal) writeStatus(number uint64, hash common.Hash, state consensus.State) error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	bytes, err := rlp.EncodeToBytes(&journalStatus{
+		Number: number,
+		Hash:   hash,
+		State:  state,
+	})
+	if err != nil {
+		return err
+	}
+
+	return j.db.Put(journalStatusKey, bytes)
+}
+
+// readStatus returns the latest state transition persisted in journal
+func (j *lbft2Journal) readStatus() (*journalStatus, error) {
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+
+	bytes, err := j.db.Get(journalStatusKey)
+	if err != nil || len(bytes) == 0 {
+		return nil, errNoJournalStatus
+	}
+
+	status := new(journalStatus)
+	if err := rlp.DecodeBytes(bytes, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// markAsSigned records the hash of the block signed by local validator at given height
+func (j *lbft2Journal) markAsSigned(number uint64, hash common.Hash) error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	bytes, err := rlp.EncodeToBytes(&journalSigned{
+		Number: number,
+		Hash:   hash,
+	})
+	if err != nil {
+		return err
+	}
+
+	return j.db.Put(journalSignedKey(number), bytes)
+}
+
+// signedHashOf returns the hash of the block signed by local validator at given height
+func (j *lbft2Journal) signedHashOf(number uint64) (common.Hash, bool) {
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+
+	bytes, err := j.db.Get(journalSignedKey(number))
+	if err != nil || len(bytes) == 0 {
+		return common.Hash{}, false
+	}
+
+	signed := new(journalSigned)
+	if err := rlp.DecodeBytes(bytes, signed); err != nil {
+		log.Warn("err when decoding signed record from journal", "number", number, "err", err)
+		return common.Hash{}, false
+	}
+
+	// the slot is reused by a height out of the window
+	if signed.Number != number {
+		return common.Hash{}, false
+	}
+	return signed.Hash, true
+}
+
+// conflicts checks if local validator already signed a different block at the height
+func (j *lbft2Journal) conflicts(number uint64, hash common.Hash) bool {
+	signedHash, signed := j.signedHashOf(number)
+	return signed && signedHash != hash
+}
+
+// journalSignedKey = journalSignedPrefix + (num % journalSignedWindow) (uint64 big endian)
+func journalSignedKey(number uint64) []byte {
+	enc := make([]byte, 8)
+	binary.BigEndian.PutUint64(enc, number%journalSignedWindow)
+	return append(append([]byte{}, journalSignedPrefix...), enc...)
+}
@@ -0,0 +1,198 @@
+package backend
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/gcchains/chain/consensus"
+	"github.com/gcchains/chain/database"
+	"github.com/gcchains/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var errNoSignatures = errors.New("no signatures")
+
+// fakeDposServiceForJournal implements the parts of DposService used when creating LBFT2
+type fakeDposServiceForJournal struct {
+	DposService
+	current *types.Block
+}
+
+func (f *fakeDposServiceForJournal) GetCurrentBlock() *types.Block {
+	return f.current
+}
+
+func (f *fakeDposServiceForJournal) HasBlockInChain(hash common.Hash, number uint64) bool {
+	return f.current.Hash() == hash && f.current.NumberU64() == number
+}
+
+func (f *fakeDposServiceForJournal) CreateFailbackImpeachBlocks() (*types.Block, *types.Block, error) {
+	return nil, nil, nil
+}
+
+// fakeDposServiceForConflict counts the headers signed by the local validator
+type fakeDposServiceForConflict struct {
+	fakeDposServiceForJournal
+	signed int
+}
+
+func (f *fakeDposServiceForConflict) ECRecoverSigs(header *types.Header, state consensus.State) ([]common.Address, []types.DposSignature, error) {
+	return nil, nil, errNoSignatures
+}
+
+func (f *fakeDposServiceForConflict) SignHeader(header *types.Header, state consensus.State) error {
+	f.signed++
+	return nil
+}
+
+func newBlockForJournal(number int64) *types.Block {
+	return types.NewBlock(&types.Header{Number: big.NewInt(number)}, nil, nil)
+}
+
+func TestLBFT2Journal_Status(t *testing.T) {
+	j := newLBFT2Journal(database.NewMemDatabase())
+
+	if _, err := j.readStatus(); err != errNoJournalStatus {
+		t.Fatalf("readStatus() error = %v, want %v", err, errNoJournalStatus)
+	}
+
+	hash := common.HexToHash("0x01")
+	if err := j.writeStatus(10, hash, consensus.Commit); err != nil {
+		t.Fatalf("writeStatus() error = %v", err)
+	}
+
+	status, err := j.readStatus()
+	if err != nil {
+		t.Fatalf("readStatus() error = %v", err)
+	}
+	if status.Number != 10 || status.Hash != hash || status.State != consensus.Commit {
+		t.Errorf("readStatus() = %+v, want number %d, hash %x, state %v", status, 10, hash, consensus.Commit)
+	}
+}
+
+func TestLBFT2Journal_Signed(t *testing.T) {
+	j := newLBFT2Journal(database.NewMemDatabase())
+
+	var (
+		hash1 = common.HexToHash("0x01")
+		hash2 = common.HexToHash("0x02")
+	)
+
+	if j.conflicts(1, hash1) {
+		t.Error("conflicts() = true for a height never signed")
+	}
+
+	j.markAsSigned(1, hash1)
+	if j.conflicts(1, hash1) {
+		t.Error("conflicts() = true for the signed block")
+	}
+	if !j.conflicts(1, hash2) {
+		t.Error("conflicts() = false for a different block at a signed height")
+	}
+
+	// a height out of the window reuses the slot
+	j.markAsSigned(1+journalSignedWindow, hash2)
+	if _, signed := j.signedHashOf(1); signed {
+		t.Error("signedHashOf() returns a record overwritten by a height out of the window")
+	}
+	if hash, signed := j.signedHashOf(1 + journalSignedWindow); !signed || hash != hash2 {
+		t.Errorf("signedHashOf() = %x, %v, want %x, true", hash, signed, hash2)
+	}
+}
+
+func TestSignaturesForBlockCaches_Persist(t *testing.T) {
+	db := database.NewMemDatabase()
+
+	var (
+		bi      = NewBlockIdentifier(1, common.HexToHash("0x01"))
+		signer1 = common.HexToAddress("0x01")
+		signer2 = common.HexToAddress("0x02")
+	)
+
+	prepare := newSignaturesForBlockCaches(db, prepareSignaturesPrefix)
+	prepare.addSignatureFor(bi, signer1, types.DposSignature{1})
+	prepare.addSignatureFor(bi, signer2, types.DposSignature{2})
+
+	// prepare and commit signatures do not mix
+	if count := newSignaturesForBlockCaches(db, commitSignaturesPrefix).getSignaturesCountOf(bi); count != 0 {
+		t.Errorf("commit signatures count = %d, want %d", count, 0)
+	}
+
+	// a new cache, like after a restart, loads signatures from db
+	restarted := newSignaturesForBlockCaches(db, prepareSignaturesPrefix)
+	if count := restarted.getSignaturesCountOf(bi); count != 2 {
+		t.Errorf("prepare signatures count = %d, want %d", count, 2)
+	}
+	if sig, ok := restarted.getSignatureFor(bi, signer2); !ok || sig != (types.DposSignature{2}) {
+		t.Errorf("getSignatureFor() = %x, %v, want %x, true", sig, ok, types.DposSignature{2})
+	}
+}
+
+func TestLBFT2_ReplayJournal(t *testing.T) {
+	var (
+		current = newBlockForJournal(9)
+		pending = newBlockForJournal(10)
+	)
+
+	tests := []struct {
+		name      string
+		number    uint64
+		hash      common.Hash
+		state     consensus.State
+		wantState consensus.State
+	}{
+		{"resume commit", 10, pending.Hash(), consensus.Commit, consensus.Commit},
+		{"resume prepare", 10, pending.Hash(), consensus.Prepare, consensus.Prepare},
+		{"outdated round", 9, current.Hash(), consensus.Commit, consensus.Idle},
+		{"idle round", 10, pending.Hash(), consensus.Idle, consensus.Idle},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := database.NewMemDatabase()
+			newLBFT2Journal(db).writeStatus(tt.number, tt.hash, tt.state)
+
+			lbft := NewLBFT2(1, &fakeDposServiceForJournal{current: current}, nil, nil, db)
+			if lbft.State() != tt.wantState {
+				t.Errorf("State() = %v, want %v", lbft.State(), tt.wantState)
+			}
+			if lbft.Number() != 10 {
+				t.Errorf("Number() = %d, want %d", lbft.Number(), 10)
+			}
+		})
+	}
+}
+
+func TestLBFT2_RefuseToCommitConflictingBlock(t *testing.T) {
+	var (
+		current = newBlockForJournal(9)
+		pending = newBlockForJournal(10)
+		dpos    = &fakeDposServiceForConflict{fakeDposServiceForJournal: fakeDposServiceForJournal{current: current}}
+		bi      = NewBlockIdentifier(pending.NumberU64(), pending.Hash())
+	)
+
+	lbft := NewLBFT2(1, dpos, nil, nil, database.NewMemDatabase())
+	lbft.SetState(consensus.Prepare)
+
+	// a different block at the height was signed before
+	lbft.journal.markAsSigned(pending.NumberU64(), common.HexToHash("0x01"))
+
+	// prepare certificate of the pending block is satisfied
+	for i := 1; i <= 2*int(lbft.Faulty())+1; i++ {
+		lbft.prepareSignatures.addSignatureFor(bi, common.BigToAddress(big.NewInt(int64(i))), types.DposSignature{byte(i)})
+	}
+
+	output, action, msgCode, err := lbft.FSM(NewBOHFromHeader(pending.Header()), PrepareMsgCode)
+	if err != nil {
+		t.Fatalf("FSM() error = %v", err)
+	}
+	if output != nil || action != NoAction || msgCode != NoMsgCode {
+		t.Errorf("FSM() = %v, %v, %v, want no output and no action", output, action, msgCode)
+	}
+	if lbft.State() != consensus.Prepare {
+		t.Errorf("State() = %v, want %v", lbft.State(), consensus.Prepare)
+	}
+	if dpos.signed != 0 {
+		t.Errorf("signed %d headers, want none", dpos.signed)
+	}
+}
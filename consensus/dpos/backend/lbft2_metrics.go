@@ -0,0 +1,153 @@
+package backend
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gcchains/chain/commons/chainmetrics"
+	"github.com/gcchains/chain/consensus"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// kinds of lbft2 rounds
+const (
+	roundNormal  = "normal"
+	roundImpeach = "impeach"
+)
+
+// events of impeachments
+const (
+	impeachProposed = "proposed" // an impeach block is proposed as the proposer timed out
+	impeachFailback = "failback" // a failback impeach block is proposed after a reboot
+	impeachFinished = "finished" // an impeach block is validated and inserted
+)
+
+var lbft2States = []consensus.State{
+	consensus.Idle,
+	consensus.Prepare,
+	consensus.Commit,
+	consensus.ImpeachPrepare,
+	consensus.ImpeachCommit,
+	consensus.Validate,
+}
+
+var (
+	lbft2NumberGauge = prometheus.NewGauge(prometheus.GaugeOpts{Name: "gcchain_lbft2_number",
+		Help: "number of the block lbft2 is working on."})
+
+	lbft2StateGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "gcchain_lbft2_state",
+		Help: "state of lbft2, 1 for the current state and 0 for the others."}, []string{"state"})
+
+	lbft2RoundDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "gcchain_lbft2_round_duration_seconds",
+		Help:    "duration of lbft2 rounds from the preprepare msg to the insertion of the block.",
+		Buckets: []float64{.1, .25, .5, 1, 2, 5, 10, 20, 60}}, []string{"kind"})
+
+	lbft2Impeachments = prometheus.NewCounterVec(prometheus.CounterOpts{Name: "gcchain_lbft2_impeachments_total",
+		Help: "impeachments of lbft2 by event."}, []string{"event"})
+
+	lbft2SignatureLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "gcchain_lbft2_signature_latency_seconds",
+		Help:    "latency of the first prepare or commit signature of a validator seen in a round since the preprepare msg.",
+		Buckets: []float64{.05, .1, .25, .5, 1, 2, 5, 10}}, []string{"phase", "validator"})
+)
+
+func init() {
+	chainmetrics.Register(lbft2NumberGauge)
+	chainmetrics.Register(lbft2StateGauge)
+	chainmetrics.Register(lbft2RoundDuration)
+	chainmetrics.Register(lbft2Impeachments)
+	chainmetrics.Register(lbft2SignatureLatency)
+}
+
+// lbft2Metrics records the metrics of the rounds of an lbft2 fsm.
+type lbft2Metrics struct {
+	lock   sync.Mutex
+	number uint64    // number of the current round
+	start  time.Time // time the preprepare msg of the current round is received
+	seen   map[string]map[common.Address]struct{}
+}
+
+func newLBFT2Metrics() *lbft2Metrics {
+	return &lbft2Metrics{}
+}
+
+// startRound starts a round of the number as its preprepare msg is received.
+func (m *lbft2Metrics) startRound(number uint64) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.number = number
+	m.start = time.Now()
+	m.seen = make(map[string]map[common.Address]struct{})
+}
+
+// observeSignatures observes the latency of the signatures of validators seen for the first time in the round.
+// Signers not in validators are ignored, they are recovered from unverified headers and would label unbounded series.
+func (m *lbft2Metrics) observeSignatures(number uint64, state consensus.State, signers []common.Address, validators []common.Address) {
+	var phase string
+	switch state {
+	case consensus.Prepare, consensus.ImpeachPrepare:
+		phase = "prepare"
+	case consensus.Commit, consensus.ImpeachCommit:
+		phase = "commit"
+	default:
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.seen == nil || number != m.number {
+		return
+	}
+	seen, ok := m.seen[phase]
+	if !ok {
+		seen = make(map[common.Address]struct{})
+		m.seen[phase] = seen
+	}
+	latency := time.Since(m.start).Seconds()
+	for _, signer := range signers {
+		if _, ok := seen[signer]; ok || !containsAddress(validators, signer) {
+			continue
+		}
+		seen[signer] = struct{}{}
+		lbft2SignatureLatency.WithLabelValues(phase, signer.Hex()).Observe(latency)
+	}
+}
+
+// finishRound observes the duration of the round once its block is inserted.
+func (m *lbft2Metrics) finishRound(number uint64, kind string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.seen == nil || number != m.number {
+		return
+	}
+	lbft2RoundDuration.WithLabelValues(kind).Observe(time.Since(m.start).Seconds())
+	m.seen = nil
+}
+
+// observeLBFT2Status sets the gauges of the number and state of lbft2.
+func observeLBFT2Status(number uint64, state consensus.State) {
+	lbft2NumberGauge.Set(float64(number))
+	for _, s := range lbft2States {
+		value := 0.
+		if s == state {
+			value = 1
+		}
+		lbft2StateGauge.WithLabelValues(s.String()).Set(value)
+	}
+}
+
+func observeImpeachment(event string) {
+	lbft2Impeachments.WithLabelValues(event).Inc()
+}
+
+func containsAddress(addrs []common.Address, addr common.Address) bool {
+	for _, a := range addrs {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package backend
+
+import (
+	"testing"
+
+	"github.com/gcchains/chain/consensus"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func histogramCount(t *testing.T, o prometheus.Observer) uint64 {
+	var m dto.Metric
+	if err := o.(prometheus.Metric).Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestLBFT2MetricsSignatures(t *testing.T) {
+	var (
+		m  = newLBFT2Metrics()
+		v1 = common.HexToAddress("0x01")
+		v2 = common.HexToAddress("0x02")
+		v3 = common.HexToAddress("0x03")
+
+		validators = []common.Address{v1, v2}
+	)
+	count := func(phase string, v common.Address) uint64 {
+		return histogramCount(t, lbft2SignatureLatency.WithLabelValues(phase, v.Hex()))
+	}
+	before1, before2 := count("prepare", v1), count("prepare", v2)
+
+	// signatures before the preprepare msg are not observed
+	m.observeSignatures(10, consensus.Prepare, []common.Address{v1}, validators)
+	if got := count("prepare", v1); got != before1 {
+		t.Fatalf("observed signature out of a round, count %d, want %d", got, before1)
+	}
+
+	m.startRound(10)
+	// a signature is observed once a round, however many headers carry it
+	m.observeSignatures(10, consensus.Prepare, []common.Address{v1}, validators)
+	m.observeSignatures(10, consensus.Prepare, []common.Address{v1, v2}, validators)
+	m.observeSignatures(11, consensus.Prepare, []common.Address{v2}, validators)
+	if got := count("prepare", v1); got != before1+1 {
+		t.Errorf("count of v1 %d, want %d", got, before1+1)
+	}
+	if got := count("prepare", v2); got != before2+1 {
+		t.Errorf("count of v2 %d, want %d", got, before2+1)
+	}
+
+	// signers not in the validators are not observed
+	m.observeSignatures(10, consensus.Prepare, []common.Address{v3}, validators)
+	if got := count("prepare", v3); got != 0 {
+		t.Errorf("count of a non validator %d, want 0", got)
+	}
+
+	// a round is observed once
+	rounds := histogramCount(t, lbft2RoundDuration.WithLabelValues(roundNormal))
+	m.finishRound(10, roundNormal)
+	m.finishRound(10, roundNormal)
+	if got := histogramCount(t, lbft2RoundDuration.WithLabelValues(roundNormal)); got != rounds+1 {
+		t.Errorf("count of rounds %d, want %d", got, rounds+1)
+	}
+}
+
+func TestObserveLBFT2Status(t *testing.T) {
+	observeLBFT2Status(5, consensus.Commit)
+
+	if got := testutil.ToFloat64(lbft2NumberGauge); got != 5 {
+		t.Errorf("number %v, want 5", got)
+	}
+	for _, s := range lbft2States {
+		want := 0.
+		if s == consensus.Commit {
+			want = 1
+		}
+		if got := testutil.ToFloat64(lbft2StateGauge.WithLabelValues(s.String())); got != want {
+			t.Errorf("state %v is %v, want %v", s, got, want)
+		}
+	}
+}
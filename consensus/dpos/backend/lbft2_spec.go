@@ -0,0 +1,308 @@
+package backend
+
+import (
+	"fmt"
+
+	"github.com/gcchains/chain/consensus"
+)
+
+// This file is a Go port of the LBFT spec in tlaplus/lbft.tla, replaying the traces
+// recorded by Tracer against the transition relation of the spec.
+//
+// The spec models a validator at a height by its state and the signatures it collected,
+// the fsm macro being the transition relation: an idle validator prepares the proposed
+// block, a prepared one accumulates prepare signatures until a prepare certificate lets
+// it commit, a committed one accumulates commit signatures until a commit certificate
+// lets it go idle in the next height, and any input can be skipped. The certificates of
+// 3 signatures out of 4 validators are 2f+1 here.
+//
+// The spec declares the impeachment states and their certificates of 2 signatures, f+1
+// here, without actions, the port mirrors the normal actions for them. Validate msgs,
+// carrying the certificate of another validator, are not in the spec either, the port
+// lets them decide a height not decided yet.
+
+// specState is the state of a validator in the spec.
+type specState uint8
+
+// Those are the states of the spec, numbered as in the spec
+const (
+	specIdle           specState = 0
+	specPrepare        specState = 1
+	specCommit         specState = 2
+	specImpeachPrepare specState = 3
+	specImpeachCommit  specState = 4
+	specNextHeight     specState = 9 // idle state in next block height
+)
+
+var (
+	specStateName = map[specState]string{
+		specIdle:           "idle",
+		specPrepare:        "prepare",
+		specCommit:         "commit",
+		specImpeachPrepare: "impeach prepare",
+		specImpeachCommit:  "impeach commit",
+		specNextHeight:     "next height",
+	}
+
+	// specStateOf maps the states of lbft2 fsm to the ones of the spec, a validated
+	// block is decided as the spec's commit certificate leads to the next height
+	specStateOf = map[consensus.State]specState{
+		consensus.Idle:           specIdle,
+		consensus.Prepare:        specPrepare,
+		consensus.Commit:         specCommit,
+		consensus.ImpeachPrepare: specImpeachPrepare,
+		consensus.ImpeachCommit:  specImpeachCommit,
+		consensus.Validate:       specNextHeight,
+	}
+)
+
+func (s specState) String() string {
+	return fmt.Sprintf("%d (%s)", s, specStateName[s])
+}
+
+// specAction is an action of the fsm macro of the spec, taking a validator from one of
+// the states to another on an input msg.
+type specAction struct {
+	name   string
+	from   []specState
+	input  MsgCode
+	to     specState
+	output MsgCode // msgs signed and broadcast on the transition
+
+	// certified checks the certificate the action awaits, nil if none
+	certified func(step *TraceStep) bool
+}
+
+var specActions = []specAction{
+	{
+		name:   "prepare",
+		from:   []specState{specIdle},
+		input:  PreprepareMsgCode,
+		to:     specPrepare,
+		output: PrepareMsgCode,
+	},
+	{
+		name:      "prepare certificate",
+		from:      []specState{specPrepare},
+		input:     PrepareMsgCode,
+		to:        specCommit,
+		output:    PrepareAndCommitMsgCode,
+		certified: func(step *TraceStep) bool { return step.PrepareSigs >= 2*int(step.Faulty)+1 },
+	},
+	{
+		name:      "commit certificate",
+		from:      []specState{specCommit},
+		input:     CommitMsgCode,
+		to:        specNextHeight,
+		output:    ValidateMsgCode,
+		certified: func(step *TraceStep) bool { return step.CommitSigs >= 2*int(step.Faulty)+1 },
+	},
+	{
+		name:   "validate",
+		from:   []specState{specIdle, specPrepare, specCommit},
+		input:  ValidateMsgCode,
+		to:     specNextHeight,
+		output: ValidateMsgCode,
+	},
+	{
+		name:   "impeach prepare",
+		from:   []specState{specIdle, specPrepare, specCommit},
+		input:  ImpeachPreprepareMsgCode,
+		to:     specImpeachPrepare,
+		output: ImpeachPrepareMsgCode,
+	},
+	{
+		name:      "impeach prepare certificate",
+		from:      []specState{specImpeachPrepare},
+		input:     ImpeachPrepareMsgCode,
+		to:        specImpeachCommit,
+		output:    ImpeachPrepareAndCommitMsgCode,
+		certified: func(step *TraceStep) bool { return step.PrepareSigs >= int(step.Faulty)+1 },
+	},
+	{
+		name:      "impeach commit certificate",
+		from:      []specState{specImpeachCommit},
+		input:     ImpeachCommitMsgCode,
+		to:        specNextHeight,
+		output:    ImpeachValidateMsgCode,
+		certified: func(step *TraceStep) bool { return step.CommitSigs >= int(step.Faulty)+1 },
+	},
+	{
+		name:   "impeach validate",
+		from:   []specState{specIdle, specPrepare, specCommit, specImpeachPrepare, specImpeachCommit},
+		input:  ImpeachValidateMsgCode,
+		to:     specNextHeight,
+		output: ImpeachValidateMsgCode,
+	},
+}
+
+// Divergence is a step of a trace which the spec does not allow.
+type Divergence struct {
+	Index  int // index of the step in the trace
+	Step   *TraceStep
+	Reason string
+}
+
+func (d *Divergence) Error() string {
+	s := d.Step
+	return fmt.Sprintf("step %d at height %d, %s --%s(%d)--> %s, %s %s, %d prepare sigs, %d commit sigs: %s",
+		d.Index, s.Height, s.State, s.Input, s.Number, s.Next, s.Action, s.Output, s.PrepareSigs, s.CommitSigs, d.Reason)
+}
+
+// tracedStep is a step of a trace with the names parsed.
+type tracedStep struct {
+	*TraceStep
+	state, next    consensus.State
+	input, output  MsgCode
+	action         Action
+	applied        bool // if the fsm took the state returned by realFSM
+	height, number uint64
+}
+
+// CheckTrace replays a trace of lbft2 fsm against the spec, returning the steps not
+// allowed by it. A step is allowed if it starts where the previous one ended, a new
+// height starting in idle state, and it is either skipped or an action of the spec.
+func CheckTrace(steps []*TraceStep) []*Divergence {
+	var (
+		divergences []*Divergence
+		prev        *tracedStep
+	)
+	for i, s := range steps {
+		diverge := func(format string, args ...interface{}) {
+			divergences = append(divergences, &Divergence{Index: i, Step: s, Reason: fmt.Sprintf(format, args...)})
+		}
+
+		step, err := parseTraceStep(s)
+		if err != nil {
+			diverge("%v", err)
+			prev = nil
+			continue
+		}
+
+		if prev != nil {
+			if reason := checkContinuity(prev, step); reason != "" {
+				diverge("%s", reason)
+			}
+		}
+		if reason := checkTransition(step); reason != "" {
+			diverge("%s", reason)
+		}
+		prev = step
+	}
+	return divergences
+}
+
+// checkContinuity checks that a step starts where the previous one ended.
+func checkContinuity(prev, step *tracedStep) string {
+	height, state := prev.height, prev.state
+	if prev.applied {
+		height, state = prev.number, prev.next
+	}
+
+	switch {
+	case step.height < height:
+		return fmt.Sprintf("height goes back from %d", height)
+
+	case step.height > height && step.state != consensus.Idle:
+		return fmt.Sprintf("height %d starts in state %v, the spec starts it in %v", step.height, step.state, specIdle)
+
+	case step.height == height && step.state != state:
+		return fmt.Sprintf("state changed from %v out of the fsm", state)
+	}
+	return ""
+}
+
+// checkTransition checks that a step is either skipped or an action of the spec.
+func checkTransition(step *tracedStep) string {
+	from, to := specStateOf[step.state], specStateOf[step.next]
+
+	// the validated block is inserted, the fsm goes idle in the next height
+	validated := step.action != NoAction && (step.output == ValidateMsgCode || step.output == ImpeachValidateMsgCode)
+	if validated {
+		to = specNextHeight
+	}
+
+	// a step leaving the state unchanged without signing anything, relaying a validated
+	// block signs nothing, is a skip of the spec
+	if from == to && (step.action == NoAction || validated) {
+		return ""
+	}
+
+	for _, a := range specActions {
+		if a.input != step.input || a.to != to || !containsSpecState(a.from, from) {
+			continue
+		}
+		if a.certified != nil && !a.certified(step.TraceStep) {
+			return fmt.Sprintf("%s action without the certificate", a.name)
+		}
+		if step.action != BroadcastMsgAction || step.output != a.output {
+			return fmt.Sprintf("%s action broadcasts %v, the spec broadcasts %v", a.name, step.output, a.output)
+		}
+		return ""
+	}
+	if from == to {
+		return fmt.Sprintf("no action of the spec broadcasts %v in state %v", step.output, from)
+	}
+	return fmt.Sprintf("no action of the spec takes state %v to %v on %v", from, to, step.input)
+}
+
+func containsSpecState(states []specState, state specState) bool {
+	for _, s := range states {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+func parseTraceStep(s *TraceStep) (*tracedStep, error) {
+	step := &tracedStep{TraceStep: s, height: s.Height, number: s.Number}
+
+	var err error
+	if step.state, err = parseState(s.State); err != nil {
+		return nil, err
+	}
+	if step.next, err = parseState(s.Next); err != nil {
+		return nil, err
+	}
+	if step.input, err = parseMsgCode(s.Input); err != nil {
+		return nil, err
+	}
+	if step.output, err = parseMsgCode(s.Output); err != nil {
+		return nil, err
+	}
+	if step.action, err = parseAction(s.Action); err != nil {
+		return nil, err
+	}
+
+	// as FSM does, the state returned by realFSM is taken only along with output msgs
+	step.applied = step.action != NoAction && step.output != NoMsgCode
+	return step, nil
+}
+
+func parseState(name string) (consensus.State, error) {
+	for state := range specStateOf {
+		if state.String() == name {
+			return state, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown state %q", name)
+}
+
+func parseMsgCode(name string) (MsgCode, error) {
+	for code, n := range msgCodeName {
+		if n == name {
+			return code, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown msg code %q", name)
+}
+
+func parseAction(name string) (Action, error) {
+	for action, n := range actionName {
+		if n == name {
+			return action, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown action %q", name)
+}
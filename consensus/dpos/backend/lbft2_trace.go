@@ -0,0 +1,140 @@
+package backend
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/gcchains/chain/commons/log"
+	"github.com/gcchains/chain/consensus"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TraceStep is a step of lbft2 fsm, i.e. an input msg handled by realFSM, with the
+// state before and after it. States and msg codes are kept by name for the trace file
+// to be readable.
+type TraceStep struct {
+	Height uint64 `json:"height"` // number of the fsm before the step
+	State  string `json:"state"`  // state of the fsm before the step
+	Faulty uint64 `json:"faulty"` // f of the 3f+1 validators
+
+	Number uint64      `json:"number"` // number of the input block or header
+	Hash   common.Hash `json:"hash"`   // hash of the input block or header
+	Input  string      `json:"input"`  // msg code of the input
+
+	Action string `json:"action"` // action returned by realFSM
+	Output string `json:"output"` // msg code of the output msgs
+	Next   string `json:"next"`   // state returned by realFSM
+
+	PrepareSigs int `json:"prepareSigs"` // prepare signatures of the input block after the step
+	CommitSigs  int `json:"commitSigs"`  // commit signatures of the input block after the step
+}
+
+// Tracer records the steps of lbft2 fsm into a trace file, one json object per line,
+// to be checked against the spec in tlaplus/lbft.tla by CheckTrace.
+type Tracer struct {
+	lock   sync.Mutex
+	enc    *json.Encoder
+	closer io.Closer
+}
+
+// NewTracer returns a tracer writing the steps to w.
+func NewTracer(w io.Writer) *Tracer {
+	return &Tracer{
+		enc: json.NewEncoder(w),
+	}
+}
+
+// OpenTracer returns a tracer appending the steps to the file.
+func OpenTracer(path string) (*Tracer, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	t := NewTracer(f)
+	t.closer = f
+	return t, nil
+}
+
+// Trace writes a step.
+func (t *Tracer) Trace(step *TraceStep) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.enc.Encode(step)
+}
+
+// Close closes the file of the tracer, if it is opened by OpenTracer.
+func (t *Tracer) Close() error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.closer == nil {
+		return nil
+	}
+	return t.closer.Close()
+}
+
+// ReadTrace reads the steps written by a tracer.
+func ReadTrace(r io.Reader) ([]*TraceStep, error) {
+	var (
+		steps []*TraceStep
+		dec   = json.NewDecoder(bufio.NewReader(r))
+	)
+	for {
+		step := new(TraceStep)
+		switch err := dec.Decode(step); err {
+		case nil:
+			steps = append(steps, step)
+		case io.EOF:
+			return steps, nil
+		default:
+			return steps, err
+		}
+	}
+}
+
+// SetTracer sets the tracer recording the steps of the fsm, nil disables it.
+func (p *LBFT2) SetTracer(tracer *Tracer) {
+	p.stateLock.Lock()
+	defer p.stateLock.Unlock()
+
+	p.tracer = tracer
+}
+
+// newTraceStep returns the step about to be taken with the input, nil if the fsm is not
+// traced. The state lock must be held.
+func (p *LBFT2) newTraceStep(input *BlockOrHeader, msgCode MsgCode) *TraceStep {
+	if p.tracer == nil {
+		return nil
+	}
+	return &TraceStep{
+		Height: p.number,
+		State:  p.state.String(),
+		Faulty: p.Faulty(),
+		Number: input.Number(),
+		Hash:   input.Hash(),
+		Input:  msgCode.String(),
+	}
+}
+
+// traceStep completes the step with the result of realFSM and records it.
+func (p *LBFT2) traceStep(step *TraceStep, action Action, msgCode MsgCode, state consensus.State) {
+	if step == nil {
+		return
+	}
+
+	bi := NewBlockIdentifier(step.Number, step.Hash)
+
+	step.Action = action.String()
+	step.Output = msgCode.String()
+	step.Next = state.String()
+	step.PrepareSigs = p.prepareSignatures.getSignaturesCountOf(bi)
+	step.CommitSigs = p.commitSignatures.getSignaturesCountOf(bi)
+
+	if err := p.tracer.Trace(step); err != nil {
+		log.Warn("failed to trace lbft2 step", "number", step.Number, "err", err)
+	}
+}
@@ -0,0 +1,147 @@
+package backend
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gcchains/chain/consensus"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func newTraceStep(height uint64, state consensus.State, input MsgCode, action Action, output MsgCode, next consensus.State, prepareSigs, commitSigs int) *TraceStep {
+	return &TraceStep{
+		Height:      height,
+		State:       state.String(),
+		Faulty:      1,
+		Number:      height,
+		Hash:        common.BigToHash(common.Big1),
+		Input:       input.String(),
+		Action:      action.String(),
+		Output:      output.String(),
+		Next:        next.String(),
+		PrepareSigs: prepareSigs,
+		CommitSigs:  commitSigs,
+	}
+}
+
+// conformingTrace is a trace of a validator deciding a block, then an impeachment block.
+func conformingTrace() []*TraceStep {
+	return []*TraceStep{
+		// a prepare msg before the block is skipped
+		newTraceStep(1, consensus.Idle, PrepareMsgCode, NoAction, NoMsgCode, consensus.Idle, 1, 0),
+		newTraceStep(1, consensus.Idle, PreprepareMsgCode, BroadcastMsgAction, PrepareMsgCode, consensus.Prepare, 2, 0),
+		newTraceStep(1, consensus.Prepare, PrepareMsgCode, BroadcastMsgAction, PrepareAndCommitMsgCode, consensus.Commit, 3, 1),
+		newTraceStep(1, consensus.Commit, CommitMsgCode, NoAction, NoMsgCode, consensus.Commit, 3, 2),
+		newTraceStep(1, consensus.Commit, CommitMsgCode, BroadcastMsgAction, ValidateMsgCode, consensus.Validate, 3, 3),
+		// the validated block is relayed
+		newTraceStep(1, consensus.Validate, ValidateMsgCode, BroadcastMsgAction, ValidateMsgCode, consensus.Idle, 3, 3),
+
+		newTraceStep(2, consensus.Idle, ImpeachPreprepareMsgCode, BroadcastMsgAction, ImpeachPrepareMsgCode, consensus.ImpeachPrepare, 1, 0),
+		newTraceStep(2, consensus.ImpeachPrepare, ImpeachPrepareMsgCode, BroadcastMsgAction, ImpeachPrepareAndCommitMsgCode, consensus.ImpeachCommit, 2, 1),
+		newTraceStep(2, consensus.ImpeachCommit, ImpeachCommitMsgCode, BroadcastMsgAction, ImpeachValidateMsgCode, consensus.Validate, 2, 2),
+
+		newTraceStep(3, consensus.Idle, ValidateMsgCode, BroadcastMsgAction, ValidateMsgCode, consensus.Idle, 0, 3),
+	}
+}
+
+func TestTracer(t *testing.T) {
+	var (
+		buf    = new(bytes.Buffer)
+		tracer = NewTracer(buf)
+		trace  = conformingTrace()
+	)
+	for _, step := range trace {
+		if err := tracer.Trace(step); err != nil {
+			t.Fatalf("Trace() error = %v", err)
+		}
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != len(trace) {
+		t.Errorf("trace has %d lines, want %d", lines, len(trace))
+	}
+
+	steps, err := ReadTrace(buf)
+	if err != nil {
+		t.Fatalf("ReadTrace() error = %v", err)
+	}
+	if !reflect.DeepEqual(steps, trace) {
+		t.Errorf("ReadTrace() = %v, want %v", steps, trace)
+	}
+}
+
+func TestCheckTrace(t *testing.T) {
+	if divergences := CheckTrace(conformingTrace()); len(divergences) != 0 {
+		t.Fatalf("CheckTrace() = %v, want no divergence", divergences)
+	}
+
+	tests := []struct {
+		name   string
+		index  int
+		step   *TraceStep
+		reason string
+	}{
+		{
+			name:   "commit before the block",
+			index:  1,
+			step:   newTraceStep(1, consensus.Idle, PrepareMsgCode, BroadcastMsgAction, PrepareAndCommitMsgCode, consensus.Commit, 3, 1),
+			reason: "no action of the spec takes state 0 (idle) to 2 (commit) on PrepareMsgCode",
+		},
+		{
+			name:   "commit without prepare certificate",
+			index:  2,
+			step:   newTraceStep(1, consensus.Prepare, PrepareMsgCode, BroadcastMsgAction, PrepareAndCommitMsgCode, consensus.Commit, 2, 1),
+			reason: "prepare certificate action without the certificate",
+		},
+		{
+			name:   "commit certificate without validate msg",
+			index:  4,
+			step:   newTraceStep(1, consensus.Commit, CommitMsgCode, BroadcastMsgAction, CommitMsgCode, consensus.Commit, 3, 3),
+			reason: "no action of the spec broadcasts CommitMsgCode in state 2 (commit)",
+		},
+		{
+			name:   "prepare broadcasting commit msg",
+			index:  1,
+			step:   newTraceStep(1, consensus.Idle, PreprepareMsgCode, BroadcastMsgAction, CommitMsgCode, consensus.Prepare, 2, 0),
+			reason: "prepare action broadcasts CommitMsgCode, the spec broadcasts PrepareMsgCode",
+		},
+		{
+			name:   "impeaching a decided height",
+			index:  5,
+			step:   newTraceStep(1, consensus.Validate, ImpeachPreprepareMsgCode, BroadcastMsgAction, ImpeachPrepareMsgCode, consensus.ImpeachPrepare, 1, 0),
+			reason: "no action of the spec takes state 9 (next height) to 3 (impeach prepare) on ImpeachPreprepareMsgCode",
+		},
+		{
+			name:   "height starting in prepare",
+			index:  6,
+			step:   newTraceStep(2, consensus.Prepare, PrepareMsgCode, NoAction, NoMsgCode, consensus.Prepare, 1, 0),
+			reason: "height 2 starts in state Prepare, the spec starts it in 0 (idle)",
+		},
+		{
+			name:   "state changed out of the fsm",
+			index:  3,
+			step:   newTraceStep(1, consensus.Prepare, CommitMsgCode, NoAction, NoMsgCode, consensus.Prepare, 3, 2),
+			reason: "state changed from Commit out of the fsm",
+		},
+		{
+			name:   "unknown state",
+			index:  0,
+			step:   &TraceStep{State: "Unknown"},
+			reason: `unknown state "Unknown"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trace := conformingTrace()
+			trace[tt.index] = tt.step
+
+			divergences := CheckTrace(trace)
+			if len(divergences) == 0 {
+				t.Fatal("CheckTrace() = no divergence")
+			}
+			if d := divergences[0]; d.Index != tt.index || d.Step != tt.step || d.Reason != tt.reason {
+				t.Errorf("CheckTrace()[0] = step %d: %s, want step %d: %s", d.Index, d.Reason, tt.index, tt.reason)
+			}
+		})
+	}
+}
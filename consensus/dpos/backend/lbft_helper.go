@@ -1,6 +1,9 @@
 package backend
 
 import (
+	"bytes"
+	"io"
+	"sort"
 	"sync"
 
 	"github.com/gcchains/chain/commons/log"
@@ -41,6 +44,45 @@ func (sb *signaturesOfBlock) getSignature(signer common.Address) (types.DposSign
 	return signature, ok
 }
 
+// signatureOfSigner is the rlp representation of a signature in signaturesOfBlock
+type signatureOfSigner struct {
+	Signer    common.Address
+	Signature types.DposSignature
+}
+
+// EncodeRLP implements rlp.Encoder, signatures are encoded in the order of signers
+func (sb *signaturesOfBlock) EncodeRLP(w io.Writer) error {
+	sb.lock.RLock()
+	defer sb.lock.RUnlock()
+
+	sigs := make([]signatureOfSigner, 0, len(sb.signatures))
+	for signer, signature := range sb.signatures {
+		sigs = append(sigs, signatureOfSigner{Signer: signer, Signature: signature})
+	}
+	sort.Slice(sigs, func(i, j int) bool {
+		return bytes.Compare(sigs[i].Signer[:], sigs[j].Signer[:]) < 0
+	})
+
+	return rlp.Encode(w, sigs)
+}
+
+// DecodeRLP implements rlp.Decoder
+func (sb *signaturesOfBlock) DecodeRLP(s *rlp.Stream) error {
+	var sigs []signatureOfSigner
+	if err := s.Decode(&sigs); err != nil {
+		return err
+	}
+
+	sb.lock.Lock()
+	defer sb.lock.Unlock()
+
+	sb.signatures = make(map[common.Address]types.DposSignature, len(sigs))
+	for _, sig := range sigs {
+		sb.signatures[sig.Signer] = sig.Signature
+	}
+	return nil
+}
+
 func (sb *signaturesOfBlock) count() int {
 	sb.lock.RLock()
 	defer sb.lock.RUnlock()
@@ -54,42 +96,53 @@ func (sb *signaturesOfBlock) count() int {
 
 type signaturesForBlockCaches struct {
 	db                  database.Database
+	prefix              []byte // prefix of keys to persist signatures in db
 	signaturesForBlocks *lru.ARCCache
 	lock                sync.RWMutex
 }
 
-func newSignaturesForBlockCaches(db database.Database) *signaturesForBlockCaches {
+func newSignaturesForBlockCaches(db database.Database, prefix []byte) *signaturesForBlockCaches {
 	sigCaches, _ := lru.NewARC(defaultSizeOfSignatureCache)
 	return &signaturesForBlockCaches{
 		db:                  db,
+		prefix:              prefix,
 		signaturesForBlocks: sigCaches,
 	}
 }
 
-// getSignaturesCountOf returns the number of signatures for given block identifier
-func (sc *signaturesForBlockCaches) getSignaturesCountOf(bi BlockIdentifier) int {
-	sc.lock.RLock()
-	defer sc.lock.RUnlock()
+// key returns the db key of signatures for given block identifier
+func (sc *signaturesForBlockCaches) key(bi BlockIdentifier) []byte {
+	return append(append([]byte{}, sc.prefix...), bi.hash.Bytes()...)
+}
 
+// signaturesOf returns signatures of given block identifier from cache, or from db if not cached,
+// returns an empty one if not found
+func (sc *signaturesForBlockCaches) signaturesOf(bi BlockIdentifier) *signaturesOfBlock {
 	sigs, ok := sc.signaturesForBlocks.Get(bi)
 	if sigs != nil && ok {
-
-		log.Debug("counting signatures of block", "number", bi.number, "hash", bi.hash.Hex())
-
-		return sigs.(*signaturesOfBlock).count()
+		return sigs.(*signaturesOfBlock)
 	}
 
 	signatures := newSignaturesOfBlock()
-	bytes, err := sc.db.Get(bi.hash.Bytes())
+	bytes, err := sc.db.Get(sc.key(bi))
 	if err == nil {
-		err = rlp.DecodeBytes(bytes, &signatures)
-		if err != nil {
+		if err = rlp.DecodeBytes(bytes, signatures); err != nil {
 			log.Debug("err when decoding signatures from byte retrieved from db", "err", err, "number", bi.number, "hash", bi.hash.Hex())
+			return newSignaturesOfBlock()
 		}
-		return signatures.count()
+		sc.signaturesForBlocks.Add(bi, signatures)
 	}
+	return signatures
+}
+
+// getSignaturesCountOf returns the number of signatures for given block identifier
+func (sc *signaturesForBlockCaches) getSignaturesCountOf(bi BlockIdentifier) int {
+	sc.lock.RLock()
+	defer sc.lock.RUnlock()
+
+	log.Debug("counting signatures of block", "number", bi.number, "hash", bi.hash.Hex())
 
-	return 0
+	return sc.signaturesOf(bi).count()
 }
 
 // addSignatureFor adds a signature to signature caches
@@ -97,12 +150,7 @@ func (sc *signaturesForBlockCaches) addSignatureFor(bi BlockIdentifier, signer c
 	sc.lock.Lock()
 	defer sc.lock.Unlock()
 
-	signatures := newSignaturesOfBlock()
-	sigs, ok := sc.signaturesForBlocks.Get(bi)
-	if sigs != nil && ok {
-		signatures = sigs.(*signaturesOfBlock)
-	}
-
+	signatures := sc.signaturesOf(bi)
 	signatures.setSignature(signer, signature)
 	sc.signaturesForBlocks.Add(bi, signatures)
 
@@ -112,7 +160,7 @@ func (sc *signaturesForBlockCaches) addSignatureFor(bi BlockIdentifier, signer c
 		return
 	}
 
-	err = sc.db.Put(bi.hash.Bytes(), bytes)
+	err = sc.db.Put(sc.key(bi), bytes)
 	if err != nil {
 		log.Warn("err when saving signatures to db", "err", err, "number", bi.number, "hash", bi.hash.Hex())
 	}
@@ -124,11 +172,7 @@ func (sc *signaturesForBlockCaches) getSignatureFor(bi BlockIdentifier, signer c
 	sc.lock.RLock()
 	defer sc.lock.RUnlock()
 
-	sigs, ok := sc.signaturesForBlocks.Get(bi)
-	if sigs != nil && ok {
-		return sigs.(*signaturesOfBlock).getSignature(signer)
-	}
-	return types.DposSignature{}, false
+	return sc.signaturesOf(bi).getSignature(signer)
 }
 
 func (sc *signaturesForBlockCaches) cacheSignaturesFromHeader(signers []common.Address, signatures []types.DposSignature, validators []common.Address, header *types.Header) error {
@@ -37,6 +37,9 @@ const (
 	PrepareImpeachHeaderMsg   = 0x48
 	CommitImpeachHeaderMsg    = 0x49
 	ValidateImpeachBlockMsg   = 0x50
+
+	// EvidenceMsg is a msg code used for gossiping equivocation evidence
+	EvidenceMsg = 0x51
 )
 
 // ProtocolMaxMsgSize Maximum cap on the size of a protocol message
@@ -71,6 +74,9 @@ const (
 
 	// ErrSuspendedPeer is returned if remote signer is dead
 	ErrSuspendedPeer
+
+	// ErrInvalidEvidence is returned if remote signer sends an invalid evidence
+	ErrInvalidEvidence
 )
 
 func (e errCode) String() string {
@@ -88,6 +94,7 @@ var errorToString = map[int]string{
 	ErrNoStatusMsg:             "No status message",
 	ErrExtraStatusMsg:          "Extra status message",
 	ErrSuspendedPeer:           "Suspended peer",
+	ErrInvalidEvidence:         "Invalid evidence",
 }
 
 // SignerStatusData represents signer status when handshaking
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/gcchains/chain/commons/log"
+	"github.com/gcchains/chain/consensus/dpos/evidence"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/p2p/discover"
@@ -136,6 +137,17 @@ func (s *RemoteSigner) SetPeer(cpcVersion int, dposVersion int, role Role, p *p2
 	s.cpcVersion, s.dposVersion, s.role, s.Peer, s.rw = cpcVersion, dposVersion, role, p, rw
 }
 
+// SendEvidence propagates an equivocation evidence to remote peer
+func (s *RemoteSigner) SendEvidence(ev *evidence.Evidence) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.Peer == nil || s.rw == nil {
+		return errNilPeer
+	}
+	return p2p.Send(s.rw, EvidenceMsg, ev)
+}
+
 // Handshake tries to handshake with remote validator
 func Handshake(p *p2p.Peer, rw p2p.MsgReadWriter, mac string, sig []byte, term uint64, futureTerm uint64) (address common.Address, dposVersion int, err error) {
 	// Send out own handshake in a new thread
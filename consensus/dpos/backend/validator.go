@@ -200,6 +200,9 @@ func (vh *Handler) handleLBFT2Msg(msg p2p.Msg, p *RemoteSigner) error {
 	// log output received msg
 	logMsgReceived(input.Number(), input.Hash(), inputMsgCode, p)
 
+	// collect equivocation evidence before the fsm drops conflicting msgs
+	vh.detectEquivocation(input, inputMsgCode)
+
 	// if number is larger than local current number, sync from remote peer
 	if input.Number() > currentNumber+1 && p != nil {
 		go vh.dpos.SyncFrom(p.Peer)
@@ -54,6 +54,10 @@ var (
 	// ErrInvalidGasLimit is returned if the gasLimit of a block is invalid
 	ErrInvalidGasLimit = errors.New("invalid gas limit for the block")
 
+	// ErrInvalidExtra is returned if the extra-data of a block is not exactly the
+	// vanity length after Cep2
+	ErrInvalidExtra = errors.New("invalid extra-data length for the block")
+
 	// errInvalidChain is returned if an authorization list is attempted to
 	// be modified via out-of-range or non-contiguous headers.
 	errInvalidChain = errors.New("invalid voting chain")
@@ -117,6 +121,56 @@ func (d *Dpos) VerifyHeaders(chain consensus.ChainReader, headers []*types.Heade
 	return abort, results
 }
 
+// VerifyLightHeaders verifies a batch of headers for a light client following the chain by headers only.
+// Besides basic fields and 2f+1 validator signatures of every header, the seal is checked against the proposer
+// listed in the header itself, since a light client runs no election. The listed proposers are certified by the
+// validators, who sign them as part of the header.
+func (d *Dpos) VerifyLightHeaders(chain consensus.ChainReader, headers []*types.Header) (chan<- struct{}, <-chan error) {
+	abort := make(chan struct{})
+	results := make(chan error, len(headers))
+
+	go func() {
+		for i, header := range headers {
+			err := d.dh.verifyHeader(d, chain, header, headers[:i], nil, true, false)
+			if err == nil {
+				err = d.verifyLightSeal(header)
+			}
+
+			select {
+			case <-abort:
+				return
+			case results <- err:
+			}
+		}
+	}()
+	return abort, results
+}
+
+// verifyLightSeal checks whether the seal is signed by the proposer of the block's view listed in the header.
+func (d *Dpos) verifyLightSeal(header *types.Header) error {
+	number := header.Number.Uint64()
+	if number == 0 || header.Impeachment() {
+		return nil
+	}
+
+	// Fake Dpos doesn't do seal check
+	if d.Mode() == FakeMode || d.Mode() == DoNothingFakeMode {
+		return nil
+	}
+
+	proposer, _, err := d.dh.ecrecover(header, d.finalSigs)
+	if err != nil {
+		return err
+	}
+
+	proposers := header.Dpos.Proposers
+	idx := ((number - 1) % (d.config.TermLen * d.config.ViewLen)) % d.config.TermLen
+	if idx >= uint64(len(proposers)) || proposers[idx] != proposer {
+		return consensus.ErrUnauthorized
+	}
+	return nil
+}
+
 // VerifySeal implements consensus.Engine, checking whether the signature contained
 // in the header satisfies the consensus protocol requirements.
 func (d *Dpos) VerifySeal(chain consensus.ChainReader, header *types.Header, refHeader *types.Header) error {
@@ -273,6 +327,15 @@ func (d *Dpos) Authorize(signer common.Address, signFn backend.SignFn) {
 	}
 }
 
+// SetConsensusSigner sets the signer of the headers and handshake macs of the coinbase
+// authorized with Authorize, nil signs their hashes with the sign function.
+func (d *Dpos) SetConsensusSigner(signer backend.ConsensusSigner) {
+	d.coinbaseLock.Lock()
+	defer d.coinbaseLock.Unlock()
+
+	d.signer = signer
+}
+
 // Seal implements consensus.Engine, attempting to create a sealed block using
 // the local signing credentials.
 // NB please populate the correct field values.  we are now removing some fields such as nonce.
@@ -282,7 +345,6 @@ func (d *Dpos) Seal(chain consensus.ChainReader, block *types.Block, stop <-chan
 		number = header.Number.Uint64()
 
 		coinbase = d.Coinbase()
-		signFn   = d.SignHash
 	)
 
 	// Sealing the genesis block is not supported
@@ -329,7 +391,7 @@ func (d *Dpos) Seal(chain consensus.ChainReader, block *types.Block, stop <-chan
 	}
 
 	// Proposer seals the block with signature
-	sighash, err := signFn(d.dh.sigHash(header).Bytes())
+	sighash, err := d.signHeader(header, consensus.Idle)
 	if err != nil {
 		return nil, err
 	}
@@ -6,7 +6,12 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/gcchains/chain/configs"
+	"github.com/gcchains/chain/consensus"
 	"github.com/gcchains/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -72,4 +77,40 @@ func TestDpos_APIs(t *testing.T) {
 	assert.Equal(t, 1, len(got), "only 1 api should be created")
 }
 
+func TestDpos_verifyLightSeal(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	signer := crypto.PubkeyToAddress(key.PublicKey)
+	other := common.HexToAddress("0x01")
+
+	finalSigs, _ := lru.NewARC(inMemorySignatures)
+	d := &Dpos{
+		dh:        &defaultDposHelper{&defaultDposUtil{}},
+		config:    &configs.DposConfig{TermLen: 2, ViewLen: 2},
+		finalSigs: finalSigs,
+	}
+
+	// the proposer of block 2 is the second one in the committee
+	tests := []struct {
+		name      string
+		proposers []common.Address
+		wantErr   error
+	}{
+		{"proposer of the view", []common.Address{other, signer}, nil},
+		{"proposer of another view", []common.Address{signer, other}, consensus.ErrUnauthorized},
+		{"not a proposer", []common.Address{other, other}, consensus.ErrUnauthorized},
+		{"missing proposers", nil, consensus.ErrUnauthorized},
+	}
+	for _, tt := range tests {
+		header := newHeader()
+		header.Number.SetUint64(2)
+		header.Dpos.Proposers = tt.proposers
+		sig, _ := crypto.Sign(d.dh.sigHash(header).Bytes(), key)
+		copy(header.Dpos.Seal[:], sig)
+
+		if err := d.verifyLightSeal(header); err != tt.wantErr {
+			t.Errorf("%s: verifyLightSeal() error = %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
 // ========================================================
@@ -14,6 +14,9 @@ import (
 	"github.com/gcchains/chain/consensus"
 	"github.com/gcchains/chain/consensus/dpos/backend"
 	"github.com/gcchains/chain/consensus/dpos/campaign"
+	"github.com/gcchains/chain/consensus/dpos/election"
+	"github.com/gcchains/chain/consensus/dpos/evidence"
+	"github.com/gcchains/chain/consensus/dpos/governance"
 	"github.com/gcchains/chain/consensus/dpos/rnode"
 	"github.com/gcchains/chain/consensus/dpos/rpt"
 	"github.com/gcchains/chain/database"
@@ -68,12 +71,15 @@ type Dpos struct {
 	currentSnap     *DposSnapshot // Current snapshot
 	currentSnapLock sync.RWMutex
 
-	coinbase     common.Address // Coinbase of the miner(proposer or validator)
-	signFn       backend.SignFn // Sign function to authorize hashes with
-	coinbaseLock sync.RWMutex   // Protects the signer fields
+	coinbase     common.Address          // Coinbase of the miner(proposer or validator)
+	signFn       backend.SignFn          // Sign function to authorize hashes with
+	signer       backend.ConsensusSigner // Signer of headers and macs, nil to sign their hashes with signFn
+	coinbaseLock sync.RWMutex            // Protects the signer fields
 
 	handler *backend.Handler
 
+	lbft2Tracer *backend.Tracer // records the steps of lbft2 fsm of a validator, nil if not traced
+
 	isMiner     bool
 	isMinerLock sync.RWMutex
 
@@ -93,6 +99,8 @@ type Dpos struct {
 	rptBackend      rpt.RptService
 	campaignBackend campaign.CandidateService
 
+	governanceBackend governance.ParamService // parameters adopted by the governance contract, nil if it is not deployed
+
 	chain consensus.ChainReadWriter
 
 	pmBroadcastBlockFn   BroadcastBlockFn
@@ -122,6 +130,24 @@ func (d *Dpos) SignHash(hash []byte) ([]byte, error) {
 	return d.signFn(account, hash)
 }
 
+// signHeader signs the header in the state with dpos coinbase account, by the consensus
+// signer if any, or else by signing the hash of the header in the state
+func (d *Dpos) signHeader(header *types.Header, state consensus.State) ([]byte, error) {
+	d.coinbaseLock.RLock()
+	signer, account := d.signer, accounts.Account{Address: d.coinbase}
+	d.coinbaseLock.RUnlock()
+
+	if signer != nil {
+		return signer.SignHeader(account, header, state)
+	}
+
+	hash, err := headerHashToSign(d.dh, header, state)
+	if err != nil {
+		return nil, err
+	}
+	return d.SignHash(hash)
+}
+
 // IsMiner returns if local coinbase is a miner(proposer or validator)
 func (d *Dpos) IsMiner() bool {
 	d.isMinerLock.RLock()
@@ -201,6 +227,13 @@ func New(config *configs.DposConfig, db database.Database) *Dpos {
 		return nil
 	}
 
+	for _, strategy := range conf.ElectionStrategies {
+		if _, err := election.StrategyOf(strategy.Strategy); err != nil {
+			log.Fatal("wrong election strategy configuration", "strategy", strategy.Strategy, "from block", strategy.FromBlock, "err", err)
+			return nil
+		}
+	}
+
 	// Allocate the Snapshot caches and create the engine
 	recentSnaps, _ := lru.NewARC(inMemorySnapshots)
 	finalSigs, _ := lru.NewARC(inMemorySignatures)
@@ -261,6 +294,14 @@ func (d *Dpos) SetHandler(handler *backend.Handler) error {
 	return nil
 }
 
+// Dialer returns the dialer of dpos.handler
+func (d *Dpos) Dialer() *backend.Dialer {
+	if d.handler == nil {
+		return nil
+	}
+	return d.handler.Dialer()
+}
+
 // IfSigned checks if already signed a block
 func (d *Dpos) IfSigned(number uint64) (common.Hash, bool) {
 	return d.signedBlocks.ifAlreadySigned(number)
@@ -322,6 +363,7 @@ func (d *Dpos) initMinerAndValidator(blockchain consensus.ChainReadWriter, serve
 
 	if d.IsValidator() {
 		fsm := backend.NewLBFT2(faulty, d, handler.ReceiveImpeachBlock, handler.ReceiveFailbackImpeachBlock, d.db)
+		fsm.SetTracer(d.lbft2Tracer)
 		handler.SetDposStateMachine(fsm)
 	}
 
@@ -393,6 +435,12 @@ func (d *Dpos) ImpeachTimeout() time.Duration {
 	return d.config.ImpeachTimeout
 }
 
+// SetLBFT2Tracer sets the tracer recording the steps of lbft2 fsm, it takes effect when
+// the validator is set up
+func (d *Dpos) SetLBFT2Tracer(tracer *backend.Tracer) {
+	d.lbft2Tracer = tracer
+}
+
 // SetupAdmission setups admission backend
 func (d *Dpos) SetupAdmission(ac admission.ApiBackend) {
 	d.ac = ac
@@ -402,10 +450,19 @@ func (d *Dpos) SetRptBackend(rptContract common.Address, backend backend.ClientB
 	d.rptBackend, _ = rpt.NewRptService(rptContract, backend)
 }
 
+// GetRptBackend returns the rpt service, with the election configs adopted by the governance contract if any
 func (d *Dpos) GetRptBackend() rpt.RptService {
+	if d.governanceBackend != nil && d.rptBackend != nil {
+		return governance.NewRptService(d.rptBackend, d.governanceBackend)
+	}
 	return d.rptBackend
 }
 
+// SetGovernanceBackend sets the service reading the parameters adopted by the governance contract
+func (d *Dpos) SetGovernanceBackend(governanceContract common.Address, backend backend.ClientBackend) {
+	d.governanceBackend, _ = governance.NewParamService(governanceContract, backend)
+}
+
 func (d *Dpos) SetCampaignBackend(campaignContract common.Address, backend backend.ClientBackend) {
 	d.campaignBackend, _ = campaign.NewCampaignService(campaignContract, backend)
 }
@@ -425,3 +482,11 @@ func (d *Dpos) GetRNodes() ([]common.Address, error) {
 
 	return []common.Address{}, nil
 }
+
+// EvidencePool returns the pool of equivocation evidence collected by dpos handler
+func (d *Dpos) EvidencePool() *evidence.Pool {
+	if d.handler != nil {
+		return d.handler.EvidencePool()
+	}
+	return nil
+}
@@ -189,6 +189,11 @@ func (dh *defaultDposHelper) verifyBasic(dpos *Dpos, chain consensus.ChainReader
 		return ErrInvalidGasLimit
 	}
 
+	// Ensure that the block's extra-data only contains the vanity after Cep2
+	if chain.Config().IsCep2(header.Number) && !isImpeach && len(header.Extra) != extraVanity {
+		return ErrInvalidExtra
+	}
+
 	if isImpeach {
 		return dh.verifyBasicImpeach(dpos, chain, header, parent)
 	}
@@ -670,15 +675,8 @@ func (dh *defaultDposHelper) signHeader(dpos *Dpos, chain consensus.ChainReader,
 			return errMultiBlocksInOneHeight
 		}
 
-		// get hash with state
-		hashToSign, err := hashBytesWithState(dpos.dh.sigHash(header).Bytes(), state)
-		if err != nil {
-			log.Warn("failed to get hash bytes with state", "number", number, "hash", hash.Hex(), "state", state)
-			return err
-		}
-
-		// Sign it
-		sighash, err := dpos.SignHash(hashToSign)
+		// Sign it with state
+		sighash, err := dpos.signHeader(header, state)
 		if err != nil {
 			log.Warn("signing block header failed", "error", err)
 			return err
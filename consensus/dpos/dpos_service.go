@@ -413,8 +413,16 @@ func (d *Dpos) GetMac() (mac string, sig []byte, err error) {
 
 	log.Debug("generated mac", "mac", mac)
 
-	// sign it!
-	sig, err = d.signFn(accounts.Account{Address: d.Coinbase()}, hash.Bytes())
+	d.coinbaseLock.RLock()
+	signer, account := d.signer, accounts.Account{Address: d.coinbase}
+	d.coinbaseLock.RUnlock()
+
+	// sign it, the consensus signer checks the mac before
+	if signer != nil {
+		sig, err = signer.SignMac(account, mac)
+		return mac, sig, err
+	}
+	sig, err = d.signFn(account, hash.Bytes())
 
 	return mac, sig, err
 }
@@ -248,3 +248,17 @@ func hashBytesWithState(hash []byte, state consensus.State) (signHashBytes []byt
 	signHashBytes = signHash.Bytes()
 	return
 }
+
+// HeaderHashToSign returns the hash signed for the header in the state, the proposer seals
+// the header in idle state and the validators sign it in the others.
+func HeaderHashToSign(header *types.Header, state consensus.State) ([]byte, error) {
+	return headerHashToSign(new(defaultDposUtil), header, state)
+}
+
+func headerHashToSign(du dposUtil, header *types.Header, state consensus.State) ([]byte, error) {
+	hash := du.sigHash(header).Bytes()
+	if state == consensus.Idle {
+		return hash, nil
+	}
+	return hashBytesWithState(hash, state)
+}
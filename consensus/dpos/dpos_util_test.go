@@ -251,6 +251,14 @@ func (f *fakeDBForSignedBlocksRecord) Close() {
 	panic("not implemented")
 }
 
+func (f *fakeDBForSignedBlocksRecord) NewIterator(prefix []byte, start []byte) database.Iterator {
+	panic("not implemented")
+}
+
+func (f *fakeDBForSignedBlocksRecord) DeleteRange(start, end []byte) error {
+	panic("not implemented")
+}
+
 func (f *fakeDBForSignedBlocksRecord) NewBatch() database.Batch {
 	panic("not implemented")
 }
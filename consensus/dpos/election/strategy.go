@@ -0,0 +1,120 @@
+package election
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+
+	"github.com/gcchains/chain/consensus/dpos/rpt"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Names of built-in election strategies, they are used in dpos config to select a strategy
+const (
+	// RptStrategy elects proposers randomly weighted by rpt, with seats reserved for low rpt candidates
+	RptStrategy = "rpt"
+
+	// StakeStrategy elects proposers randomly weighted by candidates' stakes
+	StakeStrategy = "stake"
+
+	// RoundRobinStrategy elects proposers in turn, it is deterministic and only meant for test networks
+	RoundRobinStrategy = "roundRobin"
+
+	// DefaultStrategy is used if no strategy is configured for a block
+	DefaultStrategy = RptStrategy
+)
+
+// ErrUnknownStrategy is returned if an election strategy name is not registered
+var ErrUnknownStrategy = errors.New("unknown election strategy")
+
+// Params is the input of an election
+type Params struct {
+	Candidates  rpt.RptList // candidates with their weights, rpts or stakes depending on the strategy
+	Seed        int64       // random seed derived from the checkpoint block hash
+	Term        uint64      // the term elected proposers serve for
+	TotalSeats  int
+	LowRptCount int
+	LowRptSeats int
+}
+
+// ElectionStrategy elects a proposers committee from candidates
+type ElectionStrategy interface {
+	// Name returns the name used to select the strategy in config
+	Name() string
+
+	// Elect returns exactly TotalSeats proposers, or an empty list if there are not enough candidates
+	Elect(params Params) []common.Address
+}
+
+var strategies = map[string]ElectionStrategy{
+	RptStrategy:        rptStrategy{},
+	StakeStrategy:      stakeStrategy{},
+	RoundRobinStrategy: roundRobinStrategy{},
+}
+
+// StrategyOf returns the election strategy with given name, the default one is returned for an empty name
+func StrategyOf(name string) (ElectionStrategy, error) {
+	if name == "" {
+		name = DefaultStrategy
+	}
+	if strategy, ok := strategies[name]; ok {
+		return strategy, nil
+	}
+	return nil, ErrUnknownStrategy
+}
+
+// rptStrategy is the original rpt weighted election
+type rptStrategy struct{}
+
+func (rptStrategy) Name() string { return RptStrategy }
+
+func (rptStrategy) Elect(params Params) []common.Address {
+	return Elect(params.Candidates, params.Seed, params.TotalSeats, params.LowRptCount, params.LowRptSeats)
+}
+
+// stakeStrategy selects proposers among all candidates weighted by their stakes, no seat is reserved
+type stakeStrategy struct{}
+
+func (stakeStrategy) Name() string { return StakeStrategy }
+
+func (stakeStrategy) Elect(params Params) []common.Address {
+	if params.TotalSeats > params.Candidates.Len() {
+		return []common.Address{}
+	}
+
+	// every candidate has a chance even without stake, this also avoids a zero sum
+	stakes := make(rpt.RptList, 0, params.Candidates.Len())
+	for _, c := range params.Candidates {
+		if c.Rpt <= 0 {
+			c.Rpt = 1
+		}
+		stakes = append(stakes, c)
+	}
+
+	myRand := rand.New(rand.NewSource(params.Seed))
+	return randomSelectByRpt(stakes, myRand, params.TotalSeats)
+}
+
+// roundRobinStrategy sorts candidates by address and takes seats in turn, starting from an offset moved by term
+type roundRobinStrategy struct{}
+
+func (roundRobinStrategy) Name() string { return RoundRobinStrategy }
+
+func (roundRobinStrategy) Elect(params Params) []common.Address {
+	n := params.Candidates.Len()
+	if params.TotalSeats > n {
+		return []common.Address{}
+	}
+
+	addrs := params.Candidates.Addrs()
+	sort.Slice(addrs, func(i, j int) bool {
+		return addrs[i].Big().Cmp(addrs[j].Big()) < 0
+	})
+
+	start := int(params.Term * uint64(params.TotalSeats) % uint64(n))
+	result := make([]common.Address, 0, params.TotalSeats)
+	for i := 0; i < params.TotalSeats; i++ {
+		result = append(result, addrs[(start+i)%n])
+	}
+	return result
+}
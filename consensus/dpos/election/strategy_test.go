@@ -0,0 +1,109 @@
+package election
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/gcchains/chain/consensus/dpos/rpt"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// newTestCandidates creates candidates with addresses 0x01, 0x02, ... and given weights
+func newTestCandidates(weights ...int64) rpt.RptList {
+	var candidates rpt.RptList
+	for i, w := range weights {
+		candidates = append(candidates, rpt.Rpt{Address: common.BigToAddress(big.NewInt(int64(i + 1))), Rpt: w})
+	}
+	return candidates
+}
+
+func TestStrategyOf(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr error
+	}{
+		{"", RptStrategy, nil},
+		{RptStrategy, RptStrategy, nil},
+		{StakeStrategy, StakeStrategy, nil},
+		{RoundRobinStrategy, RoundRobinStrategy, nil},
+		{"unknown", "", ErrUnknownStrategy},
+	}
+	for _, tt := range tests {
+		strategy, err := StrategyOf(tt.name)
+		if err != tt.wantErr {
+			t.Errorf("StrategyOf(%q) error = %v, want %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err == nil && strategy.Name() != tt.want {
+			t.Errorf("StrategyOf(%q) = %v, want %v", tt.name, strategy.Name(), tt.want)
+		}
+	}
+}
+
+func TestRptStrategy_Elect(t *testing.T) {
+	strategy, _ := StrategyOf(RptStrategy)
+
+	params := Params{Candidates: newTestCandidates(10, 20, 30, 40, 50, 60), Seed: 7, TotalSeats: 4, LowRptCount: 2, LowRptSeats: 1}
+	want := Elect(newTestCandidates(10, 20, 30, 40, 50, 60), 7, 4, 2, 1)
+
+	if got := strategy.Elect(params); !reflect.DeepEqual(got, want) {
+		t.Errorf("Elect() = %v, want %v", got, want)
+	}
+}
+
+func TestStakeStrategy_Elect(t *testing.T) {
+	strategy, _ := StrategyOf(StakeStrategy)
+
+	// candidates without stake can still be elected
+	params := Params{Candidates: newTestCandidates(0, 0, 0, 100), Seed: 1, TotalSeats: 4}
+	got := strategy.Elect(params)
+	if len(got) != 4 {
+		t.Fatalf("Elect() elected %d proposers, want %d", len(got), 4)
+	}
+
+	// deterministic with the same seed
+	if again := strategy.Elect(params); !reflect.DeepEqual(got, again) {
+		t.Errorf("Elect() = %v, then %v with the same seed", got, again)
+	}
+
+	// the candidate with all stakes is almost always elected first
+	hits := 0
+	for seed := int64(0); seed < 100; seed++ {
+		elected := strategy.Elect(Params{Candidates: newTestCandidates(1, 1, 1, 10000), Seed: seed, TotalSeats: 1})
+		if elected[0] == common.BigToAddress(big.NewInt(4)) {
+			hits++
+		}
+	}
+	if hits < 90 {
+		t.Errorf("Elect() elected the largest stake %d times in 100 elections", hits)
+	}
+
+	// not enough candidates
+	if got := strategy.Elect(Params{Candidates: newTestCandidates(1, 2), TotalSeats: 3}); len(got) != 0 {
+		t.Errorf("Elect() = %v, want empty", got)
+	}
+}
+
+func TestRoundRobinStrategy_Elect(t *testing.T) {
+	strategy, _ := StrategyOf(RoundRobinStrategy)
+	addr := func(i int64) common.Address { return common.BigToAddress(big.NewInt(i)) }
+
+	tests := []struct {
+		term uint64
+		want []common.Address
+	}{
+		{0, []common.Address{addr(1), addr(2)}},
+		{1, []common.Address{addr(3), addr(4)}},
+		{2, []common.Address{addr(5), addr(1)}},
+		{3, []common.Address{addr(2), addr(3)}},
+	}
+	for _, tt := range tests {
+		// weights and seed do not matter
+		params := Params{Candidates: newTestCandidates(50, 40, 30, 20, 10), Seed: int64(tt.term) * 31, Term: tt.term, TotalSeats: 2}
+		if got := strategy.Elect(params); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Elect() at term %d = %v, want %v", tt.term, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package evidence
+
+import (
+	"github.com/gcchains/chain/commons/log"
+	"github.com/gcchains/chain/consensus"
+	"github.com/gcchains/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+const (
+	// maxObservedSigners is the number of (kind, signer, height) observations kept in memory
+	maxObservedSigners = 4096
+)
+
+// observation identifies what a signer signed at a height
+type observation struct {
+	kind   Kind
+	signer common.Address
+	number uint64
+}
+
+// Detector observes headers exchanged in consensus msgs and finds equivocations
+type Detector struct {
+	committee Committee
+	pool      *Pool
+	observed  *lru.ARCCache // observation -> *types.Header
+}
+
+// NewDetector creates a detector, found evidence is added to the pool
+func NewDetector(committee Committee, pool *Pool) *Detector {
+	observed, _ := lru.NewARC(maxObservedSigners)
+	return &Detector{
+		committee: committee,
+		pool:      pool,
+		observed:  observed,
+	}
+}
+
+// ObserveSeal observes the proposer's seal of a header, returns new evidence if the proposer sealed
+// a different header at the same height
+func (d *Detector) ObserveSeal(header *types.Header) []*Evidence {
+	if header == nil || header.Impeachment() || header.Dpos.Seal.IsEmpty() {
+		return nil
+	}
+
+	proposer, err := d.committee.ECRecoverProposer(header)
+	if err != nil {
+		log.Debug("failed to recover proposer when observing seal", "number", header.Number.Uint64(), "hash", header.Hash().Hex(), "err", err)
+		return nil
+	}
+
+	if ev := d.observe(DoubleSeal, proposer, header); ev != nil {
+		return []*Evidence{ev}
+	}
+	return nil
+}
+
+// ObserveSigs observes validators' signatures in a header signed with given state, returns new evidence
+// for each validator who signed a different header at the same height
+func (d *Detector) ObserveSigs(header *types.Header, state consensus.State) []*Evidence {
+	if header == nil || header.Impeachment() {
+		return nil
+	}
+
+	var kind Kind
+	switch state {
+	case consensus.Prepare:
+		kind = DoublePrepare
+	case consensus.Commit:
+		kind = DoubleCommit
+	default:
+		return nil
+	}
+
+	signers, _, err := d.committee.ECRecoverSigs(header, state)
+	if err != nil {
+		log.Debug("failed to recover signers when observing sigs", "number", header.Number.Uint64(), "hash", header.Hash().Hex(), "err", err)
+		return nil
+	}
+
+	var evidence []*Evidence
+	for _, signer := range signers {
+		if ev := d.observe(kind, signer, header); ev != nil {
+			evidence = append(evidence, ev)
+		}
+	}
+	return evidence
+}
+
+// observe records the header signed by the signer, returns a new evidence if it conflicts with a recorded one
+func (d *Detector) observe(kind Kind, signer common.Address, header *types.Header) *Evidence {
+	key := observation{
+		kind:   kind,
+		signer: signer,
+		number: header.Number.Uint64(),
+	}
+
+	seen, ok := d.observed.Get(key)
+	if !ok {
+		d.observed.Add(key, types.CopyHeader(header))
+		return nil
+	}
+
+	if seenHeader := seen.(*types.Header); seenHeader.Hash() != header.Hash() {
+		ev := NewEvidence(kind, signer, seenHeader, header)
+		if added, err := d.pool.Add(ev, d.committee); added && err == nil {
+			return ev
+		}
+	}
+	return nil
+}
@@ -0,0 +1,268 @@
+// Package evidence implements equivocation evidence collection for dpos proposers and validators.
+package evidence
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/gcchains/chain/consensus"
+	"github.com/gcchains/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto/sha3"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var (
+	// ErrUnknownKind is returned if the kind of evidence is unknown
+	ErrUnknownKind = errors.New("unknown evidence kind")
+
+	// ErrNilHeader is returned if one of the conflicting headers is nil
+	ErrNilHeader = errors.New("nil header in evidence")
+
+	// ErrNumberMismatch is returned if the conflicting headers are not at the height of the evidence
+	ErrNumberMismatch = errors.New("conflicting headers are not at the same height")
+
+	// ErrSameHeader is returned if the conflicting headers are the same one
+	ErrSameHeader = errors.New("conflicting headers are the same")
+
+	// ErrImpeachHeader is returned if one of the conflicting headers is an impeachment header,
+	// validators are allowed to sign an impeachment block besides the normal one at the same height
+	ErrImpeachHeader = errors.New("impeachment header is not an equivocation")
+
+	// ErrOffenderNotSigned is returned if the offender's signature is not found in one of the conflicting headers
+	ErrOffenderNotSigned = errors.New("offender did not sign the conflicting header")
+
+	// ErrOffenderNotProposer is returned if the offender of a double seal is not in the proposers committee
+	ErrOffenderNotProposer = errors.New("offender is not a proposer of the height")
+
+	// ErrOffenderNotValidator is returned if the offender of a double prepare or commit is not in the validators committee
+	ErrOffenderNotValidator = errors.New("offender is not a validator of the height")
+
+	// ErrFutureEvidence is returned if the evidence is of a height after the one being agreed on
+	ErrFutureEvidence = errors.New("evidence of a future height")
+
+	// ErrStaleEvidence is returned if the committee of the evidence height is too old to be checked
+	ErrStaleEvidence = errors.New("evidence of a stale height")
+)
+
+const (
+	// maxEvidenceTerms is how many terms before the current one evidence is accepted, the committees of them
+	// are kept by dpos engine
+	maxEvidenceTerms = 100
+)
+
+// Kind is the type of misbehavior an evidence proves
+type Kind uint8
+
+// Those are kinds of misbehavior
+const (
+	// DoubleSeal is a proposer sealed two different headers at the same height
+	DoubleSeal Kind = iota
+
+	// DoublePrepare is a validator signed two different headers at the same height with prepare state
+	DoublePrepare
+
+	// DoubleCommit is a validator signed two different headers at the same height with commit state
+	DoubleCommit
+)
+
+var kindName = map[Kind]string{
+	DoubleSeal:    "DoubleSeal",
+	DoublePrepare: "DoublePrepare",
+	DoubleCommit:  "DoubleCommit",
+}
+
+func (k Kind) String() string {
+	if name, ok := kindName[k]; ok {
+		return name
+	}
+	return "Unknown Kind"
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (k Kind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (k *Kind) UnmarshalText(input []byte) error {
+	for kind, name := range kindName {
+		if name == string(input) {
+			*k = kind
+			return nil
+		}
+	}
+	return ErrUnknownKind
+}
+
+// state returns the consensus state with which a header is signed for the kind
+func (k Kind) state() consensus.State {
+	switch k {
+	case DoublePrepare:
+		return consensus.Prepare
+	case DoubleCommit:
+		return consensus.Commit
+	default:
+		return consensus.Idle
+	}
+}
+
+// SignerRecoverer recovers signers from a signed header, it is implemented by dpos engine
+type SignerRecoverer interface {
+	// ECRecoverProposer recovers proposer's address from a seal of a header
+	ECRecoverProposer(header *types.Header) (common.Address, error)
+
+	// ECRecoverSigs recovers signer addresses and corresponding signatures from a header signed with given state
+	ECRecoverSigs(header *types.Header, state consensus.State) ([]common.Address, []types.DposSignature, error)
+}
+
+// Committee checks if an address is in the committee of a height, it is implemented by dpos engine
+type Committee interface {
+	SignerRecoverer
+
+	// GetCurrentBlock returns current block
+	GetCurrentBlock() *types.Block
+
+	// TermOf returns the term number of given block number
+	TermOf(number uint64) uint64
+
+	// VerifyProposerOf verifies if an address is a proposer of given term
+	VerifyProposerOf(signer common.Address, term uint64) (bool, error)
+
+	// VerifyValidatorOf verifies if an address is a validator of given term
+	VerifyValidatorOf(signer common.Address, term uint64) (bool, error)
+}
+
+// Evidence is a self-verifying proof that an offender signed two different headers at the same height
+type Evidence struct {
+	Kind     Kind           `json:"kind"`
+	Offender common.Address `json:"offender"`
+	Number   uint64         `json:"number"`
+	HeaderA  *types.Header  `json:"headerA"`
+	HeaderB  *types.Header  `json:"headerB"`
+}
+
+// NewEvidence creates an evidence with given conflicting headers, the headers are ordered by hash
+// so that the same equivocation always results in the same evidence
+func NewEvidence(kind Kind, offender common.Address, a *types.Header, b *types.Header) *Evidence {
+	a, b = types.CopyHeader(a), types.CopyHeader(b)
+	if ha, hb := a.Hash(), b.Hash(); bytes.Compare(ha[:], hb[:]) > 0 {
+		a, b = b, a
+	}
+
+	return &Evidence{
+		Kind:     kind,
+		Offender: offender,
+		Number:   a.Number.Uint64(),
+		HeaderA:  a,
+		HeaderB:  b,
+	}
+}
+
+// Hash returns the identifier of the evidence
+func (e *Evidence) Hash() (hash common.Hash) {
+	hasher := sha3.NewKeccak256()
+	rlp.Encode(hasher, []interface{}{
+		e.Kind,
+		e.Offender,
+		e.Number,
+		e.HeaderA.Hash(),
+		e.HeaderB.Hash(),
+	})
+	hasher.Sum(hash[:0])
+	return hash
+}
+
+func (e *Evidence) String() string {
+	return fmt.Sprintf("%v{offender: %x, number: %d, a: %x, b: %x}", e.Kind, e.Offender, e.Number, e.HeaderA.Hash(), e.HeaderB.Hash())
+}
+
+// Verify checks the evidence only with the signatures it carries, it does not depend on local chain
+func (e *Evidence) Verify(r SignerRecoverer) error {
+	if e.HeaderA == nil || e.HeaderB == nil || e.HeaderA.Number == nil || e.HeaderB.Number == nil {
+		return ErrNilHeader
+	}
+
+	if e.HeaderA.Number.Uint64() != e.Number || e.HeaderB.Number.Uint64() != e.Number {
+		return ErrNumberMismatch
+	}
+
+	if e.HeaderA.Hash() == e.HeaderB.Hash() {
+		return ErrSameHeader
+	}
+
+	if e.HeaderA.Impeachment() || e.HeaderB.Impeachment() {
+		return ErrImpeachHeader
+	}
+
+	for _, header := range []*types.Header{e.HeaderA, e.HeaderB} {
+		if err := e.verifyHeader(r, header); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyHeader checks if the offender signed the header
+func (e *Evidence) verifyHeader(r SignerRecoverer, header *types.Header) error {
+	switch e.Kind {
+	case DoubleSeal:
+		proposer, err := r.ECRecoverProposer(header)
+		if err != nil {
+			return err
+		}
+		if proposer != e.Offender {
+			return ErrOffenderNotSigned
+		}
+		return nil
+
+	case DoublePrepare, DoubleCommit:
+		signers, _, err := r.ECRecoverSigs(header, e.Kind.state())
+		if err != nil {
+			return err
+		}
+		for _, signer := range signers {
+			if signer == e.Offender {
+				return nil
+			}
+		}
+		return ErrOffenderNotSigned
+
+	default:
+		return ErrUnknownKind
+	}
+}
+
+// VerifyOffender checks the offender was in the committee of the evidence height, the proposers listed in
+// the conflicting headers are not trusted. Only heights up to the one being agreed on and of recent terms
+// are checked, the committees of others are not known.
+func (e *Evidence) VerifyOffender(c Committee) error {
+	current := c.GetCurrentBlock()
+	if current == nil || e.Number > current.NumberU64()+1 {
+		return ErrFutureEvidence
+	}
+	if c.TermOf(e.Number)+maxEvidenceTerms < c.TermOf(current.NumberU64()) {
+		return ErrStaleEvidence
+	}
+
+	var (
+		ok  bool
+		err error
+	)
+	switch e.Kind {
+	case DoubleSeal:
+		if ok, err = c.VerifyProposerOf(e.Offender, c.TermOf(e.Number)); err == nil && !ok {
+			err = ErrOffenderNotProposer
+		}
+
+	case DoublePrepare, DoubleCommit:
+		if ok, err = c.VerifyValidatorOf(e.Offender, c.TermOf(e.Number)); err == nil && !ok {
+			err = ErrOffenderNotValidator
+		}
+
+	default:
+		err = ErrUnknownKind
+	}
+	return err
+}
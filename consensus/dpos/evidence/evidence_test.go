@@ -0,0 +1,330 @@
+package evidence
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/gcchains/chain/consensus"
+	"github.com/gcchains/chain/database"
+	"github.com/gcchains/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// fakeRecoverer recovers signers from the header hash, prepare signatures are signed with a "Prepare" prefix
+type fakeRecoverer struct{}
+
+func hashWithState(header *types.Header, state consensus.State) []byte {
+	hash := header.Hash().Bytes()
+	if state == consensus.Prepare {
+		return crypto.Keccak256(append([]byte("Prepare"), hash...))
+	}
+	return hash
+}
+
+func (fakeRecoverer) ECRecoverProposer(header *types.Header) (common.Address, error) {
+	pubkey, err := crypto.SigToPub(header.Hash().Bytes(), header.Dpos.Seal[:])
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pubkey), nil
+}
+
+func (fakeRecoverer) ECRecoverSigs(header *types.Header, state consensus.State) ([]common.Address, []types.DposSignature, error) {
+	var (
+		signers    []common.Address
+		signatures []types.DposSignature
+	)
+	for _, sig := range header.Dpos.Sigs {
+		if sig.IsEmpty() {
+			continue
+		}
+		pubkey, err := crypto.SigToPub(hashWithState(header, state), sig[:])
+		if err != nil {
+			return nil, nil, err
+		}
+		signers = append(signers, crypto.PubkeyToAddress(*pubkey))
+		signatures = append(signatures, sig)
+	}
+	return signers, signatures, nil
+}
+
+// fakeCommittee recovers signers with fakeRecoverer, terms are of 10 blocks and every signer is in the
+// committees except the excluded ones
+type fakeCommittee struct {
+	fakeRecoverer
+	current  uint64
+	excluded map[common.Address]bool
+}
+
+func newFakeCommittee(current uint64, excluded ...common.Address) *fakeCommittee {
+	c := &fakeCommittee{current: current, excluded: make(map[common.Address]bool)}
+	for _, addr := range excluded {
+		c.excluded[addr] = true
+	}
+	return c
+}
+
+func (c *fakeCommittee) GetCurrentBlock() *types.Block {
+	return types.NewBlockWithHeader(&types.Header{Number: new(big.Int).SetUint64(c.current)})
+}
+
+func (c *fakeCommittee) TermOf(number uint64) uint64 {
+	return number / 10
+}
+
+func (c *fakeCommittee) VerifyProposerOf(signer common.Address, term uint64) (bool, error) {
+	return !c.excluded[signer], nil
+}
+
+func (c *fakeCommittee) VerifyValidatorOf(signer common.Address, term uint64) (bool, error) {
+	return !c.excluded[signer], nil
+}
+
+func newTestHeader(number int64, extra string, proposers ...common.Address) *types.Header {
+	return &types.Header{
+		Number:   big.NewInt(number),
+		Coinbase: common.HexToAddress("0x01"),
+		Time:     big.NewInt(0),
+		Extra:    []byte(extra),
+		Dpos:     types.DposSnap{Proposers: proposers},
+	}
+}
+
+func seal(t *testing.T, header *types.Header, key *ecdsa.PrivateKey) *types.Header {
+	sig, err := crypto.Sign(header.Hash().Bytes(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	copy(header.Dpos.Seal[:], sig)
+	return header
+}
+
+func sign(t *testing.T, header *types.Header, state consensus.State, key *ecdsa.PrivateKey) *types.Header {
+	sig, err := crypto.Sign(hashWithState(header, state), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var signature types.DposSignature
+	copy(signature[:], sig)
+	header.Dpos.Sigs = append(header.Dpos.Sigs, signature)
+	return header
+}
+
+func TestDetector_DoubleSeal(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	proposer := crypto.PubkeyToAddress(key.PublicKey)
+
+	pool := NewPool(database.NewMemDatabase())
+	detector := NewDetector(newFakeCommittee(10), pool)
+
+	a := seal(t, newTestHeader(10, "a", proposer), key)
+	b := seal(t, newTestHeader(10, "b", proposer), key)
+
+	if found := detector.ObserveSeal(a); len(found) != 0 {
+		t.Fatalf("ObserveSeal() found %d evidence for the first header", len(found))
+	}
+	if found := detector.ObserveSeal(a); len(found) != 0 {
+		t.Fatalf("ObserveSeal() found %d evidence for the same header", len(found))
+	}
+
+	found := detector.ObserveSeal(b)
+	if len(found) != 1 {
+		t.Fatalf("ObserveSeal() found %d evidence, want 1", len(found))
+	}
+	if ev := found[0]; ev.Kind != DoubleSeal || ev.Offender != proposer || ev.Number != 10 {
+		t.Errorf("ObserveSeal() = %v, want a double seal of %x at %d", ev, proposer, 10)
+	}
+
+	// the same equivocation is only reported once
+	if found := detector.ObserveSeal(b); len(found) != 0 {
+		t.Errorf("ObserveSeal() found %d evidence for a known equivocation", len(found))
+	}
+	if evidence := pool.Evidence(); len(evidence) != 1 {
+		t.Errorf("pool has %d evidence, want 1", len(evidence))
+	}
+}
+
+func TestDetector_DoublePrepareAndCommit(t *testing.T) {
+	key1, _ := crypto.GenerateKey()
+	key2, _ := crypto.GenerateKey()
+	validator1 := crypto.PubkeyToAddress(key1.PublicKey)
+
+	detector := NewDetector(newFakeCommittee(10), NewPool(database.NewMemDatabase()))
+
+	// validator1 and validator2 prepare a, only validator1 prepares b
+	a := sign(t, sign(t, newTestHeader(10, "a"), consensus.Prepare, key1), consensus.Prepare, key2)
+	b := sign(t, newTestHeader(10, "b"), consensus.Prepare, key1)
+
+	detector.ObserveSigs(a, consensus.Prepare)
+	found := detector.ObserveSigs(b, consensus.Prepare)
+	if len(found) != 1 || found[0].Kind != DoublePrepare || found[0].Offender != validator1 {
+		t.Fatalf("ObserveSigs() = %v, want a double prepare of %x", found, validator1)
+	}
+
+	// a commit of a different header is a different kind of equivocation
+	c := sign(t, newTestHeader(10, "c"), consensus.Commit, key1)
+	d := sign(t, newTestHeader(10, "d"), consensus.Commit, key1)
+	detector.ObserveSigs(c, consensus.Commit)
+	found = detector.ObserveSigs(d, consensus.Commit)
+	if len(found) != 1 || found[0].Kind != DoubleCommit || found[0].Offender != validator1 {
+		t.Fatalf("ObserveSigs() = %v, want a double commit of %x", found, validator1)
+	}
+}
+
+func TestDetector_IgnoreImpeachment(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+
+	detector := NewDetector(newFakeCommittee(10), NewPool(database.NewMemDatabase()))
+
+	normal := sign(t, newTestHeader(10, "a"), consensus.Prepare, key)
+	impeach := newTestHeader(10, "")
+	impeach.Coinbase = common.Address{}
+	impeach = sign(t, impeach, consensus.Prepare, key)
+
+	detector.ObserveSigs(normal, consensus.Prepare)
+	if found := detector.ObserveSigs(impeach, consensus.Prepare); len(found) != 0 {
+		t.Errorf("ObserveSigs() found %d evidence for an impeachment header", len(found))
+	}
+}
+
+func TestEvidence_Verify(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	other, _ := crypto.GenerateKey()
+	proposer := crypto.PubkeyToAddress(key.PublicKey)
+
+	a := seal(t, newTestHeader(10, "a", proposer), key)
+	b := seal(t, newTestHeader(10, "b", proposer), key)
+
+	tests := []struct {
+		name string
+		ev   *Evidence
+		want error
+	}{
+		{"valid", NewEvidence(DoubleSeal, proposer, a, b), nil},
+		{"same header", NewEvidence(DoubleSeal, proposer, a, a), ErrSameHeader},
+		{"different height", NewEvidence(DoubleSeal, proposer, a, seal(t, newTestHeader(11, "b", proposer), key)), ErrNumberMismatch},
+		{"forged seal", NewEvidence(DoubleSeal, proposer, a, seal(t, newTestHeader(10, "b", proposer), other)), ErrOffenderNotSigned},
+		{"unsigned prepare", NewEvidence(DoublePrepare, proposer, a, b), ErrOffenderNotSigned},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.ev.Verify(fakeRecoverer{}); err != tt.want {
+				t.Errorf("Verify() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	// the order of conflicting headers does not change the evidence
+	if NewEvidence(DoubleSeal, proposer, a, b).Hash() != NewEvidence(DoubleSeal, proposer, b, a).Hash() {
+		t.Error("Hash() differs with the order of conflicting headers")
+	}
+}
+
+func TestEvidence_VerifyOffender(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	offender := crypto.PubkeyToAddress(key.PublicKey)
+
+	// the proposers listed in the headers are not trusted
+	doubleSeal := NewEvidence(DoubleSeal, offender, seal(t, newTestHeader(10, "a", offender), key), seal(t, newTestHeader(10, "b", offender), key))
+	doublePrepare := NewEvidence(DoublePrepare, offender, sign(t, newTestHeader(10, "a"), consensus.Prepare, key), sign(t, newTestHeader(10, "b"), consensus.Prepare, key))
+
+	tests := []struct {
+		name      string
+		ev        *Evidence
+		committee *fakeCommittee
+		want      error
+	}{
+		{"proposer", doubleSeal, newFakeCommittee(10), nil},
+		{"not a proposer", doubleSeal, newFakeCommittee(10, offender), ErrOffenderNotProposer},
+		{"validator", doublePrepare, newFakeCommittee(10), nil},
+		{"not a validator", doublePrepare, newFakeCommittee(10, offender), ErrOffenderNotValidator},
+		{"height being agreed on", doubleSeal, newFakeCommittee(9), nil},
+		{"future", doubleSeal, newFakeCommittee(8), ErrFutureEvidence},
+		{"stale", doubleSeal, newFakeCommittee(10 + (maxEvidenceTerms+1)*10), ErrStaleEvidence},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.ev.VerifyOffender(tt.committee); err != tt.want {
+				t.Errorf("VerifyOffender() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPool_Persist(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	proposer := crypto.PubkeyToAddress(key.PublicKey)
+
+	db := database.NewMemDatabase()
+	ev := NewEvidence(DoubleSeal, proposer, seal(t, newTestHeader(10, "a", proposer), key), seal(t, newTestHeader(10, "b", proposer), key))
+
+	if added, err := NewPool(db).Add(ev, newFakeCommittee(10)); !added || err != nil {
+		t.Fatalf("Add() = %v, %v, want true, nil", added, err)
+	}
+
+	// a new pool loads known evidence from db
+	pool := NewPool(db)
+	if !pool.Has(ev.Hash()) {
+		t.Fatal("Has() = false for persisted evidence")
+	}
+	evidence := pool.Evidence()
+	if len(evidence) != 1 || evidence[0].Hash() != ev.Hash() {
+		t.Fatalf("Evidence() = %v, want [%v]", evidence, ev)
+	}
+	if err := evidence[0].Verify(fakeRecoverer{}); err != nil {
+		t.Errorf("Verify() of decoded evidence error = %v", err)
+	}
+}
+
+func TestPool_Limits(t *testing.T) {
+	defer func(total, perHeight int) {
+		maxEvidence, maxEvidencePerHeight = total, perHeight
+	}(maxEvidence, maxEvidencePerHeight)
+	maxEvidence, maxEvidencePerHeight = 4, 2
+
+	key, _ := crypto.GenerateKey()
+	proposer := crypto.PubkeyToAddress(key.PublicKey)
+	newEvidence := func(number int64, extra string) *Evidence {
+		return NewEvidence(DoubleSeal, proposer, seal(t, newTestHeader(number, "a"), key), seal(t, newTestHeader(number, extra), key))
+	}
+
+	db := database.NewMemDatabase()
+	pool := NewPool(db)
+	committee := newFakeCommittee(20)
+
+	var added []*Evidence
+	for _, ev := range []*Evidence{newEvidence(10, "b"), newEvidence(10, "c"), newEvidence(11, "b"), newEvidence(11, "c")} {
+		if ok, err := pool.Add(ev, committee); !ok || err != nil {
+			t.Fatalf("Add() = %v, %v, want true, nil", ok, err)
+		}
+		added = append(added, ev)
+	}
+
+	// a height is limited
+	if ok, err := pool.Add(newEvidence(10, "d"), committee); ok || err != ErrTooManyEvidence {
+		t.Fatalf("Add() = %v, %v, want false, %v", ok, err, ErrTooManyEvidence)
+	}
+
+	// the oldest evidence is dropped for a new one if the pool is full, which frees its height
+	ev := newEvidence(12, "b")
+	if ok, err := pool.Add(ev, committee); !ok || err != nil {
+		t.Fatalf("Add() = %v, %v, want true, nil", ok, err)
+	}
+	if pool.Has(added[0].Hash()) {
+		t.Error("Has() = true for the dropped evidence")
+	}
+	if ok, err := pool.Add(newEvidence(10, "d"), committee); !ok || err != nil {
+		t.Fatalf("Add() = %v, %v, want true, nil", ok, err)
+	}
+
+	// a new pool loads the index kept in db
+	evidence := NewPool(db).Evidence()
+	if len(evidence) != maxEvidence {
+		t.Fatalf("Evidence() has %d evidence, want %d", len(evidence), maxEvidence)
+	}
+	if evidence[0].Hash() != added[2].Hash() || evidence[2].Hash() != ev.Hash() {
+		t.Errorf("Evidence() = %v, want the order they are added", evidence)
+	}
+}
@@ -0,0 +1,211 @@
+package evidence
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/gcchains/chain/commons/log"
+	"github.com/gcchains/chain/database"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var (
+	// maxEvidence is the most evidence kept in the pool, the oldest one is dropped for a new one when it is full
+	maxEvidence = 1024
+
+	// maxEvidencePerHeight is the most evidence accepted at a height
+	maxEvidencePerHeight = 64
+)
+
+// ErrTooManyEvidence is returned if the pool has accepted maxEvidencePerHeight evidence at the height
+var ErrTooManyEvidence = errors.New("too many evidence at the height")
+
+// The fields below define the database schema used by evidence pool.
+var (
+	// evidenceIndexPrefix + seq (uint64 big endian) -> index entry, tracks all known evidence in the order they are added.
+	evidenceIndexPrefix = []byte("dpos-evidence-index-")
+
+	evidencePrefix = []byte("dpos-evidence-") // evidencePrefix + hash -> evidence
+)
+
+// NewEvidenceEvent is posted when a new evidence is added to the pool
+type NewEvidenceEvent struct {
+	Evidence *Evidence
+}
+
+// indexEntry is an evidence tracked by the index
+type indexEntry struct {
+	Seq    uint64 `rlp:"-"`
+	Number uint64
+	Hash   common.Hash
+}
+
+// Pool persists verified evidence in database
+type Pool struct {
+	db        database.Database
+	index     []indexEntry   // known evidence in the order they are added
+	perHeight map[uint64]int // number of known evidence at each height
+	nextSeq   uint64
+	lock      sync.RWMutex
+
+	feed  event.Feed
+	scope event.SubscriptionScope
+}
+
+// NewPool creates an evidence pool and loads the index of known evidence from db
+func NewPool(db database.Database) *Pool {
+	pool := &Pool{
+		db:        db,
+		perHeight: make(map[uint64]int),
+	}
+
+	it := db.NewIterator(evidenceIndexPrefix, nil)
+	defer it.Release()
+
+	for it.Next() {
+		key := it.Key()[len(evidenceIndexPrefix):]
+		if len(key) != 8 {
+			continue
+		}
+		var entry indexEntry
+		if err := rlp.DecodeBytes(it.Value(), &entry); err != nil {
+			log.Warn("failed to decode evidence index", "key", fmt.Sprintf("%x", it.Key()), "err", err)
+			continue
+		}
+		entry.Seq = binary.BigEndian.Uint64(key)
+		pool.index = append(pool.index, entry)
+		pool.perHeight[entry.Number]++
+		pool.nextSeq = entry.Seq + 1
+	}
+	if err := it.Error(); err != nil {
+		log.Warn("failed to load evidence index", "err", err)
+	}
+
+	return pool
+}
+
+// Add verifies the evidence and the offender against the committee, then persists it, returns true if it is a new one
+func (p *Pool) Add(ev *Evidence, c Committee) (bool, error) {
+	if err := ev.Verify(c); err != nil {
+		return false, err
+	}
+	if err := ev.VerifyOffender(c); err != nil {
+		return false, err
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	hash := ev.Hash()
+	if has, _ := p.db.Has(evidenceKey(hash)); has {
+		return false, nil
+	}
+	if p.perHeight[ev.Number] >= maxEvidencePerHeight {
+		return false, ErrTooManyEvidence
+	}
+
+	bytes, err := rlp.EncodeToBytes(ev)
+	if err != nil {
+		return false, err
+	}
+
+	entry := indexEntry{Seq: p.nextSeq, Number: ev.Number, Hash: hash}
+	index, err := rlp.EncodeToBytes(&entry)
+	if err != nil {
+		return false, err
+	}
+
+	batch := p.db.NewBatch()
+	batch.Put(evidenceKey(hash), bytes)
+	batch.Put(evidenceIndexKey(entry.Seq), index)
+
+	// drop the oldest evidence if the pool is full
+	dropped := 0
+	for ; len(p.index)-dropped >= maxEvidence; dropped++ {
+		batch.Delete(evidenceKey(p.index[dropped].Hash))
+		batch.Delete(evidenceIndexKey(p.index[dropped].Seq))
+	}
+	if err := batch.Write(); err != nil {
+		return false, err
+	}
+
+	for _, old := range p.index[:dropped] {
+		if p.perHeight[old.Number]--; p.perHeight[old.Number] == 0 {
+			delete(p.perHeight, old.Number)
+		}
+		log.Debug("dropped the oldest evidence", "number", old.Number, "hash", old.Hash.Hex())
+	}
+	p.index = append(p.index[dropped:], entry)
+	p.perHeight[ev.Number]++
+	p.nextSeq++
+
+	log.Warn("found equivocation evidence", "kind", ev.Kind, "offender", ev.Offender.Hex(), "number", ev.Number, "hash", hash.Hex())
+
+	go p.feed.Send(NewEvidenceEvent{Evidence: ev})
+
+	return true, nil
+}
+
+// Has returns if the evidence with given hash is known
+func (p *Pool) Has(hash common.Hash) bool {
+	has, _ := p.db.Has(evidenceKey(hash))
+	return has
+}
+
+// Get returns the evidence with given hash
+func (p *Pool) Get(hash common.Hash) *Evidence {
+	bytes, err := p.db.Get(evidenceKey(hash))
+	if err != nil {
+		return nil
+	}
+
+	ev := new(Evidence)
+	if err := rlp.DecodeBytes(bytes, ev); err != nil {
+		log.Warn("failed to decode evidence", "hash", hash.Hex(), "err", err)
+		return nil
+	}
+	return ev
+}
+
+// Evidence returns all known evidence in the order they are added
+func (p *Pool) Evidence() []*Evidence {
+	p.lock.RLock()
+	index := make([]indexEntry, len(p.index))
+	copy(index, p.index)
+	p.lock.RUnlock()
+
+	evidence := make([]*Evidence, 0, len(index))
+	for _, entry := range index {
+		if ev := p.Get(entry.Hash); ev != nil {
+			evidence = append(evidence, ev)
+		}
+	}
+	return evidence
+}
+
+// SubscribeNewEvidenceEvent registers a subscription of NewEvidenceEvent
+func (p *Pool) SubscribeNewEvidenceEvent(ch chan<- NewEvidenceEvent) event.Subscription {
+	return p.scope.Track(p.feed.Subscribe(ch))
+}
+
+// Stop closes all subscriptions
+func (p *Pool) Stop() {
+	p.scope.Close()
+}
+
+// evidenceKey = evidencePrefix + hash
+func evidenceKey(hash common.Hash) []byte {
+	return append(append([]byte{}, evidencePrefix...), hash.Bytes()...)
+}
+
+// evidenceIndexKey = evidenceIndexPrefix + seq (uint64 big endian)
+func evidenceIndexKey(seq uint64) []byte {
+	key := make([]byte, len(evidenceIndexPrefix)+8)
+	copy(key, evidenceIndexPrefix)
+	binary.BigEndian.PutUint64(key[len(evidenceIndexPrefix):], seq)
+	return key
+}
@@ -0,0 +1,150 @@
+// Package governance reads the dpos parameters adopted by the votes of rnodes in the governance contract.
+//
+// The adopted parameters override the ones of system contracts when the engine reads them, i.e. when the
+// election of a term runs at a checkpoint, so a parameter adopted in a term takes effect from a term boundary.
+package governance
+
+import (
+	"math/big"
+
+	"github.com/gcchains/chain/accounts/abi/bind"
+	"github.com/gcchains/chain/commons/log"
+	"github.com/gcchains/chain/consensus/dpos/rpt"
+	governanceContract "github.com/gcchains/chain/contracts/dpos/governance"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Names of the dpos parameters adopted by the governance contract
+const (
+	ParamTotalSeats       = "totalSeats"       // overrides totalSeats of rpt contract
+	ParamLowRptSeats      = "lowRptSeats"      // overrides lowRptSeats of rpt contract
+	ParamLowRptPercentage = "lowRptPercentage" // overrides lowRptPercentage of rpt contract
+)
+
+// ParamKey returns the key of the parameter in the governance contract, its name left aligned in bytes32 as a
+// solidity string literal.
+func ParamKey(name string) [32]byte {
+	var key [32]byte
+	copy(key[:], name)
+	return key
+}
+
+// ParamName returns the name of the parameter of the key.
+func ParamName(key [32]byte) string {
+	n := len(key)
+	for n > 0 && key[n-1] == 0 {
+		n--
+	}
+	return string(key[:n])
+}
+
+// ParamService provides methods to obtain the parameters adopted by the governance contract
+type ParamService interface {
+	// ParamOf returns the value of the parameter adopted at the block number
+	ParamOf(name string, number uint64) (value uint64, adopted bool, err error)
+}
+
+// ParamServiceImpl is the default parameter service
+type ParamServiceImpl struct {
+	client   bind.ContractBackend
+	contract common.Address
+}
+
+// NewParamService creates a concrete parameter service instance.
+func NewParamService(governanceContract common.Address, backend bind.ContractBackend) (ParamService, error) {
+	ps := &ParamServiceImpl{
+		contract: governanceContract,
+		client:   backend,
+	}
+	return ps, nil
+}
+
+// ParamOf implements ParamService, nothing is adopted before the governance contract is deployed.
+func (ps *ParamServiceImpl) ParamOf(name string, number uint64) (uint64, bool, error) {
+	instance, err := governanceContract.NewGovernance(ps.contract, ps.client)
+	if err != nil {
+		log.Debug("error when create governance instance", "err", err)
+		return 0, false, err
+	}
+
+	param, err := instance.GetParam(&bind.CallOpts{BlockNumber: new(big.Int).SetUint64(number)}, ParamKey(name))
+	if err == bind.ErrNoCode {
+		return 0, false, nil
+	}
+	if err != nil {
+		log.Debug("error when read param from governance contract", "param", name, "err", err)
+		return 0, false, err
+	}
+	if !param.Set {
+		return 0, false, nil
+	}
+	if !param.Value.IsUint64() {
+		log.Warn("param adopted by governance contract overflows", "param", name, "value", param.Value)
+		return 0, false, nil
+	}
+
+	log.Debug("now read param from governance contract", "param", name, "value", param.Value, "number", number, "contract addr", ps.contract.Hex())
+	return param.Value.Uint64(), true, nil
+}
+
+// governedRptService is an rpt service whose election configs are overridden by the parameters adopted.
+type governedRptService struct {
+	rpt.RptService
+	params ParamService
+}
+
+// NewRptService returns the rpt service with the election configs adopted by the governance contract, falling back
+// to the ones of the rpt service for the parameters not adopted. The election fails if the adopted ones can not be read.
+func NewRptService(rptService rpt.RptService, params ParamService) rpt.RptService {
+	return &governedRptService{
+		RptService: rptService,
+		params:     params,
+	}
+}
+
+// adopted returns the value of the parameter adopted at the block number, capped by the bound of its rpt contract
+// counterpart.
+func (rs *governedRptService) adopted(name string, number uint64, bound int) (int, bool, error) {
+	value, adopted, err := rs.params.ParamOf(name, number)
+	if err != nil {
+		log.Warn("failed to read param adopted by governance contract", "param", name, "number", number, "err", err)
+		return 0, false, err
+	}
+	if !adopted {
+		return 0, false, nil
+	}
+	if value >= uint64(bound) {
+		return bound, true, nil
+	}
+	return int(value), true, nil
+}
+
+// TotalSeats implements rpt.RptService
+func (rs *governedRptService) TotalSeats(number uint64) (int, error) {
+	v, ok, err := rs.adopted(ParamTotalSeats, number, rpt.MaxTotalSeats)
+	if err != nil || ok {
+		return v, err
+	}
+	return rs.RptService.TotalSeats(number)
+}
+
+// LowRptSeats implements rpt.RptService
+func (rs *governedRptService) LowRptSeats(number uint64) (int, error) {
+	v, ok, err := rs.adopted(ParamLowRptSeats, number, rpt.MaxLowRptSeats)
+	if err != nil || ok {
+		return v, err
+	}
+	return rs.RptService.LowRptSeats(number)
+}
+
+// LowRptCount implements rpt.RptService
+func (rs *governedRptService) LowRptCount(total int, number uint64) (int, error) {
+	pct, ok, err := rs.adopted(ParamLowRptPercentage, number, rpt.MaxLowRptPct)
+	if err != nil {
+		return 0, err
+	}
+	if ok {
+		return rpt.PctCount(pct, total), nil
+	}
+	return rs.RptService.LowRptCount(total, number)
+}
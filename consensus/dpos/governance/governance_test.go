@@ -0,0 +1,112 @@
+package governance_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gcchains/chain/consensus/dpos/governance"
+	"github.com/gcchains/chain/consensus/dpos/rpt"
+)
+
+type fakeParamService struct {
+	params map[string]uint64
+	err    error
+}
+
+func (ps *fakeParamService) ParamOf(name string, number uint64) (uint64, bool, error) {
+	if ps.err != nil {
+		return 0, false, ps.err
+	}
+	value, ok := ps.params[name]
+	return value, ok, nil
+}
+
+// fakeRptService returns the election configs of the rpt contract
+type fakeRptService struct {
+	rpt.RptService
+}
+
+func (rs *fakeRptService) TotalSeats(number uint64) (int, error)  { return 8, nil }
+func (rs *fakeRptService) LowRptSeats(number uint64) (int, error) { return 2, nil }
+func (rs *fakeRptService) LowRptCount(total int, number uint64) (int, error) {
+	return rpt.PctCount(50, total), nil
+}
+
+func TestParamKey(t *testing.T) {
+	for _, name := range []string{governance.ParamTotalSeats, governance.ParamLowRptSeats, governance.ParamLowRptPercentage, ""} {
+		key := governance.ParamKey(name)
+		if string(key[:len(name)]) != name {
+			t.Errorf("ParamKey(%q) = %x, want the name left aligned", name, key)
+		}
+		if got := governance.ParamName(key); got != name {
+			t.Errorf("ParamName(ParamKey(%q)) = %q", name, got)
+		}
+	}
+}
+
+func TestGovernedRptService(t *testing.T) {
+	tests := []struct {
+		name            string
+		params          map[string]uint64
+		err             error
+		wantTotalSeats  int
+		wantLowRptSeats int
+		wantLowRptCount int // of 8 candidates
+		wantErr         bool
+	}{
+		{
+			name:            "not adopted",
+			params:          map[string]uint64{},
+			wantTotalSeats:  8,
+			wantLowRptSeats: 2,
+			wantLowRptCount: 4,
+		},
+		{
+			name:            "adopted",
+			params:          map[string]uint64{governance.ParamTotalSeats: 6, governance.ParamLowRptSeats: 1, governance.ParamLowRptPercentage: 25},
+			wantTotalSeats:  6,
+			wantLowRptSeats: 1,
+			wantLowRptCount: 2,
+		},
+		{
+			name:            "capped",
+			params:          map[string]uint64{governance.ParamTotalSeats: 100, governance.ParamLowRptSeats: 100, governance.ParamLowRptPercentage: 100},
+			wantTotalSeats:  rpt.MaxTotalSeats,
+			wantLowRptSeats: rpt.MaxLowRptSeats,
+			wantLowRptCount: rpt.PctCount(rpt.MaxLowRptPct, 8),
+		},
+		{
+			name:    "failed to read",
+			params:  map[string]uint64{governance.ParamTotalSeats: 6},
+			err:     errors.New("missing trie node"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rs := governance.NewRptService(&fakeRptService{}, &fakeParamService{params: tt.params, err: tt.err})
+
+			if tt.wantErr {
+				if _, err := rs.TotalSeats(1); err == nil {
+					t.Errorf("TotalSeats() succeeded, want error")
+				}
+				if _, err := rs.LowRptSeats(1); err == nil {
+					t.Errorf("LowRptSeats() succeeded, want error")
+				}
+				if _, err := rs.LowRptCount(8, 1); err == nil {
+					t.Errorf("LowRptCount(8) succeeded, want error")
+				}
+				return
+			}
+			if got, err := rs.TotalSeats(1); err != nil || got != tt.wantTotalSeats {
+				t.Errorf("TotalSeats() = %v, %v, want %v", got, err, tt.wantTotalSeats)
+			}
+			if got, err := rs.LowRptSeats(1); err != nil || got != tt.wantLowRptSeats {
+				t.Errorf("LowRptSeats() = %v, %v, want %v", got, err, tt.wantLowRptSeats)
+			}
+			if got, err := rs.LowRptCount(8, 1); err != nil || got != tt.wantLowRptCount {
+				t.Errorf("LowRptCount(8) = %v, %v, want %v", got, err, tt.wantLowRptCount)
+			}
+		})
+	}
+}
@@ -6,10 +6,14 @@ package rpt
 // then calculates the reputations of candidates.
 
 import (
+	"context"
+	"fmt"
+	"math/big"
 	"time"
 
 	"github.com/gcchains/chain/accounts/abi/bind"
 	"github.com/gcchains/chain/commons/log"
+	"github.com/gcchains/chain/configs"
 	"github.com/gcchains/chain/consensus/dpos/backend"
 	rptContract "github.com/gcchains/chain/contracts/dpos/rpt"
 	"github.com/ethereum/go-ethereum/common"
@@ -23,13 +27,21 @@ const (
 	defaultMinimumRptValue = 1000
 )
 
+// bounds of the election configs, the values read from contracts are capped by them
+const (
+	MaxTotalSeats  = defaultTotalSeats
+	MaxLowRptSeats = defaultLowRptSeats
+	MaxLowRptPct   = defaultLowRptPct
+)
+
 // RptService provides methods to obtain all rpt related information from block txs and contracts.
 type RptService interface {
 	CalcRptInfoList(addresses []common.Address, number uint64) RptList
 	CalcRptInfo(address common.Address, addresses []common.Address, blockNum uint64) Rpt
-	TotalSeats() (int, error)
-	LowRptSeats() (int, error)
-	LowRptCount(total int) int
+	CalcStakeList(addresses []common.Address, number uint64) (RptList, error)
+	TotalSeats(number uint64) (int, error)
+	LowRptSeats(number uint64) (int, error)
+	LowRptCount(total int, number uint64) (int, error)
 }
 
 // RptCollector collects rpts infos of a given candidate
@@ -39,7 +51,8 @@ type RptCollector interface {
 
 // BasicCollector is the default rpt collector
 type RptServiceImpl struct {
-	client bind.ContractBackend
+	client       bind.ContractBackend
+	chainBackend backend.ChainBackend
 
 	contractAddr common.Address
 	rptInstance  *rptContract.Rpt
@@ -57,7 +70,8 @@ func NewRptService(contractAddr common.Address, backend backend.ClientBackend) (
 	newRptCollector := NewRptCollectorImpl6(rptInstance, backend)
 
 	bc := &RptServiceImpl{
-		client: backend,
+		client:       backend,
+		chainBackend: backend,
 
 		contractAddr: contractAddr,
 		rptInstance:  rptInstance,
@@ -66,15 +80,20 @@ func NewRptService(contractAddr common.Address, backend backend.ClientBackend) (
 	return bc, nil
 }
 
-// TotalSeats returns total dynaimc seats
-func (rs *RptServiceImpl) TotalSeats() (int, error) {
+// callOptsAt returns the opts reading contracts at the block number.
+func callOptsAt(number uint64) *bind.CallOpts {
+	return &bind.CallOpts{BlockNumber: new(big.Int).SetUint64(number)}
+}
+
+// TotalSeats returns total dynaimc seats at the block number
+func (rs *RptServiceImpl) TotalSeats(number uint64) (int, error) {
 	if rs.rptInstance == nil {
 		log.Error("New rpt contract 2 error")
 		return defaultTotalSeats, nil
 	}
 
 	instance := rs.rptInstance
-	ts, err := instance.TotalSeats(nil)
+	ts, err := instance.TotalSeats(callOptsAt(number))
 	if err != nil {
 		log.Error("Get total seats error", "error", err)
 		return defaultTotalSeats, err
@@ -92,15 +111,15 @@ func (rs *RptServiceImpl) TotalSeats() (int, error) {
 	return int(ts.Int64()), nil
 }
 
-// LowRptSeats returns low rpt seats
-func (rs *RptServiceImpl) LowRptSeats() (int, error) {
+// LowRptSeats returns low rpt seats at the block number
+func (rs *RptServiceImpl) LowRptSeats(number uint64) (int, error) {
 	if rs.rptInstance == nil {
 		log.Error("New rpt contract 2 error")
 		return defaultLowRptSeats, nil
 	}
 
 	instance := rs.rptInstance
-	lrs, err := instance.LowRptSeats(nil)
+	lrs, err := instance.LowRptSeats(callOptsAt(number))
 	if err != nil {
 		log.Error("Get low rpt seats error", "error", err)
 		return defaultLowRptSeats, err
@@ -118,15 +137,15 @@ func (rs *RptServiceImpl) LowRptSeats() (int, error) {
 	return int(lrs.Int64()), nil
 }
 
-// LowRptPercentage returns low rpt percentage among all rpt list
-func (rs *RptServiceImpl) LowRptPercentage() (int, error) {
+// LowRptPercentage returns low rpt percentage among all rpt list at the block number
+func (rs *RptServiceImpl) LowRptPercentage(number uint64) (int, error) {
 	if rs.rptInstance == nil {
 		log.Error("New rpt contract 2 error")
 		return defaultLowRptPct, nil
 	}
 
 	instance := rs.rptInstance
-	lrp, err := instance.LowRptPercentage(nil)
+	lrp, err := instance.LowRptPercentage(callOptsAt(number))
 	if err != nil {
 		log.Error("Get low rpt percentage error", "error", err)
 		return defaultLowRptPct, err
@@ -144,10 +163,13 @@ func (rs *RptServiceImpl) LowRptPercentage() (int, error) {
 	return int(lrp.Int64()), nil
 }
 
-// LowRptCount returns LowRptCount
-func (rs *RptServiceImpl) LowRptCount(total int) int {
-	pct, _ := rs.LowRptPercentage()
-	return PctCount(pct, total)
+// LowRptCount returns LowRptCount at the block number
+func (rs *RptServiceImpl) LowRptCount(total int, number uint64) (int, error) {
+	pct, err := rs.LowRptPercentage(number)
+	if err != nil {
+		return 0, err
+	}
+	return PctCount(pct, total), nil
 }
 
 // CalcRptInfoList returns reputation of
@@ -172,3 +194,22 @@ func (rs *RptServiceImpl) CalcRptInfo(address common.Address, addresses []common
 	log.Debug("now calc rpt for with rpt method 6", "addr", address.Hex(), "number", number)
 	return rs.rptCollector.RptOf(address, addresses, number)
 }
+
+// CalcStakeList returns stakes of the given addresses, a stake is the balance in gcc at the block number.
+// It fails if a balance can not be read.
+func (rs *RptServiceImpl) CalcStakeList(addresses []common.Address, number uint64) (RptList, error) {
+	stakes := RptList{}
+	for _, address := range addresses {
+		balance, err := rs.chainBackend.BalanceAt(context.Background(), address, new(big.Int).SetUint64(number))
+		if err != nil {
+			log.Error("Get balance error", "address", address.Hex(), "number", number, "error", err)
+			return nil, err
+		}
+		if balance == nil {
+			return nil, fmt.Errorf("no balance of %s at block %d", address.Hex(), number)
+		}
+		stake := new(big.Int).Div(balance, big.NewInt(configs.Gcc)).Int64()
+		stakes = append(stakes, Rpt{Address: address, Rpt: stake})
+	}
+	return stakes, nil
+}
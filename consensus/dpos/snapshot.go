@@ -337,7 +337,10 @@ func (s *DposSnapshot) applyHeader(header *types.Header, ifUpdateCommittee bool,
 			}
 
 			log.Debug("update proposers committee", "number", s.number())
-			s.updateProposers(rpts, seed, rptService)
+			if err := s.updateProposers(rpts, seed, rptService); err != nil {
+				log.Warn("err when update proposers", "err", err)
+				return err
+			}
 		}
 	}
 
@@ -436,8 +439,9 @@ func (s *DposSnapshot) isAboutToCampaign() bool {
 	return s.number() >= s.config.MaxInitBlockNumber-((TermDistBetweenElectionAndMining+2)*s.config.TermLen*s.config.ViewLen)
 }
 
-// updateProposer uses rpt and election result to get new proposers committee
-func (s *DposSnapshot) updateProposers(rpts rpt.RptList, seed int64, rptService rpt.RptService) {
+// updateProposer uses rpt and election result to get new proposers committee, the election fails if the election
+// configs or the stakes of candidates can not be read at the checkpoint
+func (s *DposSnapshot) updateProposers(rpts rpt.RptList, seed int64, rptService rpt.RptService) error {
 	// Elect proposers
 	if s.isStartElection() {
 
@@ -449,17 +453,53 @@ func (s *DposSnapshot) updateProposers(rpts rpt.RptList, seed int64, rptService
 		log.Debug("term length", "term", int(s.config.TermLen))
 		log.Debug("---------------------------")
 
-		// run the election algorithm
+		// run the election algorithm scheduled for current block
+		strategy, err := election.StrategyOf(s.config.ElectionStrategyAt(s.number()))
+		if err != nil {
+			log.Error("unknown election strategy, fall back to default", "strategy", s.config.ElectionStrategyAt(s.number()), "number", s.number())
+			strategy, _ = election.StrategyOf(election.DefaultStrategy)
+		}
+		log.Debug("election strategy", "strategy", strategy.Name())
+
+		// stake strategy weights candidates by balances instead of rpts
+		candidates := rpts
+		if strategy.Name() == election.StakeStrategy && s.Mode == NormalMode && rptService != nil {
+			stakes, err := rptService.CalcStakeList(rpts.Addrs(), s.number())
+			if err != nil {
+				return err
+			}
+			candidates = stakes
+			log.Debug("stake list", "stakes", candidates.FormatString())
+		}
+
+		term := s.FutureTermOf(s.number())
+
 		var proposers []common.Address
 		if int(s.config.TermLen) > defaultProposersSeats {
 
 			logOutAddrs("default 12 proposers", "proposer", configs.Proposers())
 
 			// elect some proposers based on rpts
-			dynamicSeats, _ := rptService.TotalSeats()
-			lowRptCount := rptService.LowRptCount(rpts.Len())
-			lowRptSeats, _ := rptService.LowRptSeats()
-			electedProposers := election.Elect(rpts, seed, dynamicSeats, lowRptCount, lowRptSeats)
+			dynamicSeats, err := rptService.TotalSeats(s.number())
+			if err != nil {
+				return err
+			}
+			lowRptCount, err := rptService.LowRptCount(rpts.Len(), s.number())
+			if err != nil {
+				return err
+			}
+			lowRptSeats, err := rptService.LowRptSeats(s.number())
+			if err != nil {
+				return err
+			}
+			electedProposers := strategy.Elect(election.Params{
+				Candidates:  candidates,
+				Seed:        seed,
+				Term:        term,
+				TotalSeats:  dynamicSeats,
+				LowRptCount: lowRptCount,
+				LowRptSeats: lowRptSeats,
+			})
 
 			logOutAddrs("elected proposers", "proposers", electedProposers)
 
@@ -487,7 +527,14 @@ func (s *DposSnapshot) updateProposers(rpts rpt.RptList, seed int64, rptService
 			logOutAddrs("evenly spared 12 proposers", "proposer", proposers)
 
 		} else {
-			proposers = election.Elect(rpts, seed, int(s.config.TermLen), 2, 2)
+			proposers = strategy.Elect(election.Params{
+				Candidates:  candidates,
+				Seed:        seed,
+				Term:        term,
+				TotalSeats:  int(s.config.TermLen),
+				LowRptCount: 2,
+				LowRptSeats: 2,
+			})
 		}
 
 		if len(proposers) != int(s.config.TermLen) {
@@ -495,7 +542,6 @@ func (s *DposSnapshot) updateProposers(rpts rpt.RptList, seed int64, rptService
 		}
 
 		// save to cache
-		term := s.FutureTermOf(s.number())
 		s.setRecentProposers(term, proposers)
 
 		logOutAddrs(fmt.Sprintf("result of elected proposers, current number #%d, future term(election term) #%d", s.number(), term), "proposer", proposers)
@@ -511,7 +557,7 @@ func (s *DposSnapshot) updateProposers(rpts rpt.RptList, seed int64, rptService
 
 	}
 
-	return
+	return nil
 }
 
 // Term returns the term index of current block number, which is 0-based
@@ -595,7 +641,7 @@ func (s *DposSnapshot) FutureProposerViewOf(signer common.Address, number uint64
 	return -1, errValidatorNotInCommittee
 }
 
-//IsFutureProposerOf returns if an address is a future proposer in the given block number
+// IsFutureProposerOf returns if an address is a future proposer in the given block number
 func (s *DposSnapshot) IsFutureProposerOf(proposer common.Address, number uint64) bool {
 	_, err := s.FutureProposerViewOf(proposer, number)
 	return err == nil
@@ -47,6 +47,14 @@ func (*fakeDb) Close() {
 	panic("implement me4")
 }
 
+func (*fakeDb) NewIterator(prefix []byte, start []byte) database.Iterator {
+	panic("implement me6")
+}
+
+func (*fakeDb) DeleteRange(start, end []byte) error {
+	panic("implement me7")
+}
+
 func (*fakeDb) NewBatch() database.Batch {
 	panic("implement me5")
 }
@@ -695,8 +703,29 @@ func Test_evenlyInsertDefaultProposers(t *testing.T) {
 	}
 }
 
+// fakeStakeRptService fails to read stakes
+type fakeStakeRptService struct {
+	rpt.RptService
+}
+
+func (*fakeStakeRptService) CalcStakeList(addresses []common.Address, number uint64) (rpt.RptList, error) {
+	return nil, fmt.Errorf("missing trie node")
+}
+
 func TestDposSnapshot_updateProposers(t *testing.T) {
+	config := &configs.DposConfig{
+		Period:             3,
+		TermLen:            3,
+		ViewLen:            3,
+		MaxInitBlockNumber: 100,
+		ElectionStrategies: []configs.ElectionStrategyConfig{{FromBlock: 0, Strategy: "stake"}},
+	}
+	snap := newSnapshot(config, 100, common.Hash{}, getProposerAddress(), getValidatorAddress(), NormalMode)
+	rpts := rpt.RptList{{Address: common.HexToAddress("0x1"), Rpt: 100}}
 
+	if err := snap.updateProposers(rpts, 1, &fakeStakeRptService{}); err == nil {
+		t.Error("election succeeded without stakes")
+	}
 }
 
 func Test_addressExcept(t *testing.T) {
@@ -14,10 +14,10 @@ import (
 )
 
 // AdmissionABI is the input ABI used to generate the binding from.
-const AdmissionABI = "[{\"constant\":true,\"inputs\":[],\"name\":\"memoryDifficulty\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_cpuWorkTimeout\",\"type\":\"uint256\"}],\"name\":\"updateCPUWorkTimeout\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"_cpuNonce\",\"type\":\"uint64\"},{\"name\":\"_cpuBlockNumber\",\"type\":\"uint256\"},{\"name\":\"_memoryNonce\",\"type\":\"uint64\"},{\"name\":\"_memoryBlockNumber\",\"type\":\"uint256\"},{\"name\":\"_sender\",\"type\":\"address\"}],\"name\":\"verify\",\"outputs\":[{\"name\":\"\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"_sender\",\"type\":\"address\"},{\"name\":\"_nonce\",\"type\":\"uint64\"},{\"name\":\"_blockNumber\",\"type\":\"uint256\"},{\"name\":\"_difficulty\",\"type\":\"uint256\"}],\"name\":\"verifyMemory\",\"outputs\":[{\"name\":\"b\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"cpuWorkTimeout\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"_sender\",\"type\":\"address\"},{\"name\":\"_nonce\",\"type\":\"uint64\"},{\"name\":\"_blockNumber\",\"type\":\"uint256\"},{\"name\":\"_difficulty\",\"type\":\"uint256\"}],\"name\":\"verifyCPU\",\"outputs\":[{\"name\":\"b\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"memoryWorkTimeout\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_memoryWorkTimeout\",\"type\":\"uint256\"}],\"name\":\"updateMemoryWorkTimeout\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"cpuDifficulty\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"owner\",\"outputs\":[{\"name\":\"\",\"type\":\"address\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_difficulty\",\"type\":\"uint256\"}],\"name\":\"updateCPUDifficulty\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"getAdmissionParameters\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"},{\"name\":\"\",\"type\":\"uint256\"},{\"name\":\"\",\"type\":\"uint256\"},{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_difficulty\",\"type\":\"uint256\"}],\"name\":\"updateMemoryDifficulty\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"name\":\"_cpuDifficulty\",\"type\":\"uint256\"},{\"name\":\"_memoryDifficulty\",\"type\":\"uint256\"},{\"name\":\"_cpuWorkTimeout\",\"type\":\"uint256\"},{\"name\":\"_memoryWorkTimeout\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"}]"
+const AdmissionABI = "[{\"constant\":true,\"inputs\":[],\"name\":\"memoryDifficulty\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_cpuWorkTimeout\",\"type\":\"uint256\"}],\"name\":\"updateCPUWorkTimeout\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"_cpuNonce\",\"type\":\"uint64\"},{\"name\":\"_cpuBlockNumber\",\"type\":\"uint256\"},{\"name\":\"_memoryNonce\",\"type\":\"uint64\"},{\"name\":\"_memoryBlockNumber\",\"type\":\"uint256\"},{\"name\":\"_sender\",\"type\":\"address\"}],\"name\":\"verify\",\"outputs\":[{\"name\":\"\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"_sender\",\"type\":\"address\"},{\"name\":\"_nonce\",\"type\":\"uint64\"},{\"name\":\"_blockNumber\",\"type\":\"uint256\"},{\"name\":\"_difficulty\",\"type\":\"uint256\"}],\"name\":\"verifyMemory\",\"outputs\":[{\"name\":\"b\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"cpuWorkTimeout\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"_sender\",\"type\":\"address\"},{\"name\":\"_nonce\",\"type\":\"uint64\"},{\"name\":\"_blockNumber\",\"type\":\"uint256\"},{\"name\":\"_difficulty\",\"type\":\"uint256\"}],\"name\":\"verifyCPU\",\"outputs\":[{\"name\":\"b\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"memoryWorkTimeout\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_memoryWorkTimeout\",\"type\":\"uint256\"}],\"name\":\"updateMemoryWorkTimeout\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"cpuDifficulty\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"owner\",\"outputs\":[{\"name\":\"\",\"type\":\"address\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_difficulty\",\"type\":\"uint256\"}],\"name\":\"updateCPUDifficulty\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"getAdmissionParameters\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"},{\"name\":\"\",\"type\":\"uint256\"},{\"name\":\"\",\"type\":\"uint256\"},{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_difficulty\",\"type\":\"uint256\"}],\"name\":\"updateMemoryDifficulty\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"storageDifficulty\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_nonce\",\"type\":\"uint64\"},{\"name\":\"_blockNumber\",\"type\":\"uint256\"}],\"name\":\"submitStorageProof\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"storageWorkTimeout\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_storageWorkTimeout\",\"type\":\"uint256\"}],\"name\":\"updateStorageWorkTimeout\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"_sender\",\"type\":\"address\"},{\"name\":\"_nonce\",\"type\":\"uint64\"},{\"name\":\"_blockNumber\",\"type\":\"uint256\"},{\"name\":\"_difficulty\",\"type\":\"uint256\"}],\"name\":\"verifyStorage\",\"outputs\":[{\"name\":\"b\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"\",\"type\":\"address\"}],\"name\":\"storageBlockNumbers\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_difficulty\",\"type\":\"uint256\"}],\"name\":\"updateStorageDifficulty\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"acceptableBlocks\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_acceptableBlocks\",\"type\":\"uint256\"}],\"name\":\"updateAcceptableBlocks\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"name\":\"_cpuDifficulty\",\"type\":\"uint256\"},{\"name\":\"_memoryDifficulty\",\"type\":\"uint256\"},{\"name\":\"_cpuWorkTimeout\",\"type\":\"uint256\"},{\"name\":\"_memoryWorkTimeout\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"}]"
 
 // AdmissionBin is the compiled bytecode used for deploying new contracts.
-const AdmissionBin = `0x6080604052600c600251600660045534101561001a57600080fd5b50604051608080610706833981016040108152815160208301519183015160609093015160068054600160a060020a03191633179055909290610065846401000000006100a4810204565b61007783640100000000610137810204565b610089826401000000006101c8810204565b61009b816401000000006101cd810204565b505050506101d2565b61010081118015906100b7575060008110155b151561012457604080517f08c379a000000000000000000000000000000000000000000000000000000000815260206004820152601d60248201527f446966666963756c7479206d757374206c657373207468616e20323536000000604482015290519081900360640190fd5b600281815561010091909103900a600055565b610100811180159061014a575060008110155b15156101b757604080517f08c379a000000000000000000000000000000000000000000000000000000000815260206004820152601d60248201527f446966666963756c7479206d757374206c657373207468616e20323536000000604482015290519081900360640190fd5b60048190556101000360020a600155565b600355565b600555565b610525806101e16000396000f3006080604052600436106100c45763ffffffff7c010000000000000000000000000000000000000000000000000000000060003504166317e6b96681146100c957806331ee5d33146100f05780633395492e1461010a5780634bda895714610166578063615cc243146101a75780636ac03dcc146101bc5780636d44a935146101fd57806386395077146102125780638b6546131461022a5780638da5cb5b1461023f578063be981db81461027d578063c651cfc914610295578063e1718946146102d0575b600080fd5b3480156100d557600080fd5b506100de6102e8565b60408051918252519081900360200190f35b3480156100fc57600080fd5b506101086004356102ee565b005b34801561011657600080fd5b5061015267ffffffffffffffff600435811690602435906044351660643573ffffffffffffffffffffffffffffffffffffffff608435166102f3565b604080519115158252519081900360200190f35b34801561017257600080fd5b5061015273ffffffffffffffffffffffffffffffffffffffff6004351667ffffffffffffffff60243516604435606435610322565b3480156101b357600080fd5b506100de61035e565b3480156101c857600080fd5b5061015273ffffffffffffffffffffffffffffffffffffffff6004351667ffffffffffffffff60243516604435606435610364565b34801561020957600080fd5b506100de610396565b34801561021e57600080fd5b5061010860043561039c565b34801561023657600080fd5b506100de6103a1565b34801561024b57600080fd5b506102546103a7565b6040805173ffffffffffffffffffffffffffffffffffffffff9092168252519081900360200190f35b34801561028957600080fd5b506101086004356103c3565b3480156102a157600080fd5b506102aa610456565b604080519485526020850193909352838301919091526060830152519081900360800190f35b3480156102dc57600080fd5b50610108600435610468565b60045481565b600355565b6000610303828787600254610364565b80156103185750610318828585600454610322565b9695505050505050565b600060405185815284602082015283406040820152826060820152602081608083606b600019fa151561035457600080fd5b5195945050505050565b60035481565b600060405185815284602082015283406040820152826060820152602081608083606a600019fa151561035457600080fd5b60055481565b600555565b60025481565b60065473ffffffffffffffffffffffffffffffffffffffff1681565b61010081118015906103d6575060008110155b151561044357604080517f08c379a000000000000000000000000000000000000000000000000000000000815260206004820152601d60248201527f446966666963756c7479206d757374206c657373207468616e20323536000000604482015290519081900360640190fd5b600281815561010091909103900a600055565b60025460045460035460055490919293565b610100811180159061047b575060008110155b15156104e857604080517f08c379a000000000000000000000000000000000000000000000000000000000815260206004820152601d60248201527f446966666963756c7479206d757374206c657373207468616e20323536000000604482015290519081900360640190fd5b60048190556101000360020a6001555600a165627a7a723058207e122d67e768a548b0c5202fed4107883adafddd646001bba1033fd2f98deacb0029`
+const AdmissionBin = `0x600c600255600a600a5560066004553461012857608060803803600039336006556101006000511115610084577f08c379a0000000000000000000000000000000000000000000000000000000006000526020600452601d6024527f446966666963756c7479206d757374206c657373207468616e2032353600000060445260646000fd5b6000516002556000516101000360020a60005561010060205111156100fb577f08c379a0000000000000000000000000000000000000000000000000000000006000526020600452601d6024527f446966666963756c7479206d757374206c657373207468616e2032353600000060445260646000fd5b6020516004556020516101000360020a6001556040516003556060516005556106e28061012d6000396000f35b600080fd6004361061010a5760003560e060020a900463ffffffff1680638b6546131461010f57806317e6b96614610120578063615cc243146101315780636d44a935146101425780638da5cb5b14610153578063499996921461017a578063da8792db1461018b578063b667beb11461019c578063a9d1de48146101d1578063dae49ab2146101e2578063c651cfc9146101f957806331ee5d331461021c57806386395077146102295780639409208814610236578063be981db814610243578063e1718946146102c1578063f1ee1d1c1461033f5780636ac03dcc146103af5780634bda89571461040a578063449ef902146104655780633395492e146104c5578063b7e9cdbe146105b1575b600080fd5b3461010a5760025460005260206000f35b3461010a5760045460005260206000f35b3461010a5760035460005260206000f35b3461010a5760055460005260206000f35b3461010a5760065473ffffffffffffffffffffffffffffffffffffffff1660005260206000f35b3461010a5760075460005260206000f35b3461010a5760085460005260206000f35b3461010a5760043573ffffffffffffffffffffffffffffffffffffffff16600052600960205260406000205460005260206000f35b3461010a57600a5460005260206000f35b3461010a5760146004351161010a57600435600a55005b3461010a5760025460005260045460205260035460405260055460605260806000f35b3461010a57600435600355005b3461010a57600435600555005b3461010a57600435600855005b3461010a5761010060043511156102ac577f08c379a0000000000000000000000000000000000000000000000000000000006000526020600452601d6024527f446966666963756c7479206d757374206c657373207468616e2032353600000060445260646000fd5b6004356002556004356101000360020a600055005b3461010a57610100600435111561032a577f08c379a0000000000000000000000000000000000000000000000000000000006000526020600452601d6024527f446966666963756c7479206d757374206c657373207468616e2032353600000060445260646000fd5b6004356004556004356101000360020a600155005b3461010a57602060043511156103a7577f08c379a0000000000000000000000000000000000000000000000000000000006000526020600452601d6024527f446966666963756c7479206d757374206e6f742065786365656420333200000060445260646000fd5b600435600755005b3461010a5760043573ffffffffffffffffffffffffffffffffffffffff1660805260243567ffffffffffffffff1660a0526044354060c05260643560e0526020608060806080606a5afa1561010a5760805160005260206000f35b3461010a5760043573ffffffffffffffffffffffffffffffffffffffff1660805260243567ffffffffffffffff1660a0526044354060c05260643560e0526020608060806080606b5afa1561010a5760805160005260206000f35b3461010a5760043573ffffffffffffffffffffffffffffffffffffffff1660805260243567ffffffffffffffff1660a0526044354060c05260643560e0526020608060806080606c5afa1561010a576080513d6020141660005260206000f35b3461010a5760843573ffffffffffffffffffffffffffffffffffffffff1660805260043567ffffffffffffffff1660a0526024354060c05260025460e0526020608060806080606a5afa1561010a5760805180156105a8575060843573ffffffffffffffffffffffffffffffffffffffff1660805260443567ffffffffffffffff1660a0526064354060c05260045460e0526020608060806080606b5afa1561010a5760805180156105a8575060075415806105a8575060243560843573ffffffffffffffffffffffffffffffffffffffff166000526009602052604060002054115b60005260206000f35b3461010a576024354311600a546024354303111516610622577f08c379a0000000000000000000000000000000000000000000000000000000006000526020600452601f6024527f6368616c6c656e676520626c6f636b206e6f742061636365707461626c652e0060445260646000fd5b3360805260043567ffffffffffffffff1660a0526024354060c05260075460e0526020608060806080606c5afa1561010a576080513d602014166106b8577f08c379a000000000000000000000000000000000000000000000000000000000600052602060045260196024527f73746f726167652070726f6f66206e6f74207061737365642e0000000000000060445260646000fd5b6024353373ffffffffffffffffffffffffffffffffffffffff16600052600960205260406000205500`
 
 // DeployAdmission deploys a new gcchain contract, binding an instance of Admission to it.
 func DeployAdmission(auth *bind.TransactOpts, backend bind.ContractBackend, _cpuDifficulty *big.Int, _memoryDifficulty *big.Int, _cpuWorkTimeout *big.Int, _memoryWorkTimeout *big.Int) (common.Address, *types.Transaction, *Admission, error) {
@@ -174,6 +174,32 @@ func (_Admission *AdmissionTransactorRaw) Transact(opts *bind.TransactOpts, meth
 	return _Admission.Contract.contract.Transact(opts, method, params...)
 }
 
+// AcceptableBlocks is a free data retrieval call binding the contract method 0xa9d1de48.
+//
+// Solidity: function acceptableBlocks() constant returns(uint256)
+func (_Admission *AdmissionCaller) AcceptableBlocks(opts *bind.CallOpts) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Admission.contract.Call(opts, out, "acceptableBlocks")
+	return *ret0, err
+}
+
+// AcceptableBlocks is a free data retrieval call binding the contract method 0xa9d1de48.
+//
+// Solidity: function acceptableBlocks() constant returns(uint256)
+func (_Admission *AdmissionSession) AcceptableBlocks() (*big.Int, error) {
+	return _Admission.Contract.AcceptableBlocks(&_Admission.CallOpts)
+}
+
+// AcceptableBlocks is a free data retrieval call binding the contract method 0xa9d1de48.
+//
+// Solidity: function acceptableBlocks() constant returns(uint256)
+func (_Admission *AdmissionCallerSession) AcceptableBlocks() (*big.Int, error) {
+	return _Admission.Contract.AcceptableBlocks(&_Admission.CallOpts)
+}
+
 // CpuDifficulty is a free data retrieval call binding the contract method 0x8b654613.
 //
 // Solidity: function cpuDifficulty() constant returns(uint256)
@@ -338,6 +364,84 @@ func (_Admission *AdmissionCallerSession) Owner() (common.Address, error) {
 	return _Admission.Contract.Owner(&_Admission.CallOpts)
 }
 
+// StorageBlockNumbers is a free data retrieval call binding the contract method 0xb667beb1.
+//
+// Solidity: function storageBlockNumbers( address) constant returns(uint256)
+func (_Admission *AdmissionCaller) StorageBlockNumbers(opts *bind.CallOpts, arg0 common.Address) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Admission.contract.Call(opts, out, "storageBlockNumbers", arg0)
+	return *ret0, err
+}
+
+// StorageBlockNumbers is a free data retrieval call binding the contract method 0xb667beb1.
+//
+// Solidity: function storageBlockNumbers( address) constant returns(uint256)
+func (_Admission *AdmissionSession) StorageBlockNumbers(arg0 common.Address) (*big.Int, error) {
+	return _Admission.Contract.StorageBlockNumbers(&_Admission.CallOpts, arg0)
+}
+
+// StorageBlockNumbers is a free data retrieval call binding the contract method 0xb667beb1.
+//
+// Solidity: function storageBlockNumbers( address) constant returns(uint256)
+func (_Admission *AdmissionCallerSession) StorageBlockNumbers(arg0 common.Address) (*big.Int, error) {
+	return _Admission.Contract.StorageBlockNumbers(&_Admission.CallOpts, arg0)
+}
+
+// StorageDifficulty is a free data retrieval call binding the contract method 0x49999692.
+//
+// Solidity: function storageDifficulty() constant returns(uint256)
+func (_Admission *AdmissionCaller) StorageDifficulty(opts *bind.CallOpts) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Admission.contract.Call(opts, out, "storageDifficulty")
+	return *ret0, err
+}
+
+// StorageDifficulty is a free data retrieval call binding the contract method 0x49999692.
+//
+// Solidity: function storageDifficulty() constant returns(uint256)
+func (_Admission *AdmissionSession) StorageDifficulty() (*big.Int, error) {
+	return _Admission.Contract.StorageDifficulty(&_Admission.CallOpts)
+}
+
+// StorageDifficulty is a free data retrieval call binding the contract method 0x49999692.
+//
+// Solidity: function storageDifficulty() constant returns(uint256)
+func (_Admission *AdmissionCallerSession) StorageDifficulty() (*big.Int, error) {
+	return _Admission.Contract.StorageDifficulty(&_Admission.CallOpts)
+}
+
+// StorageWorkTimeout is a free data retrieval call binding the contract method 0xda8792db.
+//
+// Solidity: function storageWorkTimeout() constant returns(uint256)
+func (_Admission *AdmissionCaller) StorageWorkTimeout(opts *bind.CallOpts) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Admission.contract.Call(opts, out, "storageWorkTimeout")
+	return *ret0, err
+}
+
+// StorageWorkTimeout is a free data retrieval call binding the contract method 0xda8792db.
+//
+// Solidity: function storageWorkTimeout() constant returns(uint256)
+func (_Admission *AdmissionSession) StorageWorkTimeout() (*big.Int, error) {
+	return _Admission.Contract.StorageWorkTimeout(&_Admission.CallOpts)
+}
+
+// StorageWorkTimeout is a free data retrieval call binding the contract method 0xda8792db.
+//
+// Solidity: function storageWorkTimeout() constant returns(uint256)
+func (_Admission *AdmissionCallerSession) StorageWorkTimeout() (*big.Int, error) {
+	return _Admission.Contract.StorageWorkTimeout(&_Admission.CallOpts)
+}
+
 // Verify is a free data retrieval call binding the contract method 0x3395492e.
 //
 // Solidity: function verify(_cpuNonce uint64, _cpuBlockNumber uint256, _memoryNonce uint64, _memoryBlockNumber uint256, _sender address) constant returns(bool)
@@ -416,6 +520,74 @@ func (_Admission *AdmissionCallerSession) VerifyMemory(_sender common.Address, _
 	return _Admission.Contract.VerifyMemory(&_Admission.CallOpts, _sender, _nonce, _blockNumber, _difficulty)
 }
 
+// VerifyStorage is a free data retrieval call binding the contract method 0x449ef902.
+//
+// Solidity: function verifyStorage(_sender address, _nonce uint64, _blockNumber uint256, _difficulty uint256) constant returns(b bool)
+func (_Admission *AdmissionCaller) VerifyStorage(opts *bind.CallOpts, _sender common.Address, _nonce uint64, _blockNumber *big.Int, _difficulty *big.Int) (bool, error) {
+	var (
+		ret0 = new(bool)
+	)
+	out := ret0
+	err := _Admission.contract.Call(opts, out, "verifyStorage", _sender, _nonce, _blockNumber, _difficulty)
+	return *ret0, err
+}
+
+// VerifyStorage is a free data retrieval call binding the contract method 0x449ef902.
+//
+// Solidity: function verifyStorage(_sender address, _nonce uint64, _blockNumber uint256, _difficulty uint256) constant returns(b bool)
+func (_Admission *AdmissionSession) VerifyStorage(_sender common.Address, _nonce uint64, _blockNumber *big.Int, _difficulty *big.Int) (bool, error) {
+	return _Admission.Contract.VerifyStorage(&_Admission.CallOpts, _sender, _nonce, _blockNumber, _difficulty)
+}
+
+// VerifyStorage is a free data retrieval call binding the contract method 0x449ef902.
+//
+// Solidity: function verifyStorage(_sender address, _nonce uint64, _blockNumber uint256, _difficulty uint256) constant returns(b bool)
+func (_Admission *AdmissionCallerSession) VerifyStorage(_sender common.Address, _nonce uint64, _blockNumber *big.Int, _difficulty *big.Int) (bool, error) {
+	return _Admission.Contract.VerifyStorage(&_Admission.CallOpts, _sender, _nonce, _blockNumber, _difficulty)
+}
+
+// SubmitStorageProof is a paid mutator transaction binding the contract method 0xb7e9cdbe.
+//
+// Solidity: function submitStorageProof(_nonce uint64, _blockNumber uint256) returns()
+func (_Admission *AdmissionTransactor) SubmitStorageProof(opts *bind.TransactOpts, _nonce uint64, _blockNumber *big.Int) (*types.Transaction, error) {
+	return _Admission.contract.Transact(opts, "submitStorageProof", _nonce, _blockNumber)
+}
+
+// SubmitStorageProof is a paid mutator transaction binding the contract method 0xb7e9cdbe.
+//
+// Solidity: function submitStorageProof(_nonce uint64, _blockNumber uint256) returns()
+func (_Admission *AdmissionSession) SubmitStorageProof(_nonce uint64, _blockNumber *big.Int) (*types.Transaction, error) {
+	return _Admission.Contract.SubmitStorageProof(&_Admission.TransactOpts, _nonce, _blockNumber)
+}
+
+// SubmitStorageProof is a paid mutator transaction binding the contract method 0xb7e9cdbe.
+//
+// Solidity: function submitStorageProof(_nonce uint64, _blockNumber uint256) returns()
+func (_Admission *AdmissionTransactorSession) SubmitStorageProof(_nonce uint64, _blockNumber *big.Int) (*types.Transaction, error) {
+	return _Admission.Contract.SubmitStorageProof(&_Admission.TransactOpts, _nonce, _blockNumber)
+}
+
+// UpdateAcceptableBlocks is a paid mutator transaction binding the contract method 0xdae49ab2.
+//
+// Solidity: function updateAcceptableBlocks(_acceptableBlocks uint256) returns()
+func (_Admission *AdmissionTransactor) UpdateAcceptableBlocks(opts *bind.TransactOpts, _acceptableBlocks *big.Int) (*types.Transaction, error) {
+	return _Admission.contract.Transact(opts, "updateAcceptableBlocks", _acceptableBlocks)
+}
+
+// UpdateAcceptableBlocks is a paid mutator transaction binding the contract method 0xdae49ab2.
+//
+// Solidity: function updateAcceptableBlocks(_acceptableBlocks uint256) returns()
+func (_Admission *AdmissionSession) UpdateAcceptableBlocks(_acceptableBlocks *big.Int) (*types.Transaction, error) {
+	return _Admission.Contract.UpdateAcceptableBlocks(&_Admission.TransactOpts, _acceptableBlocks)
+}
+
+// UpdateAcceptableBlocks is a paid mutator transaction binding the contract method 0xdae49ab2.
+//
+// Solidity: function updateAcceptableBlocks(_acceptableBlocks uint256) returns()
+func (_Admission *AdmissionTransactorSession) UpdateAcceptableBlocks(_acceptableBlocks *big.Int) (*types.Transaction, error) {
+	return _Admission.Contract.UpdateAcceptableBlocks(&_Admission.TransactOpts, _acceptableBlocks)
+}
+
 // UpdateCPUDifficulty is a paid mutator transaction binding the contract method 0xbe981db8.
 //
 // Solidity: function updateCPUDifficulty(_difficulty uint256) returns()
@@ -499,3 +671,45 @@ func (_Admission *AdmissionSession) UpdateMemoryWorkTimeout(_memoryWorkTimeout *
 func (_Admission *AdmissionTransactorSession) UpdateMemoryWorkTimeout(_memoryWorkTimeout *big.Int) (*types.Transaction, error) {
 	return _Admission.Contract.UpdateMemoryWorkTimeout(&_Admission.TransactOpts, _memoryWorkTimeout)
 }
+
+// UpdateStorageDifficulty is a paid mutator transaction binding the contract method 0xf1ee1d1c.
+//
+// Solidity: function updateStorageDifficulty(_difficulty uint256) returns()
+func (_Admission *AdmissionTransactor) UpdateStorageDifficulty(opts *bind.TransactOpts, _difficulty *big.Int) (*types.Transaction, error) {
+	return _Admission.contract.Transact(opts, "updateStorageDifficulty", _difficulty)
+}
+
+// UpdateStorageDifficulty is a paid mutator transaction binding the contract method 0xf1ee1d1c.
+//
+// Solidity: function updateStorageDifficulty(_difficulty uint256) returns()
+func (_Admission *AdmissionSession) UpdateStorageDifficulty(_difficulty *big.Int) (*types.Transaction, error) {
+	return _Admission.Contract.UpdateStorageDifficulty(&_Admission.TransactOpts, _difficulty)
+}
+
+// UpdateStorageDifficulty is a paid mutator transaction binding the contract method 0xf1ee1d1c.
+//
+// Solidity: function updateStorageDifficulty(_difficulty uint256) returns()
+func (_Admission *AdmissionTransactorSession) UpdateStorageDifficulty(_difficulty *big.Int) (*types.Transaction, error) {
+	return _Admission.Contract.UpdateStorageDifficulty(&_Admission.TransactOpts, _difficulty)
+}
+
+// UpdateStorageWorkTimeout is a paid mutator transaction binding the contract method 0x94092088.
+//
+// Solidity: function updateStorageWorkTimeout(_storageWorkTimeout uint256) returns()
+func (_Admission *AdmissionTransactor) UpdateStorageWorkTimeout(opts *bind.TransactOpts, _storageWorkTimeout *big.Int) (*types.Transaction, error) {
+	return _Admission.contract.Transact(opts, "updateStorageWorkTimeout", _storageWorkTimeout)
+}
+
+// UpdateStorageWorkTimeout is a paid mutator transaction binding the contract method 0x94092088.
+//
+// Solidity: function updateStorageWorkTimeout(_storageWorkTimeout uint256) returns()
+func (_Admission *AdmissionSession) UpdateStorageWorkTimeout(_storageWorkTimeout *big.Int) (*types.Transaction, error) {
+	return _Admission.Contract.UpdateStorageWorkTimeout(&_Admission.TransactOpts, _storageWorkTimeout)
+}
+
+// UpdateStorageWorkTimeout is a paid mutator transaction binding the contract method 0x94092088.
+//
+// Solidity: function updateStorageWorkTimeout(_storageWorkTimeout uint256) returns()
+func (_Admission *AdmissionTransactorSession) UpdateStorageWorkTimeout(_storageWorkTimeout *big.Int) (*types.Transaction, error) {
+	return _Admission.Contract.UpdateStorageWorkTimeout(&_Admission.TransactOpts, _storageWorkTimeout)
+}
@@ -19,6 +19,12 @@ contract Admission {
     uint public memoryWorkTimeout;
     address public owner;
 
+    uint public storageDifficulty;
+    uint public storageWorkTimeout;
+    // storageBlockNumbers records the challenge block of the last storage proof submitted by each node
+    mapping(address => uint) public storageBlockNumbers;
+    uint public acceptableBlocks = 10; // only storage proofs based on latest 10 blocks will be accepted
+
     modifier onlyOwner(){msg.sender == owner; _;}
 
     constructor (uint _cpuDifficulty, uint _memoryDifficulty,uint _cpuWorkTimeout,uint _memoryWorkTimeout) public {
@@ -65,13 +71,44 @@ contract Admission {
         memoryTarget = 1 << (256 - _difficulty);
     }
 
+    function updateStorageWorkTimeout(uint _storageWorkTimeout) public onlyOwner{
+        storageWorkTimeout = _storageWorkTimeout;
+    }
+
+    /**
+     * @dev updateStorageDifficulty updates storage difficulty, 0 disables the storage proof
+     * @param _difficulty the new storage difficulty, the number of plot bits
+     */
+    function updateStorageDifficulty(uint _difficulty) public onlyOwner {
+        require(_difficulty <= 32, "Difficulty must not exceed 32");
+        storageDifficulty = _difficulty;
+    }
+
+    function updateAcceptableBlocks(uint _acceptableBlocks) public onlyOwner {
+        require(_acceptableBlocks <= 20);
+        acceptableBlocks = _acceptableBlocks;
+    }
+
+    /**
+     * @dev submitStorageProof verifies and records the storage proof of the sender, it is sent along with
+     * the campaign claim
+     * @param _nonce the index of the answered plot entry
+     * @param _blockNumber the challenge block number, one of the latest acceptableBlocks blocks
+     */
+    function submitStorageProof(uint64 _nonce, uint _blockNumber) public {
+        // blockhash is 0 for future and old blocks, the proof must be based on a latest block
+        require(_blockNumber < block.number && block.number - _blockNumber <= acceptableBlocks, "challenge block not acceptable.");
+        require(verifyStorage(msg.sender, _nonce, _blockNumber, storageDifficulty), "storage proof not passed.");
+        storageBlockNumbers[msg.sender] = _blockNumber;
+    }
+
     /**
      * @dev verify verifies the given proof
      * @param _cpuNonce the cpu nonce
      * @param _cpuBlockNumber the cpu pow input block number
      * @param _memoryNonce the memory nonce
      * @param _memoryBlockNumber the memory pow input block number
-     * @return true returns true if all is ok
+     * @return true returns true if all is ok, the storage proof must be submitted after the cpu pow input block
      */
     function verify(
         uint64 _cpuNonce,
@@ -85,7 +122,8 @@ contract Admission {
         returns (bool)
     {
         return verifyCPU(_sender, _cpuNonce, _cpuBlockNumber, cpuDifficulty)
-                && verifyMemory(_sender, _memoryNonce, _memoryBlockNumber, memoryDifficulty);
+                && verifyMemory(_sender, _memoryNonce, _memoryBlockNumber, memoryDifficulty)
+                && (storageDifficulty == 0 || storageBlockNumbers[_sender] > _cpuBlockNumber);
     }
 
     /**
@@ -130,4 +168,27 @@ contract Admission {
             b := mload(p)
         }
     }
+
+    /**
+     * @dev verifyStorage verifies the given storage proof
+     * @param _sender the campaign participant
+     * @param _nonce the index of the answered plot entry
+     * @param _blockNumber the challenge block number
+     * @param _difficulty the storage difficulty
+     * @return true returns true if storage proof is ok
+     */
+    function verifyStorage(address _sender, uint64 _nonce, uint _blockNumber, uint _difficulty) public view returns (bool b) {
+        assembly {
+            let p := mload(0x40)
+            mstore(p, _sender)
+            mstore(add(p, 0x20), _nonce)
+            mstore(add(p, 0x40), blockhash(_blockNumber))
+            mstore(add(p, 0x60), _difficulty)
+            if iszero(staticcall(not(0), 0x6C, p, 0x80, p, 0x20)) {
+                revert(0, 0)
+            }
+            // 0x6C returns nothing before the storageProof fork
+            b := and(mload(p), eq(returndatasize, 0x20))
+        }
+    }
 }
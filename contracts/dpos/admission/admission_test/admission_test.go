@@ -19,6 +19,7 @@ import (
 	"github.com/gcchains/chain/contracts/dpos/rnode"
 	"github.com/gcchains/chain/core"
 	"github.com/gcchains/chain/core/vm"
+	"github.com/gcchains/chain/types"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 )
@@ -39,6 +40,7 @@ var (
 func init() {
 	vm.RegisterPrimitiveContract(common.BytesToAddress([]byte{106}), &primitives.CpuPowValidate{})
 	vm.RegisterPrimitiveContract(common.BytesToAddress([]byte{107}), &primitives.MemPowValidate{})
+	vm.RegisterPrimitiveContract(common.BytesToAddress([]byte{108}), &primitives.StorageValidate{})
 }
 
 func newTestBackend() *backends.SimulatedBackend {
@@ -231,3 +233,128 @@ func computeCorrectPow(prvKey *ecdsa.PrivateKey, contractBackend *backends.Simul
 	memNonce = results[admission.Memory].Nonce
 	return
 }
+
+func TestSubmitStorageProof(t *testing.T) {
+	storageDifficulty := uint64(6)
+
+	backend := newTestBackend()
+	admissionAddr, err := deployAdmission(key0, cpuDifficulty, memDifficulty, cpuWorkTimeout, memoryWorkTimeout, backend)
+	if err != nil {
+		t.Fatalf("deploy contract: expected no error, got %v", err)
+	}
+
+	instance, err := contract.NewAdmission(admissionAddr, backend)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	auth := bind.NewKeyedTransactor(key0)
+	if _, err = instance.UpdateStorageDifficulty(auth, new(big.Int).SetUint64(33)); err == nil {
+		t.Fatalf("expected error of difficulty out of range, got nil")
+	}
+	if _, err = instance.UpdateStorageDifficulty(auth, new(big.Int).SetUint64(storageDifficulty)); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	backend.Commit()
+
+	v, err := instance.StorageDifficulty(nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if v.Uint64() != storageDifficulty {
+		t.Fatalf("expected %d, got %v", storageDifficulty, v.Uint64())
+	}
+
+	// answer the challenge of the current block as a node does with its plot
+	challenge := backend.Blockchain().CurrentBlock()
+	nonce, found := findStorageNonce(addr1, challenge.Hash(), storageDifficulty)
+	if !found {
+		t.Skip("no plot entry matches the challenge")
+	}
+
+	submitter := bind.NewKeyedTransactor(key1)
+	if _, err = instance.SubmitStorageProof(submitter, nonce+1<<storageDifficulty, challenge.Number()); err == nil {
+		t.Fatalf("expected error of invalid storage proof, got nil")
+	}
+	if _, err = instance.SubmitStorageProof(submitter, nonce, challenge.Number()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	backend.Commit()
+
+	number, err := instance.StorageBlockNumbers(nil, addr1)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if number.Cmp(challenge.Number()) != 0 {
+		t.Fatalf("expected %v, got %v", challenge.Number(), number)
+	}
+}
+
+func TestSubmitStorageProofChallengeBlock(t *testing.T) {
+	storageDifficulty := uint64(6)
+
+	backend := newTestBackend()
+	admissionAddr, err := deployAdmission(key0, cpuDifficulty, memDifficulty, cpuWorkTimeout, memoryWorkTimeout, backend)
+	if err != nil {
+		t.Fatalf("deploy contract: expected no error, got %v", err)
+	}
+	instance, err := contract.NewAdmission(admissionAddr, backend)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err = instance.UpdateStorageDifficulty(bind.NewKeyedTransactor(key0), new(big.Int).SetUint64(storageDifficulty)); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	backend.Commit()
+
+	acceptableBlocks, err := instance.AcceptableBlocks(nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	submitter := bind.NewKeyedTransactor(key1)
+
+	// blockhash of a future block is 0, an answer to the zero challenge must not be accepted
+	future := new(big.Int).Add(backend.Blockchain().CurrentBlock().Number(), big.NewInt(100))
+	nonce, found := findStorageNonce(addr1, common.Hash{}, storageDifficulty)
+	if !found {
+		t.Skip("no plot entry matches the zero challenge")
+	}
+	if ok, err := instance.VerifyStorage(nil, addr1, nonce, future, new(big.Int).SetUint64(storageDifficulty)); err != nil || !ok {
+		t.Fatalf("expected the answer to the zero challenge to pass, got %v, %v", ok, err)
+	}
+	if _, err = instance.SubmitStorageProof(submitter, nonce, future); err == nil {
+		t.Fatalf("expected error of future challenge block, got nil")
+	}
+
+	// a stale challenge block is rejected while its hash is still available
+	var challenge *types.Block
+	for i := 0; i < 10 && challenge == nil; i++ {
+		backend.Commit()
+		block := backend.Blockchain().CurrentBlock()
+		if nonce, found = findStorageNonce(addr1, block.Hash(), storageDifficulty); found {
+			challenge = block
+		}
+	}
+	if challenge == nil {
+		t.Skip("no plot entry matches the challenges")
+	}
+	for i := uint64(0); i <= acceptableBlocks.Uint64(); i++ {
+		backend.Commit()
+	}
+	if ok, err := instance.VerifyStorage(nil, addr1, nonce, challenge.Number(), new(big.Int).SetUint64(storageDifficulty)); err != nil || !ok {
+		t.Fatalf("expected the answer to the stale challenge to pass, got %v, %v", ok, err)
+	}
+	if _, err = instance.SubmitStorageProof(submitter, nonce, challenge.Number()); err == nil {
+		t.Fatalf("expected error of stale challenge block, got nil")
+	}
+}
+
+// findStorageNonce answers the challenge as a node does with its plot.
+func findStorageNonce(sender common.Address, challenge common.Hash, difficulty uint64) (uint64, bool) {
+	for nonce := uint64(0); nonce < 1<<difficulty; nonce++ {
+		if admission.ValidateStorage(sender, challenge.Bytes(), nonce, difficulty) {
+			return nonce, true
+		}
+	}
+	return 0, false
+}
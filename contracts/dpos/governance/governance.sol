@@ -0,0 +1,190 @@
+pragma solidity ^0.4.24;
+
+import "./lib/safeMath.sol";
+
+// rnodes vote the proposals, the rnode contract must be deployed before this governance contract
+contract RnodeInterface {
+    function isRnode(address _addr) public view returns (bool);
+    function getRnodeNum() public view returns (uint256);
+    function Participants(address _addr) public view returns (uint256 lockedDeposit, uint256 lockedTime);
+}
+
+// Governance replaces the single owner key of system contracts by the votes of rnodes.
+// A proposal calls a target contract with some data once adopted: an rnode proposes it, it is adopted when
+// a quorum of rnodes approves it within the voting period, and anyone executes it after the timelock.
+// Parameters read by the dpos engine are adopted by proposals calling setParam of this contract, the engine
+// reads them at term boundaries. To govern the setters of another contract, this contract must be its owner.
+contract Governance {
+
+    using SafeMath for uint256;
+
+    struct Proposal {
+        address proposer;
+        address target; // contract called once adopted
+        bytes data; // data of the call
+        string description;
+        uint256 createdTime;
+        uint256 quorum; // approvals to adopt, fixed by the number of rnodes when proposed
+        uint256 approvals;
+        uint256 rejections;
+        uint256 adoptedTime; // 0 until adopted
+        bool executed;
+        mapping(address => bool) voted;
+    }
+
+    RnodeInterface public rnode;
+
+    uint256 public quorumPercentage = 67; // percentage of rnodes to approve a proposal
+    uint256 public votingPeriod = 3 days; // a proposal is voted within the period after it is proposed
+    uint256 public timelock = 1 days; // an adopted proposal is executed after the timelock
+    uint256 public executionPeriod = 7 days; // an adopted proposal expires if not executed within the period after the timelock
+
+    Proposal[] proposals;
+
+    // parameters adopted
+    mapping(bytes32 => uint256) params;
+    mapping(bytes32 => bool) paramSet;
+    bytes32[] paramKeys;
+
+    modifier onlyRnode() {require(rnode.isRnode(msg.sender));_;}
+    // setters of this contract are only called by executing proposals
+    modifier onlySelf() {require(msg.sender == address(this));_;}
+
+    event Proposed(uint256 id, address proposer, address target, string description);
+    event Voted(uint256 id, address voter, bool approve);
+    event Adopted(uint256 id, uint256 executableTime);
+    event Executed(uint256 id);
+    event ParamSet(bytes32 key, uint256 value);
+
+    constructor(address _rnodeAddr) public {
+        rnode = RnodeInterface(_rnodeAddr);
+    }
+
+    function propose(address _target, bytes _data, string _description) public onlyRnode returns (uint256) {
+        require(_target != address(0));
+
+        uint256 quorum = rnode.getRnodeNum().mul(quorumPercentage).add(99).div(100);
+        if (quorum == 0) {
+            quorum = 1;
+        }
+        proposals.push(Proposal({
+            proposer: msg.sender,
+            target: _target,
+            data: _data,
+            description: _description,
+            createdTime: block.timestamp,
+            quorum: quorum,
+            approvals: 0,
+            rejections: 0,
+            adoptedTime: 0,
+            executed: false
+        }));
+
+        uint256 id = proposals.length - 1;
+        emit Proposed(id, msg.sender, _target, _description);
+        return id;
+    }
+
+    function vote(uint256 _id, bool _approve) public onlyRnode {
+        require(_id < proposals.length);
+        Proposal storage p = proposals[_id];
+        require(p.adoptedTime == 0);
+        require(block.timestamp < p.createdTime.add(votingPeriod));
+        require(!p.voted[msg.sender]);
+        // only rnodes locked before the proposal vote, as the quorum is counted against them. rejoining resets
+        // the locked time, so a deposit rotated through new addresses or a late rnode can not vote
+        (, uint256 lockedTime) = rnode.Participants(msg.sender);
+        require(lockedTime < p.createdTime);
+
+        p.voted[msg.sender] = true;
+        if (_approve) {
+            p.approvals = p.approvals.add(1);
+        } else {
+            p.rejections = p.rejections.add(1);
+        }
+        emit Voted(_id, msg.sender, _approve);
+
+        if (p.approvals >= p.quorum) {
+            p.adoptedTime = block.timestamp;
+            emit Adopted(_id, block.timestamp.add(timelock));
+        }
+    }
+
+    function execute(uint256 _id) public {
+        require(_id < proposals.length);
+        Proposal storage p = proposals[_id];
+        require(p.adoptedTime != 0 && !p.executed);
+        require(block.timestamp >= p.adoptedTime.add(timelock));
+        require(block.timestamp < p.adoptedTime.add(timelock).add(executionPeriod));
+
+        p.executed = true;
+        require(p.target.call(p.data));
+        emit Executed(_id);
+    }
+
+    function setParam(bytes32 _key, uint256 _value) public onlySelf {
+        if (!paramSet[_key]) {
+            paramSet[_key] = true;
+            paramKeys.push(_key);
+        }
+        params[_key] = _value;
+        emit ParamSet(_key, _value);
+    }
+
+    function updateQuorumPercentage(uint256 _quorumPercentage) public onlySelf {
+        require(_quorumPercentage > 50 && _quorumPercentage <= 100);
+        quorumPercentage = _quorumPercentage;
+    }
+
+    function updateVotingPeriod(uint256 _votingPeriod) public onlySelf {
+        require(_votingPeriod >= 1 hours && _votingPeriod <= 30 days);
+        votingPeriod = _votingPeriod;
+    }
+
+    function updateTimelock(uint256 _timelock) public onlySelf {
+        require(_timelock >= 1 hours && _timelock <= 30 days);
+        timelock = _timelock;
+    }
+
+    function updateExecutionPeriod(uint256 _executionPeriod) public onlySelf {
+        require(_executionPeriod >= 1 days && _executionPeriod <= 30 days);
+        executionPeriod = _executionPeriod;
+    }
+
+    function getParam(bytes32 _key) public view returns (uint256 value, bool set) {
+        return (params[_key], paramSet[_key]);
+    }
+
+    function getParamKeys() public view returns (bytes32[]) {
+        return paramKeys;
+    }
+
+    function proposalCount() public view returns (uint256) {
+        return proposals.length;
+    }
+
+    function proposalOf(uint256 _id)
+    public
+    view
+    returns (address proposer, address target, bytes data, string description, uint256 createdTime)
+    {
+        require(_id < proposals.length);
+        Proposal storage p = proposals[_id];
+        return (p.proposer, p.target, p.data, p.description, p.createdTime);
+    }
+
+    function proposalStatusOf(uint256 _id)
+    public
+    view
+    returns (uint256 quorum, uint256 approvals, uint256 rejections, uint256 adoptedTime, bool executed)
+    {
+        require(_id < proposals.length);
+        Proposal storage p = proposals[_id];
+        return (p.quorum, p.approvals, p.rejections, p.adoptedTime, p.executed);
+    }
+
+    function hasVoted(uint256 _id, address _voter) public view returns (bool) {
+        require(_id < proposals.length);
+        return proposals[_id].voted[_voter];
+    }
+}
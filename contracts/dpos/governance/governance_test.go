@@ -0,0 +1,337 @@
+package governance_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gcchains/chain/accounts/abi"
+	"github.com/gcchains/chain/accounts/abi/bind"
+	"github.com/gcchains/chain/accounts/abi/bind/backends"
+	"github.com/gcchains/chain/configs"
+	"github.com/gcchains/chain/contracts/dpos/governance"
+	"github.com/gcchains/chain/contracts/dpos/rnode"
+	"github.com/gcchains/chain/core"
+	"github.com/gcchains/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	ownerKey, _ = crypto.HexToECDSA("171c71a67e1177ad4e901695e1b419ee17ae16c6668d313eac2f96dbcd13f291")
+	ownerAddr   = crypto.PubkeyToAddress(ownerKey.PublicKey)
+
+	rnodeKeys = []*ecdsa.PrivateKey{
+		mustKey("2a1f9a8f95be41cd7ccb6168179afb4504ae1e388d1e14474d32c45172ce7b7a"),
+		mustKey("cad9c8855b740a0fed4c221dbad0f33a83a49cad6b3fe8d5817ac83d38b6a195"),
+		mustKey("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45172ce7b7a"),
+		mustKey("49a7b37aa6f6645917e7b807e9d1c00d4fa71f18343b0d4122a4d2df64dd6fee"),
+	}
+	lateKey = mustKey("b71c71a67e1177ad4e901695e1b419ee17ae16c6668d313eac2f96dbcd13f291")
+
+	paramKey = [32]byte{'t', 'e', 's', 't'}
+)
+
+func mustKey(hex string) *ecdsa.PrivateKey {
+	key, err := crypto.HexToECDSA(hex)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+type testGovernance struct {
+	t          *testing.T
+	backend    *backends.SimulatedBackend
+	rnode      *rnode.Rnode
+	governance *governance.Governance
+	addr       common.Address
+	abi        abi.ABI
+}
+
+// newTestGovernance deploys the rnode and governance contracts, the rnodeKeys joining rnode before governance
+func newTestGovernance(t *testing.T) *testGovernance {
+	alloc := core.GenesisAlloc{ownerAddr: {Balance: new(big.Int).Mul(big.NewInt(1000000), big.NewInt(configs.Gcc))}}
+	for _, key := range append(rnodeKeys, lateKey) {
+		alloc[crypto.PubkeyToAddress(key.PublicKey)] = core.GenesisAccount{Balance: new(big.Int).Mul(big.NewInt(1000000), big.NewInt(configs.Gcc))}
+	}
+	g := &testGovernance{t: t, backend: backends.NewDposSimulatedBackend(alloc)}
+
+	rnodeAddr, _, rnodeInstance, err := rnode.DeployRnode(bind.NewKeyedTransactor(ownerKey), g.backend)
+	if err != nil {
+		t.Fatal("deploy rnode", err)
+	}
+	g.rnode = rnodeInstance
+	g.backend.Commit()
+	for _, key := range rnodeKeys {
+		g.joinRnode(key)
+	}
+
+	g.addr, _, g.governance, err = governance.DeployGovernance(bind.NewKeyedTransactor(ownerKey), g.backend, rnodeAddr)
+	if err != nil {
+		t.Fatal("deploy governance", err)
+	}
+	g.backend.Commit()
+	g.abi, err = abi.JSON(strings.NewReader(governance.GovernanceABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.adjustTime(time.Minute)
+	return g
+}
+
+// adjustTime moves the clock of the evm by d, header times of the simulated backend are in milliseconds
+func (g *testGovernance) adjustTime(d time.Duration) {
+	if err := g.backend.AdjustTime(d * 1000); err != nil {
+		g.t.Fatal(err)
+	}
+	g.backend.Commit()
+}
+
+// mined commits the transaction and checks it succeeded
+func (g *testGovernance) mined(name string, tx *types.Transaction, err error) {
+	g.t.Helper()
+	if err != nil {
+		g.t.Fatalf("%s: %v", name, err)
+	}
+	g.backend.Commit()
+	receipt, err := g.backend.TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil {
+		g.t.Fatalf("%s: %v", name, err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		g.t.Fatalf("%s: transaction failed", name)
+	}
+}
+
+// rejected checks the transaction is rejected by the contract
+func (g *testGovernance) rejected(_ *types.Transaction, err error) {
+	g.t.Helper()
+	if err == nil {
+		g.t.Fatal("transaction is not rejected")
+	}
+}
+
+func (g *testGovernance) joinRnode(key *ecdsa.PrivateKey) {
+	opts := bind.NewKeyedTransactor(key)
+	opts.Value = new(big.Int).Mul(big.NewInt(200000), big.NewInt(configs.Gcc))
+	tx, err := g.rnode.JoinRnode(opts, big.NewInt(1))
+	g.mined("join rnode", tx, err)
+}
+
+func (g *testGovernance) pack(method string, args ...interface{}) []byte {
+	data, err := g.abi.Pack(method, args...)
+	if err != nil {
+		g.t.Fatal(err)
+	}
+	return data
+}
+
+// propose proposes a call of the governance contract by the first rnode and returns the id of the proposal
+func (g *testGovernance) propose(data []byte) *big.Int {
+	tx, err := g.governance.Propose(bind.NewKeyedTransactor(rnodeKeys[0]), g.addr, data, "test")
+	g.mined("propose", tx, err)
+	count, err := g.governance.ProposalCount(nil)
+	if err != nil {
+		g.t.Fatal(err)
+	}
+	g.adjustTime(time.Minute)
+	return count.Sub(count, big.NewInt(1))
+}
+
+// adopt proposes the call and approves it by the rnodes
+func (g *testGovernance) adopt(data []byte) *big.Int {
+	id := g.propose(data)
+	for _, key := range rnodeKeys[:3] {
+		tx, err := g.governance.Vote(bind.NewKeyedTransactor(key), id, true)
+		g.mined("vote", tx, err)
+	}
+	return id
+}
+
+func (g *testGovernance) status(id *big.Int) (quorum, approvals, rejections, adoptedTime uint64, executed bool) {
+	status, err := g.governance.ProposalStatusOf(nil, id)
+	if err != nil {
+		g.t.Fatal(err)
+	}
+	return status.Quorum.Uint64(), status.Approvals.Uint64(), status.Rejections.Uint64(), status.AdoptedTime.Uint64(), status.Executed
+}
+
+func TestGovernancePropose(t *testing.T) {
+	g := newTestGovernance(t)
+
+	data := g.pack("setParam", paramKey, big.NewInt(42))
+	// propose by a non rnode
+	g.rejected(g.governance.Propose(bind.NewKeyedTransactor(lateKey), g.addr, data, "test"))
+	// propose to the zero address
+	g.rejected(g.governance.Propose(bind.NewKeyedTransactor(rnodeKeys[0]), common.Address{}, data, "test"))
+
+	description := strings.Repeat("a description longer than a word ", 3)
+	tx, err := g.governance.Propose(bind.NewKeyedTransactor(rnodeKeys[0]), g.addr, data, description)
+	g.mined("propose", tx, err)
+
+	count, err := g.governance.ProposalCount(nil)
+	if err != nil || count.Uint64() != 1 {
+		t.Fatalf("proposal count: want 1, got %v, %v", count, err)
+	}
+	p, err := g.governance.ProposalOf(nil, big.NewInt(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Proposer != crypto.PubkeyToAddress(rnodeKeys[0].PublicKey) || p.Target != g.addr {
+		t.Errorf("proposer, target: got %x, %x", p.Proposer, p.Target)
+	}
+	if !bytes.Equal(p.Data, data) || p.Description != description {
+		t.Errorf("data, description: got %x, %q", p.Data, p.Description)
+	}
+	if p.CreatedTime.Sign() == 0 {
+		t.Error("created time is not set")
+	}
+	// 67% of 4 rnodes
+	if quorum, approvals, _, adopted, _ := g.status(big.NewInt(0)); quorum != 3 || approvals != 0 || adopted != 0 {
+		t.Errorf("status: got quorum %d, approvals %d, adopted %d", quorum, approvals, adopted)
+	}
+	if _, err := g.governance.ProposalOf(nil, big.NewInt(1)); err == nil {
+		t.Error("proposal of an unknown id: want error")
+	}
+}
+
+func TestGovernanceVote(t *testing.T) {
+	g := newTestGovernance(t)
+	id := g.propose(g.pack("setParam", paramKey, big.NewInt(42)))
+
+	// vote by a non rnode
+	g.rejected(g.governance.Vote(bind.NewKeyedTransactor(lateKey), id, true))
+	// vote an unknown proposal
+	g.rejected(g.governance.Vote(bind.NewKeyedTransactor(rnodeKeys[0]), big.NewInt(1), true))
+
+	tx, err := g.governance.Vote(bind.NewKeyedTransactor(rnodeKeys[3]), id, false)
+	g.mined("reject", tx, err)
+	// vote twice
+	g.rejected(g.governance.Vote(bind.NewKeyedTransactor(rnodeKeys[3]), id, true))
+	for _, key := range rnodeKeys[:2] {
+		tx, err := g.governance.Vote(bind.NewKeyedTransactor(key), id, true)
+		g.mined("approve", tx, err)
+	}
+	if _, approvals, rejections, adopted, _ := g.status(id); approvals != 2 || rejections != 1 || adopted != 0 {
+		t.Fatalf("status: got approvals %d, rejections %d, adopted %d", approvals, rejections, adopted)
+	}
+	voted, err := g.governance.HasVoted(nil, id, crypto.PubkeyToAddress(rnodeKeys[0].PublicKey))
+	if err != nil || !voted {
+		t.Errorf("has voted: want true, got %v, %v", voted, err)
+	}
+
+	tx, err = g.governance.Vote(bind.NewKeyedTransactor(rnodeKeys[2]), id, true)
+	g.mined("approve", tx, err)
+	if _, approvals, _, adopted, _ := g.status(id); approvals != 3 || adopted == 0 {
+		t.Fatalf("status: got approvals %d, adopted %d", approvals, adopted)
+	}
+	// vote an adopted proposal
+	g.rejected(g.governance.Vote(bind.NewKeyedTransactor(rnodeKeys[3]), id, true))
+
+	id = g.propose(g.pack("setParam", paramKey, big.NewInt(43)))
+	g.adjustTime(3 * 24 * time.Hour)
+	// vote after the voting period
+	g.rejected(g.governance.Vote(bind.NewKeyedTransactor(rnodeKeys[0]), id, true))
+}
+
+func TestGovernanceVoteLockedBeforeProposal(t *testing.T) {
+	g := newTestGovernance(t)
+	id := g.propose(g.pack("setParam", paramKey, big.NewInt(42)))
+
+	// an rnode joining after the proposal does not vote
+	g.joinRnode(lateKey)
+	// vote by a late rnode
+	g.rejected(g.governance.Vote(bind.NewKeyedTransactor(lateKey), id, true))
+
+	// neither does a deposit rotated through a quit and a new join
+	g.adjustTime(2 * time.Hour)
+	tx, err := g.governance.Vote(bind.NewKeyedTransactor(rnodeKeys[0]), id, true)
+	g.mined("vote", tx, err)
+	tx, err = g.rnode.QuitRnode(bind.NewKeyedTransactor(rnodeKeys[1]))
+	g.mined("quit rnode", tx, err)
+	g.joinRnode(rnodeKeys[1])
+	// vote by a rejoined rnode
+	g.rejected(g.governance.Vote(bind.NewKeyedTransactor(rnodeKeys[1]), id, true))
+}
+
+func TestGovernanceExecute(t *testing.T) {
+	g := newTestGovernance(t)
+	id := g.propose(g.pack("setParam", paramKey, big.NewInt(42)))
+	// execute before adopted
+	g.rejected(g.governance.Execute(bind.NewKeyedTransactor(ownerKey), id))
+
+	for _, key := range rnodeKeys[:3] {
+		tx, err := g.governance.Vote(bind.NewKeyedTransactor(key), id, true)
+		g.mined("vote", tx, err)
+	}
+	// execute within the timelock
+	g.rejected(g.governance.Execute(bind.NewKeyedTransactor(ownerKey), id))
+
+	g.adjustTime(24 * time.Hour)
+	tx, err := g.governance.Execute(bind.NewKeyedTransactor(ownerKey), id)
+	g.mined("execute", tx, err)
+	if _, _, _, _, executed := g.status(id); !executed {
+		t.Error("proposal is not executed")
+	}
+	// execute twice
+	g.rejected(g.governance.Execute(bind.NewKeyedTransactor(ownerKey), id))
+
+	param, err := g.governance.GetParam(nil, paramKey)
+	if err != nil || !param.Set || param.Value.Uint64() != 42 {
+		t.Fatalf("param: want 42, got %v, %v", param, err)
+	}
+	keys, err := g.governance.GetParamKeys(nil)
+	if err != nil || len(keys) != 1 || keys[0] != paramKey {
+		t.Fatalf("param keys: got %x, %v", keys, err)
+	}
+
+	id = g.adopt(g.pack("setParam", paramKey, big.NewInt(43)))
+	g.adjustTime(8 * 24 * time.Hour)
+	// execute after the execution period
+	g.rejected(g.governance.Execute(bind.NewKeyedTransactor(ownerKey), id))
+}
+
+func TestGovernanceSetParam(t *testing.T) {
+	g := newTestGovernance(t)
+
+	// set param by an account
+	g.rejected(g.governance.SetParam(bind.NewKeyedTransactor(ownerKey), paramKey, big.NewInt(1)))
+	// update timelock by an account
+	g.rejected(g.governance.UpdateTimelock(bind.NewKeyedTransactor(ownerKey), big.NewInt(7200)))
+
+	id := g.adopt(g.pack("updateTimelock", big.NewInt(7200)))
+	g.adjustTime(24 * time.Hour)
+	tx, err := g.governance.Execute(bind.NewKeyedTransactor(ownerKey), id)
+	g.mined("execute", tx, err)
+	if timelock, err := g.governance.Timelock(nil); err != nil || timelock.Uint64() != 7200 {
+		t.Fatalf("timelock: want 7200, got %v, %v", timelock, err)
+	}
+
+	// the call of an adopted proposal must succeed
+	id = g.adopt(g.pack("updateQuorumPercentage", big.NewInt(50)))
+	g.adjustTime(2 * time.Hour)
+	// execute an invalid update
+	g.rejected(g.governance.Execute(bind.NewKeyedTransactor(ownerKey), id))
+
+	for i, key := range [][32]byte{paramKey, {'a'}, paramKey} {
+		id = g.adopt(g.pack("setParam", key, big.NewInt(int64(i))))
+		g.adjustTime(2 * time.Hour)
+		tx, err := g.governance.Execute(bind.NewKeyedTransactor(ownerKey), id)
+		g.mined("execute", tx, err)
+	}
+	keys, err := g.governance.GetParamKeys(nil)
+	if err != nil || len(keys) != 2 || keys[0] != paramKey || keys[1] != [32]byte{'a'} {
+		t.Fatalf("param keys: got %x, %v", keys, err)
+	}
+	if param, err := g.governance.GetParam(nil, paramKey); err != nil || param.Value.Uint64() != 2 {
+		t.Fatalf("param: want 2, got %v, %v", param, err)
+	}
+	if param, err := g.governance.GetParam(nil, [32]byte{'b'}); err != nil || param.Set {
+		t.Fatalf("unknown param: got %v, %v", param, err)
+	}
+}
@@ -92,8 +92,7 @@ func (cc *ApiClient) HeaderByNumber(ctx context.Context, number *big.Int) (*type
 }
 
 func (cc *ApiClient) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
-	blockNr := rpc.LatestBlockNumber
-	state, _, err := cc.ChainBackend.StateAndHeaderByNumber(ctx, blockNr, false)
+	state, _, err := cc.ChainBackend.StateAndHeaderByNumber(ctx, toBlockNumber(blockNumber), false)
 	if state == nil || err != nil {
 		return nil, err
 	}
@@ -102,12 +101,20 @@ func (cc *ApiClient) CodeAt(ctx context.Context, account common.Address, blockNu
 }
 
 func (cc *ApiClient) CallContract(ctx context.Context, call gcchain.CallMsg, blockNumber *big.Int) ([]byte, error) {
-	result, err := cc.ContractBackend.Call(ctx, toCallArg(call), rpc.LatestBlockNumber)
+	result, err := cc.ContractBackend.Call(ctx, toCallArg(call), toBlockNumber(blockNumber))
 	if err != nil {
-		log.Fatal("CallContract using PublicBlockChainAPI is error ", "error is ", err)
+		log.Warn("CallContract using PublicBlockChainAPI is error ", "number", blockNumber, "error is ", err)
 	}
 	return result, err
 }
+
+// toBlockNumber returns the rpc block number of the number, nil is the latest block.
+func toBlockNumber(number *big.Int) rpc.BlockNumber {
+	if number == nil {
+		return rpc.LatestBlockNumber
+	}
+	return rpc.BlockNumber(number.Int64())
+}
 func toCallArg(msg gcchain.CallMsg) gccapi.CallArgs {
 	arg := gccapi.CallArgs{
 		From: msg.From,
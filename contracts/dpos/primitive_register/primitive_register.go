@@ -6,6 +6,7 @@ import (
 
 	gcchain "/gcchain/chain"
 	"github.com/gcchains/chain/commons/log"
+	"github.com/gcchains/chain/configs"
 	"github.com/gcchains/chain/contracts/dpos/primitives"
 	"github.com/gcchains/chain/core/vm"
 	"github.com/gcchains/chain/types"
@@ -33,6 +34,14 @@ func RegisterPrimitiveContracts() {
 			log.Fatal("register primitive contract error", "error", err, "addr", addr)
 		}
 	}
+	for fork, contracts := range MakeForkPrimitiveContracts() {
+		for addr, c := range contracts {
+			err := vm.RegisterForkPrimitiveContract(addr, c, fork)
+			if err != nil {
+				log.Fatal("register primitive contract error", "error", err, "addr", addr, "fork", fork)
+			}
+		}
+	}
 }
 
 func MakePrimitiveContracts() map[common.Address]vm.PrimitiveContract {
@@ -42,3 +51,13 @@ func MakePrimitiveContracts() map[common.Address]vm.PrimitiveContract {
 	contracts[common.BytesToAddress([]byte{107})] = &primitives.MemPowValidate{}
 	return contracts
 }
+
+// MakeForkPrimitiveContracts returns the primitive contracts added by forks, calls to them are calls
+// to empty accounts before the forks are activated.
+func MakeForkPrimitiveContracts() map[configs.Fork]map[common.Address]vm.PrimitiveContract {
+	return map[configs.Fork]map[common.Address]vm.PrimitiveContract{
+		configs.StorageProof: {
+			common.BytesToAddress([]byte{108}): &primitives.StorageValidate{},
+		},
+	}
+}
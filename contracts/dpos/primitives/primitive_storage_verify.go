@@ -0,0 +1,24 @@
+package primitives
+
+import (
+	"github.com/gcchains/chain/admission"
+	"github.com/gcchains/chain/configs"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// StorageValidate does a storage proof validation, the input is laid out as the pow validation input
+// with the hash of the challenge block in place of the block hash.
+type StorageValidate struct{}
+
+func (s *StorageValidate) RequiredGas(input []byte) uint64 {
+	return configs.StorageValidateGas
+}
+
+func (s *StorageValidate) Run(input []byte) ([]byte, error) {
+	address, nonce, challengeHash, difficulty := unpackPowValidateArgs(common.RightPadBytes(input, 128))
+	if admission.ValidateStorage(address, challengeHash, nonce, difficulty) {
+		return true32Byte, nil
+	} else {
+		return false32Byte, nil
+	}
+}
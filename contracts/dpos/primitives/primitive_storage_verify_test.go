@@ -0,0 +1,65 @@
+package primitives
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/gcchains/chain/admission"
+	"github.com/gcchains/chain/configs"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func packStorageValidateArgs(address common.Address, nonce uint64, challengeHash []byte, difficulty uint64) []byte {
+	input := common.LeftPadBytes(address.Bytes(), 32)
+	input = append(input, common.LeftPadBytes(new(big.Int).SetUint64(nonce).Bytes(), 32)...)
+	input = append(input, challengeHash...)
+	return append(input, common.LeftPadBytes(new(big.Int).SetUint64(difficulty).Bytes(), 32)...)
+}
+
+func TestStorageValidate(t *testing.T) {
+	var (
+		sender        = common.HexToAddress("0x7900dd1d71fc5c57ba56e4b768de3c2264253335")
+		challengeHash = crypto.Keccak256([]byte("challenge"))
+		difficulty    = uint64(6)
+		validator     = &StorageValidate{}
+	)
+
+	// search the answer as a node does with its plot
+	nonce, found := uint64(0), false
+	for ; nonce < 1<<difficulty; nonce++ {
+		if admission.ValidateStorage(sender, challengeHash, nonce, difficulty) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Fatal("no plot entry matches the challenge")
+	}
+
+	tests := []struct {
+		name  string
+		input []byte
+		want  []byte
+	}{
+		{"valid proof", packStorageValidateArgs(sender, nonce, challengeHash, difficulty), true32Byte},
+		{"nonce out of plot", packStorageValidateArgs(sender, 1<<difficulty, challengeHash, difficulty), false32Byte},
+		{"zero difficulty", packStorageValidateArgs(sender, nonce, challengeHash, 0), false32Byte},
+		{"empty input", nil, false32Byte},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if gas := validator.RequiredGas(tt.input); gas != configs.StorageValidateGas {
+				t.Errorf("RequiredGas() = %d, want %d", gas, configs.StorageValidateGas)
+			}
+			got, err := validator.Run(tt.input)
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("Run() = %x, want %x", got, tt.want)
+			}
+		})
+	}
+}
@@ -112,6 +112,8 @@ type CacheConfig struct {
 	Disabled      bool          // Whether to disable trie write caching (archive node)
 	TrieNodeLimit int           // Memory limit (MB) at which to flush the current in-memory trie to disk
 	TrieTimeLimit time.Duration // Time limit after which to flush the current in-memory trie to disk
+
+	StateRetention uint64 // Number of recent blocks whose state is kept when pruning online, 0 keeps all states
 }
 
 // BlockChain represents the canonical chain given a database with a genesis
@@ -166,6 +168,8 @@ type BlockChain struct {
 	// procInterrupt must be atomically called
 	procInterrupt int32          // interrupt signaler for block processing
 	wg            sync.WaitGroup // chain processing wait group for shutting down
+	pruning       int32          // whether state pruning is running, must be accessed atomically
+	writtenRoots  []common.Hash  // state roots written while pruning, nil if not pruning, guarded by mu
 
 	engine    consensus.Engine
 	processor Processor // block processor interface
@@ -204,6 +208,9 @@ func NewBlockChain(db database.Database, cacheConfig *CacheConfig, chainConfig *
 			TrieTimeLimit: 5 * time.Minute,
 		}
 	}
+	if retention := cacheConfig.StateRetention; retention > 0 && retention < MinStateRetention {
+		return nil, fmt.Errorf("state retention %d is less than %d", retention, MinStateRetention)
+	}
 	bodyCache, _ := lru.New(bodyCacheLimit)
 	bodyRLPCache, _ := lru.New(bodyCacheLimit)
 	blockCache, _ := lru.New(blockCacheLimit)
@@ -390,6 +397,11 @@ func (bc *BlockChain) SetHead(head uint64) error {
 	bc.hc.SetHead(head, delFn)
 	currentHeader := bc.hc.CurrentHeader()
 
+	// Drop the frozen blocks above the new head, they are no longer canonical
+	if err := rawdb.TruncateAncients(bc.db, currentHeader.Number.Uint64()+1); err != nil {
+		log.Error("Failed to truncate ancient blocks", "err", err)
+	}
+
 	// Clear out any stale content from the caches
 	bc.bodyCache.Purge()
 	bc.bodyRLPCache.Purge()
@@ -756,6 +768,11 @@ func (bc *BlockChain) GetBlocksFromHash(hash common.Hash, n int) (blocks []*type
 	return
 }
 
+// StateCache returns the caching database underpinning the blockchain instance.
+func (bc *BlockChain) StateCache() state.Database {
+	return bc.stateCache
+}
+
 // TrieNode retrieves a blob of data associated with a trie node (or code hash)
 // either from ephemeral in-memory cache, or from persistent storage.
 func (bc *BlockChain) TrieNode(hash common.Hash) ([]byte, error) {
@@ -1062,6 +1079,9 @@ func (bc *BlockChain) WriteBlockWithState(block *types.Block, pubReceipts []*typ
 	if err != nil {
 		return NonStatTy, err
 	}
+	if bc.writtenRoots != nil {
+		bc.writtenRoots = append(bc.writtenRoots, root)
+	}
 
 	triedb := bc.stateCache.TrieDB()
 	// If we're running an archive node, always flush
@@ -1154,11 +1174,35 @@ func (bc *BlockChain) WriteBlockWithState(block *types.Block, pubReceipts []*typ
 	// Set new head.
 	if status == CanonStatTy {
 		bc.insert(block)
+		if retention := bc.cacheConfig.StateRetention; retention > 0 && block.NumberU64()%retention == 0 {
+			bc.pruneStateInBackground(retention)
+		}
 	}
 	bc.futureBlocks.Remove(block.Hash())
 	return status, nil
 }
 
+// pruneStateInBackground prunes states older than the recent retention blocks without blocking the caller,
+// it does nothing if a previous pruning is still running.
+func (bc *BlockChain) pruneStateInBackground(retention uint64) {
+	if !atomic.CompareAndSwapInt32(&bc.pruning, 0, 1) {
+		return
+	}
+
+	bc.wg.Add(1)
+	go func() {
+		defer bc.wg.Done()
+		defer atomic.StoreInt32(&bc.pruning, 0)
+
+		if bc.getProcInterrupt() {
+			return
+		}
+		if _, err := bc.PruneState(retention); err != nil {
+			log.Error("Failed to prune state", "err", err)
+		}
+	}()
+}
+
 // InsertChain attempts to insert the given batch of blocks in to the canonical
 // chain or, otherwise, create a fork. If an error is returned it will return
 // the index number of the failing block as well an error describing what went
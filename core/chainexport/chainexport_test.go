@@ -0,0 +1,243 @@
+package chainexport
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gcchains/chain/configs"
+	"github.com/gcchains/chain/consensus/dpos"
+	"github.com/gcchains/chain/core"
+	"github.com/gcchains/chain/core/vm"
+	"github.com/gcchains/chain/database"
+	"github.com/gcchains/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	testKey, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	testAddress = crypto.PubkeyToAddress(testKey.PublicKey)
+	testGenesis = &core.Genesis{
+		Config:   configs.TestChainConfig,
+		GasLimit: 3141592,
+		Alloc:    core.GenesisAlloc{testAddress: {Balance: big.NewInt(1000000000)}},
+	}
+)
+
+// newTestChain creates a chain holding the blocks, or only the genesis if blocks is nil.
+func newTestChain(t *testing.T, blocks types.Blocks) *core.BlockChain {
+	db := database.NewMemDatabase()
+	testGenesis.MustCommit(db)
+	engine := dpos.NewFaker(configs.ChainConfigInfo().Dpos, db)
+	chain, err := core.NewBlockChain(db, nil, testGenesis.Config, engine, vm.Config{}, database.NewIpfsDbWithAdapter(database.NewFakeIpfsAdapter()), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	return chain
+}
+
+// generateBlocks generates n blocks, every third one with a transfer.
+func generateBlocks(n int) types.Blocks {
+	db := database.NewMemDatabase()
+	genesis := testGenesis.MustCommit(db)
+	engine := dpos.NewFaker(configs.ChainConfigInfo().Dpos, db)
+	signer := types.NewCep1Signer(testGenesis.Config.ChainID)
+	blocks, _ := core.GenerateChain(testGenesis.Config, genesis, engine, db, database.NewIpfsDbWithAdapter(database.NewFakeIpfsAdapter()), n, func(i int, gen *core.BlockGen) {
+		if i%3 == 0 {
+			tx, _ := types.SignTx(types.NewTransaction(gen.TxNonce(testAddress), common.Address{1}, big.NewInt(1), configs.TxGas, nil, nil), signer, testKey)
+			gen.AddTx(tx)
+		}
+	})
+	return blocks
+}
+
+func newExportDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "chainexport")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func segmentRanges(m *Manifest) string {
+	var ranges []string
+	for _, s := range m.Segments {
+		ranges = append(ranges, fmt.Sprintf("%d-%d", s.First, s.Last))
+	}
+	return fmt.Sprint(ranges)
+}
+
+func TestExportResume(t *testing.T) {
+	dir := newExportDir(t)
+	defer os.RemoveAll(dir)
+
+	blocks := generateBlocks(25)
+	chain := newTestChain(t, blocks)
+	defer chain.Stop()
+
+	manifest, err := Export(chain, dir, ExportConfig{SegmentSize: 10, Last: 15}, nil)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if got := segmentRanges(manifest); got != "[0-9 10-15]" {
+		t.Fatalf("segments = %s, want [0-9 10-15]", got)
+	}
+	// A later export continues after the last segment
+	if manifest, err = Export(chain, dir, ExportConfig{Last: 25}, nil); err != nil {
+		t.Fatalf("resumed Export() error = %v", err)
+	}
+	if got := segmentRanges(manifest); got != "[0-9 10-15 16-19 20-25]" {
+		t.Fatalf("resumed segments = %s, want [0-9 10-15 16-19 20-25]", got)
+	}
+	if manifest.Segments[3].TipHash != blocks[24].Hash() {
+		t.Errorf("tip hash = %x, want %x", manifest.Segments[3].TipHash, blocks[24].Hash())
+	}
+	if _, err := Export(chain, dir, ExportConfig{First: 27, Last: 25}, nil); err == nil {
+		t.Error("export of an empty range succeeded")
+	}
+	if _, err := Export(chain, dir, ExportConfig{Format: FormatJSONL, Last: 25}, nil); err == nil {
+		t.Error("export in another format into the directory succeeded")
+	}
+
+	// Segments no longer canonical are exported again
+	manifest.Segments[2].TipHash = common.Hash{1}
+	if err := manifest.save(dir); err != nil {
+		t.Fatal(err)
+	}
+	if manifest, err = Export(chain, dir, ExportConfig{Last: 25}, nil); err != nil {
+		t.Fatalf("Export() after reorg error = %v", err)
+	}
+	if got := segmentRanges(manifest); got != "[0-9 10-15 16-19 20-25]" {
+		t.Errorf("segments after reorg = %s, want [0-9 10-15 16-19 20-25]", got)
+	}
+	if manifest.Segments[2].TipHash != blocks[18].Hash() {
+		t.Errorf("reexported tip hash = %x, want %x", manifest.Segments[2].TipHash, blocks[18].Hash())
+	}
+	stopped := newExportDir(t)
+	defer os.RemoveAll(stopped)
+	stop := make(chan struct{})
+	close(stop)
+	if _, err := Export(chain, stopped, ExportConfig{Last: 25}, stop); err != errInterrupted {
+		t.Errorf("stopped Export() error = %v, want %v", err, errInterrupted)
+	}
+}
+
+func TestImportSegments(t *testing.T) {
+	dir := newExportDir(t)
+	defer os.RemoveAll(dir)
+
+	blocks := generateBlocks(25)
+	source := newTestChain(t, blocks)
+	defer source.Stop()
+	manifest, err := Export(source, dir, ExportConfig{SegmentSize: 10, Last: 25}, nil)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	tip := Checkpoint{Number: 25, Hash: blocks[24].Hash()}
+
+	// A wrong checkpoint fails the import before any block is inserted
+	chain := newTestChain(t, nil)
+	defer chain.Stop()
+	for _, checkpoint := range []Checkpoint{{Number: 12, Hash: common.Hash{1}}, {Number: 30, Hash: common.Hash{1}}} {
+		if _, err := Import(chain, dir, []Checkpoint{checkpoint}, nil); err == nil {
+			t.Errorf("import with checkpoint %d succeeded", checkpoint.Number)
+		}
+	}
+	if head := chain.CurrentBlock().NumberU64(); head != 0 {
+		t.Fatalf("failed import inserted blocks up to %d", head)
+	}
+
+	// An interrupted import resumes with the missing blocks
+	if _, err := chain.InsertChain(blocks[:12]); err != nil {
+		t.Fatal(err)
+	}
+	imported, err := Import(chain, dir, []Checkpoint{tip}, nil)
+	if err != nil || imported != 13 {
+		t.Fatalf("Import() = %d, %v, want 13 blocks", imported, err)
+	}
+	if head := chain.CurrentBlock(); head.Hash() != blocks[24].Hash() {
+		t.Fatalf("head after import = %d, want 25", head.NumberU64())
+	}
+	imported, err = Import(chain, dir, manifest.Checkpoints(), nil)
+	if err != nil || imported != 0 {
+		t.Errorf("repeated Import() = %d, %v, want 0 blocks", imported, err)
+	}
+
+	// A corrupted segment fails the verification
+	path := filepath.Join(dir, manifest.Segments[1].File)
+	data, _ := ioutil.ReadFile(path)
+	data[len(data)-5] ^= 0xff
+	ioutil.WriteFile(path, data, 0644)
+
+	other := newTestChain(t, nil)
+	defer other.Stop()
+	if _, err := Import(other, dir, nil, nil); err == nil {
+		t.Error("import of a corrupted segment succeeded")
+	}
+	if head := other.CurrentBlock().NumberU64(); head != 0 {
+		t.Errorf("failed import inserted blocks up to %d", head)
+	}
+}
+
+func TestExportJSONL(t *testing.T) {
+	dir := newExportDir(t)
+	defer os.RemoveAll(dir)
+
+	blocks := generateBlocks(4)
+	chain := newTestChain(t, blocks)
+	defer chain.Stop()
+
+	manifest, err := Export(chain, dir, ExportConfig{Format: FormatJSONL, First: 1, Last: 4}, nil)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if _, err := Import(chain, dir, nil, nil); err == nil {
+		t.Error("import of a jsonl export succeeded")
+	}
+	fh, err := os.Open(filepath.Join(dir, manifest.Segments[0].File))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fh.Close()
+	zr, err := gzip.NewReader(fh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lines []map[string]interface{}
+	for scanner := bufio.NewScanner(zr); scanner.Scan(); {
+		line := make(map[string]interface{})
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("invalid line %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) != 4 {
+		t.Fatalf("exported %d lines, want 4", len(lines))
+	}
+	if number := lines[0]["number"]; number != float64(1) {
+		t.Errorf("number of the first line = %v, want 1", number)
+	}
+	for _, field := range []string{"proposers", "validators", "sigs", "seal"} {
+		if _, ok := lines[0]["dpos"].(map[string]interface{})[field]; !ok {
+			t.Errorf("dpos snapshot misses %s", field)
+		}
+	}
+	txs := lines[0]["transactions"].([]interface{})
+	if len(txs) != 1 {
+		t.Fatalf("block 1 has %d transactions, want 1", len(txs))
+	}
+	tx := txs[0].(map[string]interface{})
+	if tx["from"] != fmt.Sprintf("0x%x", testAddress) || tx["receipt"] == nil {
+		t.Errorf("transaction = %v, want from %x with a receipt", tx, testAddress)
+	}
+}
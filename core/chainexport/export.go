@@ -0,0 +1,292 @@
+// Copyright 2018 The gcchain authors
+
+package chainexport
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"github.com/gcchains/chain/commons/log"
+	"github.com/gcchains/chain/core"
+	"github.com/gcchains/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// errInterrupted is returned if an export or import is stopped, the completed segments are kept.
+var errInterrupted = errors.New("interrupted")
+
+// ExportConfig selects the blocks and the format of an export.
+type ExportConfig struct {
+	Format      string // FormatRLP or FormatJSONL, FormatRLP if empty
+	SegmentSize uint64 // number of blocks per segment, that of the manifest or DefaultSegmentSize if 0
+	First       uint64 // first block to export
+	Last        uint64 // last block to export, at most the chain head
+}
+
+// Export exports the canonical blocks [First, Last] into segments in the directory, which is
+// created if needed. An export into a directory with a manifest resumes after its last segment,
+// segments no longer canonical due to a reorg are exported again. Closing stop interrupts the
+// export, the segment being written is discarded and the manifest lists the segments completed
+// so far.
+func Export(chain *core.BlockChain, dir string, config ExportConfig, stop <-chan struct{}) (*Manifest, error) {
+	if config.Format == "" {
+		config.Format = FormatRLP
+	}
+	if err := validateFormat(config.Format); err != nil {
+		return nil, err
+	}
+	if config.First > config.Last {
+		return nil, fmt.Errorf("first block %d is after last block %d", config.First, config.Last)
+	}
+	if head := chain.CurrentBlock().NumberU64(); config.Last > head {
+		return nil, fmt.Errorf("last block %d is beyond the chain head %d", config.Last, head)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	manifest, err := openManifest(chain, dir, config)
+	if err != nil {
+		return nil, err
+	}
+	next := config.First
+	if last, ok := manifest.Last(); ok {
+		if first := manifest.Segments[0].First; config.First < first || config.First > last+1 {
+			return nil, fmt.Errorf("export of %d-%d does not continue the exported blocks %d-%d", config.First, config.Last, first, last)
+		}
+		next = last + 1
+	}
+	for next <= config.Last {
+		select {
+		case <-stop:
+			return manifest, errInterrupted
+		default:
+		}
+		// segments end at multiples of the segment size, so exports of growing ranges line up
+		end := (next/manifest.SegmentSize+1)*manifest.SegmentSize - 1
+		if end > config.Last {
+			end = config.Last
+		}
+		segment, err := writeSegment(chain, dir, manifest.Format, next, end, stop)
+		if err != nil {
+			return manifest, err
+		}
+		manifest.Segments = append(manifest.Segments, *segment)
+		if err := manifest.save(dir); err != nil {
+			return manifest, err
+		}
+		log.Info("Exported segment", "file", segment.File, "first", segment.First, "last", segment.Last, "tip", segment.TipHash)
+		next = end + 1
+	}
+	return manifest, nil
+}
+
+// openManifest loads the manifest of the directory, or creates one for the config if there is
+// none, and drops the segments which are no longer canonical.
+func openManifest(chain *core.BlockChain, dir string, config ExportConfig) (*Manifest, error) {
+	genesis := chain.Genesis().Hash()
+
+	manifest, err := LoadManifest(dir)
+	switch {
+	case os.IsNotExist(err):
+		size := config.SegmentSize
+		if size == 0 {
+			size = DefaultSegmentSize
+		}
+		return &Manifest{Version: manifestVersion, Format: config.Format, Genesis: genesis, SegmentSize: size}, nil
+	case err != nil:
+		return nil, err
+	}
+	if manifest.Genesis != genesis {
+		return nil, fmt.Errorf("export directory holds another chain, genesis %x", manifest.Genesis)
+	}
+	if manifest.Format != config.Format {
+		return nil, fmt.Errorf("export directory holds format %s, not %s", manifest.Format, config.Format)
+	}
+	if config.SegmentSize != 0 && config.SegmentSize != manifest.SegmentSize {
+		return nil, fmt.Errorf("export directory has segment size %d, not %d", manifest.SegmentSize, config.SegmentSize)
+	}
+	for i, segment := range manifest.Segments {
+		if block := chain.GetBlockByNumber(segment.Last); block == nil || block.Hash() != segment.TipHash {
+			log.Warn("Exported segments reorged, exporting them again", "first", segment.First)
+			for _, dropped := range manifest.Segments[i:] {
+				os.Remove(filepath.Join(dir, dropped.File))
+			}
+			manifest.Segments = manifest.Segments[:i]
+			return manifest, manifest.save(dir)
+		}
+	}
+	return manifest, nil
+}
+
+// segmentFile returns the name of the segment file of the blocks [first, last].
+func segmentFile(format string, first, last uint64) string {
+	return fmt.Sprintf("blocks-%012d-%012d.%s.gz", first, last, format)
+}
+
+// writeSegment writes the canonical blocks [first, last] into a segment file. The file is written
+// under a temporary name and synced before it is renamed, a crash never leaves a partial segment.
+func writeSegment(chain *core.BlockChain, dir, format string, first, last uint64, stop <-chan struct{}) (*Segment, error) {
+	name := segmentFile(format, first, last)
+	path := filepath.Join(dir, name)
+
+	fh, err := os.OpenFile(path+".tmp", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return nil, err
+	}
+	tip, checksum, err := writeBlocks(fh, chain, format, first, last, stop)
+	if err == nil {
+		err = fh.Sync()
+	}
+	if cerr := fh.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(path+".tmp", path)
+	}
+	if err != nil {
+		os.Remove(path + ".tmp")
+		return nil, err
+	}
+	return &Segment{File: name, First: first, Last: last, TipHash: tip, Checksum: checksum}, nil
+}
+
+// writeBlocks writes the compressed blocks to w and returns the hash of the last one and the
+// checksum of the compressed stream.
+func writeBlocks(w io.Writer, chain *core.BlockChain, format string, first, last uint64, stop <-chan struct{}) (common.Hash, string, error) {
+	var (
+		hasher = sha256.New()
+		buf    = bufio.NewWriter(io.MultiWriter(w, hasher))
+		zw     = gzip.NewWriter(buf)
+		enc    = json.NewEncoder(zw)
+		signer = types.MakeSigner(chain.Config())
+		parent common.Hash
+	)
+	for number := first; number <= last; number++ {
+		if number%1000 == 0 {
+			select {
+			case <-stop:
+				return common.Hash{}, "", errInterrupted
+			default:
+			}
+		}
+		block := chain.GetBlockByNumber(number)
+		if block == nil {
+			return common.Hash{}, "", fmt.Errorf("block %d not found", number)
+		}
+		// the blocks are read without a lock, a reorg while exporting is caught by the links
+		if number > first && block.ParentHash() != parent {
+			return common.Hash{}, "", fmt.Errorf("chain reorganised at block %d during the export", number)
+		}
+		parent = block.Hash()
+
+		var err error
+		if format == FormatJSONL {
+			err = enc.Encode(newJSONBlock(block, chain.GetReceiptsByHash(block.Hash()), signer))
+		} else {
+			err = block.EncodeRLP(zw)
+		}
+		if err != nil {
+			return common.Hash{}, "", err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return common.Hash{}, "", err
+	}
+	if err := buf.Flush(); err != nil {
+		return common.Hash{}, "", err
+	}
+	return parent, hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// jsonBlock is a line of a JSON-lines export.
+type jsonBlock struct {
+	Number       uint64             `json:"number"`
+	Hash         common.Hash        `json:"hash"`
+	ParentHash   common.Hash        `json:"parentHash"`
+	Miner        common.Address     `json:"miner"`
+	StateRoot    common.Hash        `json:"stateRoot"`
+	TxsRoot      common.Hash        `json:"transactionsRoot"`
+	ReceiptsRoot common.Hash        `json:"receiptsRoot"`
+	GasLimit     uint64             `json:"gasLimit"`
+	GasUsed      uint64             `json:"gasUsed"`
+	Timestamp    *big.Int           `json:"timestamp"`
+	Extra        hexutil.Bytes      `json:"extraData"`
+	Dpos         jsonDposSnap       `json:"dpos"`
+	Transactions []*jsonTransaction `json:"transactions"`
+}
+
+// jsonDposSnap holds the DPoS snapshot fields of a block header.
+type jsonDposSnap struct {
+	Proposers  []common.Address      `json:"proposers"`
+	Validators []common.Address      `json:"validators"`
+	Sigs       []types.DposSignature `json:"sigs"`
+	Seal       types.DposSignature   `json:"seal"`
+}
+
+// jsonTransaction is a transaction with its receipt in a JSON-lines export.
+type jsonTransaction struct {
+	Hash     common.Hash     `json:"hash"`
+	Index    int             `json:"index"`
+	From     common.Address  `json:"from"`
+	To       *common.Address `json:"to"`
+	Nonce    uint64          `json:"nonce"`
+	Value    *big.Int        `json:"value"`
+	Gas      uint64          `json:"gas"`
+	GasPrice *big.Int        `json:"gasPrice"`
+	Input    hexutil.Bytes   `json:"input"`
+	Private  bool            `json:"private"`
+	Receipt  *types.Receipt  `json:"receipt"`
+}
+
+func newJSONBlock(block *types.Block, receipts types.Receipts, signer types.Signer) *jsonBlock {
+	header := block.Header()
+	b := &jsonBlock{
+		Number:       block.NumberU64(),
+		Hash:         block.Hash(),
+		ParentHash:   header.ParentHash,
+		Miner:        header.Coinbase,
+		StateRoot:    header.StateRoot,
+		TxsRoot:      header.TxsRoot,
+		ReceiptsRoot: header.ReceiptsRoot,
+		GasLimit:     header.GasLimit,
+		GasUsed:      header.GasUsed,
+		Timestamp:    header.Time,
+		Extra:        header.Extra,
+		Dpos: jsonDposSnap{
+			Proposers:  header.Dpos.Proposers,
+			Validators: header.Dpos.Validators,
+			Sigs:       header.Dpos.Sigs,
+			Seal:       header.Dpos.Seal,
+		},
+		Transactions: make([]*jsonTransaction, len(block.Transactions())),
+	}
+	for i, tx := range block.Transactions() {
+		from, _ := types.Sender(signer, tx)
+		b.Transactions[i] = &jsonTransaction{
+			Hash:     tx.Hash(),
+			Index:    i,
+			From:     from,
+			To:       tx.To(),
+			Nonce:    tx.Nonce(),
+			Value:    tx.Value(),
+			Gas:      tx.Gas(),
+			GasPrice: tx.GasPrice(),
+			Input:    tx.Data(),
+			Private:  tx.IsPrivate(),
+		}
+		if i < len(receipts) {
+			b.Transactions[i].Receipt = receipts[i]
+		}
+	}
+	return b
+}
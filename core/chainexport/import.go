@@ -0,0 +1,213 @@
+// Copyright 2018 The gcchain authors
+
+package chainexport
+
+import (
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/gcchains/chain/commons/log"
+	"github.com/gcchains/chain/core"
+	"github.com/gcchains/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// importBatchSize is the number of blocks inserted at once.
+const importBatchSize = 2500
+
+// Import imports the RLP export in the directory into the chain and returns the number of blocks
+// inserted.
+//
+// All segments are verified before any block is inserted: their checksums, that their blocks link
+// up to the tip hashes of the manifest, and that they hold the checkpoints. The checkpoints must
+// be within the export or the local chain, a checkpoint at the last exported block authenticates
+// every exported block as they are linked by hash. Segments whose tip is in the chain already are
+// skipped, so an interrupted import resumes where it stopped.
+func Import(chain *core.BlockChain, dir string, checkpoints []Checkpoint, stop <-chan struct{}) (int, error) {
+	manifest, err := LoadManifest(dir)
+	if err != nil {
+		return 0, err
+	}
+	if manifest.Format != FormatRLP {
+		return 0, fmt.Errorf("cannot import format %s, only %s", manifest.Format, FormatRLP)
+	}
+	if genesis := chain.Genesis().Hash(); manifest.Genesis != genesis {
+		return 0, fmt.Errorf("export of another chain, genesis %x, want %x", manifest.Genesis, genesis)
+	}
+	pending, err := verifyCheckpoints(chain, manifest, checkpoints)
+	if err != nil {
+		return 0, err
+	}
+	var parent common.Hash
+	for i, segment := range manifest.Segments {
+		if i > 0 && segment.First != manifest.Segments[i-1].Last+1 {
+			return 0, fmt.Errorf("segment %s does not continue the previous one", segment.File)
+		}
+		blocks := 0
+		err := readSegment(dir, segment, stop, func(block *types.Block) error {
+			if blocks > 0 || i > 0 {
+				if block.ParentHash() != parent {
+					return fmt.Errorf("block %d does not link to its parent", block.NumberU64())
+				}
+			}
+			if hash, ok := pending[block.NumberU64()]; ok && block.Hash() != hash {
+				return fmt.Errorf("block %d is %x, checkpoint is %x", block.NumberU64(), block.Hash(), hash)
+			}
+			parent = block.Hash()
+			blocks++
+			return nil
+		})
+		if err != nil {
+			return 0, fmt.Errorf("segment %s: %v", segment.File, err)
+		}
+	}
+	log.Info("Verified exported segments", "segments", len(manifest.Segments), "checkpoints", len(checkpoints))
+
+	imported := 0
+	for _, segment := range manifest.Segments {
+		if block := chain.GetBlockByNumber(segment.Last); block != nil && block.Hash() == segment.TipHash {
+			log.Info("Skipping segment as all blocks present", "file", segment.File)
+			continue
+		}
+		n, err := importSegment(chain, dir, segment, stop)
+		imported += n
+		if err != nil {
+			return imported, fmt.Errorf("segment %s: %v", segment.File, err)
+		}
+		log.Info("Imported segment", "file", segment.File, "blocks", n)
+	}
+	return imported, nil
+}
+
+// verifyCheckpoints checks the checkpoints below the export against the local chain and returns
+// those within the export by number.
+func verifyCheckpoints(chain *core.BlockChain, manifest *Manifest, checkpoints []Checkpoint) (map[uint64]common.Hash, error) {
+	pending := make(map[uint64]common.Hash)
+	for _, checkpoint := range checkpoints {
+		last, ok := manifest.Last()
+		switch {
+		case ok && checkpoint.Number >= manifest.Segments[0].First && checkpoint.Number <= last:
+			pending[checkpoint.Number] = checkpoint.Hash
+		case ok && checkpoint.Number > last:
+			return nil, fmt.Errorf("checkpoint %d is beyond the exported blocks", checkpoint.Number)
+		default:
+			if block := chain.GetBlockByNumber(checkpoint.Number); block == nil || block.Hash() != checkpoint.Hash {
+				return nil, fmt.Errorf("checkpoint %d is neither exported nor in the local chain", checkpoint.Number)
+			}
+		}
+	}
+	return pending, nil
+}
+
+// importSegment inserts the blocks of the segment missing from the chain.
+func importSegment(chain *core.BlockChain, dir string, segment Segment, stop <-chan struct{}) (int, error) {
+	var (
+		batch    = make(types.Blocks, 0, importBatchSize)
+		imported int
+	)
+	insert := func() error {
+		missing := missingBlocks(chain, batch)
+		batch = batch[:0]
+		if len(missing) == 0 {
+			return nil
+		}
+		if _, err := chain.InsertChain(missing); err != nil {
+			return err
+		}
+		imported += len(missing)
+		return nil
+	}
+	err := readSegment(dir, segment, stop, func(block *types.Block) error {
+		// the genesis block is never imported
+		if block.NumberU64() == 0 {
+			return nil
+		}
+		if batch = append(batch, block); len(batch) == importBatchSize {
+			return insert()
+		}
+		return nil
+	})
+	if err == nil {
+		err = insert()
+	}
+	return imported, err
+}
+
+// missingBlocks returns the blocks from the first one missing from the chain on.
+func missingBlocks(chain *core.BlockChain, blocks []*types.Block) []*types.Block {
+	head := chain.CurrentBlock()
+	for i, block := range blocks {
+		// If we're behind the chain head, only check block, state is available at head
+		if head.NumberU64() > block.NumberU64() {
+			if !chain.HasBlock(block.Hash(), block.NumberU64()) {
+				return blocks[i:]
+			}
+			continue
+		}
+		// If we're above the chain head, state availability is a must
+		if !chain.HasBlockAndState(block.Hash(), block.NumberU64()) {
+			return blocks[i:]
+		}
+	}
+	return nil
+}
+
+// readSegment decodes the blocks of the segment and checks their numbers, its tip hash and
+// its checksum, which is only known once the file is read to its end.
+func readSegment(dir string, segment Segment, stop <-chan struct{}, fn func(*types.Block) error) error {
+	fh, err := os.Open(filepath.Join(dir, segment.File))
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	hasher := sha256.New()
+	tee := io.TeeReader(fh, hasher)
+	zr, err := gzip.NewReader(tee)
+	if err != nil {
+		return err
+	}
+	stream := rlp.NewStream(zr, 0)
+
+	var tip common.Hash
+	for number := segment.First; number <= segment.Last; number++ {
+		if number%1000 == 0 {
+			select {
+			case <-stop:
+				return errInterrupted
+			default:
+			}
+		}
+		block := new(types.Block)
+		if err := stream.Decode(block); err != nil {
+			return fmt.Errorf("block %d: %v", number, err)
+		}
+		if block.NumberU64() != number {
+			return fmt.Errorf("block %d found at %d", block.NumberU64(), number)
+		}
+		if err := fn(block); err != nil {
+			return err
+		}
+		tip = block.Hash()
+	}
+	if _, err := stream.Raw(); err != io.EOF {
+		return fmt.Errorf("data after block %d", segment.Last)
+	}
+	if _, err := io.Copy(ioutil.Discard, tee); err != nil {
+		return err
+	}
+	if tip != segment.TipHash {
+		return fmt.Errorf("tip is %x, manifest has %x", tip, segment.TipHash)
+	}
+	if checksum := hex.EncodeToString(hasher.Sum(nil)); checksum != segment.Checksum {
+		return fmt.Errorf("checksum is %s, manifest has %s", checksum, segment.Checksum)
+	}
+	return nil
+}
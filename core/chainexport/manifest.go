@@ -0,0 +1,159 @@
+// Copyright 2018 The gcchain authors
+
+// Package chainexport exports ranges of the canonical chain into compressed segment files listed
+// in a manifest, and imports them back verified against trusted checkpoints.
+package chainexport
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// FormatRLP exports blocks as RLP, which can be imported back.
+	FormatRLP = "rlp"
+
+	// FormatJSONL exports one JSON object per line holding a block with its transactions, receipts
+	// and DPoS snapshot, for analytics. It cannot be imported.
+	FormatJSONL = "jsonl"
+
+	// DefaultSegmentSize is the number of blocks in a segment if none is configured.
+	DefaultSegmentSize = 10000
+
+	// ManifestFile is the name of the manifest in the export directory.
+	ManifestFile = "manifest.json"
+
+	manifestVersion = 1
+)
+
+// Formats lists the supported export formats.
+var Formats = []string{FormatRLP, FormatJSONL}
+
+// Manifest lists the segments of an export in block order, they cover a contiguous range.
+type Manifest struct {
+	Version     int         `json:"version"`
+	Format      string      `json:"format"`
+	Genesis     common.Hash `json:"genesis"`
+	SegmentSize uint64      `json:"segmentSize"`
+	Segments    []Segment   `json:"segments"`
+}
+
+// Segment is a gzip compressed file holding the blocks [First, Last].
+type Segment struct {
+	File     string      `json:"file"`
+	First    uint64      `json:"first"`
+	Last     uint64      `json:"last"`
+	TipHash  common.Hash `json:"tipHash"` // hash of the block Last
+	Checksum string      `json:"sha256"`  // hex encoded SHA256 of the file
+}
+
+// Checkpoint is a trusted hash of a canonical block.
+type Checkpoint struct {
+	Number uint64
+	Hash   common.Hash
+}
+
+// LoadManifest reads the manifest of the export directory.
+func LoadManifest(dir string) (*Manifest, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return nil, err
+	}
+	manifest := new(Manifest)
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %v", err)
+	}
+	if manifest.Version != manifestVersion {
+		return nil, fmt.Errorf("unsupported manifest version %d", manifest.Version)
+	}
+	return manifest, nil
+}
+
+// save writes the manifest to the export directory, replacing the previous one atomically.
+func (m *Manifest) save(dir string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(dir, ManifestFile+".tmp")
+	if err := writeFileSync(tmp, data); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(dir, ManifestFile))
+}
+
+// Last returns the last exported block number and false if there are no segments.
+func (m *Manifest) Last() (uint64, bool) {
+	if len(m.Segments) == 0 {
+		return 0, false
+	}
+	return m.Segments[len(m.Segments)-1].Last, true
+}
+
+// Checkpoints returns the tip hashes of the segments as checkpoints, for verifying an import
+// against a manifest obtained from a trusted source.
+func (m *Manifest) Checkpoints() []Checkpoint {
+	checkpoints := make([]Checkpoint, len(m.Segments))
+	for i, segment := range m.Segments {
+		checkpoints[i] = Checkpoint{Number: segment.Last, Hash: segment.TipHash}
+	}
+	return checkpoints
+}
+
+// ParseCheckpoint parses a "<number>:<hash>" checkpoint, or the path of a trusted manifest whose
+// segment tips are all checkpoints.
+func ParseCheckpoint(s string) ([]Checkpoint, error) {
+	if parts := strings.SplitN(s, ":", 2); len(parts) == 2 {
+		number, err := strconv.ParseUint(parts[0], 10, 64)
+		if err == nil {
+			hash := parts[1]
+			if !strings.HasPrefix(hash, "0x") || len(hash) != 2+2*common.HashLength {
+				return nil, fmt.Errorf("invalid checkpoint hash %q", hash)
+			}
+			return []Checkpoint{{Number: number, Hash: common.HexToHash(hash)}}, nil
+		}
+	}
+	dir := s
+	if filepath.Base(s) == ManifestFile {
+		dir = filepath.Dir(s)
+	}
+	manifest, err := LoadManifest(dir)
+	if err != nil {
+		return nil, fmt.Errorf("checkpoint %q is neither <number>:<hash> nor a manifest: %v", s, err)
+	}
+	return manifest.Checkpoints(), nil
+}
+
+// validateFormat returns an error if the format is not supported.
+func validateFormat(format string) error {
+	for _, f := range Formats {
+		if f == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown export format %q, supported are %v", format, Formats)
+}
+
+// writeFileSync writes the file and flushes it to disk.
+func writeFileSync(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
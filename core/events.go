@@ -13,6 +13,9 @@ type NewTxsEvent struct {
 	ForceBroadcast bool
 }
 
+// DroppedTxsEvent is posted when a batch of transactions are dropped from the transaction pool.
+type DroppedTxsEvent struct{ Txs []*DroppedTx }
+
 // PendingLogsEvent is posted pre mining and notifies of pending logs.
 type PendingLogsEvent struct {
 	Logs []*types.Log
@@ -129,6 +129,11 @@ func SetupGenesisBlock(db database.Database, genesis *Genesis) (*configs.ChainCo
 	if genesis != nil && genesis.Config == nil {
 		return configs.ChainConfigInfo(), common.Hash{}, errGenesisNoConfig
 	}
+	if genesis != nil {
+		if err := genesis.Config.Forks.Validate(); err != nil {
+			return genesis.Config, common.Hash{}, err
+		}
+	}
 
 	stored := rawdb.ReadCanonicalHash(db, 0)
 	if (stored == common.Hash{}) {
@@ -145,14 +150,17 @@ func SetupGenesisBlock(db database.Database, genesis *Genesis) (*configs.ChainCo
 		// Get the existing chain configuration.
 		storedCfg := rawdb.ReadChainConfig(db, stored)
 		newCfg := genesis.configOrDefault(stored)
-		var finalCfg *configs.ChainConfig
+		var (
+			finalCfg *configs.ChainConfig
+			err      error
+		)
 		if genesis != nil {
 			// Check whether the genesis block is already written.
 			hash := genesis.ToBlock(nil).Hash()
 			if hash != stored {
 				return genesis.Config, hash, &GenesisMismatchError{stored, hash}
 			}
-			finalCfg = updateChainConfig(storedCfg, newCfg, db, stored)
+			finalCfg, err = updateChainConfig(storedCfg, newCfg, db, stored)
 		} else {
 			// Special case: don't change the existing config of a non-mainnet chain if no new
 			// config is supplied. These chains would get AllProtocolChanges (and a compat error)
@@ -160,20 +168,33 @@ func SetupGenesisBlock(db database.Database, genesis *Genesis) (*configs.ChainCo
 			if stored != MainnetGenesisHash {
 				return storedCfg, stored, nil
 			} else {
-				finalCfg = updateChainConfig(storedCfg, newCfg, db, stored)
+				finalCfg, err = updateChainConfig(storedCfg, newCfg, db, stored)
 			}
 		}
-		return finalCfg, stored, nil
+		return finalCfg, stored, err
 	}
 }
 
-func updateChainConfig(storedcfg *configs.ChainConfig, newcfg *configs.ChainConfig, db database.Database, stored common.Hash) *configs.ChainConfig {
+// updateChainConfig writes the new configuration if it is compatible with the stored one,
+// a ConfigCompatError is returned with the stored configuration if the head block is past a changed fork.
+func updateChainConfig(storedcfg *configs.ChainConfig, newcfg *configs.ChainConfig, db database.Database, stored common.Hash) (*configs.ChainConfig, error) {
 	if storedcfg == nil {
 		log.Warn("Found genesis block without chain config")
+		rawdb.WriteChainConfig(db, stored, newcfg)
+		return newcfg, nil
+	}
+
+	// a chain that only has the genesis block can take any fork schedule
+	var height uint64
+	if number := rawdb.ReadHeaderNumber(db, rawdb.ReadHeadHeaderHash(db)); number != nil {
+		height = *number
+	}
+	if compatErr := storedcfg.CheckCompatible(newcfg, height); compatErr != nil && height != 0 {
+		return storedcfg, compatErr
 	}
-	// NOTICE: in future we may need to check the compatibility of old and new configurations.
+
 	rawdb.WriteChainConfig(db, stored, newcfg)
-	return newcfg
+	return newcfg, nil
 }
 
 // OpenGenesisBlock opens genesis block and returns its chain configuration and hash.
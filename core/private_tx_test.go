@@ -0,0 +1,179 @@
+package core
+
+import (
+	"crypto/ecdsa"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/gcchains/chain/accounts"
+	"github.com/gcchains/chain/accounts/keystore"
+	"github.com/gcchains/chain/configs"
+	"github.com/gcchains/chain/core/state"
+	"github.com/gcchains/chain/core/vm"
+	"github.com/gcchains/chain/database"
+	"github.com/gcchains/chain/private"
+	"github.com/gcchains/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// privateStorageCode deploys a contract which stores the first word of call data in slot 0
+var privateStorageCode = common.Hex2Bytes("6007600c60003960076000f3" + "60003560005500")
+
+// newTestAccountManager creates an account manager with an unlocked keystore account for key
+func newTestAccountManager(t *testing.T, key *ecdsa.PrivateKey) (*accounts.Manager, func()) {
+	dir, err := ioutil.TempDir("", "private-tx-keystore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	account, err := ks.ImportECDSA(key, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.Unlock(account, ""); err != nil {
+		t.Fatal(err)
+	}
+	return accounts.NewManager(ks), func() { os.RemoveAll(dir) }
+}
+
+// newSealedPrivateTx seals data for participants and signs a private tx carrying the sealed payload reference
+func newSealedPrivateTx(t *testing.T, nonce uint64, to *common.Address, data []byte, participants []*ecdsa.PrivateKey,
+	remoteDB database.RemoteDatabase, signer types.Signer, key *ecdsa.PrivateKey) *types.Transaction {
+	var pubKeys []string
+	for _, p := range participants {
+		pubKeys = append(pubKeys, hexutil.Encode(crypto.FromECDSAPub(&p.PublicKey)))
+	}
+	replacement, err := private.SealPrivatePayload(data, nonce, pubKeys, remoteDB)
+	if err != nil {
+		t.Fatalf("failed to seal private payload: %v", err)
+	}
+	payload, _ := rlp.EncodeToBytes(replacement)
+
+	var tx *types.Transaction
+	if to == nil {
+		tx = types.NewContractCreation(nonce, new(big.Int), 1000000, new(big.Int), payload)
+	} else {
+		tx = types.NewTransaction(nonce, *to, new(big.Int), 1000000, new(big.Int), payload)
+	}
+	tx.SetType(types.PrivateTx)
+	signed, err := types.SignTx(tx, signer, key)
+	if err != nil {
+		t.Fatalf("failed to sign tx: %v", err)
+	}
+	return signed
+}
+
+// Tests that a private contract is created and called in participants' private states only,
+// while all nodes agree on the public chain.
+func TestPrivateContractCall(t *testing.T) {
+	var (
+		senderKey, _ = crypto.GenerateKey()
+		aliceKey, _  = crypto.GenerateKey()
+		bobKey, _    = crypto.GenerateKey()
+		eveKey, _    = crypto.GenerateKey()
+		sender       = crypto.PubkeyToAddress(senderKey.PublicKey)
+
+		remoteDB = database.NewIpfsDbWithAdapter(database.NewFakeIpfsAdapter())
+		config   = *configs.TestChainConfig
+		gspec    = &Genesis{Config: &config, Alloc: GenesisAlloc{sender: {Balance: big.NewInt(10000000000000)}}}
+		signer   = types.NewCep1Signer(config.ChainID)
+		contract = crypto.CreateAddress(sender, 0)
+	)
+	config.Forks = configs.ForkSchedule{configs.PrivateTx: 0}
+
+	genDb := database.NewMemDatabase()
+	genesis := gspec.MustCommit(genDb)
+
+	participants := []*ecdsa.PrivateKey{aliceKey, bobKey}
+	var callTx *types.Transaction
+	blocks, _ := GenerateChain(&config, genesis, fakeDpos(genDb), genDb, remoteDB, 2, func(i int, gen *BlockGen) {
+		switch i {
+		case 0:
+			gen.AddTx(newSealedPrivateTx(t, gen.TxNonce(sender), nil, privateStorageCode, participants, remoteDB, signer, senderKey))
+		case 1:
+			callTx = newSealedPrivateTx(t, gen.TxNonce(sender), &contract, common.BigToHash(big.NewInt(42)).Bytes(), participants, remoteDB, signer, senderKey)
+			gen.AddTx(callTx)
+		}
+	})
+
+	tests := []struct {
+		name        string
+		key         *ecdsa.PrivateKey
+		participant bool
+	}{
+		{"alice", aliceKey, true},
+		{"bob", bobKey, true},
+		{"eve", eveKey, false},
+	}
+	for _, tt := range tests {
+		accm, cleanup := newTestAccountManager(t, tt.key)
+		defer cleanup()
+
+		db := database.NewMemDatabase()
+		gspec.MustCommit(db)
+		chain, _ := NewBlockChain(db, nil, &config, fakeDpos(db), vm.Config{}, remoteDB, accm)
+		defer chain.Stop()
+
+		if _, err := chain.InsertChain(blocks); err != nil {
+			t.Fatalf("%s: failed to insert chain: %v", tt.name, err)
+		}
+		if head := chain.CurrentBlock().Hash(); head != blocks[1].Hash() {
+			t.Fatalf("%s: head = %x, want %x", tt.name, head, blocks[1].Hash())
+		}
+
+		privState, err := chain.StatePrivAt(blocks[1].StateRoot())
+		if err != nil {
+			t.Fatalf("%s: failed to open private state: %v", tt.name, err)
+		}
+		receipt, _ := ReadPrivateReceipt(callTx.Hash(), db)
+
+		if tt.participant {
+			if got, want := privState.GetState(contract, common.Hash{}), common.BigToHash(big.NewInt(42)); got != want {
+				t.Errorf("%s: private storage = %x, want %x", tt.name, got, want)
+			}
+			if receipt == nil || receipt.Status != types.ReceiptStatusSuccessful {
+				t.Errorf("%s: private receipt = %+v, want a successful one", tt.name, receipt)
+			}
+		} else {
+			if code := privState.GetCode(contract); len(code) != 0 {
+				t.Errorf("%s: private contract code = %x, want empty", tt.name, code)
+			}
+			if receipt != nil {
+				t.Errorf("%s: private receipt = %+v, want nil", tt.name, receipt)
+			}
+		}
+	}
+}
+
+// Tests that private txs are rejected before the PrivateTx fork.
+func TestPrivateTxBeforeFork(t *testing.T) {
+	var (
+		key, _   = crypto.GenerateKey()
+		addr     = crypto.PubkeyToAddress(key.PublicKey)
+		db       = database.NewMemDatabase()
+		remoteDB = database.NewIpfsDbWithAdapter(database.NewFakeIpfsAdapter())
+		config   = *configs.TestChainConfig
+		gspec    = &Genesis{Config: &config, Alloc: GenesisAlloc{addr: {Balance: big.NewInt(10000000000000)}}}
+		signer   = types.NewCep1Signer(config.ChainID)
+	)
+	config.Forks = configs.ForkSchedule{configs.PrivateTx: 2}
+	genesis := gspec.MustCommit(db)
+
+	tx := newSealedPrivateTx(t, 0, nil, privateStorageCode, []*ecdsa.PrivateKey{key}, remoteDB, signer, key)
+
+	statedb, _ := state.New(genesis.StateRoot(), state.NewDatabase(db))
+	for _, num := range []int64{1, 2} {
+		header := &types.Header{ParentHash: genesis.Hash(), Number: big.NewInt(num), GasLimit: genesis.GasLimit(), Time: new(big.Int)}
+		_, _, _, err := ApplyTransaction(&config, nil, &common.Address{}, new(GasPool).AddGas(header.GasLimit), statedb.Copy(),
+			statedb.Copy(), remoteDB, header, tx, new(uint64), vm.Config{}, nil)
+
+		if want := num < 2; (err == types.ErrNotSupportedTxType) != want {
+			t.Errorf("ApplyTransaction() at block %d error = %v, want rejected %v", num, err, want)
+		}
+	}
+}
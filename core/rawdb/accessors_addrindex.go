@@ -0,0 +1,112 @@
+// Copyright 2018 The gcchain authors
+
+package rawdb
+
+import (
+	"encoding/binary"
+
+	"github.com/gcchains/chain/commons/log"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// AddrIndexEntry is the position of a transaction sent from, sent to or creating an
+// address. The entries of an address are numbered in the order of the blocks.
+type AddrIndexEntry struct {
+	BlockNumber uint64
+	Index       uint64
+}
+
+// AddrIndexBlock records the addresses indexed in a block, allowing to roll back the
+// entries of the block on reorgs.
+type AddrIndexBlock struct {
+	Hash      common.Hash
+	Addresses []common.Address
+}
+
+// ReadAddrIndexCount retrieves the number of index entries of an address.
+func ReadAddrIndexCount(db DatabaseReader, address common.Address) uint64 {
+	data, _ := db.Get(addrIndexCountKey(address))
+	if len(data) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(data)
+}
+
+// WriteAddrIndexCount stores the number of index entries of an address.
+func WriteAddrIndexCount(db DatabaseWriter, address common.Address, count uint64) {
+	if err := db.Put(addrIndexCountKey(address), encodeBlockNumber(count)); err != nil {
+		log.Fatal("Failed to store address index count", "err", err)
+	}
+}
+
+// DeleteAddrIndexCount removes the number of index entries of an address.
+func DeleteAddrIndexCount(db DatabaseDeleter, address common.Address) {
+	if err := db.Delete(addrIndexCountKey(address)); err != nil {
+		log.Fatal("Failed to delete address index count", "err", err)
+	}
+}
+
+// ReadAddrIndexEntry retrieves the seq-th index entry of an address.
+func ReadAddrIndexEntry(db DatabaseReader, address common.Address, seq uint64) (AddrIndexEntry, bool) {
+	var entry AddrIndexEntry
+	data, _ := db.Get(addrIndexEntryKey(address, seq))
+	if len(data) == 0 {
+		return entry, false
+	}
+	if err := rlp.DecodeBytes(data, &entry); err != nil {
+		log.Error("Invalid address index entry RLP", "address", address, "seq", seq, "err", err)
+		return entry, false
+	}
+	return entry, true
+}
+
+// WriteAddrIndexEntry stores the seq-th index entry of an address.
+func WriteAddrIndexEntry(db DatabaseWriter, address common.Address, seq uint64, entry AddrIndexEntry) {
+	data, err := rlp.EncodeToBytes(entry)
+	if err != nil {
+		log.Fatal("Failed to encode address index entry", "err", err)
+	}
+	if err := db.Put(addrIndexEntryKey(address, seq), data); err != nil {
+		log.Fatal("Failed to store address index entry", "err", err)
+	}
+}
+
+// DeleteAddrIndexEntry removes the seq-th index entry of an address.
+func DeleteAddrIndexEntry(db DatabaseDeleter, address common.Address, seq uint64) {
+	if err := db.Delete(addrIndexEntryKey(address, seq)); err != nil {
+		log.Fatal("Failed to delete address index entry", "err", err)
+	}
+}
+
+// ReadAddrIndexBlock retrieves the addresses indexed in the block with the given number.
+func ReadAddrIndexBlock(db DatabaseReader, number uint64) *AddrIndexBlock {
+	data, _ := db.Get(addrIndexBlockKey(number))
+	if len(data) == 0 {
+		return nil
+	}
+	block := new(AddrIndexBlock)
+	if err := rlp.DecodeBytes(data, block); err != nil {
+		log.Error("Invalid address index block RLP", "number", number, "err", err)
+		return nil
+	}
+	return block
+}
+
+// WriteAddrIndexBlock stores the addresses indexed in the block with the given number.
+func WriteAddrIndexBlock(db DatabaseWriter, number uint64, block *AddrIndexBlock) {
+	data, err := rlp.EncodeToBytes(block)
+	if err != nil {
+		log.Fatal("Failed to encode address index block", "err", err)
+	}
+	if err := db.Put(addrIndexBlockKey(number), data); err != nil {
+		log.Fatal("Failed to store address index block", "err", err)
+	}
+}
+
+// DeleteAddrIndexBlock removes the addresses indexed in the block with the given number.
+func DeleteAddrIndexBlock(db DatabaseDeleter, number uint64) {
+	if err := db.Delete(addrIndexBlockKey(number)); err != nil {
+		log.Fatal("Failed to delete address index block", "err", err)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"math/big"
 
 	"github.com/gcchains/chain/commons/log"
+	"github.com/gcchains/chain/database"
 	"github.com/gcchains/chain/types"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -16,12 +17,63 @@ import (
 // ReadCanonicalHash retrieves the hash assigned to a canonical block number.
 func ReadCanonicalHash(db DatabaseReader, number uint64) common.Hash {
 	data, _ := db.Get(headerHashKey(number))
+	if len(data) == 0 {
+		data = readAncient(db, freezerHashTable, number)
+	}
 	if len(data) == 0 {
 		return common.Hash{}
 	}
 	return common.BytesToHash(data)
 }
 
+// readAncient retrieves an item of the canonical block from the freezer, or nil if the database
+// has no freezer or the block is not frozen.
+func readAncient(db DatabaseReader, kind string, number uint64) []byte {
+	ancients, ok := db.(AncientReader)
+	if !ok {
+		return nil
+	}
+	data, _ := ancients.Ancient(kind, number)
+	return data
+}
+
+// readAncientBlock retrieves an item of the block from the freezer if it is the frozen canonical
+// block with the number, frozen items of other blocks are never returned.
+func readAncientBlock(db DatabaseReader, kind string, hash common.Hash, number uint64) []byte {
+	if frozen := readAncient(db, freezerHashTable, number); len(frozen) == 0 || common.BytesToHash(frozen) != hash {
+		return nil
+	}
+	return readAncient(db, kind, number)
+}
+
+// hasAncientBlock verifies the existence of an item of the block in the freezer.
+func hasAncientBlock(db DatabaseReader, kind string, hash common.Hash, number uint64) bool {
+	ancients, ok := db.(AncientReader)
+	if !ok {
+		return false
+	}
+	if has, err := ancients.HasAncient(kind, number); !has || err != nil {
+		return false
+	}
+	return common.BytesToHash(readAncient(db, freezerHashTable, number)) == hash
+}
+
+// ReadAllHashes retrieves the hashes of all blocks with the number in the key-value store,
+// canonical and side blocks.
+func ReadAllHashes(db database.Iteratee, number uint64) []common.Hash {
+	prefix := headerKeyPrefix(number)
+
+	var hashes []common.Hash
+	it := db.NewIterator(prefix, nil)
+	defer it.Release()
+	for it.Next() {
+		if key := it.Key(); len(key) == len(prefix)+common.HashLength {
+			hashes = append(hashes, common.BytesToHash(key[len(prefix):]))
+		}
+	}
+	return hashes
+}
+
 // WriteCanonicalHash stores the hash assigned to a canonical block number.
 func WriteCanonicalHash(db DatabaseWriter, hash common.Hash, number uint64) {
 	if err := db.Put(headerHashKey(number), hash.Bytes()); err != nil {
@@ -115,13 +167,16 @@ func WriteFastTrieProgress(db DatabaseWriter, count uint64) {
 // ReadHeaderRLP retrieves a block header in its raw RLP database encoding.
 func ReadHeaderRLP(db DatabaseReader, hash common.Hash, number uint64) rlp.RawValue {
 	data, _ := db.Get(headerKey(number, hash))
+	if len(data) == 0 {
+		data = readAncientBlock(db, freezerHeaderTable, hash, number)
+	}
 	return data
 }
 
 // HasHeader verifies the existence of a block header corresponding to the hash.
 func HasHeader(db DatabaseReader, hash common.Hash, number uint64) bool {
 	if has, err := db.Has(headerKey(number, hash)); !has || err != nil {
-		return false
+		return hasAncientBlock(db, freezerHeaderTable, hash, number)
 	}
 	return true
 }
@@ -177,6 +232,9 @@ func DeleteHeader(db DatabaseDeleter, hash common.Hash, number uint64) {
 // ReadBodyRLP retrieves the block body (transactions and uncles) in RLP encoding.
 func ReadBodyRLP(db DatabaseReader, hash common.Hash, number uint64) rlp.RawValue {
 	data, _ := db.Get(blockBodyKey(number, hash))
+	if len(data) == 0 {
+		data = readAncientBlock(db, freezerBodiesTable, hash, number)
+	}
 	return data
 }
 
@@ -190,7 +248,7 @@ func WriteBodyRLP(db DatabaseWriter, hash common.Hash, number uint64, rlp rlp.Ra
 // HasBody verifies the existence of a block body corresponding to the hash.
 func HasBody(db DatabaseReader, hash common.Hash, number uint64) bool {
 	if has, err := db.Has(blockBodyKey(number, hash)); !has || err != nil {
-		return false
+		return hasAncientBlock(db, freezerBodiesTable, hash, number)
 	}
 	return true
 }
@@ -225,10 +283,19 @@ func DeleteBody(db DatabaseDeleter, hash common.Hash, number uint64) {
 	}
 }
 
+// ReadReceiptsRLP retrieves all the transaction receipts belonging to a block in RLP encoding.
+func ReadReceiptsRLP(db DatabaseReader, hash common.Hash, number uint64) rlp.RawValue {
+	data, _ := db.Get(blockReceiptsKey(number, hash))
+	if len(data) == 0 {
+		data = readAncientBlock(db, freezerReceiptTable, hash, number)
+	}
+	return data
+}
+
 // ReadReceipts retrieves all the transaction receipts belonging to a block.
 func ReadReceipts(db DatabaseReader, hash common.Hash, number uint64) types.Receipts {
 	// Retrieve the flattened receipt slice
-	data, _ := db.Get(blockReceiptsKey(number, hash))
+	data := ReadReceiptsRLP(db, hash, number)
 	if len(data) == 0 {
 		return nil
 	}
@@ -300,6 +367,16 @@ func DeleteBlock(db DatabaseDeleter, hash common.Hash, number uint64) {
 	DeleteBody(db, hash, number)
 }
 
+// DeleteBlockWithoutNumber removes all block data associated with a hash, except
+// the hash to number mapping.
+func DeleteBlockWithoutNumber(db DatabaseDeleter, hash common.Hash, number uint64) {
+	DeleteReceipts(db, hash, number)
+	if err := db.Delete(headerKey(number, hash)); err != nil {
+		log.Fatal("Failed to delete header", "err", err)
+	}
+	DeleteBody(db, hash, number)
+}
+
 // FindCommonAncestor returns the last common ancestor of two block headers
 func FindCommonAncestor(db DatabaseReader, a, b *types.Header) *types.Header {
 	for bn := b.Number.Uint64(); a.Number.Uint64() > bn; {
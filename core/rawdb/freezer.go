@@ -0,0 +1,370 @@
+// Copyright 2018 The gcchain authors
+
+package rawdb
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gcchains/chain/commons/log"
+	"github.com/gcchains/chain/database"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// The tables of the freezer, item n of each holds the data of canonical block n.
+const (
+	freezerHashTable     = "hashes"
+	freezerHeaderTable   = "headers"
+	freezerBodiesTable   = "bodies"
+	freezerReceiptTable  = "receipts"
+	freezerRecheckPeriod = time.Minute
+
+	// freezerBatchLimit is the maximum number of blocks moved in one pass of the freezer.
+	freezerBatchLimit = 30000
+)
+
+var freezerTables = []string{freezerHashTable, freezerHeaderTable, freezerBodiesTable, freezerReceiptTable}
+
+var (
+	// errUnknownTable is returned if the kind of an ancient item is not a freezer table.
+	errUnknownTable = errors.New("unknown table")
+)
+
+// freezer is an append-only store of the canonical blocks older than a threshold, which are
+// moved out of the key-value store as they are never written again.
+//
+// Blocks are appended to all tables and synced to disk before they are deleted from the key-value
+// store. A crash before the sync leaves the blocks in the key-value store and the repair on startup
+// truncates the tables to the blocks they all hold, a crash after it leaves blocks in both stores,
+// which read the same.
+type freezer struct {
+	frozen    uint64 // number of blocks in all tables, accessed atomically
+	threshold uint64 // number of recent blocks kept in the key-value store, 0 never moves blocks
+
+	tables map[string]*freezerTable
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newFreezer opens or creates the tables in the directory and repairs them.
+func newFreezer(dir string, threshold uint64) (*freezer, error) {
+	f := &freezer{
+		threshold: threshold,
+		tables:    make(map[string]*freezerTable),
+		quit:      make(chan struct{}),
+	}
+	for _, name := range freezerTables {
+		table, err := newFreezerTable(dir, name)
+		if err != nil {
+			for _, table := range f.tables {
+				table.Close()
+			}
+			return nil, err
+		}
+		f.tables[name] = table
+	}
+	if err := f.repair(); err != nil {
+		f.close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// repair truncates the tables to the number of blocks all of them hold.
+func (f *freezer) repair() error {
+	min := uint64(1<<64 - 1)
+	for _, table := range f.tables {
+		if items := table.Items(); items < min {
+			min = items
+		}
+	}
+	for _, table := range f.tables {
+		if err := table.truncate(min); err != nil {
+			return err
+		}
+	}
+	atomic.StoreUint64(&f.frozen, min)
+	return nil
+}
+
+// HasAncient returns whether the item of the block is in the freezer.
+func (f *freezer) HasAncient(kind string, number uint64) (bool, error) {
+	if _, ok := f.tables[kind]; !ok {
+		return false, errUnknownTable
+	}
+	return number < atomic.LoadUint64(&f.frozen), nil
+}
+
+// Ancient returns the item of the block from the freezer.
+func (f *freezer) Ancient(kind string, number uint64) ([]byte, error) {
+	table, ok := f.tables[kind]
+	if !ok {
+		return nil, errUnknownTable
+	}
+	if number >= atomic.LoadUint64(&f.frozen) {
+		return nil, errOutOfBounds
+	}
+	return table.Retrieve(number)
+}
+
+// Ancients returns the number of blocks in the freezer.
+func (f *freezer) Ancients() (uint64, error) {
+	return atomic.LoadUint64(&f.frozen), nil
+}
+
+// TruncateAncients drops the blocks from the given number on, for rewinding the chain below them.
+func (f *freezer) TruncateAncients(items uint64) error {
+	if atomic.LoadUint64(&f.frozen) <= items {
+		return nil
+	}
+	// lower the count first, so no reader sees a block being dropped
+	atomic.StoreUint64(&f.frozen, items)
+	for _, table := range f.tables {
+		if err := table.truncate(items); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendAncient appends the block to all tables, which are rolled back if one of them fails.
+func (f *freezer) appendAncient(number uint64, hash common.Hash, header, body, receipts []byte) error {
+	items := map[string][]byte{
+		freezerHashTable:    hash.Bytes(),
+		freezerHeaderTable:  header,
+		freezerBodiesTable:  body,
+		freezerReceiptTable: receipts,
+	}
+	for _, name := range freezerTables {
+		if err := f.tables[name].Append(number, items[name]); err != nil {
+			for _, table := range f.tables {
+				table.truncate(number)
+			}
+			return err
+		}
+	}
+	atomic.AddUint64(&f.frozen, 1)
+	return nil
+}
+
+// Sync flushes all tables to disk.
+func (f *freezer) Sync() error {
+	for _, name := range freezerTables {
+		if err := f.tables[name].Sync(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// close stops the freeze loop and closes the tables.
+func (f *freezer) close() error {
+	select {
+	case <-f.quit:
+	default:
+		close(f.quit)
+	}
+	f.wg.Wait()
+
+	var errs []error
+	for _, table := range f.tables {
+		if err := table.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%v", errs)
+	}
+	return nil
+}
+
+// freeze moves the canonical blocks older than the threshold from the key-value store to the
+// freezer until the freezer is closed.
+func (f *freezer) freeze(db database.Database) {
+	defer f.wg.Done()
+
+	backoff := false
+	for {
+		if backoff {
+			select {
+			case <-time.After(freezerRecheckPeriod):
+			case <-f.quit:
+				return
+			}
+		}
+		select {
+		case <-f.quit:
+			return
+		default:
+		}
+		backoff = true
+
+		head := ReadHeaderNumber(db, ReadHeadBlockHash(db))
+		if head == nil || *head < f.threshold {
+			continue
+		}
+		first := atomic.LoadUint64(&f.frozen)
+		limit := *head - f.threshold + 1
+		if limit <= first {
+			continue
+		}
+		if limit-first > freezerBatchLimit {
+			limit = first + freezerBatchLimit
+		}
+		hashes, err := f.freezeRange(db, first, limit)
+		if err != nil {
+			log.Error("Failed to freeze blocks", "number", first+uint64(len(hashes)), "err", err)
+		}
+		if len(hashes) == 0 {
+			continue
+		}
+		// The blocks must be on disk before they are deleted from the key-value store
+		if err := f.Sync(); err != nil {
+			log.Fatal("Failed to flush frozen tables", "err", err)
+		}
+		if err := pruneFrozen(db, first, hashes); err != nil {
+			log.Error("Failed to delete frozen blocks", "err", err)
+			continue
+		}
+		log.Info("Moved blocks into the freezer", "blocks", len(hashes), "number", first+uint64(len(hashes))-1)
+
+		// keep going without waiting while there is a backlog
+		backoff = err != nil || len(hashes) < freezerBatchLimit
+	}
+}
+
+// freezeRange appends the canonical blocks [first, limit) to the freezer and returns their hashes.
+func (f *freezer) freezeRange(db database.Database, first, limit uint64) ([]common.Hash, error) {
+	var hashes []common.Hash
+	for number := first; number < limit; number++ {
+		select {
+		case <-f.quit:
+			return hashes, nil
+		default:
+		}
+		hash := ReadCanonicalHash(db, number)
+		if hash == (common.Hash{}) {
+			return hashes, errors.New("canonical hash missing")
+		}
+		header := ReadHeaderRLP(db, hash, number)
+		if len(header) == 0 {
+			return hashes, errors.New("block header missing")
+		}
+		body := ReadBodyRLP(db, hash, number)
+		if len(body) == 0 {
+			return hashes, errors.New("block body missing")
+		}
+		receipts := ReadReceiptsRLP(db, hash, number)
+		if len(receipts) == 0 {
+			// blocks without stored receipts have no transactions
+			receipts = rlp.EmptyList
+		}
+		if err := f.appendAncient(number, hash, header, body, receipts); err != nil {
+			return hashes, err
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes, nil
+}
+
+// pruneFrozen deletes the frozen canonical blocks from first on and all side blocks at their
+// heights from the key-value store. The hash to number mappings are kept for lookups by hash and
+// the genesis block is kept for the checks on startup.
+func pruneFrozen(db database.Database, first uint64, hashes []common.Hash) error {
+	batch := db.NewBatch()
+	for i, hash := range hashes {
+		number := first + uint64(i)
+		for _, side := range ReadAllHashes(db, number) {
+			if side != hash {
+				DeleteBlock(batch, side, number)
+			}
+		}
+		if number != 0 {
+			DeleteBlockWithoutNumber(batch, hash, number)
+			DeleteCanonicalHash(batch, number)
+		}
+		if batch.ValueSize() >= database.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	return batch.Write()
+}
+
+// freezerdb is a key-value store backed by a freezer for the ancient blocks.
+type freezerdb struct {
+	database.Database
+	*freezer
+}
+
+// Close stops the freezer before closing the key-value store.
+func (db *freezerdb) Close() {
+	if err := db.freezer.close(); err != nil {
+		log.Error("Failed to close freezer", "err", err)
+	}
+	db.Database.Close()
+}
+
+// Stat returns the statistics of the key-value store, the freezer has none.
+func (db *freezerdb) Stat(property string) (string, error) {
+	stater, ok := db.Database.(database.Stater)
+	if !ok {
+		return "", fmt.Errorf("database does not support stats")
+	}
+	return stater.Stat(property)
+}
+
+// Compact compacts the key-value store, the freezer is append only and needs no compaction.
+func (db *freezerdb) Compact(start []byte, limit []byte) error {
+	if compacter, ok := db.Database.(database.Compacter); ok {
+		return compacter.Compact(start, limit)
+	}
+	return nil
+}
+
+// NewDatabaseWithFreezer backs the key-value store with a freezer in the directory, which moves
+// the canonical blocks older than threshold blocks out of the store, or none if the threshold is 0.
+// The accessors of this package read ancient blocks from the freezer transparently.
+func NewDatabaseWithFreezer(db database.Database, dir string, threshold uint64) (database.Database, error) {
+	frdb, err := newFreezer(dir, threshold)
+	if err != nil {
+		return nil, err
+	}
+	// A freezer of another chain would serve its blocks as ours
+	if frozen, _ := frdb.Ancients(); frozen > 0 {
+		ancient, _ := frdb.Ancient(freezerHashTable, 0)
+		if genesis := ReadCanonicalHash(db, 0); genesis != (common.Hash{}) && genesis != common.BytesToHash(ancient) {
+			frdb.close()
+			return nil, fmt.Errorf("genesis mismatch: %x (database) != %x (ancients)", genesis, ancient)
+		}
+	}
+	if threshold > 0 {
+		frdb.wg.Add(1)
+		go frdb.freeze(db)
+	}
+	return &freezerdb{Database: db, freezer: frdb}, nil
+}
+
+// TruncateAncients drops the ancient blocks from the given number on if the database has a freezer.
+func TruncateAncients(db DatabaseReader, items uint64) error {
+	if ancients, ok := db.(AncientStore); ok {
+		return ancients.TruncateAncients(items)
+	}
+	return nil
+}
+
+// ReadAncients returns the number of ancient blocks, 0 if the database has no freezer.
+func ReadAncients(db DatabaseReader) uint64 {
+	if ancients, ok := db.(AncientReader); ok {
+		frozen, _ := ancients.Ancients()
+		return frozen
+	}
+	return 0
+}
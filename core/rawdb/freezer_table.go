@@ -0,0 +1,247 @@
+// Copyright 2018 The gcchain authors
+
+package rawdb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	// indexEntrySize is the size of an index entry, the end offset of the item in the data file.
+	indexEntrySize = 8
+
+	freezerIndexSuffix = ".ridx"
+	freezerDataSuffix  = ".rdat"
+)
+
+var (
+	// errOutOfBounds is returned if the item requested is not in the table.
+	errOutOfBounds = errors.New("out of bounds")
+
+	// errOutOrderInsertion is returned if an item is not appended at the end of the table.
+	errOutOrderInsertion = errors.New("the append operation is out-order")
+
+	// errClosed is returned if an operation is attempted on a closed table.
+	errClosed = errors.New("closed")
+)
+
+// freezerTable is an append-only table of items, stored in a data file holding the items back to
+// back and an index file holding the end offset of every item in the data file.
+//
+// Items are appended to the data file before the index, a table cut off at any point is repaired
+// by dropping the index entries past the data file and the data past the last index entry.
+type freezerTable struct {
+	name  string
+	index *os.File
+	data  *os.File
+
+	items  uint64 // number of items in the table
+	offset uint64 // end offset of the last item in the data file
+
+	lock sync.RWMutex
+}
+
+// newFreezerTable opens or creates the table in the directory and repairs it.
+func newFreezerTable(dir string, name string) (*freezerTable, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	index, err := os.OpenFile(filepath.Join(dir, name+freezerIndexSuffix), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.OpenFile(filepath.Join(dir, name+freezerDataSuffix), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		index.Close()
+		return nil, err
+	}
+	t := &freezerTable{name: name, index: index, data: data}
+	if err := t.repair(); err != nil {
+		t.Close()
+		return nil, err
+	}
+	return t, nil
+}
+
+// repair drops a partially written index entry, index entries pointing past the data file and
+// data past the last index entry, which are left by a write interrupted by a crash.
+func (t *freezerTable) repair() error {
+	stat, err := t.index.Stat()
+	if err != nil {
+		return err
+	}
+	items := uint64(stat.Size()) / indexEntrySize
+
+	if stat, err = t.data.Stat(); err != nil {
+		return err
+	}
+	size := uint64(stat.Size())
+
+	var offset uint64
+	for ; items > 0; items-- {
+		if offset, err = t.readIndex(items - 1); err != nil {
+			return err
+		}
+		if offset <= size {
+			break
+		}
+	}
+	if items == 0 {
+		offset = 0
+	}
+	if err := t.index.Truncate(int64(items * indexEntrySize)); err != nil {
+		return err
+	}
+	if err := t.data.Truncate(int64(offset)); err != nil {
+		return err
+	}
+	t.items, t.offset = items, offset
+	return t.sync()
+}
+
+// readIndex returns the end offset of the item in the data file.
+func (t *freezerTable) readIndex(item uint64) (uint64, error) {
+	var entry [indexEntrySize]byte
+	if _, err := t.index.ReadAt(entry[:], int64(item*indexEntrySize)); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(entry[:]), nil
+}
+
+// Items returns the number of items in the table.
+func (t *freezerTable) Items() uint64 {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.items
+}
+
+// Append appends the blob as the item, which has to be the next item of the table.
+func (t *freezerTable) Append(item uint64, blob []byte) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.index == nil {
+		return errClosed
+	}
+	if item != t.items {
+		return fmt.Errorf("%s: %v, have %d, got %d", t.name, errOutOrderInsertion, t.items, item)
+	}
+	if _, err := t.data.WriteAt(blob, int64(t.offset)); err != nil {
+		return err
+	}
+	var entry [indexEntrySize]byte
+	binary.BigEndian.PutUint64(entry[:], t.offset+uint64(len(blob)))
+	if _, err := t.index.WriteAt(entry[:], int64(t.items*indexEntrySize)); err != nil {
+		return err
+	}
+	t.items++
+	t.offset += uint64(len(blob))
+	return nil
+}
+
+// Retrieve returns the blob of the item.
+func (t *freezerTable) Retrieve(item uint64) ([]byte, error) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.index == nil {
+		return nil, errClosed
+	}
+	if item >= t.items {
+		return nil, errOutOfBounds
+	}
+	var start uint64
+	if item > 0 {
+		offset, err := t.readIndex(item - 1)
+		if err != nil {
+			return nil, err
+		}
+		start = offset
+	}
+	end, err := t.readIndex(item)
+	if err != nil {
+		return nil, err
+	}
+	if end < start || end > t.offset {
+		return nil, fmt.Errorf("%s: corrupt index entry %d", t.name, item)
+	}
+	blob := make([]byte, end-start)
+	if _, err := t.data.ReadAt(blob, int64(start)); err != nil {
+		return nil, err
+	}
+	return blob, nil
+}
+
+// truncate drops the items from the given one on.
+func (t *freezerTable) truncate(items uint64) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.index == nil {
+		return errClosed
+	}
+	if items >= t.items {
+		return nil
+	}
+	var offset uint64
+	if items > 0 {
+		var err error
+		if offset, err = t.readIndex(items - 1); err != nil {
+			return err
+		}
+	}
+	// cut the index first, a crash in between leaves only unreferenced data
+	if err := t.index.Truncate(int64(items * indexEntrySize)); err != nil {
+		return err
+	}
+	if err := t.data.Truncate(int64(offset)); err != nil {
+		return err
+	}
+	t.items, t.offset = items, offset
+	return nil
+}
+
+// Sync flushes the data file before the index file, so the index never points past the data.
+func (t *freezerTable) Sync() error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.index == nil {
+		return errClosed
+	}
+	return t.sync()
+}
+
+func (t *freezerTable) sync() error {
+	if err := t.data.Sync(); err != nil {
+		return err
+	}
+	return t.index.Sync()
+}
+
+// Close closes the files of the table.
+func (t *freezerTable) Close() error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.index == nil {
+		return nil
+	}
+	var errs []error
+	for _, f := range []*os.File{t.data, t.index} {
+		if err := f.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	t.index, t.data = nil, nil
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
@@ -0,0 +1,293 @@
+package rawdb
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gcchains/chain/database"
+	"github.com/gcchains/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func newFreezerDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "freezer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+// writeTestChain writes a canonical chain of n blocks with receipts and a side block at height 2.
+func writeTestChain(db database.Database, n int) (blocks []*types.Block, side *types.Block) {
+	parent := common.Hash{}
+	for i := 0; i < n; i++ {
+		block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(int64(i)), ParentHash: parent, Extra: []byte("canonical")})
+		WriteBlock(db, block)
+		WriteReceipts(db, block.Hash(), block.NumberU64(), types.Receipts{{CumulativeGasUsed: uint64(i), Logs: []*types.Log{}}})
+		WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		blocks = append(blocks, block)
+		parent = block.Hash()
+	}
+	WriteHeadHeaderHash(db, parent)
+	WriteHeadBlockHash(db, parent)
+
+	side = types.NewBlockWithHeader(&types.Header{Number: big.NewInt(2), ParentHash: blocks[1].Hash(), Extra: []byte("side")})
+	WriteBlock(db, side)
+	return blocks, side
+}
+
+func TestFreezerTableRepair(t *testing.T) {
+	dir := newFreezerDir(t)
+	defer os.RemoveAll(dir)
+
+	table, err := newFreezerTable(dir, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		if err := table.Append(uint64(i), bytes.Repeat([]byte{byte(i)}, i+1)); err != nil {
+			t.Fatalf("Append(%d) error = %v", i, err)
+		}
+	}
+	if err := table.Append(7, []byte{7}); err == nil {
+		t.Error("out of order append succeeded")
+	}
+	table.Close()
+
+	// A crash cut the data of the last item and left half an index entry
+	data := filepath.Join(dir, "test"+freezerDataSuffix)
+	if err := os.Truncate(data, 1+2+3+4+2); err != nil {
+		t.Fatal(err)
+	}
+	f, _ := os.OpenFile(filepath.Join(dir, "test"+freezerIndexSuffix), os.O_WRONLY|os.O_APPEND, 0644)
+	f.Write([]byte{0, 0, 1})
+	f.Close()
+
+	if table, err = newFreezerTable(dir, "test"); err != nil {
+		t.Fatal(err)
+	}
+	defer table.Close()
+	if items := table.Items(); items != 4 {
+		t.Fatalf("repaired table has %d items, want 4", items)
+	}
+	if stat, _ := os.Stat(data); stat.Size() != 1+2+3+4 {
+		t.Errorf("repaired data file size = %d, want %d", stat.Size(), 1+2+3+4)
+	}
+	for i := 0; i < 4; i++ {
+		if blob, err := table.Retrieve(uint64(i)); err != nil || !bytes.Equal(blob, bytes.Repeat([]byte{byte(i)}, i+1)) {
+			t.Errorf("Retrieve(%d) = %x, %v", i, blob, err)
+		}
+	}
+	if _, err := table.Retrieve(4); err != errOutOfBounds {
+		t.Errorf("Retrieve(4) error = %v, want %v", err, errOutOfBounds)
+	}
+	// The table is appendable after the repair
+	if err := table.Append(4, []byte{4}); err != nil {
+		t.Fatalf("Append after repair error = %v", err)
+	}
+	if blob, _ := table.Retrieve(4); !bytes.Equal(blob, []byte{4}) {
+		t.Errorf("Retrieve(4) = %x, want 04", blob)
+	}
+}
+
+func TestFreezerRepairUnevenTables(t *testing.T) {
+	dir := newFreezerDir(t)
+	defer os.RemoveAll(dir)
+
+	f, err := newFreezer(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := uint64(0); i < 3; i++ {
+		if err := f.appendAncient(i, common.Hash{byte(i)}, []byte{1}, []byte{2}, []byte{3}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A crash in the middle of appending a block wrote it to some tables only
+	f.tables[freezerHashTable].Append(3, common.Hash{3}.Bytes())
+	f.tables[freezerHeaderTable].Append(3, []byte{1})
+	f.close()
+
+	if f, err = newFreezer(dir, 0); err != nil {
+		t.Fatal(err)
+	}
+	defer f.close()
+	if frozen, _ := f.Ancients(); frozen != 3 {
+		t.Fatalf("repaired freezer has %d blocks, want 3", frozen)
+	}
+	for name, table := range f.tables {
+		if items := table.Items(); items != 3 {
+			t.Errorf("table %s has %d items, want 3", name, items)
+		}
+	}
+	if err := f.appendAncient(3, common.Hash{3}, []byte{1}, []byte{2}, []byte{3}); err != nil {
+		t.Errorf("append after repair error = %v", err)
+	}
+}
+
+// waitAncients waits until the freezer of the database holds the number of blocks.
+func waitAncients(t *testing.T, db database.Database, want uint64) {
+	for i := 0; i < 500 && ReadAncients(db) != want; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if frozen := ReadAncients(db); frozen != want {
+		t.Fatalf("freezer holds %d blocks, want %d", frozen, want)
+	}
+}
+
+func TestFreezerMigration(t *testing.T) {
+	dir := newFreezerDir(t)
+	defer os.RemoveAll(dir)
+
+	kvdb := database.NewMemDatabase()
+	blocks, side := writeTestChain(kvdb, 10)
+
+	db, err := NewDatabaseWithFreezer(kvdb, dir, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The head is 9, blocks 0-6 are older than the 3 blocks kept
+	waitAncients(t, db, 7)
+	for i := 0; i < 500 && HasHeader(kvdb, blocks[6].Hash(), 6); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	check := func(stage string, db database.Database) {
+		for _, block := range blocks {
+			hash, number := block.Hash(), block.NumberU64()
+			if got := ReadCanonicalHash(db, number); got != hash {
+				t.Errorf("%s: canonical hash %d = %x, want %x", stage, number, got, hash)
+			}
+			if got := ReadBlock(db, hash, number); got == nil || got.Hash() != hash {
+				t.Errorf("%s: block %d not readable", stage, number)
+			}
+			if !HasHeader(db, hash, number) || !HasBody(db, hash, number) {
+				t.Errorf("%s: block %d reported missing", stage, number)
+			}
+			if receipts := ReadReceipts(db, hash, number); len(receipts) != 1 || receipts[0].CumulativeGasUsed != number {
+				t.Errorf("%s: receipts of block %d = %v", stage, number, receipts)
+			}
+			if got := ReadHeaderNumber(db, hash); got == nil || *got != number {
+				t.Errorf("%s: number of block %x = %v, want %d", stage, hash, got, number)
+			}
+		}
+		// Frozen items are only returned for the canonical block
+		if HasHeader(db, side.Hash(), 2) || ReadHeader(db, side.Hash(), 2) != nil || ReadBody(db, side.Hash(), 2) != nil {
+			t.Errorf("%s: side block at a frozen height readable", stage)
+		}
+	}
+	check("frozen", db)
+
+	// The frozen blocks but the genesis and the side blocks left the key-value store
+	for _, block := range blocks[1:7] {
+		if HasHeader(kvdb, block.Hash(), block.NumberU64()) || HasBody(kvdb, block.Hash(), block.NumberU64()) {
+			t.Errorf("frozen block %d still in the key-value store", block.NumberU64())
+		}
+	}
+	for _, block := range []*types.Block{blocks[0], blocks[7], blocks[9]} {
+		if !HasHeader(kvdb, block.Hash(), block.NumberU64()) {
+			t.Errorf("block %d missing from the key-value store", block.NumberU64())
+		}
+	}
+	if HasHeader(kvdb, side.Hash(), 2) || ReadHeaderNumber(kvdb, side.Hash()) != nil {
+		t.Error("side block at a frozen height still in the key-value store")
+	}
+	db.(*freezerdb).freezer.close()
+
+	// A freezer left by a previous run is read without moving more blocks
+	if db, err = NewDatabaseWithFreezer(kvdb, dir, 0); err != nil {
+		t.Fatal(err)
+	}
+	check("reopened", db)
+
+	// Rewinding below the frozen blocks drops them
+	if err := TruncateAncients(db, 5); err != nil {
+		t.Fatal(err)
+	}
+	if hash := ReadCanonicalHash(db, 5); hash != (common.Hash{}) {
+		t.Errorf("truncated block 5 still canonical: %x", hash)
+	}
+	if ReadAncients(db) != 5 || ReadBlock(db, blocks[4].Hash(), 4) == nil {
+		t.Errorf("blocks below the truncation dropped")
+	}
+	db.(*freezerdb).freezer.close()
+}
+
+func TestFreezerGenesisMismatch(t *testing.T) {
+	dir := newFreezerDir(t)
+	defer os.RemoveAll(dir)
+
+	f, err := newFreezer(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.appendAncient(0, common.Hash{1}, []byte{1}, []byte{2}, []byte{3})
+	f.close()
+
+	kvdb := database.NewMemDatabase()
+	writeTestChain(kvdb, 3)
+	if _, err := NewDatabaseWithFreezer(kvdb, dir, 0); err == nil {
+		t.Error("freezer of another chain opened")
+	}
+}
+
+func TestFreezerDatabaseStatCompact(t *testing.T) {
+	dir := newFreezerDir(t)
+	defer os.RemoveAll(dir)
+
+	kvdb, err := database.NewLDBDatabase(filepath.Join(dir, "chaindata"), 16, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := NewDatabaseWithFreezer(kvdb, filepath.Join(dir, "ancient"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if _, err := db.(database.Stater).Stat("leveldb.stats"); err != nil {
+		t.Errorf("stat of the key-value store failed: %v", err)
+	}
+	if err := db.(database.Compacter).Compact(nil, nil); err != nil {
+		t.Errorf("compaction of the key-value store failed: %v", err)
+	}
+
+	memdb, err := NewDatabaseWithFreezer(database.NewMemDatabase(), filepath.Join(dir, "memancient"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer memdb.Close()
+
+	if _, err := memdb.(database.Stater).Stat("leveldb.stats"); err == nil {
+		t.Error("stat of a store without statistics succeeded")
+	}
+	if err := memdb.(database.Compacter).Compact(nil, nil); err != nil {
+		t.Errorf("compaction of a store without compaction failed: %v", err)
+	}
+}
+
+func TestReadAllHashes(t *testing.T) {
+	db := database.NewMemDatabase()
+	blocks, side := writeTestChain(db, 4)
+	WriteReceipts(db, side.Hash(), 2, nil)
+
+	for number, want := range [][]common.Hash{{blocks[0].Hash()}, {blocks[1].Hash()}, {blocks[2].Hash(), side.Hash()}, {blocks[3].Hash()}, nil} {
+		got := ReadAllHashes(db, uint64(number))
+		if fmt.Sprint(sortedHashes(got)) != fmt.Sprint(sortedHashes(want)) {
+			t.Errorf("ReadAllHashes(%d) = %x, want %x", number, got, want)
+		}
+	}
+}
+
+func sortedHashes(hashes []common.Hash) []common.Hash {
+	sorted := append([]common.Hash{}, hashes...)
+	sort.Slice(sorted, func(i, j int) bool { return bytes.Compare(sorted[i][:], sorted[j][:]) < 0 })
+	return sorted
+}
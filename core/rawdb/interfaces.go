@@ -17,3 +17,16 @@ type DatabaseWriter interface {
 type DatabaseDeleter interface {
 	Delete(key []byte) error
 }
+
+// AncientReader wraps the reading of the ancient blocks in a freezer, kind is the table of the item.
+type AncientReader interface {
+	HasAncient(kind string, number uint64) (bool, error)
+	Ancient(kind string, number uint64) ([]byte, error)
+	Ancients() (uint64, error)
+}
+
+// AncientStore wraps a freezer, which appends blocks itself and is only truncated from outside.
+type AncientStore interface {
+	AncientReader
+	TruncateAncients(items uint64) error
+}
@@ -39,11 +39,16 @@ var (
 	txLookupPrefix  = []byte("l") // txLookupPrefix + hash -> transaction/receipt lookup metadata
 	bloomBitsPrefix = []byte("B") // bloomBitsPrefix + bit (uint16 big endian) + section (uint64 big endian) + hash -> bloom bits
 
+	addrIndexCountPrefix = []byte("Ac") // addrIndexCountPrefix + address -> number of address index entries (uint64 big endian)
+	addrIndexEntryPrefix = []byte("Ae") // addrIndexEntryPrefix + address + seq (uint64 big endian) -> address index entry
+	addrIndexBlockPrefix = []byte("Ab") // addrIndexBlockPrefix + num (uint64 big endian) -> addresses indexed in the block
+
 	preimagePrefix = []byte("secure-key-")      // preimagePrefix + hash -> preimage
 	configPrefix   = []byte("ethereum-config-") // config prefix for the db
 
 	// Chain index prefixes (use `i` + single byte to avoid mixing data types).
 	BloomBitsIndexPrefix = []byte("iB") // BloomBitsIndexPrefix is the data table of a chain indexer to track its progress
+	AddrIndexPrefix      = []byte("iA") // AddrIndexPrefix is the data table of the address indexer to track its progress
 
 	preimageCounter    = metrics.NewRegisteredCounter("db/preimage/total", nil)
 	preimageHitCounter = metrics.NewRegisteredCounter("db/preimage/hits", nil)
@@ -64,6 +69,11 @@ func encodeBlockNumber(number uint64) []byte {
 	return enc
 }
 
+// headerKeyPrefix = headerPrefix + num (uint64 big endian)
+func headerKeyPrefix(number uint64) []byte {
+	return append(append([]byte{}, headerPrefix...), encodeBlockNumber(number)...)
+}
+
 // headerKey = headerPrefix + num (uint64 big endian) + hash
 func headerKey(number uint64, hash common.Hash) []byte {
 	return append(append(headerPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
@@ -109,6 +119,21 @@ func bloomBitsKey(bit uint, section uint64, hash common.Hash) []byte {
 	return key
 }
 
+// addrIndexCountKey = addrIndexCountPrefix + address
+func addrIndexCountKey(address common.Address) []byte {
+	return append(append([]byte{}, addrIndexCountPrefix...), address.Bytes()...)
+}
+
+// addrIndexEntryKey = addrIndexEntryPrefix + address + seq (uint64 big endian)
+func addrIndexEntryKey(address common.Address, seq uint64) []byte {
+	return append(append(append([]byte{}, addrIndexEntryPrefix...), address.Bytes()...), encodeBlockNumber(seq)...)
+}
+
+// addrIndexBlockKey = addrIndexBlockPrefix + num (uint64 big endian)
+func addrIndexBlockKey(number uint64) []byte {
+	return append(append([]byte{}, addrIndexBlockPrefix...), encodeBlockNumber(number)...)
+}
+
 // preimageKey = preimagePrefix + hash
 func preimageKey(hash common.Hash) []byte {
 	return append(preimagePrefix, hash.Bytes()...)
@@ -64,6 +64,9 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, sta
 		author = (*common.Address)(nil)
 	)
 
+	// resolve proxy contracts through the block by the state of the register only
+	cfg.ProxyCache = vm.NewProxyCache()
+
 	beneficiary, err := p.bc.Engine().Author(header)
 	if err == nil {
 		author = &beneficiary
@@ -105,11 +108,12 @@ func ApplyTransaction(config *configs.ChainConfig, bc ChainContext, author *comm
 	}
 
 	// if the tx type is not supported, return early
-	if !types.SupportTxType(tx.Type()) {
+	if !types.SupportTxType(config, header.Number, tx.Type()) {
 		return nil, nil, 0, types.ErrNotSupportedTxType
 	}
 
-	// this is for sanitize, may be useful later. for now, its useless because it already returned
+	// the payload of a private tx is a reference to the sealed payload in remote database, it is not executed
+	// in public state
 	if !tx.IsBasic() {
 		msg.SetData([]byte{})
 	}
@@ -142,27 +146,26 @@ func ApplyTransaction(config *configs.ChainConfig, bc ChainContext, author *comm
 
 	var privReceipt *types.Receipt
 	// For private tx, it should process its real private tx payload in participant's node. If account manager is nil,
-	// doesn't process private tx. If the node does not support private transaction, skip it.
-	if tx.IsPrivate() && accm != nil && types.SupportTxType(tx.Type()) {
-
-		// for now, it's impossible to enter here
-		privReceipt, err = tryApplyPrivateTx(config, bc, author, gp, privateStateDb, remoteDB, header, tx, cfg, accm)
-		if err != nil {
-			if err == NoPermissionError {
-				log.Info("No permission to process the transaction.")
-				return pubReceipt, privReceipt, gas, nil
-			} else {
-				log.Error("Cannot process the transaction.", err)
-				return pubReceipt, privReceipt, 0, err
-			}
+	// doesn't process private tx. The public state never depends on the private execution, so a failure of it does
+	// not invalidate the block.
+	if tx.IsPrivate() && accm != nil {
+		snap := privateStateDb.Snapshot()
+		privReceipt, err = tryApplyPrivateTx(config, bc, author, privateStateDb, remoteDB, header, tx, cfg, accm)
+		switch err {
+		case nil:
+		case NoPermissionError:
+			log.Debug("No permission to process the private transaction", "hash", tx.Hash())
+		default:
+			log.Error("Cannot process the private transaction", "hash", tx.Hash(), "err", err)
+			privateStateDb.RevertToSnapshot(snap)
 		}
 	}
 
-	return pubReceipt, privReceipt, gas, err
+	return pubReceipt, privReceipt, gas, nil
 }
 
 // applyPrivateTx attempts to apply a private transaction to the given state database
-func tryApplyPrivateTx(config *configs.ChainConfig, bc ChainContext, author *common.Address, gp *GasPool, privateStateDb *state.StateDB,
+func tryApplyPrivateTx(config *configs.ChainConfig, bc ChainContext, author *common.Address, privateStateDb *state.StateDB,
 	remoteDB database.RemoteDatabase, header *types.Header, tx *types.Transaction, cfg vm.Config, accm *accounts.Manager) (*types.Receipt, error) {
 	msg, err := tx.AsMessage(types.MakeSigner(config))
 	if err != nil {
@@ -189,9 +192,13 @@ func tryApplyPrivateTx(config *configs.ChainConfig, bc ChainContext, author *com
 	// Create a new context to be used in the EVM environment
 	context := NewEVMContext(msg, header, bc, author)
 	// Create a new environment which holds all relevant information
-	// about the transaction and calling mechanisms.
+	// about the transaction and calling mechanisms. The proxy cache
+	// is of the public state.
+	cfg.ProxyCache = nil
 	vmenv := vm.NewEVM(context, privateStateDb, config, cfg)
-	// Apply the transaction to the current state (included in the env)
+	// Apply the transaction to the current state (included in the env), the block gas pool is not touched
+	// because private execution only happens in participants' nodes
+	gp := new(GasPool).AddGas(msg.Gas())
 	_, _, failed, err := ApplyMessage(vmenv, msg, gp)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,248 @@
+package core
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/gcchains/chain/commons/log"
+	"github.com/gcchains/chain/configs"
+	"github.com/gcchains/chain/consensus/dpos/backend"
+	"github.com/gcchains/chain/core/state"
+	"github.com/gcchains/chain/database"
+	"github.com/gcchains/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// MinStateRetention is the least number of recent blocks whose state is kept when pruning online, states
+// of the recent blocks may be held in memory and reference trie nodes on disk.
+const MinStateRetention = triesInMemory
+
+// StatePruner deletes state trie nodes and contract codes which are not reachable from retained state roots.
+//
+// Trie nodes and codes are content addressed and shared among tries, including the public and private
+// state tries, so everything reachable from all retained roots is marked before anything is swept.
+type StatePruner struct {
+	db      database.Database
+	pubDB   state.Database // public state tries, may contain nodes not flushed to db yet
+	privDB  state.Database // private state tries, may contain nodes not flushed to db yet
+	marked  map[common.Hash]struct{}
+	skipped int
+}
+
+// NewStatePruner creates a state pruner for tries stored in db.
+func NewStatePruner(db database.Database, pubDB, privDB state.Database) *StatePruner {
+	return &StatePruner{
+		db:     db,
+		pubDB:  pubDB,
+		privDB: privDB,
+		marked: make(map[common.Hash]struct{}),
+	}
+}
+
+// Mark marks the public state with given root and the private state associated with it as retained.
+// States which do not exist are skipped.
+func (p *StatePruner) Mark(root common.Hash) error {
+	if err := p.markState(p.pubDB, root); err != nil {
+		return err
+	}
+	return p.markState(p.privDB, GetPrivateStateRoot(p.db, root))
+}
+
+func (p *StatePruner) markState(sdb state.Database, root common.Hash) error {
+	if root == (common.Hash{}) || root == types.EmptyRootHash {
+		return nil
+	}
+	tr, err := sdb.OpenTrie(root)
+	if err != nil {
+		if _, ok := err.(*trie.MissingNodeError); ok {
+			log.Debug("Skip missing state when pruning", "root", root)
+			p.skipped++
+			return nil
+		}
+		return err
+	}
+
+	return p.markTrie(tr.NodeIterator(nil), func(leaf []byte) error {
+		var account state.Account
+		if err := rlp.DecodeBytes(leaf, &account); err != nil {
+			return err
+		}
+		p.marked[common.BytesToHash(account.CodeHash)] = struct{}{}
+
+		if account.Root == (common.Hash{}) || account.Root == types.EmptyRootHash {
+			return nil
+		}
+		storage, err := sdb.OpenStorageTrie(common.Hash{}, account.Root)
+		if err != nil {
+			return err
+		}
+		return p.markTrie(storage.NodeIterator(nil), nil)
+	})
+}
+
+// markTrie marks all nodes of a trie, subtries already marked are not iterated again.
+func (p *StatePruner) markTrie(it trie.NodeIterator, onLeaf func(leaf []byte) error) error {
+	descend := true
+	for it.Next(descend) {
+		descend = true
+		if hash := it.Hash(); hash != (common.Hash{}) {
+			if _, ok := p.marked[hash]; ok {
+				descend = false
+				continue
+			}
+			p.marked[hash] = struct{}{}
+		}
+		if it.Leaf() && onLeaf != nil {
+			if err := onLeaf(it.LeafBlob()); err != nil {
+				return err
+			}
+		}
+	}
+	return it.Error()
+}
+
+// Sweep deletes all trie nodes and codes not marked, it returns the number of deleted entries.
+// An entry is regarded as a trie node or code only if its key is the hash of its value.
+//
+// Entries are collected from a snapshot of db and deleted in batches. Each batch is deleted holding lock
+// after marking the states whose roots are returned by written, so states can be written while sweeping
+// as long as they are written holding lock and their roots are returned by written.
+func (p *StatePruner) Sweep(lock sync.Locker, written func() []common.Hash) (int, error) {
+	var (
+		keys    [][]byte
+		deleted int
+	)
+	flush := func() error {
+		lock.Lock()
+		defer lock.Unlock()
+
+		for _, root := range written() {
+			if err := p.Mark(root); err != nil {
+				return err
+			}
+		}
+		batch := p.db.NewBatch()
+		for _, key := range keys {
+			if _, ok := p.marked[common.BytesToHash(key)]; ok {
+				continue
+			}
+			if err := batch.Delete(key); err != nil {
+				return err
+			}
+			deleted++
+		}
+		keys = keys[:0]
+		return batch.Write()
+	}
+
+	err := forEachEntry(p.db, func(key, value []byte) error {
+		if len(key) != common.HashLength {
+			return nil
+		}
+		if _, ok := p.marked[common.BytesToHash(key)]; ok {
+			return nil
+		}
+		if !bytes.Equal(crypto.Keccak256(value), key) {
+			return nil
+		}
+		keys = append(keys, common.CopyBytes(key))
+		if len(keys)*common.HashLength >= database.IdealBatchSize {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return deleted, err
+	}
+	return deleted, flush()
+}
+
+// forEachEntry calls fn with every key and value in db.
+func forEachEntry(db database.Database, fn func(key, value []byte) error) error {
+	it := db.NewIterator(nil, nil)
+	defer it.Release()
+
+	for it.Next() {
+		if err := fn(it.Key(), it.Value()); err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
+
+// retainedStateBlocks returns numbers of blocks whose state is kept when pruning with given head and retention,
+// they are the genesis, checkpoints at term boundaries and the recent retention blocks.
+func retainedStateBlocks(head, retention uint64, config *configs.DposConfig) []uint64 {
+	recent := uint64(0)
+	if head >= retention {
+		recent = head - retention + 1
+	}
+
+	numbers := []uint64{0}
+	if config != nil {
+		for number := uint64(1); number < recent; number++ {
+			if backend.IsCheckPoint(number, config.TermLen, config.ViewLen) {
+				numbers = append(numbers, number)
+			}
+		}
+	}
+	for number := recent; number <= head; number++ {
+		if number != 0 {
+			numbers = append(numbers, number)
+		}
+	}
+	return numbers
+}
+
+// PruneState deletes states of canonical blocks older than the recent retention blocks, except the genesis
+// and checkpoints at term boundaries. Private states associated with retained blocks are kept as well.
+// It returns the number of deleted trie nodes and codes.
+//
+// Blocks can be inserted while pruning, states written meanwhile are kept.
+func (bc *BlockChain) PruneState(retention uint64) (int, error) {
+	if retention == 0 {
+		return 0, nil
+	}
+
+	// snapshot the retained roots and record the states written from now on
+	bc.mu.Lock()
+	head := bc.CurrentBlock().NumberU64()
+	var roots []common.Hash
+	for _, number := range retainedStateBlocks(head, retention, bc.chainConfig.Dpos) {
+		if header := bc.GetHeaderByNumber(number); header != nil {
+			roots = append(roots, header.StateRoot)
+		}
+	}
+	bc.writtenRoots = make([]common.Hash, 0)
+	bc.mu.Unlock()
+
+	defer func() {
+		bc.mu.Lock()
+		bc.writtenRoots = nil
+		bc.mu.Unlock()
+	}()
+
+	pruner := NewStatePruner(bc.db, bc.stateCache, bc.privateStateCache)
+	for _, root := range roots {
+		if err := pruner.Mark(root); err != nil {
+			return 0, err
+		}
+	}
+
+	// called holding bc.mu
+	written := func() []common.Hash {
+		roots := bc.writtenRoots
+		bc.writtenRoots = make([]common.Hash, 0)
+		return roots
+	}
+	deleted, err := pruner.Sweep(&bc.mu, written)
+	if err != nil {
+		return deleted, err
+	}
+	log.Info("Pruned state", "head", head, "retention", retention, "marked", len(pruner.marked),
+		"deleted", deleted, "skipped", pruner.skipped)
+	return deleted, nil
+}
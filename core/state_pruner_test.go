@@ -0,0 +1,204 @@
+package core
+
+import (
+	"bytes"
+	"math/big"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gcchains/chain/configs"
+	"github.com/gcchains/chain/core/state"
+	"github.com/gcchains/chain/core/vm"
+	"github.com/gcchains/chain/database"
+	"github.com/gcchains/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestRetainedStateBlocks(t *testing.T) {
+	dpos := &configs.DposConfig{TermLen: 2, ViewLen: 2}
+	tests := []struct {
+		name      string
+		head      uint64
+		retention uint64
+		config    *configs.DposConfig
+		want      []uint64
+	}{
+		{"short chain", 2, 3, dpos, []uint64{0, 1, 2}},
+		{"with checkpoints", 10, 3, dpos, []uint64{0, 4, 8, 9, 10}},
+		{"checkpoint in recent blocks", 9, 2, dpos, []uint64{0, 4, 8, 9}},
+		{"without dpos", 10, 3, nil, []uint64{0, 8, 9, 10}},
+	}
+	for _, tt := range tests {
+		if got := retainedStateBlocks(tt.head, tt.retention, tt.config); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: retainedStateBlocks() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// writePrivateState writes a private state holding balance for addr and associates it with the block.
+func writePrivateState(t *testing.T, chain *BlockChain, block *types.Block, addr common.Address, balance int64) common.Hash {
+	privState, _ := state.New(common.Hash{}, chain.privateStateCache)
+	privState.SetBalance(addr, big.NewInt(balance))
+	root, err := privState.Commit(true)
+	if err != nil {
+		t.Fatalf("failed to commit private state: %v", err)
+	}
+	if err := chain.privateStateCache.TrieDB().Commit(root, false); err != nil {
+		t.Fatalf("failed to write private state: %v", err)
+	}
+	if err := WritePrivateStateRoot(chain.db, block.StateRoot(), root); err != nil {
+		t.Fatalf("failed to write private state root: %v", err)
+	}
+	return root
+}
+
+// Tests that states of old blocks are pruned while the genesis, checkpoints and recent states are kept.
+func TestPruneState(t *testing.T) {
+	var (
+		key, _  = crypto.GenerateKey()
+		addr    = crypto.PubkeyToAddress(key.PublicKey)
+		config  = *configs.TestChainConfig
+		gspec   = &Genesis{Config: &config, Alloc: GenesisAlloc{addr: {Balance: big.NewInt(1000000000)}}}
+		signer  = types.NewCep1Signer(config.ChainID)
+		privAcc = common.HexToAddress("0x0100")
+	)
+	config.Dpos = &configs.DposConfig{TermLen: 2, ViewLen: 2}
+
+	genDb := database.NewMemDatabase()
+	genesis := gspec.MustCommit(genDb)
+	blocks, _ := GenerateChain(&config, genesis, fakeDpos(genDb), genDb, nil, 22, func(i int, gen *BlockGen) {
+		to := common.BigToAddress(big.NewInt(int64(i + 1)))
+		tx, _ := types.SignTx(types.NewTransaction(gen.TxNonce(addr), to, big.NewInt(1000), configs.TxGas, new(big.Int), nil), signer, key)
+		gen.AddTx(tx)
+	})
+
+	db := database.NewMemDatabase()
+	gspec.MustCommit(db)
+	chain, _ := NewBlockChain(db, nil, &config, fakeDpos(db), vm.Config{}, nil, nil)
+	defer chain.Stop()
+
+	if _, err := chain.InsertChain(blocks[:20]); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	keptPriv := writePrivateState(t, chain, blocks[3], privAcc, 4)     // block 4 is a checkpoint
+	prunedPriv := writePrivateState(t, chain, blocks[5], privAcc, 6)   // block 6 is neither a checkpoint nor recent
+	recentPriv := writePrivateState(t, chain, blocks[19], privAcc, 20) // block 20 is the head
+
+	deleted, err := chain.PruneState(3)
+	if err != nil {
+		t.Fatalf("PruneState() error = %v", err)
+	}
+	if deleted == 0 {
+		t.Fatalf("PruneState() deleted nothing")
+	}
+
+	sdb := state.NewDatabase(db)
+	for number := uint64(0); number <= 20; number++ {
+		root := genesis.StateRoot()
+		if number > 0 {
+			root = blocks[number-1].StateRoot()
+		}
+		_, err := state.New(root, sdb)
+		if keep := number == 0 || number%4 == 0 || number >= 18; keep && err != nil {
+			t.Errorf("state of block %d is pruned", number)
+		} else if !keep && err == nil {
+			t.Errorf("state of block %d is not pruned", number)
+		}
+	}
+
+	for _, tt := range []struct {
+		name    string
+		root    common.Hash
+		balance int64
+		kept    bool
+	}{
+		{"checkpoint", keptPriv, 4, true},
+		{"old", prunedPriv, 6, false},
+		{"recent", recentPriv, 20, true},
+	} {
+		privState, err := state.New(tt.root, state.NewDatabase(db))
+		if !tt.kept {
+			if err == nil {
+				t.Errorf("%s: private state is not pruned", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: private state is pruned", tt.name)
+			continue
+		}
+		if got := privState.GetBalance(privAcc); got.Int64() != tt.balance {
+			t.Errorf("%s: private balance = %v, want %v", tt.name, got, tt.balance)
+		}
+	}
+
+	if _, err := chain.InsertChain(blocks[20:]); err != nil {
+		t.Fatalf("failed to insert chain after pruning: %v", err)
+	}
+}
+
+// Tests that states written while sweeping are kept.
+func TestSweepKeepsWrittenStates(t *testing.T) {
+	var (
+		db   = database.NewMemDatabase()
+		sdb  = state.NewDatabase(db)
+		lock sync.Mutex
+	)
+	commit := func(balance int64) common.Hash {
+		st, _ := state.New(common.Hash{}, sdb)
+		st.SetBalance(common.HexToAddress("0x0100"), big.NewInt(balance))
+		st.SetCode(common.HexToAddress("0x0200"), []byte{byte(balance)})
+		root, err := st.Commit(true)
+		if err != nil {
+			t.Fatalf("failed to commit state: %v", err)
+		}
+		if err := sdb.TrieDB().Commit(root, false); err != nil {
+			t.Fatalf("failed to write state: %v", err)
+		}
+		return root
+	}
+	old, written := commit(1), commit(2)
+
+	pruner := NewStatePruner(db, state.NewDatabase(db), state.NewDatabase(db))
+	deleted, err := pruner.Sweep(&lock, func() []common.Hash {
+		roots := []common.Hash{written}
+		written = common.Hash{}
+		return roots
+	})
+	if err != nil {
+		t.Fatalf("Sweep() error = %v", err)
+	}
+	if deleted == 0 {
+		t.Fatalf("Sweep() deleted nothing")
+	}
+	if _, err := state.New(old, state.NewDatabase(db)); err == nil {
+		t.Errorf("old state is not pruned")
+	}
+	st, err := state.New(commit(2), state.NewDatabase(db))
+	if err != nil {
+		t.Fatalf("written state is pruned")
+	}
+	if code := st.GetCode(common.HexToAddress("0x0200")); !bytes.Equal(code, []byte{2}) {
+		t.Errorf("code of written state = %x, want 02", code)
+	}
+}
+
+func TestNewBlockChainStateRetention(t *testing.T) {
+	db := database.NewMemDatabase()
+	gspec := &Genesis{Config: configs.TestChainConfig}
+	gspec.MustCommit(db)
+
+	cacheConfig := &CacheConfig{TrieNodeLimit: 256 * 1024 * 1024, TrieTimeLimit: time.Minute, StateRetention: MinStateRetention - 1}
+	if _, err := NewBlockChain(db, cacheConfig, configs.TestChainConfig, fakeDpos(db), vm.Config{}, nil, nil); err == nil {
+		t.Fatalf("NewBlockChain() with retention %d succeeded", cacheConfig.StateRetention)
+	}
+	cacheConfig.StateRetention = MinStateRetention
+	chain, err := NewBlockChain(db, cacheConfig, configs.TestChainConfig, fakeDpos(db), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("NewBlockChain() error = %v", err)
+	}
+	chain.Stop()
+}
@@ -64,7 +64,7 @@ type Message interface {
 }
 
 // IntrinsicGas computes the 'intrinsic gas' for a message with the given data.
-func IntrinsicGas(data []byte, contractCreation bool) (uint64, error) {
+func IntrinsicGas(data []byte, contractCreation, isCep2 bool) (uint64, error) {
 	// Set the starting gas for the raw transaction
 	var gas uint64
 	if contractCreation {
@@ -82,10 +82,14 @@ func IntrinsicGas(data []byte, contractCreation bool) (uint64, error) {
 			}
 		}
 		// Make sure we don't exceed uint64 for all data combinations
-		if (math.MaxUint64-gas)/configs.TxDataNonZeroGas < nz {
+		nonZeroGas := configs.TxDataNonZeroGas
+		if isCep2 {
+			nonZeroGas = configs.TxDataNonZeroGasCep2
+		}
+		if (math.MaxUint64-gas)/nonZeroGas < nz {
 			return 0, vm.ErrOutOfGas
 		}
-		gas += nz * configs.TxDataNonZeroGas
+		gas += nz * nonZeroGas
 
 		z := uint64(len(data)) - nz
 		if (math.MaxUint64-gas)/configs.TxDataZeroGas < z {
@@ -180,7 +184,8 @@ func (st *StateTransition) TransitionDb() (ret []byte, usedGas uint64, failed bo
 	contractCreation := msg.To() == nil
 
 	// Pay intrinsic gas
-	gas, err := IntrinsicGas(st.data, contractCreation)
+	cep2 := st.evm.ChainConfig().IsCep2(st.evm.BlockNumber)
+	gas, err := IntrinsicGas(st.data, contractCreation, cep2)
 	if err != nil {
 		return nil, 0, false, err
 	}
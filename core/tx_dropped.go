@@ -0,0 +1,89 @@
+package core
+
+import (
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/gcchains/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TxDropReason is the reason why a transaction is dropped from the pool.
+type TxDropReason string
+
+const (
+	TxDropUnderpriced     TxDropReason = "underpriced"      // discarded for a better priced tx as the pool is full, or below the price threshold
+	TxDropEvicted         TxDropReason = "evicted"          // evicted by the per account or global limits of the pool
+	TxDropNonceTooLow     TxDropReason = "nonce-too-low"    // another tx with the same nonce of the sender is mined
+	TxDropLifetimeExpired TxDropReason = "lifetime-expired" // queued longer than the lifetime of the pool
+	TxDropReplaced        TxDropReason = "replaced"         // replaced by a tx with the same nonce and a bumped price
+	TxDropUnpayable       TxDropReason = "unpayable"        // the balance of the sender or the block gas limit no longer covers it
+)
+
+// DroppedTx is a transaction dropped from the pool.
+type DroppedTx struct {
+	Hash       common.Hash
+	From       common.Address
+	Nonce      uint64
+	GasPrice   *big.Int
+	Reason     TxDropReason
+	Time       time.Time
+	ReplacedBy common.Hash // hash of the replacing tx if the reason is TxDropReplaced
+}
+
+func newDroppedTx(tx *types.Transaction, from common.Address, reason TxDropReason) *DroppedTx {
+	return &DroppedTx{
+		Hash:     tx.Hash(),
+		From:     from,
+		Nonce:    tx.Nonce(),
+		GasPrice: tx.GasPrice(),
+		Reason:   reason,
+		Time:     time.Now(),
+	}
+}
+
+// droppedTxs is a ring buffer of the last dropped transactions, indexed by hash.
+type droppedTxs struct {
+	ring  []*DroppedTx
+	next  int
+	index map[common.Hash]*DroppedTx
+	lock  sync.RWMutex
+}
+
+func newDroppedTxs(size uint64) *droppedTxs {
+	return &droppedTxs{
+		ring:  make([]*DroppedTx, size),
+		index: make(map[common.Hash]*DroppedTx),
+	}
+}
+
+// Add records a dropped transaction, overwriting the oldest one if the buffer is full.
+func (d *droppedTxs) Add(dropped *DroppedTx) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	// a tx may be dropped again after being reinjected, keep the index of the latest one
+	if old := d.ring[d.next]; old != nil && d.index[old.Hash] == old {
+		delete(d.index, old.Hash)
+	}
+	d.ring[d.next] = dropped
+	d.index[dropped.Hash] = dropped
+	d.next = (d.next + 1) % len(d.ring)
+}
+
+// Get returns the dropped transaction of the hash, or nil if it is unknown or overwritten.
+func (d *droppedTxs) Get(hash common.Hash) *DroppedTx {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	return d.index[hash]
+}
+
+// Count returns the number of dropped transactions in the buffer.
+func (d *droppedTxs) Count() int {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	return len(d.index)
+}
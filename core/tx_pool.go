@@ -65,6 +65,13 @@ var (
 
 	// ErrExceedQueueMapSize is returned if exceed txpool.queue map size
 	ErrExceedQueueMapSize = errors.New("exceeds queue map size")
+
+	// ErrTxPoolFull is returned if the fifo txpool is full.
+	ErrTxPoolFull = errors.New("txpool is full")
+
+	// ErrAccountQuotaExceeded is returned if a remote account already has as many
+	// transactions in the pool as its quota.
+	ErrAccountQuotaExceeded = errors.New("exceeds account quota")
 )
 
 var (
@@ -128,6 +135,9 @@ type TxPoolConfig struct {
 	IsFifoTxQueue bool   // Use fifo queue for txs queue, not priced heap
 
 	Lifetime time.Duration // Maximum amount of time non-executable transaction are queued
+
+	AccountQuota uint64 // Maximum number of pending and queued transactions of a remote account (0 = no quota)
+	DroppedTxs   uint64 // Number of the last dropped transactions remembered with their reasons
 }
 
 // DefaultTxPoolConfig contains the default configurations for the transaction
@@ -145,6 +155,9 @@ var DefaultTxPoolConfig = TxPoolConfig{
 	GlobalQueue:  8192,
 	MaxTxMapSize: 2048 * 16,
 	Lifetime:     3 * time.Hour,
+
+	AccountQuota: 2048,
+	DroppedTxs:   4096,
 }
 
 var DeprecatedDefaultTxPoolConfig = TxPoolConfig{
@@ -160,6 +173,8 @@ var DeprecatedDefaultTxPoolConfig = TxPoolConfig{
 	GlobalQueue:  8192,
 	MaxTxMapSize: 1024,
 	Lifetime:     3 * time.Hour,
+
+	DroppedTxs: 4096,
 }
 
 // sanitize checks the provided user configurations and changes anything that's
@@ -204,6 +219,10 @@ func (config *TxPoolConfig) sanitize() TxPoolConfig {
 		log.Warn("Sanitizing invalid txpool lifetime", "provided", conf.Lifetime, "updated", DefaultTxPoolConfig.Lifetime)
 		conf.Lifetime = DefaultTxPoolConfig.Lifetime
 	}
+	if conf.DroppedTxs < 1 {
+		log.Warn("Sanitizing invalid txpool dropped txs", "provided", conf.DroppedTxs, "updated", DefaultTxPoolConfig.DroppedTxs)
+		conf.DroppedTxs = DefaultTxPoolConfig.DroppedTxs
+	}
 
 	return conf
 }
@@ -221,6 +240,7 @@ type TxPool struct {
 	chain        blockChain
 	gasPrice     *big.Int
 	txFeed       event.Feed
+	droppedFeed  event.Feed
 	scope        event.SubscriptionScope
 	chainHeadCh  chan ChainHeadEvent
 	chainHeadSub event.Subscription
@@ -230,6 +250,8 @@ type TxPool struct {
 	currentState  *state.StateDB      // Current state in the blockchain head
 	pendingState  *state.ManagedState // Pending state tracking virtual nonces
 	currentMaxGas uint64              // Current gas limit for transaction caps
+	cep2          bool                // Fork indicator whether we are in the Cep2 stage
+	nextNumber    *big.Int            // Number of the next block, whose rules txs are validated against
 
 	locals  *accountSet // Set of local transaction to exempt from eviction rules
 	journal *txJournal  // Journal of local transaction to back up to disk
@@ -241,6 +263,10 @@ type TxPool struct {
 	all    *txLookup                    // All transactions to allow lookups
 	priced *txPricedList                // All transactions sorted by price
 
+	dropped      *droppedTxs              // Last dropped transactions with their reasons
+	droppedBatch []*DroppedTx             // Transactions dropped since the last notification
+	mined        map[common.Hash]struct{} // Transactions included by the blocks of the last reset
+
 	wg sync.WaitGroup // for shutdown sync
 }
 
@@ -301,6 +327,7 @@ func NewTxPool(config TxPoolConfig, chainconfig *configs.ChainConfig, chain bloc
 		queue:       make(map[common.Address]*txList),
 		beats:       make(map[common.Address]time.Time),
 		all:         newTxLookup(),
+		dropped:     newDroppedTxs(config.DroppedTxs),
 		chainHeadCh: make(chan ChainHeadEvent, chainHeadChanSize),
 		gasPrice:    new(big.Int).SetUint64(config.PriceLimit),
 	}
@@ -362,6 +389,7 @@ func (pool *TxPool) loop() {
 				pool.mu.Lock()
 				pool.reset(head.Header(), ev.Block.Header())
 				head = ev.Block
+				pool.notifyDropped()
 
 				pool.mu.Unlock()
 			}
@@ -392,10 +420,11 @@ func (pool *TxPool) loop() {
 				// Any non-locals old enough should be removed
 				if time.Since(pool.beats[addr]) > pool.config.Lifetime {
 					for _, tx := range pool.getQueueTxList(addr).Flatten() {
-						pool.removeTx(tx.Hash(), true)
+						pool.dropTx(tx.Hash(), true, TxDropLifetimeExpired)
 					}
 				}
 			}
+			pool.notifyDropped()
 			pool.mu.Unlock()
 
 		// Handle local transaction journal rotation
@@ -437,6 +466,7 @@ func (pool *TxPool) lockedReset(oldHead, newHead *types.Header) {
 	defer pool.mu.Unlock()
 
 	pool.reset(oldHead, newHead)
+	pool.notifyDropped()
 }
 
 // reset retrieves the current state of the blockchain and ensures the content
@@ -445,6 +475,15 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 	// If we're reorging an old state, reinject all dropped transactions
 	var reinject types.Transactions
 
+	// Track the txs of the new blocks not to record them as dropped once they are removed as stale,
+	// they are unknown if the reorg is skipped
+	pool.mined = nil
+	if oldHead != nil && oldHead.Hash() == newHead.ParentHash {
+		if block := pool.chain.GetBlock(newHead.Hash(), newHead.Number.Uint64()); block != nil {
+			pool.markMined(block.Transactions())
+		}
+	}
+
 	if oldHead != nil && oldHead.Hash() != newHead.ParentHash {
 		// If the reorg is too deep, avoid doing it (will happen during fast sync)
 		oldNum := oldHead.Number.Uint64()
@@ -487,6 +526,7 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 				}
 			}
 			reinject = types.TxDifference(discarded, included)
+			pool.markMined(included)
 		}
 	}
 	// Initialize the internal state to the current head
@@ -502,6 +542,11 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 	pool.pendingState = state.ManageState(statedb)
 	pool.currentMaxGas = newHead.GasLimit
 
+	// txs in the pool are validated against the rules of the next block
+	next := new(big.Int).Add(newHead.Number, big.NewInt(1))
+	pool.cep2 = pool.chainconfig.IsCep2(next)
+	pool.nextNumber = next
+
 	// Inject any transactions discarded due to reorgs
 	log.Debug("Reinjecting stale transactions", "count", len(reinject))
 	senderCacher.recover(pool.signer, reinject)
@@ -523,6 +568,16 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 	pool.promoteExecutables(nil)
 }
 
+// markMined marks the txs as included by the blocks of the reset.
+func (pool *TxPool) markMined(txs types.Transactions) {
+	if pool.mined == nil {
+		pool.mined = make(map[common.Hash]struct{}, len(txs))
+	}
+	for _, tx := range txs {
+		pool.mined[tx.Hash()] = struct{}{}
+	}
+}
+
 // Stop terminates the transaction pool.
 func (pool *TxPool) Stop() {
 	// Unsubscribe all subscriptions registered from txpool
@@ -544,6 +599,29 @@ func (pool *TxPool) SubscribeNewTxsEvent(ch chan<- NewTxsEvent) event.Subscripti
 	return pool.scope.Track(pool.txFeed.Subscribe(ch))
 }
 
+// SubscribeDroppedTxsEvent registers a subscription of DroppedTxsEvent and
+// starts sending event to the given channel.
+func (pool *TxPool) SubscribeDroppedTxsEvent(ch chan<- DroppedTxsEvent) event.Subscription {
+	return pool.scope.Track(pool.droppedFeed.Subscribe(ch))
+}
+
+// Dropped returns the transaction of the hash if it is one of the last dropped
+// transactions, and nil otherwise.
+func (pool *TxPool) Dropped(hash common.Hash) *DroppedTx {
+	return pool.dropped.Get(hash)
+}
+
+// ReplacementPrice returns the minimum gas price of a transaction to replace the
+// given one, i.e. its price bumped by PriceBump percent.
+func (pool *TxPool) ReplacementPrice(tx *types.Transaction) *big.Int {
+	price := new(big.Int).Mul(tx.GasPrice(), big.NewInt(100+int64(pool.config.PriceBump)))
+	price.Div(price, big.NewInt(100))
+	if price.Cmp(tx.GasPrice()) <= 0 {
+		price.Add(tx.GasPrice(), common.Big1)
+	}
+	return price
+}
+
 // GasPrice returns the current gas price enforced by the transaction pool.
 func (pool *TxPool) GasPrice() *big.Int {
 	pool.mu.RLock()
@@ -560,8 +638,9 @@ func (pool *TxPool) SetGasPrice(price *big.Int) {
 
 	pool.gasPrice = price
 	for _, tx := range pool.priced.Cap(price, pool.locals) {
-		pool.removeTx(tx.Hash(), false)
+		pool.dropTx(tx.Hash(), false, TxDropUnderpriced)
 	}
+	pool.notifyDropped()
 	log.Info("Transaction pool price threshold updated", "price", price)
 }
 
@@ -650,7 +729,7 @@ func (pool *TxPool) local() map[common.Address]types.Transactions {
 // rules and adheres to some heuristic limits of the local node (price and size).
 func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	// Return early if the tx type is not supported!
-	if !types.SupportTxType(tx.Type()) {
+	if !types.SupportTxType(pool.chainconfig, pool.nextNumber, tx.Type()) {
 		return types.ErrNotSupportedTxType
 	}
 	// Heuristic limit, reject transactions over 32KB to prevent DOS attacks
@@ -685,7 +764,7 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	if pool.currentState.GetBalance(from).Cmp(tx.Cost()) < 0 {
 		return ErrInsufficientFunds
 	}
-	intrGas, err := IntrinsicGas(tx.Data(), tx.To() == nil)
+	intrGas, err := IntrinsicGas(tx.Data(), tx.To() == nil, pool.cep2)
 	if err != nil {
 		return err
 	}
@@ -707,7 +786,7 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 	// If IsFifoTxQueue is true and the txpool is full, just ignore the tx.
 	if pool.config.IsFifoTxQueue && uint64(pool.all.Count()) >= pool.config.GlobalSlots+pool.config.GlobalQueue {
 		log.Debug("txpool is full")
-		return false, fmt.Errorf("txpool is full")
+		return false, ErrTxPoolFull
 	}
 
 	// If the transaction is already known, discard it
@@ -722,6 +801,13 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 		invalidTxCounter.Inc(1)
 		return false, err
 	}
+	from, _ := types.Sender(pool.signer, tx) // already validated
+	// Remote accounts are limited by the quota unless the transaction is a replacement, checked before
+	// making room for the transaction so that an account at its quota evicts nothing
+	if !local && !pool.locals.contains(from) && pool.exceedsQuota(from, tx) {
+		log.Debug("Discarding transaction exceeding account quota", "hash", hash.Hex(), "from", from)
+		return false, ErrAccountQuotaExceeded
+	}
 	// If the transaction pool is full, discard underpriced transactions
 	log.Debug("txPoolLen", "len", pool.all.Count())
 	if uint64(pool.all.Count()) >= pool.config.GlobalSlots+pool.config.GlobalQueue {
@@ -738,11 +824,10 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 		for _, tx := range drop {
 			log.Debug("Discarding freshly underpriced transaction", "hash", tx.Hash().Hex(), "price", tx.GasPrice())
 			underpricedTxCounter.Inc(1)
-			pool.removeTx(tx.Hash(), false)
+			pool.dropTx(tx.Hash(), false, TxDropUnderpriced)
 		}
 	}
 	// If the transaction is replacing an already pending one, do directly
-	from, _ := types.Sender(pool.signer, tx) // already validated
 	if list := pool.getPendingTxList(from); list != nil && list.Overlaps(tx) {
 		// Nonce already pending, check if required price bump is met
 		inserted, old := list.Add(tx, pool.config.PriceBump)
@@ -752,6 +837,7 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 		}
 		// New transaction is better, replace old one
 		if old != nil {
+			pool.recordDropped(old, TxDropReplaced).ReplacedBy = hash
 			pool.all.Remove(old.Hash())
 			pool.priced.Removed()
 			pendingReplaceCounter.Inc(1)
@@ -802,6 +888,7 @@ func (pool *TxPool) enqueueTx(hash common.Hash, tx *types.Transaction) (bool, er
 	}
 	// Discard any previous transaction and mark this
 	if old != nil {
+		pool.recordDropped(old, TxDropReplaced).ReplacedBy = hash
 		pool.all.Remove(old.Hash())
 		pool.priced.Removed()
 		queuedReplaceCounter.Inc(1)
@@ -842,6 +929,7 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 	inserted, old := list.Add(tx, pool.config.PriceBump)
 	if !inserted {
 		// An older transaction was better, discard this
+		pool.recordDropped(tx, TxDropUnderpriced)
 		pool.all.Remove(hash)
 		pool.priced.Removed()
 
@@ -850,6 +938,7 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 	}
 	// Otherwise discard any previous transaction and mark this
 	if old != nil {
+		pool.recordDropped(old, TxDropReplaced).ReplacedBy = hash
 		pool.all.Remove(old.Hash())
 		pool.priced.Removed()
 
@@ -899,6 +988,7 @@ func (pool *TxPool) AddRemotes(txs []*types.Transaction) []error {
 func (pool *TxPool) addTx(tx *types.Transaction, local bool) error {
 	pool.mu.Lock()
 	defer pool.mu.Unlock()
+	defer pool.notifyDropped()
 
 	// Try to inject the transaction and update any state
 	start := time.Now()
@@ -921,6 +1011,7 @@ func (pool *TxPool) addTx(tx *types.Transaction, local bool) error {
 func (pool *TxPool) addTxs(txs []*types.Transaction, local bool) []error {
 	pool.mu.Lock()
 	defer pool.mu.Unlock()
+	defer pool.notifyDropped()
 
 	return pool.addTxsLocked(txs, local)
 }
@@ -976,6 +1067,71 @@ func (pool *TxPool) Get(hash common.Hash) *types.Transaction {
 	return pool.all.Get(hash)
 }
 
+// exceedsQuota returns whether the new transaction is over the quota of the
+// account, replacements of its transactions are always allowed.
+func (pool *TxPool) exceedsQuota(addr common.Address, tx *types.Transaction) bool {
+	if pool.config.AccountQuota == 0 {
+		return false
+	}
+	count := 0
+	if list := pool.getPendingTxList(addr); list != nil {
+		if list.Overlaps(tx) {
+			return false
+		}
+		count += list.Len()
+	}
+	if list := pool.getQueueTxList(addr); list != nil {
+		if list.Overlaps(tx) {
+			return false
+		}
+		count += list.Len()
+	}
+	return uint64(count) >= pool.config.AccountQuota
+}
+
+// recordDropped records a transaction dropped from the pool, subscribers are
+// notified of it by notifyDropped.
+func (pool *TxPool) recordDropped(tx *types.Transaction, reason TxDropReason) *DroppedTx {
+	from, _ := types.Sender(pool.signer, tx) // already validated during insertion
+	dropped := newDroppedTx(tx, from, reason)
+	pool.dropped.Add(dropped)
+	pool.droppedBatch = append(pool.droppedBatch, dropped)
+	return dropped
+}
+
+// notifyDropped notifies subscribers of the transactions dropped since the last
+// notification.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) notifyDropped() {
+	if len(pool.droppedBatch) == 0 {
+		return
+	}
+	go pool.droppedFeed.Send(DroppedTxsEvent{pool.droppedBatch})
+	pool.droppedBatch = nil
+}
+
+// dropTx records a transaction as dropped for the reason and removes it.
+func (pool *TxPool) dropTx(hash common.Hash, outofbound bool, reason TxDropReason) {
+	if tx := pool.all.Get(hash); tx != nil {
+		pool.recordDropped(tx, reason)
+		pool.removeTx(hash, outofbound)
+	}
+}
+
+// dropStale removes a transaction with a nonce lower than the one of its sender,
+// recording it as dropped unless it is included by the blocks of the reset.
+func (pool *TxPool) dropStale(tx *types.Transaction) {
+	hash := tx.Hash()
+	if pool.mined != nil {
+		if _, ok := pool.mined[hash]; !ok {
+			pool.recordDropped(tx, TxDropNonceTooLow)
+		}
+	}
+	pool.all.Remove(hash)
+	pool.priced.Removed()
+}
+
 // removeTx removes a single transaction from the queue, moving all subsequent
 // transactions back to the future queue.
 func (pool *TxPool) removeTx(hash common.Hash, outofbound bool) {
@@ -1045,10 +1201,8 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 			log.Debug("Removed old queued transaction", "old queued tx len", oldQueuedTransaction.Len())
 		}
 		for _, tx := range oldQueuedTransaction {
-			hash := tx.Hash()
-			log.Debug("Removed old queued transaction", "hash", hash.Hex())
-			pool.all.Remove(hash)
-			pool.priced.Removed()
+			log.Debug("Removed old queued transaction", "hash", tx.Hash().Hex())
+			pool.dropStale(tx)
 		}
 		// Drop all transactions that are too costly (low balance or out of gas)
 		drops, _ := list.Filter(pool.currentState.GetBalance(addr), pool.currentMaxGas)
@@ -1058,6 +1212,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 		for _, tx := range drops {
 			hash := tx.Hash()
 			log.Debug("Removed unpayable queued transaction", "hash", hash.Hex())
+			pool.recordDropped(tx, TxDropUnpayable)
 			pool.all.Remove(hash)
 			pool.priced.Removed()
 			queuedNofundsCounter.Inc(1)
@@ -1083,6 +1238,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 
 			for _, tx := range capExceedingTxToRemove {
 				hash := tx.Hash()
+				pool.recordDropped(tx, TxDropEvicted)
 				pool.all.Remove(hash)
 				pool.priced.Removed()
 				queuedRateLimitCounter.Inc(1)
@@ -1132,6 +1288,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 						for _, tx := range list.Cap(list.Len() - 1) {
 							// Drop the transaction from the global pools too
 							hash := tx.Hash()
+							pool.recordDropped(tx, TxDropEvicted)
 							pool.all.Remove(hash)
 							pool.priced.Removed()
 
@@ -1154,6 +1311,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 					for _, tx := range list.Cap(list.Len() - 1) {
 						// Drop the transaction from the global pools too
 						hash := tx.Hash()
+						pool.recordDropped(tx, TxDropEvicted)
 						pool.all.Remove(hash)
 						pool.priced.Removed()
 
@@ -1194,7 +1352,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 			// Drop all transactions if they are less than the overflow
 			if size := uint64(list.Len()); size <= drop {
 				for _, tx := range list.Flatten() {
-					pool.removeTx(tx.Hash(), true)
+					pool.dropTx(tx.Hash(), true, TxDropEvicted)
 				}
 				drop -= size
 				queuedRateLimitCounter.Inc(int64(size))
@@ -1203,7 +1361,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 			// Otherwise drop only last few transactions
 			txs := list.Flatten()
 			for i := len(txs) - 1; i >= 0 && drop > 0; i-- {
-				pool.removeTx(txs[i].Hash(), true)
+				pool.dropTx(txs[i].Hash(), true, TxDropEvicted)
 				drop--
 				queuedRateLimitCounter.Inc(1)
 			}
@@ -1221,16 +1379,15 @@ func (pool *TxPool) demoteUnexecutables() {
 
 		// Drop all transactions that are deemed too old (low nonce)
 		for _, tx := range list.Forward(nonce) {
-			hash := tx.Hash()
-			log.Debug("Removed old pending transaction", "hash", hash.Hex())
-			pool.all.Remove(hash)
-			pool.priced.Removed()
+			log.Debug("Removed old pending transaction", "hash", tx.Hash().Hex())
+			pool.dropStale(tx)
 		}
 		// Drop all transactions that are too costly (low balance or out of gas), and queue any invalids back for later
 		drops, invalids := list.Filter(pool.currentState.GetBalance(addr), pool.currentMaxGas)
 		for _, tx := range drops {
 			hash := tx.Hash()
 			log.Debug("Removed unpayable pending transaction", "hash", hash.Hex())
+			pool.recordDropped(tx, TxDropUnpayable)
 			pool.all.Remove(hash)
 			pool.priced.Removed()
 			pendingNofundsCounter.Inc(1)
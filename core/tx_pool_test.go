@@ -1725,6 +1725,251 @@ func TestTransactionStatusCheck(t *testing.T) {
 	}
 }
 
+// Tests that remote accounts can't hold more transactions than their quota,
+// while replacements and local accounts are not limited.
+func TestTransactionAccountQuota(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(database.NewMemDatabase()))
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	config := testTxPoolConfig
+	config.AccountQuota = 4
+
+	pool := NewTxPool(config, configs.TestChainConfig, blockchain)
+	defer pool.Stop()
+
+	remote, _ := crypto.GenerateKey()
+	local, _ := crypto.GenerateKey()
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(remote.PublicKey), big.NewInt(1000000000))
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(local.PublicKey), big.NewInt(1000000000))
+
+	// Fill the quota with pending and queued transactions
+	for _, nonce := range []uint64{0, 1, 5, 6} {
+		if err := pool.AddRemote(transaction(nonce, 100000, remote)); err != nil {
+			t.Fatalf("failed to add transaction %d: %v", nonce, err)
+		}
+	}
+	if err := pool.AddRemote(transaction(2, 100000, remote)); err != ErrAccountQuotaExceeded {
+		t.Fatalf("quota exceeding transaction error mismatch: have %v, want %v", err, ErrAccountQuotaExceeded)
+	}
+	if err := pool.AddRemote(pricedTransaction(1, 100000, big.NewInt(2), remote)); err != nil {
+		t.Fatalf("failed to replace pending transaction: %v", err)
+	}
+	if err := pool.AddRemote(pricedTransaction(6, 100000, big.NewInt(2), remote)); err != nil {
+		t.Fatalf("failed to replace queued transaction: %v", err)
+	}
+	for nonce := uint64(0); nonce < 5; nonce++ {
+		if err := pool.AddLocal(transaction(nonce, 100000, local)); err != nil {
+			t.Fatalf("failed to add local transaction %d: %v", nonce, err)
+		}
+	}
+	pending, queued := pool.Stats()
+	if pending != 7 {
+		t.Fatalf("pending transactions mismatched: have %d, want %d", pending, 7)
+	}
+	if queued != 2 {
+		t.Fatalf("queued transactions mismatched: have %d, want %d", queued, 2)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that a remote account at its quota can't evict the transactions of
+// other accounts from a full pool by paying a higher price.
+func TestTransactionAccountQuotaFullPool(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(database.NewMemDatabase()))
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	config := testTxPoolConfig
+	config.GlobalSlots = 2
+	config.GlobalQueue = 2
+	config.AccountQuota = 2
+
+	pool := NewTxPool(config, configs.TestChainConfig, blockchain)
+	defer pool.Stop()
+
+	remote, _ := crypto.GenerateKey()
+	other, _ := crypto.GenerateKey()
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(remote.PublicKey), big.NewInt(1000000000))
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(other.PublicKey), big.NewInt(1000000000))
+
+	// Fill the pool, the remote account up to its quota
+	txs := types.Transactions{
+		pricedTransaction(0, 100000, big.NewInt(1), remote),
+		pricedTransaction(1, 100000, big.NewInt(1), remote),
+		pricedTransaction(0, 100000, big.NewInt(1), other),
+		pricedTransaction(1, 100000, big.NewInt(1), other),
+	}
+	for i, err := range pool.AddRemotes(txs) {
+		if err != nil {
+			t.Fatalf("failed to add transaction %d: %v", i, err)
+		}
+	}
+	if err := pool.AddRemote(pricedTransaction(2, 100000, big.NewInt(10), remote)); err != ErrAccountQuotaExceeded {
+		t.Fatalf("quota exceeding transaction error mismatch: have %v, want %v", err, ErrAccountQuotaExceeded)
+	}
+	for i, tx := range txs {
+		if pool.all.Get(tx.Hash()) == nil {
+			t.Errorf("transaction %d evicted by a transaction exceeding the quota", i)
+		}
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that dropped transactions are recorded with their reasons and announced
+// to the subscribers.
+func TestTransactionDropReasons(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(database.NewMemDatabase()))
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	config := testTxPoolConfig
+	config.AccountQueue = 2
+
+	pool := NewTxPool(config, configs.TestChainConfig, blockchain)
+	defer pool.Stop()
+
+	events := make(chan DroppedTxsEvent, 32)
+	sub := pool.SubscribeDroppedTxsEvent(events)
+	defer sub.Unsubscribe()
+
+	keys := make([]*ecdsa.PrivateKey, 3)
+	for i := 0; i < len(keys); i++ {
+		keys[i], _ = crypto.GenerateKey()
+		pool.currentState.AddBalance(crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(1000000000))
+	}
+	var (
+		replaced    = pricedTransaction(0, 100000, big.NewInt(1), keys[0])
+		replacement = pricedTransaction(0, 100000, big.NewInt(2), keys[0])
+		underpriced = pricedTransaction(1, 100000, big.NewInt(2), keys[0])
+		evicted     = pricedTransaction(5, 100000, big.NewInt(3), keys[1])
+		stale       = pricedTransaction(0, 100000, big.NewInt(3), keys[2])
+		mined       = pricedTransaction(1, 100000, big.NewInt(3), keys[2])
+	)
+	pool.AddRemotes(types.Transactions{
+		replaced, underpriced, stale, mined,
+		pricedTransaction(3, 100000, big.NewInt(3), keys[1]),
+		pricedTransaction(4, 100000, big.NewInt(3), keys[1]),
+	})
+	// Replace a pending transaction, evict a queued one over the account limit and drop a cheap one by the price threshold
+	if err := pool.AddRemote(replacement); err != nil {
+		t.Fatalf("failed to replace pending transaction: %v", err)
+	}
+	if err := pool.AddRemote(evicted); err != nil {
+		t.Fatalf("failed to add queued transaction: %v", err)
+	}
+	pool.SetGasPrice(big.NewInt(3))
+
+	// Include transactions in a block, only the stale one of them not mined is dropped
+	pool.mu.Lock()
+	pool.currentState.SetNonce(crypto.PubkeyToAddress(keys[2].PublicKey), 2)
+	pool.markMined(types.Transactions{mined})
+	pool.demoteUnexecutables()
+	pool.notifyDropped()
+	pool.mu.Unlock()
+
+	want := map[common.Hash]TxDropReason{
+		replaced.Hash():    TxDropReplaced,
+		underpriced.Hash(): TxDropUnderpriced,
+		evicted.Hash():     TxDropEvicted,
+		stale.Hash():       TxDropNonceTooLow,
+	}
+	for hash, reason := range want {
+		dropped := pool.Dropped(hash)
+		if dropped == nil {
+			t.Errorf("transaction %x not recorded as dropped", hash)
+			continue
+		}
+		if dropped.Reason != reason {
+			t.Errorf("transaction %x drop reason mismatch: have %v, want %v", hash, dropped.Reason, reason)
+		}
+	}
+	if dropped := pool.Dropped(replaced.Hash()); dropped != nil && dropped.ReplacedBy != replacement.Hash() {
+		t.Errorf("replacing transaction mismatch: have %x, want %x", dropped.ReplacedBy, replacement.Hash())
+	}
+	if dropped := pool.Dropped(mined.Hash()); dropped != nil {
+		t.Errorf("mined transaction recorded as dropped: %v", dropped.Reason)
+	}
+	// Ensure all the drops are announced
+	announced := make(map[common.Hash]TxDropReason)
+	for len(announced) < len(want) {
+		select {
+		case ev := <-events:
+			for _, dropped := range ev.Txs {
+				announced[dropped.Hash] = dropped.Reason
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("dropped transactions not announced: have %v, want %v", announced, want)
+		}
+	}
+	for hash, reason := range want {
+		if announced[hash] != reason {
+			t.Errorf("transaction %x announced drop reason mismatch: have %v, want %v", hash, announced[hash], reason)
+		}
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that the buffer of dropped transactions only keeps the last ones.
+func TestDroppedTxsOverwrite(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+
+	dropped := newDroppedTxs(2)
+	txs := []*types.Transaction{transaction(0, 100000, key), transaction(1, 100000, key), transaction(2, 100000, key)}
+	for _, tx := range txs {
+		dropped.Add(newDroppedTx(tx, from, TxDropEvicted))
+	}
+	if dropped.Get(txs[0].Hash()) != nil {
+		t.Errorf("oldest dropped transaction not overwritten")
+	}
+	for _, tx := range txs[1:] {
+		if dropped.Get(tx.Hash()) == nil {
+			t.Errorf("dropped transaction %d missing", tx.Nonce())
+		}
+	}
+	// A transaction dropped again is kept until its latest record is overwritten
+	dropped.Add(newDroppedTx(txs[1], from, TxDropUnderpriced))
+	if d := dropped.Get(txs[1].Hash()); d == nil || d.Reason != TxDropUnderpriced {
+		t.Errorf("transaction dropped again mismatch: have %v, want reason %v", d, TxDropUnderpriced)
+	}
+	if count := dropped.Count(); count != 2 {
+		t.Errorf("dropped transactions count mismatch: have %d, want %d", count, 2)
+	}
+}
+
+// Tests that the replacement price is bumped by PriceBump percent, and by one at
+// least for ultra low prices.
+func TestTransactionReplacementPrice(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	tests := []struct {
+		price int64
+		want  int64
+	}{
+		{1, 2},
+		{5, 6},
+		{100, 100 * (100 + int64(testTxPoolConfig.PriceBump)) / 100},
+	}
+	for _, tt := range tests {
+		if got := pool.ReplacementPrice(pricedTransaction(0, 100000, big.NewInt(tt.price), key)); got.Int64() != tt.want {
+			t.Errorf("replacement price of %d mismatch: have %v, want %v", tt.price, got, tt.want)
+		}
+	}
+}
+
 // Benchmarks the speed of validating the contents of the pending queue of the
 // transaction pool.
 func BenchmarkPendingDemotion100(b *testing.B)   { benchmarkPendingDemotion(b, 100) }
@@ -45,6 +45,27 @@ func RegisterPrimitiveContract(address common.Address, contract PrimitiveContrac
 	}
 }
 
+// primitiveContractForks maps the primitive contracts added by forks to their forks, such a contract
+// is an empty account before the fork is activated.
+var primitiveContractForks = make(map[common.Address]configs.Fork)
+
+// RegisterForkPrimitiveContract registers a primitive contract activated by the fork.
+func RegisterForkPrimitiveContract(address common.Address, contract PrimitiveContract, fork configs.Fork) error {
+	if err := RegisterPrimitiveContract(address, contract); err != nil {
+		return err
+	}
+	primitiveContractForks[address] = fork
+	return nil
+}
+
+// primitiveContract returns the primitive contract at the address if it is active in the block of the EVM.
+func (evm *EVM) primitiveContract(addr common.Address) PrimitiveContract {
+	if fork, ok := primitiveContractForks[addr]; ok && !evm.chainConfig.IsForked(fork, evm.BlockNumber) {
+		return nil
+	}
+	return PrimitiveContracts[addr]
+}
+
 // RunPrecompiledContract runs and evaluates the output of a precompiled contract.
 func RunPrecompiledContract(p PrimitiveContract, input []byte, contract *Contract) (ret []byte, err error) {
 	gas := p.RequiredGas(input)
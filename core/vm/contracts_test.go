@@ -7,6 +7,7 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/gcchains/chain/configs"
 	"github.com/ethereum/go-ethereum/common"
 )
 
@@ -467,3 +468,29 @@ func BenchmarkPrecompiledBn256Pairing(bench *testing.B) {
 		benchmarkPrecompiled("08", test, bench)
 	}
 }
+
+// Tests that a primitive contract added by a fork is an empty account before the fork.
+func TestForkPrimitiveContract(t *testing.T) {
+	addr := common.BytesToAddress([]byte{0xff})
+	if err := RegisterForkPrimitiveContract(addr, &dataCopy{}, configs.Cep2); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		delete(PrimitiveContracts, addr)
+		delete(primitiveContractForks, addr)
+	}()
+
+	config := &configs.ChainConfig{Forks: configs.ForkSchedule{configs.Cep2: 10}}
+	for _, test := range []struct {
+		number uint64
+		active bool
+	}{{9, false}, {10, true}, {11, true}} {
+		evm := NewEVM(Context{BlockNumber: new(big.Int).SetUint64(test.number)}, nil, config, Config{})
+		if active := evm.primitiveContract(addr) != nil; active != test.active {
+			t.Errorf("primitive contract active at block %d = %v, want %v", test.number, active, test.active)
+		}
+	}
+	if evm := NewEVM(Context{BlockNumber: big.NewInt(0)}, nil, config, Config{}); evm.primitiveContract(common.BytesToAddress([]byte{1})) == nil {
+		t.Error("ecrecover is not active without a fork")
+	}
+}
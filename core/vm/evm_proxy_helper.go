@@ -4,9 +4,9 @@ package vm
 
 import (
 	"fmt"
-	"time"
+	"math/big"
+	"sync"
 
-	"github.com/gcchains/chain/commons/cache"
 	"github.com/gcchains/chain/commons/log"
 	"github.com/gcchains/chain/configs"
 	"github.com/ethereum/go-ethereum/common"
@@ -14,58 +14,182 @@ import (
 )
 
 var (
-	forceLookupTTL      = 5 * time.Second
-	emptyAddress        = common.Address{}
-	contractLookupCache = cache.NewLRUExpireCache(200)
+	emptyAddress = common.Address{}
+
+	// proxyContractEnabled is whether proxy contracts are resolved, configs.EnableProxyContract unless in tests
+	proxyContractEnabled = configs.EnableProxyContract
 )
 
-// GetRealContractAddress get real logic contract address by proxy contract address
+// ProxyResolution is a proxy contract resolved to its real logic contract during an execution.
+type ProxyResolution struct {
+	Proxy   common.Address `json:"proxy"`
+	Real    common.Address `json:"real"`
+	Version *uint64        `json:"version,omitempty"` // version of the register resolved as of, nil for the current one
+	Depth   int            `json:"depth"`             // depth of the call to the proxy, as in the struct logs
+}
+
+// ProxyCache caches the real contracts of proxy contracts while processing a block. It is not bound to wall time,
+// all entries are dropped when the storage of the register is written or the state is reverted, so every node
+// resolves the same proxy to the same contract at the same point of a block.
+//
+// A cache must only be shared by the EVMs executing the transactions of a block, in order, on the same state.
+type ProxyCache struct {
+	lock    sync.Mutex
+	entries map[common.Address]common.Address
+}
+
+// NewProxyCache returns an empty proxy cache.
+func NewProxyCache() *ProxyCache {
+	return &ProxyCache{
+		entries: make(map[common.Address]common.Address),
+	}
+}
+
+// Get returns the real contract cached for the proxy contract.
+func (c *ProxyCache) Get(proxy common.Address) (common.Address, bool) {
+	if c == nil {
+		return emptyAddress, false
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	real, ok := c.entries[proxy]
+	return real, ok
+}
+
+// Add caches the real contract of the proxy contract.
+func (c *ProxyCache) Add(proxy, real common.Address) {
+	if c == nil {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.entries[proxy] = real
+}
+
+// Reset drops all the entries.
+func (c *ProxyCache) Reset() {
+	if c == nil {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if len(c.entries) > 0 {
+		c.entries = make(map[common.Address]common.Address)
+	}
+}
+
+// proxyRegister returns the address of the proxy contract register, if proxy contracts are enabled.
+func (evm *EVM) proxyRegister() (common.Address, bool) {
+	if !proxyContractEnabled {
+		return emptyAddress, false
+	}
+	if dc := evm.chainConfig.Dpos; dc != nil && dc.ProxyContractRegister != emptyAddress {
+		return dc.ProxyContractRegister, true
+	}
+	return emptyAddress, false
+}
+
+// ProxyResolutions returns the proxy contracts resolved to real ones by the EVM so far, in order.
+func (evm *EVM) ProxyResolutions() []ProxyResolution {
+	return evm.proxyResolutions
+}
+
+// onStorageWritten drops the cached real contracts when the storage of the register is written. The slots of a
+// proxy in the register are not known here, so all of them are dropped.
+func (evm *EVM) onStorageWritten(addr common.Address) {
+	if evm.vmConfig.ProxyCache == nil {
+		return
+	}
+	if register, ok := evm.proxyRegister(); ok && addr == register {
+		evm.vmConfig.ProxyCache.Reset()
+	}
+}
+
+// revertToSnapshot reverts the state, dropping the cached proxy resolutions as they may be read from the state
+// reverted.
+func (evm *EVM) revertToSnapshot(snapshot int) {
+	evm.StateDB.RevertToSnapshot(snapshot)
+	evm.vmConfig.ProxyCache.Reset()
+}
+
+// GetRealContractAddress get real logic contract address by proxy contract address. The address is resolved by the
+// state of the register, as of the version pinned in the config of the EVM if any, or else the current one.
 func GetRealContractAddress(evm *EVM, caller ContractRef, proxyContractAddress common.Address, gas uint64) common.Address {
-	if !configs.EnableProxyContract {
+	proxyRegister, ok := evm.proxyRegister()
+	if !ok {
 		return proxyContractAddress
 	}
 
-	realAddress := proxyContractAddress
+	var (
+		paramBytes []byte
+		version    *uint64
+	)
+	if v, pinned := evm.vmConfig.ProxyVersions[proxyContractAddress]; pinned {
+		version = &v
+		paramBytes = getOldContractInput(proxyContractAddress, v)
+	} else {
+		// lookup in cache
+		if realContractFromCache, gotIt := evm.vmConfig.ProxyCache.Get(proxyContractAddress); gotIt {
+			log.Debug("get real address from cache for", "proxyContractAddress", proxyContractAddress.Hex())
+			evm.recordProxyResolution(proxyContractAddress, realContractFromCache, nil)
+			return realContractFromCache
+		}
+		// setup param from #getContractInput(methodSignature,proxyAddress)
+		paramBytes = getContractInput(proxyContractAddress)
+	}
 
-	if dc := evm.chainConfig.Dpos; dc != nil {
-		if proxyRegister := dc.ProxyContractRegister; proxyRegister != emptyAddress {
-			// lookup in cache
-			realContractFromCache, gotIt := contractLookupCache.Get(proxyContractAddress)
-			if gotIt {
-				log.Debug("get real address from cache for", "proxyContractAddress", proxyContractAddress.Hex())
-				return realContractFromCache.(common.Address)
-			}
-
-			// setup param from #getContractInput(methodSignature,proxyAddress)
-			paramBytes := getContractInput(proxyContractAddress)
-			if ret, _, err := evm.StaticCall(caller, proxyRegister, paramBytes, gas); err == nil {
-				// get real contract address parse from ret
-				address := common.BytesToAddress(ret)
-				if address != emptyAddress {
-					log.Debug("GetRealContractAddress ", "hex(address)", common.Bytes2Hex(ret), "address", address.Hex())
-				}
-
-				if address != emptyAddress {
-					log.Debug("parseAddress ok", "realAddress", realAddress.Hex())
-					realAddress = address
-					contractLookupCache.Add(proxyContractAddress, realAddress, forceLookupTTL)
-				}
-			} else {
-				log.Warn("GetRealContractAddress", "err", err)
-			}
+	realAddress := proxyContractAddress
+	if ret, _, err := evm.StaticCall(caller, proxyRegister, paramBytes, gas); err == nil {
+		// get real contract address parse from ret
+		if address := common.BytesToAddress(ret); address != emptyAddress {
+			log.Debug("GetRealContractAddress ", "hex(address)", common.Bytes2Hex(ret), "address", address.Hex())
+			realAddress = address
+		}
+		if version == nil {
+			evm.vmConfig.ProxyCache.Add(proxyContractAddress, realAddress)
 		}
+	} else {
+		log.Warn("GetRealContractAddress", "err", err)
 	}
 
+	evm.recordProxyResolution(proxyContractAddress, realAddress, version)
 	log.Debug("GetRealContractAddress", "realAddress", realAddress.Hex())
 	return realAddress
 }
 
+func (evm *EVM) recordProxyResolution(proxy, real common.Address, version *uint64) {
+	if proxy == real {
+		return
+	}
+	evm.proxyResolutions = append(evm.proxyResolutions, ProxyResolution{
+		Proxy:   proxy,
+		Real:    real,
+		Version: version,
+		Depth:   evm.depth + 1,
+	})
+}
+
 // invoke contract method in proxyContractRegister.sol#getRealContract
 func getContractInput(proxyContract common.Address) []byte {
+	return methodInput("getRealContract(address)", proxyContract.Hash().Bytes())
+}
+
+// invoke contract method in proxyContractRegister.sol#getOldContract
+func getOldContractInput(proxyContract common.Address, version uint64) []byte {
+	return methodInput("getOldContract(address,uint256)", proxyContract.Hash().Bytes(),
+		common.BigToHash(new(big.Int).SetUint64(version)).Bytes())
+}
+
+func methodInput(signature string, args ...[]byte) []byte {
 	// the name is in line with the one in the contract
-	methodSignBytes := []byte("getRealContract(address)")
-	bytes := crypto.Keccak256(methodSignBytes)
+	bytes := crypto.Keccak256([]byte(signature))
 	methodSignature := fmt.Sprintf("%v", common.Bytes2Hex(bytes)[0:8])
-	addrBytes := fmt.Sprintf("%064v\n", common.Bytes2Hex(proxyContract.Bytes()))
-	return common.Hex2Bytes(methodSignature + addrBytes)
+	input := common.Hex2Bytes(methodSignature)
+	for _, arg := range args {
+		input = append(input, arg...)
+	}
+	return input
 }
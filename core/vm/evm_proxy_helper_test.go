@@ -3,9 +3,15 @@
 package vm
 
 import (
+	"math/big"
+	"reflect"
 	"testing"
 
+	"github.com/gcchains/chain/configs"
+	"github.com/gcchains/chain/core/state"
+	"github.com/gcchains/chain/database"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 func TestGetContractInput(t *testing.T) {
@@ -17,3 +23,136 @@ func TestGetContractInput(t *testing.T) {
 		t.Errorf("result error expected:%v,got:%v", expected, bh)
 	}
 }
+
+var (
+	// proxyVersionEventID is the topic of the event ProxyContractAddressVersion(_proxy, _real, _version), emitted
+	// by proxyContractRegister.sol on every registration of a proxy contract
+	proxyVersionEventID = crypto.Keccak256Hash([]byte("ProxyContractAddressVersion(address,address,uint256)"))
+
+	proxyRegisterAddr = common.HexToAddress("0x36a8ac0cad2150e036de638aa492042eeb823c6b")
+	proxyAddr         = common.HexToAddress("0x015e7baea6a6c7c4c2dfeb917efac326af552d86")
+	realAddr1         = common.HexToAddress("0x015e7baea6a6c7c4c2dfeb917efac326af552d87")
+	realAddr2         = common.HexToAddress("0x015e7baea6a6c7c4c2dfeb917efac326af552d88")
+	callerAddr        = common.HexToAddress("0x7900dd1d71fc5c57ba56e4b768de3c2264253335")
+)
+
+// proxyRegisterCode is a register mock. Called with a proxy and a real contract, 64 bytes with no method, it registers
+// the real contract at the slot of the proxy and emits ProxyContractAddressVersion. Called with a method, it returns
+// the address at the slot of the first argument xor the second one, i.e. the slot of the proxy for getRealContract.
+func proxyRegisterCode() []byte {
+	code := common.Hex2Bytes("36604014601757602435600435185460005260206000f35b6020356000355560406000600037")
+	code = append(code, byte(PUSH32))
+	code = append(code, proxyVersionEventID.Bytes()...)
+	return append(code, common.Hex2Bytes("60606000a100")...)
+}
+
+func newProxyTestEVM(t *testing.T, cfg Config) (*EVM, *state.StateDB) {
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(database.NewMemDatabase()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	statedb.SetCode(proxyRegisterAddr, proxyRegisterCode())
+	// the real contracts return their address
+	for _, addr := range []common.Address{realAddr1, realAddr2} {
+		statedb.SetCode(addr, common.Hex2Bytes("3060005260206000f3"))
+	}
+	statedb.SetState(proxyRegisterAddr, proxyAddr.Hash(), realAddr1.Hash())
+
+	ctx := Context{
+		CanTransfer: func(StateDB, common.Address, *big.Int) bool { return true },
+		Transfer:    func(StateDB, common.Address, common.Address, *big.Int) {},
+		BlockNumber: big.NewInt(1),
+	}
+	chainConfig := &configs.ChainConfig{ChainID: big.NewInt(configs.DevChainId), Dpos: &configs.DposConfig{ProxyContractRegister: proxyRegisterAddr}}
+	return NewEVM(ctx, statedb, chainConfig, cfg), statedb
+}
+
+func enableProxyContract() func() {
+	enabled := proxyContractEnabled
+	proxyContractEnabled = true
+	return func() { proxyContractEnabled = enabled }
+}
+
+func callProxy(t *testing.T, evm *EVM) common.Address {
+	ret, _, err := evm.Call(AccountRef(callerAddr), proxyAddr, nil, 1000000, new(big.Int))
+	if err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+	return common.BytesToAddress(ret)
+}
+
+func TestGetRealContractAddress(t *testing.T) {
+	defer enableProxyContract()()
+
+	cache := NewProxyCache()
+	evm, statedb := newProxyTestEVM(t, Config{ProxyCache: cache})
+	if real := callProxy(t, evm); real != realAddr1 {
+		t.Fatalf("called %v, want %v", real.Hex(), realAddr1.Hex())
+	}
+	want := []ProxyResolution{{Proxy: proxyAddr, Real: realAddr1, Depth: 1}}
+	if got := evm.ProxyResolutions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ProxyResolutions() = %+v, want %+v", got, want)
+	}
+
+	// the cache is bound to the writes of the register by the EVM only, not to the time
+	statedb.SetState(proxyRegisterAddr, proxyAddr.Hash(), realAddr2.Hash())
+	if real := callProxy(t, evm); real != realAddr1 {
+		t.Fatalf("called %v with the resolution cached, want %v", real.Hex(), realAddr1.Hex())
+	}
+	// a write of the register drops the resolutions of all proxies, the slots of a proxy are unknown
+	cache.Add(callerAddr, realAddr1)
+	register := append(proxyAddr.Hash().Bytes(), realAddr2.Hash().Bytes()...)
+	if _, _, err := evm.Call(AccountRef(callerAddr), proxyRegisterAddr, register, 1000000, new(big.Int)); err != nil {
+		t.Fatalf("register error = %v", err)
+	}
+	if _, ok := cache.Get(proxyAddr); ok {
+		t.Fatal("the resolution is cached after the registration")
+	}
+	if _, ok := cache.Get(callerAddr); ok {
+		t.Fatal("the resolution of another proxy is cached after the registration")
+	}
+	if real := callProxy(t, evm); real != realAddr2 {
+		t.Fatalf("called %v after the registration, want %v", real.Hex(), realAddr2.Hex())
+	}
+
+	// a revert drops the resolutions cached, they may be read from the state reverted
+	cache.Add(proxyAddr, realAddr1)
+	evm.revertToSnapshot(statedb.Snapshot())
+	if _, ok := cache.Get(proxyAddr); ok {
+		t.Fatal("the resolution is cached after a revert")
+	}
+
+	// with no cache the state is read on every call
+	evm, statedb = newProxyTestEVM(t, Config{})
+	statedb.SetState(proxyRegisterAddr, proxyAddr.Hash(), realAddr2.Hash())
+	if real := callProxy(t, evm); real != realAddr2 {
+		t.Fatalf("called %v with no cache, want %v", real.Hex(), realAddr2.Hex())
+	}
+}
+
+func TestGetRealContractAddressAsOfVersion(t *testing.T) {
+	defer enableProxyContract()()
+
+	evm, statedb := newProxyTestEVM(t, Config{ProxyVersions: map[common.Address]uint64{proxyAddr: 1}})
+	// the mock returns the slot of proxy xor version for getOldContract
+	slot := common.BigToHash(new(big.Int).Xor(proxyAddr.Big(), big.NewInt(1)))
+	statedb.SetState(proxyRegisterAddr, slot, realAddr2.Hash())
+
+	if real := callProxy(t, evm); real != realAddr2 {
+		t.Fatalf("called %v as of version 1, want %v", real.Hex(), realAddr2.Hex())
+	}
+	version := uint64(1)
+	want := []ProxyResolution{{Proxy: proxyAddr, Real: realAddr2, Version: &version, Depth: 1}}
+	if got := evm.ProxyResolutions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ProxyResolutions() = %+v, want %+v", got, want)
+	}
+}
+
+func TestGetOldContractInput(t *testing.T) {
+	addr := common.HexToAddress("0x7900dd1d71fc5c57ba56e4b768de3c2264253335")
+	expected := "4c9150c8" + "0000000000000000000000007900dd1d71fc5c57ba56e4b768de3c2264253335" +
+		"0000000000000000000000000000000000000000000000000000000000000003"
+	if got := common.Bytes2Hex(getOldContractInput(addr, 3)); got != expected {
+		t.Errorf("result error expected:%v,got:%v", expected, got)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"sync/atomic"
 
 	"github.com/gcchains/chain/configs"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/math"
 )
 
@@ -25,6 +26,13 @@ type Config struct {
 	// may be left uninitialised and will be set to the default
 	// table.
 	JumpTable [256]operation
+	// ProxyCache caches the real contracts of proxy contracts through
+	// the transactions of a block, nil disables caching.
+	ProxyCache *ProxyCache
+	// ProxyVersions pins proxy contracts to a version of the register,
+	// resolving them by getOldContract. It is for calls only, never
+	// set it when processing blocks.
+	ProxyVersions map[common.Address]uint64
 }
 
 // Interpreter is used to run Ethereum based contracts and will utilise the
@@ -47,7 +55,12 @@ func NewInterpreter(evm *EVM, cfg Config) *Interpreter {
 	// the jump table was initialised. If it was not
 	// we'll set the default jump table.
 	if !cfg.JumpTable[STOP].valid {
-		cfg.JumpTable = constantinopleInstructionSet
+		switch {
+		case evm.ChainConfig().IsCep2(evm.BlockNumber):
+			cfg.JumpTable = cep2InstructionSet
+		default:
+			cfg.JumpTable = constantinopleInstructionSet
+		}
 	}
 
 	return &Interpreter{
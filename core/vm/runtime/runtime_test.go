@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/gcchains/chain/accounts/abi"
+	"github.com/gcchains/chain/configs"
 	"github.com/gcchains/chain/core/state"
 	"github.com/gcchains/chain/core/vm"
 	"github.com/gcchains/chain/database"
@@ -79,6 +80,32 @@ func TestExecute(t *testing.T) {
 	}
 }
 
+func TestExecuteCep2(t *testing.T) {
+	code := []byte{
+		byte(vm.CHAINID),
+		byte(vm.PUSH1), 0,
+		byte(vm.MSTORE),
+		byte(vm.PUSH1), 32,
+		byte(vm.PUSH1), 0,
+		byte(vm.RETURN),
+	}
+
+	// CHAINID is an invalid opcode before Cep2
+	cfg := &Config{ChainConfig: &configs.ChainConfig{ChainID: big.NewInt(42), Forks: configs.ForkSchedule{configs.Cep2: 10}}, BlockNumber: big.NewInt(9)}
+	if _, _, err := Execute(code, nil, cfg); err == nil {
+		t.Fatal("expected invalid opcode error before Cep2")
+	}
+
+	cfg.BlockNumber = big.NewInt(10)
+	ret, _, err := Execute(code, nil, cfg)
+	if err != nil {
+		t.Fatal("didn't expect error", err)
+	}
+	if num := new(big.Int).SetBytes(ret); num.Cmp(big.NewInt(42)) != 0 {
+		t.Error("Expected 42, got", num)
+	}
+}
+
 func TestCall(t *testing.T) {
 	state, _ := state.New(common.Hash{}, state.NewDatabase(database.NewMemDatabase()))
 	address := common.HexToAddress("0x0a")
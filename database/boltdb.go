@@ -0,0 +1,321 @@
+// Copyright 2018 The gcchain authors
+
+package database
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gcchains/chain/commons/log"
+	"github.com/ethereum/go-ethereum/common"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	// boltFile is the name of the bolt file in the database directory.
+	boltFile = "bolt.db"
+
+	// boltIteratorChunk is the number of entries an iterator reads in one transaction.
+	boltIteratorChunk = 1024
+)
+
+var (
+	// boltBucket holds all keys but the empty one, which bolt does not allow.
+	boltBucket = []byte("kv")
+
+	// boltEmptyBucket holds the value of the empty key under boltEmptyKey.
+	boltEmptyBucket = []byte("empty")
+	boltEmptyKey    = []byte{0}
+)
+
+// boltLocate returns the bucket and the bolt key of a key.
+func boltLocate(tx *bolt.Tx, key []byte) (*bolt.Bucket, []byte) {
+	if len(key) == 0 {
+		return tx.Bucket(boltEmptyBucket), boltEmptyKey
+	}
+	return tx.Bucket(boltBucket), key
+}
+
+// BoltDatabase is a database on a bolt B+tree file. Unlike LevelDB it never compacts, writes
+// cost a page copy instead, so imports are not stalled by background compactions.
+//
+// Like LevelDB without sync, writes are not synced to disk, they survive a crash of the process
+// but not of the operating system.
+type BoltDatabase struct {
+	fn string   // filename for reporting
+	db *bolt.DB // bolt instance
+
+	log *log.Logger // Contextual logger tracking the database path
+}
+
+// NewBoltDatabase opens or creates a bolt database in the directory.
+func NewBoltDatabase(dir string) (*BoltDatabase, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	db, err := bolt.Open(filepath.Join(dir, boltFile), 0600, &bolt.Options{
+		NoSync:         true,
+		NoFreelistSync: true,
+		FreelistType:   bolt.FreelistMapType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(boltBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(boltEmptyBucket)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &BoltDatabase{
+		fn:  dir,
+		db:  db,
+		log: log.New("database", dir),
+	}, nil
+}
+
+// Path returns the path to the database directory.
+func (db *BoltDatabase) Path() string {
+	return db.fn
+}
+
+// Put puts the given key / value to the database.
+func (db *BoltDatabase) Put(key []byte, value []byte) error {
+	return db.db.Update(func(tx *bolt.Tx) error {
+		bucket, key := boltLocate(tx, key)
+		return bucket.Put(key, value)
+	})
+}
+
+func (db *BoltDatabase) Has(key []byte) (bool, error) {
+	var ok bool
+	err := db.db.View(func(tx *bolt.Tx) error {
+		bucket, key := boltLocate(tx, key)
+		ok = bucket.Get(key) != nil
+		return nil
+	})
+	return ok, err
+}
+
+// Get returns the given key if it's present.
+func (db *BoltDatabase) Get(key []byte) ([]byte, error) {
+	var value []byte
+	err := db.db.View(func(tx *bolt.Tx) error {
+		// values are only valid during the transaction
+		bucket, key := boltLocate(tx, key)
+		value = common.CopyBytes(bucket.Get(key))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return nil, ErrKeyNotFound
+	}
+	return value, nil
+}
+
+// Delete deletes the key from the database.
+func (db *BoltDatabase) Delete(key []byte) error {
+	return db.db.Update(func(tx *bolt.Tx) error {
+		bucket, key := boltLocate(tx, key)
+		return bucket.Delete(key)
+	})
+}
+
+// NewIterator returns an iterator over the keys with the prefix, starting at prefix+start.
+//
+// The iterator reads chunks of entries in short transactions instead of holding one, as an open
+// transaction would block writes growing the file. It observes writes made while iterating.
+func (db *BoltDatabase) NewIterator(prefix []byte, start []byte) Iterator {
+	return &boltIterator{
+		db:     db.db,
+		prefix: common.CopyBytes(prefix),
+		next:   append(append([]byte{}, prefix...), start...),
+		index:  -1,
+	}
+}
+
+// DeleteRange deletes all keys in [start, end) in chunks.
+func (db *BoltDatabase) DeleteRange(start, end []byte) error {
+	if len(start) == 0 && (end == nil || len(end) > 0) {
+		if err := db.Delete(nil); err != nil {
+			return err
+		}
+	}
+	for {
+		var keys [][]byte
+		err := db.db.Update(func(tx *bolt.Tx) error {
+			c := tx.Bucket(boltBucket).Cursor()
+			for k, _ := c.Seek(start); k != nil && (end == nil || bytes.Compare(k, end) < 0) && len(keys) < boltIteratorChunk; k, _ = c.Next() {
+				keys = append(keys, common.CopyBytes(k))
+			}
+			// deleting while moving the cursor skips keys
+			for _, k := range keys {
+				if err := tx.Bucket(boltBucket).Delete(k); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil || len(keys) < boltIteratorChunk {
+			return err
+		}
+	}
+}
+
+// Stat returns the statistics of the bolt file, the only property is "stats" which is the default.
+func (db *BoltDatabase) Stat(property string) (string, error) {
+	if property != "" && property != "stats" {
+		return "", fmt.Errorf("unknown bolt property %q", property)
+	}
+	stats := db.db.Stats()
+	var keys int
+	err := db.db.View(func(tx *bolt.Tx) error {
+		keys = tx.Bucket(boltBucket).Stats().KeyN
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Keys: %d\nFree pages: %d\nPending pages: %d\nFree bytes: %d\nRead transactions: %d\nPages allocated: %d\nPage writes: %d\n",
+		keys, stats.FreePageN, stats.PendingPageN, stats.FreeAlloc, stats.TxN, stats.TxStats.PageCount, stats.TxStats.Write), nil
+}
+
+// Compact is a no-op as bolt reuses freed pages instead of compacting.
+func (db *BoltDatabase) Compact(start []byte, limit []byte) error {
+	return nil
+}
+
+func (db *BoltDatabase) Close() {
+	err := db.db.Close()
+	if err == nil {
+		db.log.Info("Database closed")
+	} else {
+		db.log.Error("Failed to close database", "err", err)
+	}
+}
+
+func (db *BoltDatabase) NewBatch() Batch {
+	return &boltBatch{db: db.db}
+}
+
+type boltBatch struct {
+	db     *bolt.DB
+	writes []kv
+	size   int
+}
+
+func (b *boltBatch) Put(key, value []byte) error {
+	// a nil value marks a deletion
+	b.writes = append(b.writes, kv{common.CopyBytes(key), append([]byte{}, value...)})
+	b.size += len(value)
+	return nil
+}
+
+func (b *boltBatch) Delete(key []byte) error {
+	b.writes = append(b.writes, kv{common.CopyBytes(key), nil})
+	b.size++
+	return nil
+}
+
+// Write applies the batch in a single transaction.
+func (b *boltBatch) Write() error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		for _, kv := range b.writes {
+			bucket, key := boltLocate(tx, kv.k)
+
+			var err error
+			if kv.v == nil {
+				err = bucket.Delete(key)
+			} else {
+				err = bucket.Put(key, kv.v)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (b *boltBatch) ValueSize() int {
+	return b.size
+}
+
+func (b *boltBatch) Reset() {
+	b.writes = b.writes[:0]
+	b.size = 0
+}
+
+// boltIterator iterates over a bolt database in chunks read in separate transactions.
+type boltIterator struct {
+	db     *bolt.DB
+	prefix []byte
+	next   []byte // key to read the next chunk from, nil if exhausted
+
+	items []kv
+	index int
+	err   error
+}
+
+func (it *boltIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if it.index+1 < len(it.items) {
+		it.index++
+		return true
+	}
+	if it.next == nil {
+		it.items, it.index = nil, 0
+		return false
+	}
+	it.items, it.index = it.items[:0], 0
+	it.err = it.db.View(func(tx *bolt.Tx) error {
+		if len(it.next) == 0 {
+			// the empty key comes first, it only matches the empty prefix and start
+			if v := tx.Bucket(boltEmptyBucket).Get(boltEmptyKey); v != nil {
+				it.items = append(it.items, kv{[]byte{}, common.CopyBytes(v)})
+			}
+		}
+		c := tx.Bucket(boltBucket).Cursor()
+		k, v := c.Seek(it.next)
+		for ; k != nil && bytes.HasPrefix(k, it.prefix) && len(it.items) < boltIteratorChunk; k, v = c.Next() {
+			it.items = append(it.items, kv{common.CopyBytes(k), common.CopyBytes(v)})
+		}
+		if k != nil && bytes.HasPrefix(k, it.prefix) {
+			it.next = common.CopyBytes(k)
+		} else {
+			it.next = nil
+		}
+		return nil
+	})
+	return it.err == nil && len(it.items) > 0
+}
+
+func (it *boltIterator) Error() error { return it.err }
+
+func (it *boltIterator) Key() []byte {
+	if it.index < 0 || it.index >= len(it.items) {
+		return nil
+	}
+	return it.items[it.index].k
+}
+
+func (it *boltIterator) Value() []byte {
+	if it.index < 0 || it.index >= len(it.items) {
+		return nil
+	}
+	return it.items[it.index].v
+}
+
+func (it *boltIterator) Release() {
+	it.items, it.next = nil, nil
+}
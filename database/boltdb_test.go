@@ -0,0 +1,89 @@
+package database_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/gcchains/chain/database"
+)
+
+func newTestBoltDB() (*database.BoltDatabase, func()) {
+	dirname, err := ioutil.TempDir(os.TempDir(), "cpcdb_test")
+	if err != nil {
+		panic("failed to create test file: " + err.Error())
+	}
+	db, err := database.NewBoltDatabase(dirname)
+	if err != nil {
+		panic("failed to create test database: " + err.Error())
+	}
+	return db, func() {
+		db.Close()
+		_ = os.RemoveAll(dirname)
+	}
+}
+
+func TestBoltFuncs(t *testing.T) {
+	db, remove := newTestBoltDB()
+	defer remove()
+	testDBFuncs(db, t)
+}
+
+func TestBoltBatch(t *testing.T) {
+	db, remove := newTestBoltDB()
+	defer remove()
+	testBatchFuncs(db.NewBatch(), t)
+}
+
+func TestBoltParallelFuncs(t *testing.T) {
+	db, remove := newTestBoltDB()
+	defer remove()
+	testParallelDBFuncs(db, t)
+}
+
+func TestBoltIterator(t *testing.T) {
+	db, remove := newTestBoltDB()
+	defer remove()
+	testIteratorFuncs(db, t)
+}
+
+func TestBoltDeleteRange(t *testing.T) {
+	db, remove := newTestBoltDB()
+	defer remove()
+	testDeleteRangeFuncs(db, t)
+}
+
+// TestBoltIteratorChunks iterates over more keys than read in one transaction while writing.
+func TestBoltIteratorChunks(t *testing.T) {
+	db, remove := newTestBoltDB()
+	defer remove()
+
+	const n = 5000
+	batch := db.NewBatch()
+	for i := 0; i < n; i++ {
+		key := []byte{'k', byte(i >> 8), byte(i)}
+		if err := batch.Put(key, []byte("v"+string(key))); err != nil {
+			t.Fatalf("put failed: %v\n", err)
+		}
+	}
+	if err := batch.Write(); err != nil {
+		t.Fatalf("write failed: %v\n", err)
+	}
+	it := db.NewIterator([]byte("k"), nil)
+	defer it.Release()
+
+	count := 0
+	for it.Next() {
+		// writes while iterating must not block
+		if err := db.Delete(it.Key()); err != nil {
+			t.Fatalf("delete failed: %v\n", err)
+		}
+		count++
+	}
+	if err := it.Error(); err != nil {
+		t.Fatalf("iteration failed: %v\n", err)
+	}
+	if count != n {
+		t.Fatalf("iterated %d keys, want %d", count, n)
+	}
+}
@@ -222,3 +222,75 @@ func testBatchFuncs(b database.Batch, t *testing.T) {
 		t.Fatalf("wrong value size: %v\n", size)
 	}
 }
+
+// iteratorKeys is sorted, the shared prefix is "ab".
+var iteratorKeys = []string{"", "a", "ab", "ab\x00", "ab1", "ab2", "ab3", "abc", "b", "ba"}
+
+func putIteratorKeys(db database.Database, t *testing.T) {
+	for _, k := range iteratorKeys {
+		if err := db.Put([]byte(k), []byte("v"+k)); err != nil {
+			t.Fatalf("put failed: %v\n", err)
+		}
+	}
+}
+
+func collectKeys(it database.Iterator, t *testing.T) []string {
+	defer it.Release()
+
+	var keys []string
+	for it.Next() {
+		if !bytes.Equal(it.Value(), []byte("v"+string(it.Key()))) {
+			t.Fatalf("wrong value %q of key %q", it.Value(), it.Key())
+		}
+		keys = append(keys, string(it.Key()))
+	}
+	if err := it.Error(); err != nil {
+		t.Fatalf("iteration failed: %v\n", err)
+	}
+	return keys
+}
+
+func testIteratorFuncs(db database.Database, t *testing.T) {
+	putIteratorKeys(db, t)
+
+	tests := []struct {
+		prefix, start string
+		want          []string
+	}{
+		{"", "", iteratorKeys},
+		{"", "ab2", []string{"ab2", "ab3", "abc", "b", "ba"}},
+		{"ab", "", []string{"ab", "ab\x00", "ab1", "ab2", "ab3", "abc"}},
+		{"ab", "2", []string{"ab2", "ab3", "abc"}},
+		{"ab", "d", nil},
+		{"c", "", nil},
+	}
+	for i, tt := range tests {
+		got := collectKeys(db.NewIterator([]byte(tt.prefix), []byte(tt.start)), t)
+		if fmt.Sprintf("%q", got) != fmt.Sprintf("%q", tt.want) {
+			t.Errorf("test %d: iterated keys %q, want %q", i, got, tt.want)
+		}
+	}
+}
+
+func testDeleteRangeFuncs(db database.Database, t *testing.T) {
+	putIteratorKeys(db, t)
+
+	steps := []struct {
+		start, end []byte
+		want       []string
+	}{
+		{[]byte("ab1"), []byte("ab3"), []string{"", "a", "ab", "ab\x00", "ab3", "abc", "b", "ba"}},
+		{[]byte("b"), nil, []string{"", "a", "ab", "ab\x00", "ab3", "abc"}},
+		{nil, []byte("ab"), []string{"ab", "ab\x00", "ab3", "abc"}},
+		{nil, nil, nil},
+	}
+	for i, step := range steps {
+		if err := db.DeleteRange(step.start, step.end); err != nil {
+			t.Fatalf("step %d: delete range failed: %v\n", i, err)
+		}
+		got := collectKeys(db.NewIterator(nil, nil), t)
+		if fmt.Sprintf("%q", got) != fmt.Sprintf("%q", step.want) {
+			t.Errorf("step %d: remaining keys %q, want %q", i, got, step.want)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2018 The gcchain authors
+
+package database
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// EngineLevelDB stores the database in LevelDB, the default engine.
+	EngineLevelDB = "leveldb"
+
+	// EngineBolt stores the database in a bolt B+tree file, which does not compact.
+	EngineBolt = "bolt"
+)
+
+// Engines lists the supported database engines.
+var Engines = []string{EngineLevelDB, EngineBolt}
+
+// ValidateEngine returns an error if the engine is not supported, an empty engine is the default.
+func ValidateEngine(engine string) error {
+	if engine == "" {
+		return nil
+	}
+	for _, e := range Engines {
+		if e == engine {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown database engine %q, supported are %v", engine, Engines)
+}
+
+// DetectEngine returns the engine of the database in the directory, or an empty string if
+// there is none.
+func DetectEngine(dir string) string {
+	switch {
+	case common.FileExist(filepath.Join(dir, boltFile)):
+		return EngineBolt
+	case common.FileExist(filepath.Join(dir, "CURRENT")):
+		return EngineLevelDB
+	}
+	return ""
+}
+
+// Open opens the database in the directory with the engine it was created with, or creates it
+// with the given engine if there is none, LevelDB if empty. Opening an existing database with
+// another engine fails, it has to be migrated first.
+func Open(dir string, engine string, cache int, handles int) (Database, error) {
+	if err := ValidateEngine(engine); err != nil {
+		return nil, err
+	}
+	if existing := DetectEngine(dir); existing != "" {
+		if engine != "" && engine != existing {
+			return nil, fmt.Errorf("database %s uses engine %s, not %s", dir, existing, engine)
+		}
+		engine = existing
+	}
+	if engine == EngineBolt {
+		db, err := NewBoltDatabase(dir)
+		if err != nil {
+			return nil, err
+		}
+		return db, nil
+	}
+	db, err := NewLDBDatabase(dir, cache, handles)
+	if err != nil {
+		return nil, err
+	}
+	return db, nil
+}
@@ -0,0 +1,56 @@
+package database_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/gcchains/chain/database"
+)
+
+func TestOpenEngine(t *testing.T) {
+	for _, engine := range database.Engines {
+		dir, err := ioutil.TempDir(os.TempDir(), "cpcdb_test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		if detected := database.DetectEngine(dir); detected != "" {
+			t.Fatalf("detected engine %q in empty directory", detected)
+		}
+		db, err := database.Open(dir, engine, 0, 0)
+		if err != nil {
+			t.Fatalf("%s: open failed: %v", engine, err)
+		}
+		if err := db.Put([]byte("key"), []byte("value")); err != nil {
+			t.Fatalf("%s: put failed: %v", engine, err)
+		}
+		db.Close()
+
+		if detected := database.DetectEngine(dir); detected != engine {
+			t.Fatalf("detected engine %q, want %q", detected, engine)
+		}
+		// reopening without an engine uses the existing one
+		db, err = database.Open(dir, "", 0, 0)
+		if err != nil {
+			t.Fatalf("%s: reopen failed: %v", engine, err)
+		}
+		if value, err := db.Get([]byte("key")); err != nil || string(value) != "value" {
+			t.Fatalf("%s: get after reopen = %q, %v", engine, value, err)
+		}
+		db.Close()
+
+		for _, other := range database.Engines {
+			if other == engine {
+				continue
+			}
+			if _, err := database.Open(dir, other, 0, 0); err == nil {
+				t.Fatalf("opened %s database with engine %s", engine, other)
+			}
+		}
+	}
+	if _, err := database.Open(os.TempDir(), "rocksdb", 0, 0); err == nil {
+		t.Fatal("opened database with unknown engine")
+	}
+}
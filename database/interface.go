@@ -17,10 +17,46 @@ type Deleter interface {
 	Delete(key []byte) error
 }
 
+// Iterator iterates over the key/value pairs of a database in ascending key order. The key and
+// value are only valid until the next call of Next. An iterator must be released after use.
+type Iterator interface {
+	Next() bool
+	Error() error
+	Key() []byte
+	Value() []byte
+	Release()
+}
+
+// Iteratee wraps the creation of iterators supported by regular databases.
+type Iteratee interface {
+	// NewIterator creates an iterator over the keys with the given prefix, starting at the
+	// key prefix+start. Both may be nil.
+	NewIterator(prefix []byte, start []byte) Iterator
+}
+
+// RangeDeleter wraps the range deletion supported by regular databases.
+type RangeDeleter interface {
+	// DeleteRange deletes all keys in [start, end), a nil end deletes up to the last key.
+	DeleteRange(start, end []byte) error
+}
+
+// Stater wraps the engine specific statistics of a database.
+type Stater interface {
+	Stat(property string) (string, error)
+}
+
+// Compacter wraps the compaction of a database, which is a no-op for engines not compacting.
+type Compacter interface {
+	// Compact compacts the keys in [start, limit), nil bounds compact the whole database.
+	Compact(start []byte, limit []byte) error
+}
+
 // Database wraps all database operations. All methods are safe for concurrent use.
 type Database interface {
 	Putter
 	Deleter
+	Iteratee
+	RangeDeleter
 	Get(key []byte) ([]byte, error)
 	Has(key []byte) (bool, error)
 	Close()
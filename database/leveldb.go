@@ -4,11 +4,12 @@
 package database
 
 import (
+	"strings"
+
 	"github.com/gcchains/chain/commons/log"
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/errors"
 	"github.com/syndtr/goleveldb/leveldb/filter"
-	"github.com/syndtr/goleveldb/leveldb/iterator"
 	"github.com/syndtr/goleveldb/leveldb/opt"
 	"github.com/syndtr/goleveldb/leveldb/util"
 )
@@ -80,13 +81,51 @@ func (db *LDBDatabase) Delete(key []byte) error {
 	return db.db.Delete(key, nil)
 }
 
-func (db *LDBDatabase) NewIterator() iterator.Iterator {
-	return db.db.NewIterator(nil, nil)
+// NewIterator returns an iterator over the keys with the prefix, starting at prefix+start.
+func (db *LDBDatabase) NewIterator(prefix []byte, start []byte) Iterator {
+	r := util.BytesPrefix(prefix)
+	r.Start = append(append([]byte{}, prefix...), start...)
+	return db.db.NewIterator(r, nil)
+}
+
+// DeleteRange deletes all keys in [start, end) in batches.
+func (db *LDBDatabase) DeleteRange(start, end []byte) error {
+	it := db.db.NewIterator(&util.Range{Start: start, Limit: end}, nil)
+	defer it.Release()
+
+	var (
+		batch = new(leveldb.Batch)
+		size  int
+	)
+	for it.Next() {
+		batch.Delete(it.Key())
+		if size += len(it.Key()); size >= IdealBatchSize {
+			if err := db.db.Write(batch, nil); err != nil {
+				return err
+			}
+			batch.Reset()
+			size = 0
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	return db.db.Write(batch, nil)
 }
 
-// NewIteratorWithPrefix returns a iterator to iterate over subset of database content with a particular prefix.
-func (db *LDBDatabase) NewIteratorWithPrefix(prefix []byte) iterator.Iterator {
-	return db.db.NewIterator(util.BytesPrefix(prefix), nil)
+// Stat returns a LevelDB property, "leveldb." may be omitted and the default is "leveldb.stats".
+func (db *LDBDatabase) Stat(property string) (string, error) {
+	if property == "" {
+		property = "leveldb.stats"
+	} else if !strings.HasPrefix(property, "leveldb.") {
+		property = "leveldb." + property
+	}
+	return db.db.GetProperty(property)
+}
+
+// Compact compacts the keys in [start, limit).
+func (db *LDBDatabase) Compact(start []byte, limit []byte) error {
+	return db.db.CompactRange(util.Range{Start: start, Limit: limit})
 }
 
 func (db *LDBDatabase) Close() {
@@ -98,10 +137,6 @@ func (db *LDBDatabase) Close() {
 	}
 }
 
-func (db *LDBDatabase) LDB() *leveldb.DB {
-	return db.db
-}
-
 func (db *LDBDatabase) NewBatch() Batch {
 	return &ldbBatch{db: db.db, b: new(leveldb.Batch)}
 }
@@ -167,10 +202,38 @@ func (dt *table) Delete(key []byte) error {
 	return dt.db.Delete(append([]byte(dt.prefix), key...))
 }
 
+func (dt *table) NewIterator(prefix []byte, start []byte) Iterator {
+	return &tableIterator{
+		it:     dt.db.NewIterator(append([]byte(dt.prefix), prefix...), start),
+		prefix: len(dt.prefix),
+	}
+}
+
+func (dt *table) DeleteRange(start, end []byte) error {
+	if end == nil {
+		end = util.BytesPrefix([]byte(dt.prefix)).Limit
+	} else {
+		end = append([]byte(dt.prefix), end...)
+	}
+	return dt.db.DeleteRange(append([]byte(dt.prefix), start...), end)
+}
+
 func (dt *table) Close() {
 	// Do nothing; don't close the underlying DB.
 }
 
+// tableIterator strips the table prefix from the keys of the underlying iterator.
+type tableIterator struct {
+	it     Iterator
+	prefix int
+}
+
+func (ti *tableIterator) Next() bool    { return ti.it.Next() }
+func (ti *tableIterator) Error() error  { return ti.it.Error() }
+func (ti *tableIterator) Key() []byte   { return ti.it.Key()[ti.prefix:] }
+func (ti *tableIterator) Value() []byte { return ti.it.Value() }
+func (ti *tableIterator) Release()      { ti.it.Release() }
+
 type tableBatch struct {
 	batch  Batch
 	prefix string
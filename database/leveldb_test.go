@@ -39,7 +39,7 @@ func TestLDBFuncs(t *testing.T) {
 func TestLDBDatabase_Iterator(t *testing.T) {
 	db, remove := newTestLDB()
 	defer remove()
-	iter := db.NewIterator()
+	iter := db.NewIterator(nil, nil)
 
 	// test NewIterator
 	if err := db.Put([]byte("aaa"), []byte("bbb")); err != nil {
@@ -60,8 +60,8 @@ func TestLDBDatabase_Iterator(t *testing.T) {
 		t.Fatalf("release failed: %v\n", err)
 	}
 
-	// test NewIteratorWithPrefix
-	iter = db.NewIteratorWithPrefix([]byte("foobar"))
+	// test NewIterator with a prefix
+	iter = db.NewIterator([]byte("foobar"), nil)
 	// test NewIterator
 	if err := db.Put([]byte("aaa"), []byte("bbb")); err != nil {
 		t.Fatalf("put failed: %v\n", err)
@@ -143,5 +143,39 @@ func TestLDBMisc(t *testing.T) {
 	db, remove := newTestLDB()
 	defer remove()
 
-	_ = db.LDB()
+	if _, err := db.Stat("stats"); err != nil {
+		t.Fatalf("stat failed: %v\n", err)
+	}
+	if err := db.Compact(nil, nil); err != nil {
+		t.Fatalf("compact failed: %v\n", err)
+	}
+}
+
+func TestLDBIterator(t *testing.T) {
+	db, remove := newTestLDB()
+	defer remove()
+	testIteratorFuncs(db, t)
+}
+
+func TestLDBDeleteRange(t *testing.T) {
+	db, remove := newTestLDB()
+	defer remove()
+	testDeleteRangeFuncs(db, t)
+}
+
+func TestLDBTableIterator(t *testing.T) {
+	db, remove := newTestLDB()
+	defer remove()
+
+	// keys outside of the table must be neither iterated nor deleted
+	if err := db.Put([]byte("foobas"), []byte("x")); err != nil {
+		t.Fatalf("put failed: %v\n", err)
+	}
+	tbl := database.NewTable(db, "foobar")
+	testIteratorFuncs(tbl, t)
+	testDeleteRangeFuncs(tbl, t)
+
+	if ok, _ := db.Has([]byte("foobas")); !ok {
+		t.Fatal("key outside of the table deleted")
+	}
 }
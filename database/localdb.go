@@ -0,0 +1,165 @@
+// Copyright 2018 The gcchain authors
+
+package database
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"sync"
+)
+
+var (
+	localDataPrefix = []byte("d") // localDataPrefix + key -> data
+	localRefsPrefix = []byte("r") // localRefsPrefix + key -> reference count (uint64 big endian)
+)
+
+var ErrContentMismatch = errors.New("content does not match its key")
+
+// Fetcher retrieves data missing in local storage from elsewhere, e.g. peers.
+type Fetcher interface {
+	// Fetch returns data whose content key is the given key.
+	Fetch(key []byte) ([]byte, error)
+}
+
+// ContentKey returns the key of data in a content-addressed database, which is the sha256 hash of the data.
+func ContentKey(value []byte) []byte {
+	hash := sha256.Sum256(value)
+	return hash[:]
+}
+
+// LocalDatabase is a content-addressed "remote database" keeping data in a local key-value store.
+// Data put several times is reference counted, it is deleted after it is discarded the same times.
+// If a fetcher is set, data missing locally is retrieved with it and kept locally.
+type LocalDatabase struct {
+	db      Database
+	fetcher Fetcher
+	lock    sync.RWMutex
+}
+
+// NewLocalDatabase creates a new LocalDatabase storing data in db.
+func NewLocalDatabase(db Database) *LocalDatabase {
+	return &LocalDatabase{
+		db: db,
+	}
+}
+
+// SetFetcher sets the fetcher used to retrieve data missing locally.
+func (db *LocalDatabase) SetFetcher(fetcher Fetcher) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	db.fetcher = fetcher
+}
+
+// Get retrieves data with given key, it is fetched if it does not exist locally.
+func (db *LocalDatabase) Get(key []byte) ([]byte, error) {
+	data, err := db.GetLocal(key)
+	if err == nil {
+		return data, nil
+	}
+
+	db.lock.RLock()
+	fetcher := db.fetcher
+	db.lock.RUnlock()
+	if fetcher == nil {
+		return nil, err
+	}
+
+	data, err = fetcher.Fetch(key)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(ContentKey(data), key) {
+		return nil, ErrContentMismatch
+	}
+	if _, err := db.Put(data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// GetLocal retrieves data with given key from local storage only.
+func (db *LocalDatabase) GetLocal(key []byte) ([]byte, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	return db.db.Get(localDataKey(key))
+}
+
+// Put saves data and returns its content key, the reference count of the data is increased if it exists.
+func (db *LocalDatabase) Put(value []byte) ([]byte, error) {
+	key := ContentKey(value)
+
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	refs := db.refs(key)
+	batch := db.db.NewBatch()
+	if refs == 0 {
+		batch.Put(localDataKey(key), value)
+	}
+	batch.Put(localRefsKey(key), encodeRefs(refs+1))
+	if err := batch.Write(); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Discard decreases the reference count of data with given key and deletes the data once nothing refers to it.
+func (db *LocalDatabase) Discard(key []byte) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	refs := db.refs(key)
+	if refs == 0 {
+		return ErrKeyNotFound
+	}
+
+	batch := db.db.NewBatch()
+	if refs == 1 {
+		batch.Delete(localDataKey(key))
+		batch.Delete(localRefsKey(key))
+	} else {
+		batch.Put(localRefsKey(key), encodeRefs(refs-1))
+	}
+	return batch.Write()
+}
+
+// Has checks if data with given key exists locally.
+func (db *LocalDatabase) Has(key []byte) bool {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	has, _ := db.db.Has(localDataKey(key))
+	return has
+}
+
+// Close closes the underlying key-value store.
+func (db *LocalDatabase) Close() {
+	db.db.Close()
+}
+
+// refs returns the reference count of data with given key, zero if it does not exist.
+func (db *LocalDatabase) refs(key []byte) uint64 {
+	enc, err := db.db.Get(localRefsKey(key))
+	if err != nil || len(enc) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(enc)
+}
+
+func localDataKey(key []byte) []byte {
+	return append(append([]byte{}, localDataPrefix...), key...)
+}
+
+func localRefsKey(key []byte) []byte {
+	return append(append([]byte{}, localRefsPrefix...), key...)
+}
+
+func encodeRefs(refs uint64) []byte {
+	enc := make([]byte, 8)
+	binary.BigEndian.PutUint64(enc, refs)
+	return enc
+}
@@ -0,0 +1,93 @@
+// Copyright 2018 The gcchain authors
+
+package database
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// TestLocalDbGetPut tests for putting and getting content with a content key.
+func TestLocalDbGetPut(t *testing.T) {
+	db := NewLocalDatabase(NewMemDatabase())
+	for _, content := range [][]byte{normalContent, {}, bytes.Repeat([]byte("data"), 1000)} {
+		key, err := db.Put(content)
+		if err != nil {
+			t.Fatalf("Put() error = %v", err)
+		}
+		if !bytes.Equal(key, ContentKey(content)) {
+			t.Errorf("Put() = %x, want %x", key, ContentKey(content))
+		}
+		if !db.Has(key) {
+			t.Errorf("Has(%x) = false, want true", key)
+		}
+		got, err := db.Get(key)
+		if err != nil {
+			t.Fatalf("Get(%x) error = %v", key, err)
+		}
+		if !bytes.Equal(got, content) {
+			t.Errorf("Get(%x) = %v, want %v", key, got, content)
+		}
+	}
+}
+
+// TestLocalDbDiscard tests that content is deleted after it is discarded as many times as it is put.
+func TestLocalDbDiscard(t *testing.T) {
+	db := NewLocalDatabase(NewMemDatabase())
+	key, _ := db.Put(normalContent)
+	db.Put(normalContent)
+
+	if err := db.Discard(key); err != nil {
+		t.Fatalf("Discard() error = %v", err)
+	}
+	if !db.Has(key) {
+		t.Fatalf("content is deleted while it is still referred")
+	}
+	if err := db.Discard(key); err != nil {
+		t.Fatalf("Discard() error = %v", err)
+	}
+	if db.Has(key) {
+		t.Fatalf("content is not deleted after all references are discarded")
+	}
+	if _, err := db.Get(key); err == nil {
+		t.Errorf("Get() on discarded content should return an error")
+	}
+	if err := db.Discard(key); err != ErrKeyNotFound {
+		t.Errorf("Discard() on discarded content error = %v, want %v", err, ErrKeyNotFound)
+	}
+}
+
+type fakeFetcher map[string][]byte
+
+func (f fakeFetcher) Fetch(key []byte) ([]byte, error) {
+	if data, ok := f[string(key)]; ok {
+		return data, nil
+	}
+	return nil, errors.New("not found in peers")
+}
+
+// TestLocalDbFetch tests that content missing locally is fetched, verified and kept.
+func TestLocalDbFetch(t *testing.T) {
+	var (
+		key   = ContentKey(normalContent)
+		bogus = ContentKey([]byte("bogus"))
+	)
+
+	db := NewLocalDatabase(NewMemDatabase())
+	if _, err := db.Get(key); err == nil {
+		t.Fatalf("Get() without fetcher should return an error")
+	}
+
+	db.SetFetcher(fakeFetcher{string(key): normalContent, string(bogus): normalContent})
+	got, err := db.Get(key)
+	if err != nil || !bytes.Equal(got, normalContent) {
+		t.Fatalf("Get() = %v, %v, want %v", got, err, normalContent)
+	}
+	if !db.Has(key) {
+		t.Errorf("fetched content is not kept locally")
+	}
+	if _, err := db.Get(bogus); err != ErrContentMismatch {
+		t.Errorf("Get() on tampered content error = %v, want %v", err, ErrContentMismatch)
+	}
+}
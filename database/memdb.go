@@ -4,7 +4,10 @@
 package database
 
 import (
+	"bytes"
 	"errors"
+	"sort"
+	"strings"
 	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -68,6 +71,38 @@ func (db *MemDatabase) Delete(key []byte) error {
 	return nil
 }
 
+// NewIterator returns an iterator over a snapshot of the keys with the prefix, starting at prefix+start.
+func (db *MemDatabase) NewIterator(prefix []byte, start []byte) Iterator {
+	db.rw.RLock()
+	defer db.rw.RUnlock()
+
+	var (
+		pr    = string(prefix)
+		st    = string(append(append([]byte{}, prefix...), start...))
+		items []kv
+	)
+	for key, value := range db.db {
+		if strings.HasPrefix(key, pr) && key >= st {
+			items = append(items, kv{[]byte(key), common.CopyBytes(value)})
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return bytes.Compare(items[i].k, items[j].k) < 0 })
+	return &memIterator{items: items, index: -1}
+}
+
+// DeleteRange deletes all keys in [start, end).
+func (db *MemDatabase) DeleteRange(start, end []byte) error {
+	db.rw.Lock()
+	defer db.rw.Unlock()
+
+	for key := range db.db {
+		if key >= string(start) && (end == nil || key < string(end)) {
+			delete(db.db, key)
+		}
+	}
+	return nil
+}
+
 func (db *MemDatabase) Close() {}
 
 func (db *MemDatabase) NewBatch() Batch {
@@ -118,3 +153,35 @@ func (b *memBatch) Reset() {
 	b.writes = b.writes[:0]
 	b.size = 0
 }
+
+// memIterator iterates over a sorted snapshot of a memory database.
+type memIterator struct {
+	items []kv
+	index int
+}
+
+func (it *memIterator) Next() bool {
+	if it.index >= len(it.items) {
+		return false
+	}
+	it.index++
+	return it.index < len(it.items)
+}
+
+func (it *memIterator) Error() error { return nil }
+
+func (it *memIterator) Key() []byte {
+	if it.index < 0 || it.index >= len(it.items) {
+		return nil
+	}
+	return it.items[it.index].k
+}
+
+func (it *memIterator) Value() []byte {
+	if it.index < 0 || it.index >= len(it.items) {
+		return nil
+	}
+	return it.items[it.index].v
+}
+
+func (it *memIterator) Release() { it.items = nil }
@@ -59,3 +59,11 @@ func TestMemBatch(t *testing.T) {
 func TestMemDatabaseParallelFuncs(t *testing.T) {
 	testParallelDBFuncs(database.NewMemDatabase(), t)
 }
+
+func TestMemDatabaseIterator(t *testing.T) {
+	testIteratorFuncs(database.NewMemDatabase(), t)
+}
+
+func TestMemDatabaseDeleteRange(t *testing.T) {
+	testDeleteRangeFuncs(database.NewMemDatabase(), t)
+}
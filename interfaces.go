@@ -134,6 +134,14 @@ type FilterQuery struct {
 	Topics [][]common.Hash
 }
 
+// PendingTxQuery contains options for filtering transactions entering the pending state.
+// An empty option matches any transaction, all the non-empty ones must be matched.
+type PendingTxQuery struct {
+	From    []common.Address // restricts matches to transactions sent by the accounts
+	To      []common.Address // restricts matches to transactions sent to the accounts, excluding contract creations
+	Methods [][4]byte        // restricts matches to transactions calling the methods of the selectors
+}
+
 // LogFilterer provides access to contract log events using a one-off query or continuous
 // event subscription.
 //
@@ -0,0 +1,108 @@
+// Copyright 2018 The gcchain authors
+
+package gccapi
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/gcchains/chain/api/rpc"
+	"github.com/gcchains/chain/core/rawdb"
+	"github.com/gcchains/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// addrIndexPageSize is the maximum number of transactions returned by one call of
+// GetTransactionsByAddress.
+const addrIndexPageSize = 100
+
+var errAddressIndexDisabled = errors.New("address index is disabled, restart the node with --addrindex")
+
+// AddressTransactions is a page of the transactions of an address. Cursor is set if there are
+// further transactions in the requested range, which are returned by passing it to the next call.
+type AddressTransactions struct {
+	Transactions []*RPCTransaction `json:"transactions"`
+	Cursor       *hexutil.Uint64   `json:"cursor"`
+}
+
+// PublicAddressIndexAPI provides an API to access the transaction history of addresses.
+type PublicAddressIndexAPI struct {
+	b Backend
+}
+
+// NewPublicAddressIndexAPI creates a new address index API.
+func NewPublicAddressIndexAPI(b Backend) *PublicAddressIndexAPI {
+	return &PublicAddressIndexAPI{b}
+}
+
+// GetTransactionsByAddress returns the transactions sent from, sent to or creating the address
+// in the block range, in the order of the chain. Latest and pending refer to the last indexed block.
+func (s *PublicAddressIndexAPI) GetTransactionsByAddress(ctx context.Context, address common.Address, fromBlock, toBlock rpc.BlockNumber, cursor *hexutil.Uint64) (*AddressTransactions, error) {
+	head, ok := s.b.AddressIndexHead()
+	if !ok {
+		return nil, errAddressIndexDisabled
+	}
+	result := &AddressTransactions{Transactions: []*RPCTransaction{}}
+	if head == 0 {
+		return result, nil
+	}
+	from, to := uint64(fromBlock), uint64(toBlock)
+	if fromBlock < 0 || from >= head {
+		from = head - 1
+	}
+	if toBlock < 0 || to >= head {
+		to = head - 1
+	}
+	if from > to {
+		return result, nil
+	}
+
+	db := s.b.ChainDb()
+	count := rawdb.ReadAddrIndexCount(db, address)
+	entryBlock := func(seq uint64) uint64 {
+		entry, ok := rawdb.ReadAddrIndexEntry(db, address, seq)
+		if !ok {
+			return math.MaxUint64
+		}
+		return entry.BlockNumber
+	}
+	var seq uint64
+	if cursor != nil {
+		seq = uint64(*cursor)
+	} else {
+		seq = uint64(sort.Search(int(count), func(i int) bool { return entryBlock(uint64(i)) >= from }))
+	}
+
+	var block *types.Block
+	for ; seq < count && len(result.Transactions) < addrIndexPageSize; seq++ {
+		entry, ok := rawdb.ReadAddrIndexEntry(db, address, seq)
+		if !ok || entry.BlockNumber > to {
+			return result, nil
+		}
+		if entry.BlockNumber < from {
+			continue
+		}
+		if block == nil || block.NumberU64() != entry.BlockNumber {
+			// Skip the entries of blocks reorged but not rolled back yet
+			hash := rawdb.ReadCanonicalHash(db, entry.BlockNumber)
+			if indexed := rawdb.ReadAddrIndexBlock(db, entry.BlockNumber); indexed == nil || indexed.Hash != hash {
+				block = nil
+				continue
+			}
+			if block = rawdb.ReadBlock(db, hash, entry.BlockNumber); block == nil {
+				continue
+			}
+		}
+		if tx := newRPCTransactionFromBlockIndex(block, entry.Index); tx != nil {
+			result.Transactions = append(result.Transactions, tx)
+		}
+	}
+	if seq < count && entryBlock(seq) <= to {
+		next := hexutil.Uint64(seq)
+		result.Cursor = &next
+	}
+	return result, nil
+}
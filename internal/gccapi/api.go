@@ -8,7 +8,6 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
-	"strings"
 	"sync"
 	"time"
 
@@ -21,6 +20,8 @@ import (
 	"github.com/gcchains/chain/core"
 	"github.com/gcchains/chain/core/rawdb"
 	"github.com/gcchains/chain/core/vm"
+	"github.com/gcchains/chain/database"
+	"github.com/gcchains/chain/private"
 	"github.com/gcchains/chain/types"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/ethereum/go-ethereum/common"
@@ -29,8 +30,6 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/rlp"
-	"github.com/syndtr/goleveldb/leveldb"
-	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
 const (
@@ -170,6 +169,139 @@ func (s *PublicTxPoolAPI) Inspect() map[string]map[string]map[string]string {
 	return content
 }
 
+// RPCDroppedTransaction represents a transaction dropped from the transaction pool.
+type RPCDroppedTransaction struct {
+	Hash       common.Hash       `json:"hash"`
+	From       common.Address    `json:"from"`
+	Nonce      hexutil.Uint64    `json:"nonce"`
+	GasPrice   *hexutil.Big      `json:"gasPrice"`
+	Reason     core.TxDropReason `json:"reason"`
+	Time       hexutil.Uint64    `json:"time"`
+	ReplacedBy *common.Hash      `json:"replacedBy,omitempty"`
+}
+
+func newRPCDroppedTransaction(dropped *core.DroppedTx) *RPCDroppedTransaction {
+	result := &RPCDroppedTransaction{
+		Hash:     dropped.Hash,
+		From:     dropped.From,
+		Nonce:    hexutil.Uint64(dropped.Nonce),
+		GasPrice: (*hexutil.Big)(dropped.GasPrice),
+		Reason:   dropped.Reason,
+		Time:     hexutil.Uint64(dropped.Time.Unix()),
+	}
+	if dropped.ReplacedBy != (common.Hash{}) {
+		replacedBy := dropped.ReplacedBy
+		result.ReplacedBy = &replacedBy
+	}
+	return result
+}
+
+// Dropped returns the transaction of the given hash with the reason it was dropped from the pool, or nil if it is not
+// one of the last dropped transactions.
+func (s *PublicTxPoolAPI) Dropped(hash common.Hash) *RPCDroppedTransaction {
+	if dropped := s.b.GetPoolDroppedTransaction(hash); dropped != nil {
+		return newRPCDroppedTransaction(dropped)
+	}
+	return nil
+}
+
+// DroppedTransactions creates a subscription that is triggered each time transactions are dropped from the pool.
+func (s *PublicTxPoolAPI) DroppedTransactions(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		droppedCh := make(chan core.DroppedTxsEvent, 128)
+		droppedSub := s.b.SubscribeDroppedTxsEvent(droppedCh)
+		defer droppedSub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-droppedCh:
+				for _, dropped := range ev.Txs {
+					notifier.Notify(rpcSub.ID, newRPCDroppedTransaction(dropped))
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			case <-droppedSub.Err():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// Replace replaces the pending or queued transaction of the given hash by the same one with a higher gas price, the
+// replacement is signed by the account of the sender, which must be managed by this node. If gasPrice is not given,
+// the price of the transaction is bumped by the minimum percentage required by the pool. The fee of the replacement
+// is limited by the configured cap.
+func (s *PublicTxPoolAPI) Replace(ctx context.Context, hash common.Hash, gasPrice *hexutil.Big) (common.Hash, error) {
+	tx := s.b.GetPoolTransaction(hash)
+	if tx == nil {
+		return common.Hash{}, fmt.Errorf("transaction %#x not found in txpool", hash)
+	}
+	var signer types.Signer = types.HomesteadSigner{}
+	if tx.Protected() {
+		signer = types.NewCep1Signer(tx.ChainId())
+	}
+	from, err := types.Sender(signer, tx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	price := s.b.GetPoolReplacementPrice(tx)
+	if gasPrice != nil {
+		if gasPrice.ToInt().Cmp(price) < 0 {
+			return common.Hash{}, fmt.Errorf("gas price %v is lower than the replacement price %v", gasPrice.ToInt(), price)
+		}
+		price = gasPrice.ToInt()
+	}
+	if err := checkTxFee(price, tx.Gas(), s.b.TxReplaceFeeCap()); err != nil {
+		return common.Hash{}, err
+	}
+
+	var replacement *types.Transaction
+	if tx.To() == nil {
+		replacement = types.NewContractCreation(tx.Nonce(), tx.Value(), tx.Gas(), price, tx.Data())
+	} else {
+		replacement = types.NewTransaction(tx.Nonce(), *tx.To(), tx.Value(), tx.Gas(), price, tx.Data())
+	}
+	replacement.SetType(tx.Type())
+
+	account := accounts.Account{Address: from}
+	wallet, err := s.b.AccountManager().Find(account)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	signed, err := wallet.SignTx(account, replacement, s.b.ChainConfig().ChainID)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if err := s.b.SendTx(ctx, signed); err != nil {
+		return common.Hash{}, err
+	}
+	return signed.Hash(), nil
+}
+
+// checkTxFee returns an error if the fee of a transaction with gasPrice and gas exceeds cap in gcc, 0 for no cap.
+func checkTxFee(gasPrice *big.Int, gas uint64, cap float64) error {
+	if cap == 0 {
+		return nil
+	}
+	fee := new(big.Float).Quo(new(big.Float).SetInt(new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(gas))), new(big.Float).SetInt64(configs.Gcc))
+	if f, _ := fee.Float64(); f > cap {
+		return fmt.Errorf("tx fee (%.2f gcc) exceeds the configured cap (%.2f gcc)", f, cap)
+	}
+	return nil
+}
+
 // PublicAccountAPI provides an API to access accounts managed by this node.
 // It offers only methods that can retrieve accounts.
 type PublicAccountAPI struct {
@@ -398,7 +530,8 @@ func (s *PrivateAccountAPI) SignTransaction(ctx context.Context, args SendTxArgs
 // safely used to calculate a signature from.
 //
 // The hash is calulcated as
-//   keccak256("\x19gcchain Signed Message:\n"${message length}${message}).
+//
+//	keccak256("\x19gcchain Signed Message:\n"${message length}${message}).
 //
 // This gives context to the signed message and prevents signing of transactions.
 func signHash(data []byte) []byte {
@@ -475,7 +608,7 @@ func NewPublicBlockChainAPI(b Backend) *PublicBlockChainAPI {
 	return &PublicBlockChainAPI{b}
 }
 
-//  GetCurrentView return current RNodes
+// GetCurrentView return current RNodes
 func (s *PublicBlockChainAPI) GetRNodes() []gcclient.RNodes {
 	var rnodes []common.Address
 	var committeAddress []common.Address
@@ -711,12 +844,12 @@ type CallArgs struct {
 	IsPrivate bool            `json:"isPrivate"`
 }
 
-func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber, vmCfg vm.Config, timeout time.Duration) ([]byte, uint64, bool, error) {
+func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber, vmCfg vm.Config, timeout time.Duration) ([]byte, uint64, bool, []vm.ProxyResolution, error) {
 	defer func(start time.Time) { log.Debug("Executing EVM call finished", "runtime", time.Since(start)) }(time.Now())
 	state, header, err := s.b.StateAndHeaderByNumber(ctx, blockNr, args.IsPrivate)
 
 	if state == nil || err != nil {
-		return nil, 0, false, err
+		return nil, 0, false, nil, err
 	}
 	// Set sender address or use a default if none specified
 	addr := args.From
@@ -754,7 +887,7 @@ func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr
 	// Get a new instance of the EVM.
 	evm, vmError, err := s.b.GetEVM(ctx, msg, state, header, vmCfg)
 	if err != nil {
-		return nil, 0, false, err
+		return nil, 0, false, nil, err
 	}
 	// Wait for the context to be done and cancel the evm. Even if the
 	// EVM has finished, cancelling may be done (repeatedly)
@@ -768,19 +901,47 @@ func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr
 	gp := new(core.GasPool).AddGas(math.MaxUint64)
 	res, gas, failed, err := core.ApplyMessage(evm, msg, gp)
 	if err := vmError(); err != nil {
-		return nil, 0, false, err
+		return nil, 0, false, nil, err
 	}
 
-	return res, gas, failed, err
+	return res, gas, failed, evm.ProxyResolutions(), err
 }
 
 // Call executes the given transaction on the state for the given block number.
 // It doesn't make and changes in the state/blockchain and is useful to execute and retrieve values.
 func (s *PublicBlockChainAPI) Call(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber) (hexutil.Bytes, error) {
-	result, _, _, err := s.doCall(ctx, args, blockNr, vm.Config{}, 5*time.Second)
+	result, _, _, _, err := s.doCall(ctx, args, blockNr, vm.Config{}, 5*time.Second)
 	return (hexutil.Bytes)(result), err
 }
 
+// ProxyCallResult is the result of a call along with the proxy contracts resolved to real ones during it.
+type ProxyCallResult struct {
+	ReturnValue hexutil.Bytes        `json:"returnValue"`
+	Failed      bool                 `json:"failed"`
+	Proxies     []vm.ProxyResolution `json:"proxies"`
+}
+
+// CallWithProxies executes the given transaction like Call, reporting the proxy contracts called and the real
+// contracts they are resolved to. If version is given, the proxy contract called is resolved as of that version
+// of the register, i.e. by getOldContract, instead of the current one.
+func (s *PublicBlockChainAPI) CallWithProxies(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber, version *hexutil.Uint64) (*ProxyCallResult, error) {
+	var vmCfg vm.Config
+	if version != nil {
+		if args.To == nil {
+			return nil, errors.New("a proxy version is given for a contract creation")
+		}
+		vmCfg.ProxyVersions = map[common.Address]uint64{*args.To: uint64(*version)}
+	}
+	result, _, failed, proxies, err := s.doCall(ctx, args, blockNr, vmCfg, 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	if proxies == nil {
+		proxies = []vm.ProxyResolution{}
+	}
+	return &ProxyCallResult{ReturnValue: result, Failed: failed, Proxies: proxies}, nil
+}
+
 // EstimateGas returns an estimate of the amount of gas needed to execute the
 // given transaction against the current pending block.
 func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs) (hexutil.Uint64, error) {
@@ -806,7 +967,7 @@ func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs) (h
 	executable := func(gas uint64) bool {
 		args.Gas = hexutil.Uint64(gas)
 
-		_, _, failed, err := s.doCall(ctx, args, rpc.PendingBlockNumber, vm.Config{}, 0)
+		_, _, failed, _, err := s.doCall(ctx, args, rpc.PendingBlockNumber, vm.Config{}, 0)
 		if err != nil || failed {
 			return false
 		}
@@ -834,10 +995,11 @@ func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs) (h
 // while replaying a transaction in debug mode as well as transaction
 // execution status, the amount of gas used and the return value
 type ExecutionResult struct {
-	Gas         uint64         `json:"gas"`
-	Failed      bool           `json:"failed"`
-	ReturnValue string         `json:"returnValue"`
-	StructLogs  []StructLogRes `json:"structLogs"`
+	Gas         uint64               `json:"gas"`
+	Failed      bool                 `json:"failed"`
+	ReturnValue string               `json:"returnValue"`
+	StructLogs  []StructLogRes       `json:"structLogs"`
+	Proxies     []vm.ProxyResolution `json:"proxies,omitempty"` // proxy contracts resolved to real ones
 }
 
 // StructLogRes stores a structured log emitted by the EVM while replaying a
@@ -1166,15 +1328,11 @@ func (s *PublicTransactionPoolAPI) GetAllTransactionsByBlockNumberAndIndex(ctx c
 				go func(index int, tx *types.Transaction) {
 					rpcTx := newRPCTransaction(tx, block.Hash(), block.NumberU64(), uint64(index)+uint64(from))
 
-					var receipt *types.Receipt
-					supportPrivate, _ := s.b.SupportPrivateTx(ctx)
-					if tx.IsPrivate() && supportPrivate {
-						receipt, _ = s.b.GetPrivateReceipt(ctx, tx.Hash())
-					} else {
-						receipt = receipts[uint64(index)+uint64(from)]
-					}
-					if err != nil {
-						log.Error(err.Error())
+					receipt := ReceiptOf(ctx, s.b, tx, receipts, uint64(index)+uint64(from))
+					if receipt == nil {
+						log.Error("receipt not found", "hash", tx.Hash())
+						errCh <- true
+						return
 					}
 					gasUsed := hexutil.Uint64(receipt.GasUsed)
 					status := hexutil.Uint(receipt.Status)
@@ -1283,6 +1441,20 @@ func (s *PublicTransactionPoolAPI) GetRawTransactionByHash(ctx context.Context,
 	return rlp.EncodeToBytes(tx)
 }
 
+// ReceiptOf returns the private receipt of a private tx if this node participates in it,
+// otherwise the public receipt at index of the block receipts.
+func ReceiptOf(ctx context.Context, b Backend, tx *types.Transaction, receipts types.Receipts, index uint64) *types.Receipt {
+	if tx.IsPrivate() {
+		if receipt, _ := b.GetPrivateReceipt(ctx, tx.Hash()); receipt != nil {
+			return receipt
+		}
+	}
+	if uint64(len(receipts)) <= index {
+		return nil
+	}
+	return receipts[index]
+}
+
 // GetTransactionReceipt returns the transaction receipt for the given transaction hash.
 func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, hash common.Hash) (map[string]interface{}, error) {
 	tx, blockHash, blockNumber, index := rawdb.ReadTransaction(s.b.ChainDb(), hash)
@@ -1290,21 +1462,13 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, ha
 		return nil, nil
 	}
 
-	var receipt *types.Receipt
-	if tx.IsPrivate() {
-		receipt, _ = s.b.GetPrivateReceipt(ctx, tx.Hash())
-		if receipt == nil {
-			return nil, nil
-		}
-	} else {
-		receipts, err := s.b.GetReceipts(ctx, blockHash)
-		if err != nil {
-			return nil, err
-		}
-		if len(receipts) <= int(index) {
-			return nil, nil
-		}
-		receipt = receipts[index]
+	receipts, err := s.b.GetReceipts(ctx, blockHash)
+	if err != nil {
+		return nil, err
+	}
+	receipt := ReceiptOf(ctx, s.b, tx, receipts, index)
+	if receipt == nil {
+		return nil, nil
 	}
 
 	var signer types.Signer = types.FrontierSigner{}
@@ -1373,6 +1537,9 @@ type SendTxArgs struct {
 	Input *hexutil.Bytes `json:"input"`
 
 	Type *hexutil.Uint64 `json:"type"`
+
+	// Participants are public keys of accounts a private tx is sealed for
+	Participants []string `json:"participants"`
 }
 
 // setDefaults is a helper function that fills in default values for unspecified tx fields.
@@ -1421,6 +1588,47 @@ func (args *SendTxArgs) setDefaults(ctx context.Context, b Backend) error {
 	return nil
 }
 
+// sealPrivatePayload replaces the payload of a private tx with the reference to its sealed payload in remote database,
+// only participants can decrypt the original payload.
+func (args *SendTxArgs) sealPrivatePayload(remoteDB database.RemoteDatabase) error {
+	var input []byte
+	if args.Data != nil {
+		input = *args.Data
+	} else if args.Input != nil {
+		input = *args.Input
+	}
+	// If there is no payload, it must be the transaction of transferring tokens, that should be always public.
+	if len(args.Participants) == 0 || len(input) == 0 {
+		return InvalidPrivateTxErr
+	}
+
+	payloadReplace, err := private.SealPrivatePayload(input, uint64(*args.Nonce), args.Participants, remoteDB)
+	if err != nil {
+		return err
+	}
+	log.Debug("Payload replacement for private transaction", "payload", common.Bytes2Hex(payloadReplace.TxPayload))
+
+	// Replace original content with the secure one.
+	replaceData, err := rlp.EncodeToBytes(payloadReplace)
+	if err != nil {
+		return err
+	}
+	args.Data, args.Input = (*hexutil.Bytes)(&replaceData), nil
+	return nil
+}
+
+// checkTxType returns an error if the tx type is not supported in the next block.
+func checkTxType(b Backend, txType uint64) error {
+	next := new(big.Int).Add(b.CurrentBlock().Number(), big.NewInt(1))
+	if types.SupportTxType(b.ChainConfig(), next, txType) {
+		return nil
+	}
+	if txType == types.PrivateTx {
+		return NotSupportPrivateTxErr
+	}
+	return types.ErrNotSupportedTxType
+}
+
 func (args *SendTxArgs) toTransaction() *types.Transaction {
 	var input []byte
 	if args.Data != nil {
@@ -1464,25 +1672,9 @@ func submitTransaction(ctx context.Context, b Backend, tx *types.Transaction) (c
 	return tx.Hash(), nil
 }
 
-var ErrExceedProcessRate = errors.New("exceed transaction process rate")
-
-var (
-	lr TokenBucket
-)
-
-func init() {
-	lr.Set(40, 40*5)
-}
-
 // SendTransaction creates a transaction for the given argument, sign it and submit it to the
 // transaction pool.
 func (s *PublicTransactionPoolAPI) SendTransaction(ctx context.Context, args SendTxArgs) (common.Hash, error) {
-
-	if !lr.Allow() {
-		log.Warn("exceed rpc process rate")
-		return common.Hash{}, ErrExceedProcessRate
-	}
-
 	// Look up the wallet containing the requested signer
 	account := accounts.Account{Address: args.From}
 
@@ -1503,26 +1695,15 @@ func (s *PublicTransactionPoolAPI) SendTransaction(ctx context.Context, args Sen
 		return common.Hash{}, err
 	}
 
-	if !types.SupportTxType(uint64(*args.Type)) {
-		return common.Hash{}, types.ErrNotSupportedTxType
+	if err := checkTxType(s.b, uint64(*args.Type)); err != nil {
+		return common.Hash{}, err
 	}
 
-	// if args.IsPrivate {
-	// 	// If args.Data is nil, it must be the transaction of transferring tokens, that should be always public.
-	// 	if len(args.Participants) == 0 || args.Data == nil {
-	// 		return common.Hash{}, InvalidPrivateTxErr
-	// 	}
-
-	// 	payloadReplace, err := private.SealPrivatePayload(([]byte)(*args.Data), (uint64)(*args.Nonce), args.Participants, s.b.RemoteDB())
-	// 	if err != nil {
-	// 		return common.Hash{}, err
-	// 	}
-	// 	log.Info("Payload replacement for private transaction", "payloadReplace", payloadReplace)
-
-	// 	// Replace original content with security one.
-	// 	replaceData, _ := rlp.EncodeToBytes(payloadReplace)
-	// 	args.Data = (*hexutil.Bytes)(&replaceData)
-	// }
+	if uint64(*args.Type) == types.PrivateTx {
+		if err := args.sealPrivatePayload(s.b.RemoteDB()); err != nil {
+			return common.Hash{}, err
+		}
+	}
 
 	// Assemble the transaction and sign with the wallet
 	tx := args.toTransaction()
@@ -1543,8 +1724,8 @@ func (s *PublicTransactionPoolAPI) SendRawTransaction(ctx context.Context, encod
 		return common.Hash{}, err
 	}
 
-	if !types.SupportTxType(tx.Type()) {
-		return common.Hash{}, types.ErrNotSupportedTxType
+	if err := checkTxType(s.b, tx.Type()); err != nil {
+		return common.Hash{}, err
 	}
 
 	return submitTransaction(ctx, s.b, tx)
@@ -1725,32 +1906,23 @@ func NewPrivateDebugAPI(b Backend) *PrivateDebugAPI {
 	return &PrivateDebugAPI{b: b}
 }
 
-// ChaindbProperty returns leveldb properties of the chain database.
+// ChaindbProperty returns engine specific properties of the chain database.
 func (api *PrivateDebugAPI) ChaindbProperty(property string) (string, error) {
-	ldb, ok := api.b.ChainDb().(interface {
-		LDB() *leveldb.DB
-	})
+	db, ok := api.b.ChainDb().(database.Stater)
 	if !ok {
 		return "", fmt.Errorf("chaindbProperty does not work for memory databases")
 	}
-	if property == "" {
-		property = "leveldb.stats"
-	} else if !strings.HasPrefix(property, "leveldb.") {
-		property = "leveldb." + property
-	}
-	return ldb.LDB().GetProperty(property)
+	return db.Stat(property)
 }
 
 func (api *PrivateDebugAPI) ChaindbCompact() error {
-	ldb, ok := api.b.ChainDb().(interface {
-		LDB() *leveldb.DB
-	})
+	db, ok := api.b.ChainDb().(database.Compacter)
 	if !ok {
 		return fmt.Errorf("chaindbCompact does not work for memory databases")
 	}
 	for b := byte(0); b < 255; b++ {
 		log.Info("Compacting chain database", "range", fmt.Sprintf("0x%0.2X-0x%0.2X", b, b+1))
-		err := ldb.LDB().CompactRange(util.Range{Start: []byte{b}, Limit: []byte{b + 1}})
+		err := db.Compact([]byte{b}, []byte{b + 1})
 		if err != nil {
 			log.Error("Database compaction failed", "err", err)
 			return err
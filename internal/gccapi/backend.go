@@ -43,6 +43,7 @@ type Backend interface {
 	SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subscription
 	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
 	SubscribeChainSideEvent(ch chan<- core.ChainSideEvent) event.Subscription
+	AddressIndexHead() (uint64, bool)
 	Proposers(blockNr rpc.BlockNumber) ([]common.Address, error)
 	Validators(blockNr rpc.BlockNumber) ([]common.Address, error)
 	ProposerOf(blockNr rpc.BlockNumber) (common.Address, error)
@@ -55,6 +56,10 @@ type Backend interface {
 	Stats() (pending int, queued int)
 	TxPoolContent() (map[common.Address]types.Transactions, map[common.Address]types.Transactions)
 	SubscribeNewTxsEvent(chan<- core.NewTxsEvent) event.Subscription
+	GetPoolDroppedTransaction(txHash common.Hash) *core.DroppedTx
+	GetPoolReplacementPrice(tx *types.Transaction) *big.Int
+	TxReplaceFeeCap() float64 // maximum fee in gcc of a transaction replaced by txpool_replace, 0 for no cap
+	SubscribeDroppedTxsEvent(chan<- core.DroppedTxsEvent) event.Subscription
 
 	ChainConfig() *configs.ChainConfig
 	CurrentBlock() *types.Block
@@ -95,6 +100,11 @@ func GetAPIs(apiBackend Backend) []rpc.API {
 			Version:   "1.0",
 			Service:   NewPublicTransactionPoolAPI(apiBackend, nonceLock),
 			Public:    true,
+		}, {
+			Namespace: "gcc",
+			Version:   "1.0",
+			Service:   NewPublicAddressIndexAPI(apiBackend),
+			Public:    true,
 		}, {
 			Namespace: "txpool",
 			Version:   "1.0",
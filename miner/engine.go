@@ -58,6 +58,7 @@ type Work struct {
 	remoteDB  database.RemoteDatabase // ipfs database used for private tx processing
 	tcount    int                     // tx count in cycle
 	gasPool   *core.GasPool           // available gas used to pack transactions
+	proxies   *vm.ProxyCache          // real contracts of proxy contracts resolved in the block
 
 	Block *types.Block // the new block
 
@@ -369,6 +370,7 @@ func (e *engine) makeCurrentWork(parent *types.Block, header *types.Header) erro
 		createdAt: time.Now(),
 		remoteDB:  e.chain.RemoteDB(),
 		accm:      e.backend.AccountManager(),
+		proxies:   vm.NewProxyCache(),
 	}
 
 	// Keep track of transactions which return errors so they can be removed
@@ -576,7 +578,7 @@ func (w *Work) commitTransaction(tx *types.Transaction, bc *core.BlockChain, coi
 	snapPriv := w.privState.Snapshot()
 
 	pubReceipt, privReceipt, _, err := core.ApplyTransaction(w.config, bc, &coinbase, gp, w.pubState, w.privState, w.remoteDB,
-		w.header, tx, &w.header.GasUsed, vm.Config{}, w.accm)
+		w.header, tx, &w.header.GasUsed, vm.Config{ProxyCache: w.proxies}, w.accm)
 	if err != nil {
 		w.pubState.RevertToSnapshot(snap)
 		w.privState.RevertToSnapshot(snapPriv)
@@ -14,8 +14,12 @@ import (
 	"strings"
 
 	"github.com/gcchains/chain/accounts"
+	"github.com/gcchains/chain/accounts/external"
 	"github.com/gcchains/chain/accounts/keystore"
+	"github.com/gcchains/chain/api/rpc"
 	"github.com/gcchains/chain/configs"
+	"github.com/gcchains/chain/core/rawdb"
+	"github.com/gcchains/chain/database"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
@@ -32,6 +36,9 @@ const (
 	datadirNodeDatabase    = "nodes"              // Path within the datadir to store the node infos
 )
 
+// DefaultFreezerDir is the path within a database to its freezer if none is configured.
+const DefaultFreezerDir = "ancient"
+
 // Config represents a small collection of configuration values to fine tune the
 // P2P network layer of a protocol stack. These values can be further extended by
 // all registered services.
@@ -74,6 +81,14 @@ type Config struct {
 	// NoUSB disables hardware wallet monitoring and connectivity.
 	NoUSB bool `toml:",omitempty"`
 
+	// ExternalSigner is the endpoint of a remote signer, an IPC path or an HTTP URL, whose
+	// accounts are added to the account manager. Empty disables it.
+	ExternalSigner string `toml:",omitempty"`
+
+	// ExternalSignerSecret is the shared secret file authenticating the node to the remote
+	// signer over HTTP, empty if it requires no authentication.
+	ExternalSignerSecret string `toml:",omitempty"`
+
 	// IPCPath is the requested location to place the IPC endpoint. If the path is
 	// a simple file name, it is placed inside the data directory (or on the root
 	// pipe path on Windows), whereas if it's a resolvable path name (absolute or
@@ -133,6 +148,19 @@ type Config struct {
 	// private APIs to untrusted users is a major security risk.
 	WSExposeAll bool `toml:",omitempty"`
 
+	// DBEngine is the engine of databases created by the node, see database.Engines. Existing
+	// databases are opened with the engine they were created with, empty means LevelDB.
+	DBEngine string `toml:",omitempty"`
+
+	// RPCLimits is the per client rate limits of the HTTP and websocket RPC interfaces,
+	// requests over IPC and in-process are never limited. Nil disables rate limiting.
+	RPCLimits *rpc.LimiterConfig `toml:",omitempty"`
+
+	// RPCAuth requires the clients of the HTTP and websocket RPC interfaces to authenticate
+	// with the shared secret and restricts them to the namespaces of their role. Requests over
+	// IPC and in-process are always allowed. Nil disables authentication.
+	RPCAuth *rpc.AuthConfig `toml:",omitempty"`
+
 	// Logger is a custom logger to use with the p2p.Server.
 	Logger log.Logger `toml:",omitempty"`
 }
@@ -191,6 +219,27 @@ func (c *Config) HTTPEndpoint() string {
 	return fmt.Sprintf("%s:%d", c.HTTPHost, c.HTTPPort)
 }
 
+// rpcLimiter creates the rate limiter of the RPC interfaces, or returns nil if unlimited.
+func (c *Config) rpcLimiter() *rpc.Limiter {
+	if c.RPCLimits == nil {
+		return nil
+	}
+	return rpc.NewLimiter(*c.RPCLimits)
+}
+
+// rpcAuthenticator creates the authenticator of the RPC interfaces, or returns nil if
+// authentication is disabled. A relative secret file is resolved in the instance directory.
+func (c *Config) rpcAuthenticator() (*rpc.Authenticator, error) {
+	if c.RPCAuth == nil {
+		return nil, nil
+	}
+	config := *c.RPCAuth
+	if resolved := c.resolvePath(config.SecretFile); resolved != "" {
+		config.SecretFile = resolved
+	}
+	return rpc.NewAuthenticator(config)
+}
+
 // DefaultHTTPEndpoint returns the HTTP endpoint used by default.
 func DefaultHTTPEndpoint() string {
 	config := &Config{HTTPHost: DefaultHTTPHost, HTTPPort: DefaultHTTPPort}
@@ -249,6 +298,34 @@ func (c *Config) resolvePath(path string) string {
 	return filepath.Join(c.instanceDir(), path)
 }
 
+// openDatabaseWithFreezer opens the database with the name in the instance directory backed by a
+// freezer, which is only opened if the threshold is set or a previous run left one.
+func (c *Config) openDatabaseWithFreezer(name string, cache, handles int, freezer string, threshold uint64) (database.Database, error) {
+	if c.DataDir == "" {
+		return database.NewMemDatabase(), nil
+	}
+	root := c.resolvePath(name)
+	db, err := database.Open(root, c.DBEngine, cache, handles)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case freezer == "":
+		freezer = filepath.Join(root, DefaultFreezerDir)
+	case !filepath.IsAbs(freezer):
+		freezer = c.resolvePath(freezer)
+	}
+	if threshold == 0 && !common.FileExist(freezer) {
+		return db, nil
+	}
+	frdb, err := rawdb.NewDatabaseWithFreezer(db, freezer, threshold)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return frdb, nil
+}
+
 func (c *Config) instanceDir() string {
 	if c.DataDir == "" {
 		return ""
@@ -383,6 +460,21 @@ func makeAccountManager(conf *Config) (*accounts.Manager, string, error) {
 	backends := []accounts.Backend{
 		keystore.NewKeyStore(keydir, scryptN, scryptP),
 	}
+	if conf.ExternalSigner != "" {
+		var auth rpc.AuthProvider
+		if conf.ExternalSignerSecret != "" {
+			secret, err := rpc.ReadAuthSecret(conf.ExternalSignerSecret)
+			if err != nil {
+				return nil, "", err
+			}
+			auth = rpc.NewHMACAuth(secret)
+		}
+		signer, err := external.NewExternalBackend(conf.ExternalSigner, auth)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to connect to the external signer %s: %v", conf.ExternalSigner, err)
+		}
+		backends = append(backends, signer)
+	}
 	return accounts.NewManager(backends...), ephemeral, nil
 }
 
@@ -406,6 +498,12 @@ var DefaultConfig = Config{
 	HTTPVirtualHosts: []string{"localhost"},
 	WSPort:           DefaultWSPort,
 	WSModules:        []string{"net", "web3", "eth"},
+	RPCLimits: &rpc.LimiterConfig{
+		KeyBy: rpc.KeyByRemote,
+		Methods: map[string]rpc.RateLimit{
+			"eth_sendTransaction": {Rate: 40, Burst: 40 * 5},
+		},
+	},
 	P2P: p2p.Config{
 		ListenAddr: ":30310",
 		MaxPeers:   25,
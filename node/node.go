@@ -40,8 +40,11 @@ type Node struct {
 	serviceFuncs []ServiceConstructor     // Service constructors (in dependency order)
 	services     map[reflect.Type]Service // Currently running services
 
-	rpcAPIs       []rpc.API   // List of APIs currently provided by the node
-	inprocHandler *rpc.Server // In-process RPC request handler to process the API requests
+	rpcAPIs       []rpc.API          // List of APIs currently provided by the node
+	rpcHandlers   []rpc.HTTPHandler  // HTTP handlers of the services served on the HTTP endpoint
+	rpcLimiter    *rpc.Limiter       // Rate limiter of the HTTP and websocket clients, nil if unlimited
+	rpcAuth       *rpc.Authenticator // Authenticator of the HTTP and websocket clients, nil if open
+	inprocHandler *rpc.Server        // In-process RPC request handler to process the API requests
 
 	ipcEndpoint string       // IPC endpoint to listen at (empty = IPC disabled)
 	ipcListener net.Listener // IPC RPC listener socket to serve API requests
@@ -85,12 +88,19 @@ func New(conf *Config) (*Node, error) {
 	if strings.HasSuffix(conf.Name, ".ipc") {
 		return nil, errors.New(`Config.Name cannot end in ".ipc"`)
 	}
+	if err := database.ValidateEngine(conf.DBEngine); err != nil {
+		return nil, err
+	}
 	// Ensure that the AccountManager method works before the node has started.
 	// We rely on this in cmd/gcchain.
 	am, ephemeralKeystore, err := makeAccountManager(conf)
 	if err != nil {
 		return nil, err
 	}
+	rpcAuth, err := conf.rpcAuthenticator()
+	if err != nil {
+		return nil, err
+	}
 	if conf.Logger == nil {
 		// TODO @xumx switch to gcchain logger.  need to add the trace function
 		conf.Logger = log.New()
@@ -104,6 +114,8 @@ func New(conf *Config) (*Node, error) {
 		serviceFuncs:      []ServiceConstructor{},
 		ipcEndpoint:       conf.IPCEndpoint(),
 		httpEndpoint:      conf.HTTPEndpoint(),
+		rpcLimiter:        conf.rpcLimiter(),
+		rpcAuth:           rpcAuth,
 		wsEndpoint:        conf.WSEndpoint(),
 		eventmux:          new(event.TypeMux),
 		log:               conf.Logger,
@@ -236,8 +248,12 @@ func (n *Node) Start() error {
 func (n *Node) startRPC(services map[reflect.Type]Service) error {
 	// Gather all the possible APIs to surface
 	apis := n.apis()
+	var handlers []rpc.HTTPHandler
 	for _, service := range services {
 		apis = append(apis, service.APIs()...)
+		if service, ok := service.(HTTPService); ok {
+			handlers = append(handlers, service.HTTPHandlers()...)
+		}
 	}
 	// Start the various API endpoints, terminating all in case of errors
 	if err := n.startInProc(apis); err != nil {
@@ -247,7 +263,7 @@ func (n *Node) startRPC(services map[reflect.Type]Service) error {
 		n.stopInProc()
 		return err
 	}
-	if err := n.startHTTP(n.httpEndpoint, apis, n.config.HTTPModules, n.config.HTTPCors, n.config.HTTPVirtualHosts); err != nil {
+	if err := n.startHTTP(n.httpEndpoint, apis, handlers, n.config.HTTPModules, n.config.HTTPCors, n.config.HTTPVirtualHosts); err != nil {
 		n.stopIPC()
 		n.stopInProc()
 		return err
@@ -260,6 +276,7 @@ func (n *Node) startRPC(services map[reflect.Type]Service) error {
 	}
 	// All API endpoints started successfully
 	n.rpcAPIs = apis
+	n.rpcHandlers = handlers
 	return nil
 }
 
@@ -325,12 +342,12 @@ func (n *Node) stopIPC() {
 }
 
 // startHTTP initializes and starts the HTTP RPC endpoint.
-func (n *Node) startHTTP(endpoint string, apis []rpc.API, modules []string, cors []string, vhosts []string) error {
+func (n *Node) startHTTP(endpoint string, apis []rpc.API, handlers []rpc.HTTPHandler, modules []string, cors []string, vhosts []string) error {
 	// Short circuit if the HTTP endpoint isn't being exposed
 	if endpoint == "" {
 		return nil
 	}
-	listener, handler, err := rpc.StartHTTPEndpoint(endpoint, apis, modules, cors, vhosts)
+	listener, handler, err := rpc.StartHTTPEndpoint(endpoint, apis, handlers, modules, cors, vhosts, n.rpcLimiter, n.rpcAuth)
 	if err != nil {
 		return err
 	}
@@ -363,7 +380,7 @@ func (n *Node) startWS(endpoint string, apis []rpc.API, modules []string, wsOrig
 	if endpoint == "" {
 		return nil
 	}
-	listener, handler, err := rpc.StartWSEndpoint(endpoint, apis, modules, wsOrigins, exposeAll)
+	listener, handler, err := rpc.StartWSEndpoint(endpoint, apis, modules, wsOrigins, exposeAll, n.rpcLimiter, n.rpcAuth)
 	if err != nil {
 		return err
 	}
@@ -577,7 +594,15 @@ func (n *Node) OpenDatabase(name string, cache, handles int) (database.Database,
 	if n.config.DataDir == "" {
 		return database.NewMemDatabase(), nil
 	}
-	return database.NewLDBDatabase(n.config.resolvePath(name), cache, handles)
+	return database.Open(n.config.resolvePath(name), n.config.DBEngine, cache, handles)
+}
+
+// OpenDatabaseWithFreezer opens an existing database with the given name (or
+// creates one if no previous can be found) from within the node's instance
+// directory, backed by a freezer for the ancient blocks. See
+// ServiceContext.OpenDatabaseWithFreezer.
+func (n *Node) OpenDatabaseWithFreezer(name string, cache, handles int, freezer string, threshold uint64) (database.Database, error) {
+	return n.config.openDatabaseWithFreezer(name, cache, handles, freezer, threshold)
 }
 
 // ResolvePath returns the absolute path of a resource in the instance directory.
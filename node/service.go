@@ -29,13 +29,24 @@ func (ctx *ServiceContext) OpenDatabase(name string, cache int, handles int) (da
 	if ctx.config.DataDir == "" {
 		return database.NewMemDatabase(), nil
 	}
-	db, err := database.NewLDBDatabase(ctx.config.resolvePath(name), cache, handles)
+	db, err := database.Open(ctx.config.resolvePath(name), ctx.config.DBEngine, cache, handles)
 	if err != nil {
 		return nil, err
 	}
 	return db, nil
 }
 
+// OpenDatabaseWithFreezer opens an existing database with the given name (or
+// creates one if no previous can be found) from within the node's data directory,
+// backed by a freezer for the ancient blocks in the freezer directory, "ancient"
+// in the database if empty. The freezer moves the canonical blocks older than
+// threshold blocks out of the database, a freezer left by a previous run is still
+// read with a threshold of 0. If the node is an ephemeral one, a memory database
+// is returned.
+func (ctx *ServiceContext) OpenDatabaseWithFreezer(name string, cache int, handles int, freezer string, threshold uint64) (database.Database, error) {
+	return ctx.config.openDatabaseWithFreezer(name, cache, handles, freezer, threshold)
+}
+
 // ResolvePath resolves a user path into the data directory if that was relative
 // and if the user actually uses persistent storage. It will return an empty string
 // for emphemeral storage and the user's own input for absolute paths.
@@ -82,3 +93,10 @@ type Service interface {
 	// are all terminated.
 	Stop() error
 }
+
+// HTTPService is implemented by services serving HTTP handlers on the HTTP RPC endpoint besides
+// their APIs, such as the GraphQL service.
+type HTTPService interface {
+	// HTTPHandlers retrieves the handlers the service serves, it is called after Start.
+	HTTPHandlers() []rpc.HTTPHandler
+}
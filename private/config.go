@@ -7,16 +7,17 @@ const (
 	Dummy          = "dummy"
 	IPFS           = "ipfs"
 	Swarm          = "swarm"
-)
 
-var (
-	// Indicate whether support private transaction functionality
-	SupportPrivateTx string
+	// Local keeps sealed payloads in a local content-addressed database, RemoteDBParams is not used
+	Local = "local"
 )
 
 type Config struct {
 	RemoteDBParams string
 	RemoteDBType   string
+
+	// FetchFromPeers enables retrieving payloads missing in the local database from peers, only used by Local type
+	FetchFromPeers bool
 }
 
 func DefaultConfig() Config {
@@ -0,0 +1,252 @@
+// Copyright 2018 The gcchain authors
+
+package gcc
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/gcchains/chain/commons/log"
+	"github.com/gcchains/chain/configs"
+	"github.com/gcchains/chain/core"
+	"github.com/gcchains/chain/core/rawdb"
+	"github.com/gcchains/chain/database"
+	"github.com/gcchains/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+const (
+	// addrIndexSize is the number of blocks of an address index section, every block is a
+	// section to keep the index close to the head.
+	addrIndexSize = 1
+
+	// addrIndexConfirms is the number of confirmation blocks before a block is indexed, reorgs
+	// are rolled back instead.
+	addrIndexConfirms = 0
+)
+
+var errAddrIndexClosed = errors.New("address indexer closed")
+
+// sideChain is the chain the address indexer receives side blocks from.
+type sideChain interface {
+	SubscribeChainSideEvent(ch chan<- core.ChainSideEvent) event.Subscription
+}
+
+// addrIndexedBlock holds the addresses of the transactions of a processed block until committed.
+type addrIndexedBlock struct {
+	number uint64
+	hash   common.Hash
+	txs    [][]common.Address // addresses of every transaction
+}
+
+// AddressIndexer implements a core.ChainIndexer, maintaining for every address the positions
+// of the transactions sent from it, sent to it or creating it.
+type AddressIndexer struct {
+	db     database.Database
+	config *configs.ChainConfig
+
+	blocks []addrIndexedBlock // blocks processed in the current section
+	err    error              // error of processing the current section
+
+	lock sync.Mutex // protects the index from concurrent commits and rollbacks
+	quit chan struct{}
+}
+
+// NewAddressIndexer returns a chain indexer that maintains the address index of the canonical
+// chain, along with its backend which has to be started to roll back side blocks eagerly.
+func NewAddressIndexer(db database.Database, config *configs.ChainConfig) (*core.ChainIndexer, *AddressIndexer) {
+	backend := &AddressIndexer{
+		db:     db,
+		config: config,
+		quit:   make(chan struct{}),
+	}
+	table := database.NewTable(db, string(rawdb.AddrIndexPrefix))
+
+	return core.NewChainIndexer(db, table, backend, addrIndexSize, addrIndexConfirms, 0, "addrindex"), backend
+}
+
+// Reset implements core.ChainIndexerBackend, rolling back the blocks of the section and the
+// following ones, which were indexed before a reorg.
+func (b *AddressIndexer) Reset(section uint64, lastSectionHead common.Hash) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.blocks, b.err = nil, nil
+	return b.rollback(section * addrIndexSize)
+}
+
+// Process implements core.ChainIndexerBackend, collecting the addresses of the transactions
+// of a block.
+func (b *AddressIndexer) Process(header *types.Header) {
+	if b.err != nil {
+		return
+	}
+	number, hash := header.Number.Uint64(), header.Hash()
+	body := rawdb.ReadBody(b.db, hash, number)
+	if body == nil {
+		b.err = fmt.Errorf("block body #%d [%x…] not found", number, hash[:4])
+		return
+	}
+	var receipts types.Receipts
+	if len(body.Transactions) > 0 {
+		receipts = rawdb.ReadReceipts(b.db, hash, number)
+	}
+	signer := types.MakeSigner(b.config)
+
+	block := addrIndexedBlock{number: number, hash: hash, txs: make([][]common.Address, len(body.Transactions))}
+	for i, tx := range body.Transactions {
+		var addrs []common.Address
+		if from, err := types.Sender(signer, tx); err == nil {
+			addrs = append(addrs, from)
+		} else {
+			log.Warn("Failed to derive sender of indexed transaction", "hash", tx.Hash(), "err", err)
+		}
+		if to := tx.To(); to != nil {
+			addrs = appendAddress(addrs, *to)
+		} else if i < len(receipts) && receipts[i].ContractAddress != (common.Address{}) {
+			addrs = appendAddress(addrs, receipts[i].ContractAddress)
+		}
+		block.txs[i] = addrs
+	}
+	b.blocks = append(b.blocks, block)
+}
+
+// Commit implements core.ChainIndexerBackend, appending the entries of the processed blocks
+// to the index.
+func (b *AddressIndexer) Commit() error {
+	if b.err != nil {
+		return b.err
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	batch := b.db.NewBatch()
+	counts := make(map[common.Address]uint64)
+	for _, block := range b.blocks {
+		var touched []common.Address
+		for i, addrs := range block.txs {
+			for _, addr := range addrs {
+				count, ok := counts[addr]
+				if !ok {
+					count = rawdb.ReadAddrIndexCount(b.db, addr)
+				}
+				touched = appendAddress(touched, addr)
+				rawdb.WriteAddrIndexEntry(batch, addr, count, rawdb.AddrIndexEntry{BlockNumber: block.number, Index: uint64(i)})
+				counts[addr] = count + 1
+			}
+		}
+		rawdb.WriteAddrIndexBlock(batch, block.number, &rawdb.AddrIndexBlock{Hash: block.hash, Addresses: touched})
+	}
+	for addr, count := range counts {
+		rawdb.WriteAddrIndexCount(batch, addr, count)
+	}
+	b.blocks = nil
+	return batch.Write()
+}
+
+// rollback removes the index entries of the block with the given number and all the following
+// ones. The caller must hold the lock.
+func (b *AddressIndexer) rollback(from uint64) error {
+	last := from
+	for rawdb.ReadAddrIndexBlock(b.db, last) != nil {
+		last++
+	}
+	if last == from {
+		return nil
+	}
+	batch := b.db.NewBatch()
+	counts := make(map[common.Address]uint64)
+	for number := last; number > from; number-- {
+		block := rawdb.ReadAddrIndexBlock(b.db, number-1)
+		for _, addr := range block.Addresses {
+			count, ok := counts[addr]
+			if !ok {
+				count = rawdb.ReadAddrIndexCount(b.db, addr)
+			}
+			for count > 0 {
+				entry, ok := rawdb.ReadAddrIndexEntry(b.db, addr, count-1)
+				if ok && entry.BlockNumber < number-1 {
+					break
+				}
+				rawdb.DeleteAddrIndexEntry(batch, addr, count-1)
+				count--
+			}
+			counts[addr] = count
+		}
+		rawdb.DeleteAddrIndexBlock(batch, number-1)
+	}
+	for addr, count := range counts {
+		if count == 0 {
+			rawdb.DeleteAddrIndexCount(batch, addr)
+		} else {
+			rawdb.WriteAddrIndexCount(batch, addr, count)
+		}
+	}
+	log.Debug("Rolled back address index", "from", from, "blocks", last-from)
+	return batch.Write()
+}
+
+// rollbackSide rolls back the index from the side block if it was indexed as canonical.
+func (b *AddressIndexer) rollbackSide(block *types.Block) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	indexed := rawdb.ReadAddrIndexBlock(b.db, block.NumberU64())
+	if indexed == nil || indexed.Hash != block.Hash() {
+		return nil
+	}
+	return b.rollback(block.NumberU64())
+}
+
+// Start subscribes to side blocks of the chain to roll back the index on reorgs without
+// waiting for the chain indexer to catch up.
+func (b *AddressIndexer) Start(chain sideChain) {
+	events := make(chan core.ChainSideEvent, 16)
+	sub := chain.SubscribeChainSideEvent(events)
+
+	go func() {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case ev := <-events:
+				if err := b.rollbackSide(ev.Block); err != nil {
+					log.Error("Failed to roll back address index", "number", ev.Block.Number(), "err", err)
+				}
+			case <-sub.Err():
+				return
+			case <-b.quit:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops the rollback of side blocks.
+func (b *AddressIndexer) Close() error {
+	select {
+	case <-b.quit:
+		return errAddrIndexClosed
+	default:
+		close(b.quit)
+	}
+	return nil
+}
+
+// appendAddress appends addr to addrs unless it is already in it.
+func appendAddress(addrs []common.Address, addr common.Address) []common.Address {
+	if containsAddress(addrs, addr) {
+		return addrs
+	}
+	return append(addrs, addr)
+}
+
+func containsAddress(addrs []common.Address, addr common.Address) bool {
+	for _, a := range addrs {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,201 @@
+package gcc
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/gcchains/chain/api/rpc"
+	"github.com/gcchains/chain/configs"
+	"github.com/gcchains/chain/consensus/dpos"
+	"github.com/gcchains/chain/core"
+	"github.com/gcchains/chain/core/rawdb"
+	"github.com/gcchains/chain/core/vm"
+	"github.com/gcchains/chain/database"
+	"github.com/gcchains/chain/internal/gccapi"
+	"github.com/gcchains/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// waitAddressIndex waits until the address index covers the chain head.
+func waitAddressIndex(t *testing.T, backend *APIBackend, head uint64) {
+	for i := 0; i < 200; i++ {
+		if indexed, _ := backend.AddressIndexHead(); indexed == head+1 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	indexed, _ := backend.AddressIndexHead()
+	t.Fatalf("address index covers %d blocks, want %d", indexed, head+1)
+}
+
+// sideFeed is a chain sending side blocks to the address indexer.
+type sideFeed struct {
+	feed event.Feed
+}
+
+func (f *sideFeed) SubscribeChainSideEvent(ch chan<- core.ChainSideEvent) event.Subscription {
+	return f.feed.Subscribe(ch)
+}
+
+// indexedBlocks returns the block numbers of the index entries of the address.
+func indexedBlocks(db database.Database, address common.Address) []uint64 {
+	var blocks []uint64
+	for seq := uint64(0); seq < rawdb.ReadAddrIndexCount(db, address); seq++ {
+		entry, _ := rawdb.ReadAddrIndexEntry(db, address, seq)
+		blocks = append(blocks, entry.BlockNumber)
+	}
+	return blocks
+}
+
+func TestAddressIndexReorg(t *testing.T) {
+	var (
+		key, _   = crypto.HexToECDSA("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
+		sender   = crypto.PubkeyToAddress(key.PublicKey)
+		to       = common.HexToAddress("0x0000000000000000000000000000000000000aaa")
+		other    = common.HexToAddress("0x0000000000000000000000000000000000000bbb")
+		db       = database.NewMemDatabase()
+		remoteDB = database.NewIpfsDbWithAdapter(database.NewFakeIpfsAdapter())
+		gspec    = &core.Genesis{
+			Config:   configs.TestChainConfig,
+			GasLimit: 3141592,
+			Alloc:    core.GenesisAlloc{sender: {Balance: big.NewInt(1000000000)}},
+		}
+		genesis = gspec.MustCommit(db)
+		signer  = types.NewCep1Signer(gspec.Config.ChainID)
+		engine  = dpos.NewFaker(configs.ChainConfigInfo().Dpos, db)
+	)
+	transfer := func(gen *core.BlockGen, recipient common.Address) {
+		tx, _ := types.SignTx(types.NewTransaction(gen.TxNonce(sender), recipient, big.NewInt(1), configs.TxGas, nil, nil), signer, key)
+		gen.AddTx(tx)
+	}
+	chain, chainReceipts := core.GenerateChain(gspec.Config, genesis, engine, db, remoteDB, 3, func(i int, gen *core.BlockGen) {
+		transfer(gen, to)
+		if i == 2 {
+			transfer(gen, to)
+		}
+	})
+	fork, forkReceipts := core.GenerateChain(gspec.Config, genesis, engine, db, remoteDB, 4, func(i int, gen *core.BlockGen) {
+		if i == 0 {
+			transfer(gen, to)
+		} else {
+			transfer(gen, other)
+		}
+	})
+	// index imports the blocks as canonical and indexes them like the chain indexer
+	backend := &AddressIndexer{db: db, config: gspec.Config, quit: make(chan struct{})}
+	index := func(blocks []*types.Block, receipts []types.Receipts) {
+		for i, block := range blocks {
+			rawdb.WriteBlock(db, block)
+			rawdb.WriteReceipts(db, block.Hash(), block.NumberU64(), receipts[i])
+			rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		}
+		for _, block := range blocks {
+			if err := backend.Reset(block.NumberU64(), block.ParentHash()); err != nil {
+				t.Fatalf("Reset() error = %v", err)
+			}
+			backend.Process(block.Header())
+			if err := backend.Commit(); err != nil {
+				t.Fatalf("Commit() error = %v", err)
+			}
+		}
+	}
+	check := func(stage string, want map[common.Address][]uint64) {
+		for addr, blocks := range want {
+			if got := indexedBlocks(db, addr); fmt.Sprint(got) != fmt.Sprint(blocks) {
+				t.Errorf("%s: indexed blocks of %x = %v, want %v", stage, addr, got, blocks)
+			}
+		}
+	}
+
+	index(chain, chainReceipts)
+	check("canonical", map[common.Address][]uint64{sender: {1, 2, 3, 3}, to: {1, 2, 3, 3}, other: nil})
+
+	// Side blocks roll the index back to the first reorged block
+	feed := new(sideFeed)
+	backend.Start(feed)
+	defer backend.Close()
+
+	rawdb.WriteCanonicalHash(db, fork[0].Hash(), 1)
+	feed.feed.Send(core.ChainSideEvent{Block: fork[1]})
+	feed.feed.Send(core.ChainSideEvent{Block: chain[1]})
+	for i := 0; i < 200 && rawdb.ReadAddrIndexBlock(db, 2) != nil; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	check("rolled back", map[common.Address][]uint64{sender: {1}, to: {1}, other: nil})
+
+	index(fork, forkReceipts)
+	check("reorged", map[common.Address][]uint64{sender: {1, 2, 3, 4}, to: {1}, other: {2, 3, 4}})
+
+	// Resetting a section rolls back the blocks indexed from it
+	if err := backend.Reset(3, fork[1].Hash()); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+	check("reset", map[common.Address][]uint64{sender: {1, 2}, to: {1}, other: {2}})
+}
+
+func TestAddressIndexPagination(t *testing.T) {
+	var (
+		key, _   = crypto.HexToECDSA("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
+		sender   = crypto.PubkeyToAddress(key.PublicKey)
+		db       = database.NewMemDatabase()
+		remoteDB = database.NewIpfsDbWithAdapter(database.NewFakeIpfsAdapter())
+		gspec    = &core.Genesis{
+			Config:   configs.TestChainConfig,
+			GasLimit: 31415920,
+			Alloc:    core.GenesisAlloc{sender: {Balance: big.NewInt(1000000000)}},
+		}
+		genesis = gspec.MustCommit(db)
+		signer  = types.NewCep1Signer(gspec.Config.ChainID)
+		engine  = dpos.NewFaker(configs.ChainConfigInfo().Dpos, db)
+	)
+	chain, _ := core.GenerateChain(gspec.Config, genesis, engine, db, remoteDB, 2, func(i int, gen *core.BlockGen) {
+		for n := 0; n < 120; n++ {
+			tx, _ := types.SignTx(types.NewTransaction(gen.TxNonce(sender), common.Address{1}, big.NewInt(1), configs.TxGas, nil, nil), signer, key)
+			gen.AddTx(tx)
+		}
+	})
+	blockchain, _ := core.NewBlockChain(db, nil, gspec.Config, engine, vm.Config{}, remoteDB, nil)
+	defer blockchain.Stop()
+	if _, err := blockchain.InsertChain(chain); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	indexer, _ := NewAddressIndexer(db, gspec.Config)
+	indexer.Start(blockchain)
+	defer indexer.Close()
+
+	backend := &APIBackend{gcc: &gcchainService{chainDb: db, addrIndexer: indexer}}
+	api := gccapi.NewPublicAddressIndexAPI(backend)
+	waitAddressIndex(t, backend, 2)
+
+	var (
+		seen   = make(map[common.Hash]bool)
+		pages  int
+		cursor *hexutil.Uint64
+	)
+	for {
+		result, err := api.GetTransactionsByAddress(context.Background(), sender, 0, rpc.LatestBlockNumber, cursor)
+		if err != nil {
+			t.Fatalf("GetTransactionsByAddress() error = %v", err)
+		}
+		pages++
+		for _, tx := range result.Transactions {
+			if seen[tx.Hash] {
+				t.Fatalf("transaction %x returned twice", tx.Hash)
+			}
+			seen[tx.Hash] = true
+		}
+		if result.Cursor == nil {
+			break
+		}
+		cursor = result.Cursor
+	}
+	if len(seen) != 240 || pages != 3 {
+		t.Errorf("got %d transactions in %d pages, want 240 in 3", len(seen), pages)
+	}
+}
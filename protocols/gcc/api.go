@@ -19,6 +19,7 @@ import (
 	"github.com/gcchains/chain/consensus/dpos"
 	"github.com/gcchains/chain/consensus/dpos/backend"
 	"github.com/gcchains/chain/core"
+	"github.com/gcchains/chain/core/chainexport"
 	"github.com/gcchains/chain/core/rawdb"
 	"github.com/gcchains/chain/core/state"
 	"github.com/gcchains/chain/internal/gccapi"
@@ -257,6 +258,53 @@ func (api *PrivateAdminAPI) ImportChain(file string) (bool, error) {
 	return true, nil
 }
 
+// ExportChainSegments exports the canonical blocks from first to last, the head if
+// nil, into compressed segments listed in a manifest in the directory. Exporting into
+// a directory again resumes after its last segment, so it can be called periodically
+// to keep an export up to date. The format is "rlp" (default) or "jsonl".
+func (api *PrivateAdminAPI) ExportChainSegments(dir string, format string, first uint64, last *uint64) (*chainexport.Manifest, error) {
+	config := chainexport.ExportConfig{Format: format, First: first, Last: api.gcc.BlockChain().CurrentBlock().NumberU64()}
+	if last != nil {
+		config.Last = *last
+	}
+	stop, done := api.stopOnShutdown()
+	defer done()
+	return chainexport.Export(api.gcc.BlockChain(), dir, config, stop)
+}
+
+// ImportChainSegments imports the RLP segments exported into the directory, verified
+// against the checkpoints given as "<number>:<hash>" or paths of trusted manifests,
+// and returns the number of blocks inserted.
+func (api *PrivateAdminAPI) ImportChainSegments(dir string, checkpoints []string) (int, error) {
+	var trusted []chainexport.Checkpoint
+	for _, s := range checkpoints {
+		parsed, err := chainexport.ParseCheckpoint(s)
+		if err != nil {
+			return 0, err
+		}
+		trusted = append(trusted, parsed...)
+	}
+	stop, done := api.stopOnShutdown()
+	defer done()
+	return chainexport.Import(api.gcc.BlockChain(), dir, trusted, stop)
+}
+
+// stopOnShutdown returns a channel closed when the node shuts down, until done is called.
+func (api *PrivateAdminAPI) stopOnShutdown() (stop <-chan struct{}, done func()) {
+	var (
+		ch   = make(chan struct{})
+		quit = make(chan struct{})
+	)
+	go func() {
+		select {
+		case <-api.gcc.shutdownChan:
+			close(ch)
+		case <-quit:
+		}
+	}()
+	return ch, func() { close(quit) }
+}
+
 // PublicDebugAPI is the collection of gcchain full node APIs exposed
 // over the public debugging endpoint.
 type PublicDebugAPI struct {
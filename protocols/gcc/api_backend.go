@@ -194,6 +194,22 @@ func (b *APIBackend) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.Subs
 	return b.gcc.TxPool().SubscribeNewTxsEvent(ch)
 }
 
+func (b *APIBackend) GetPoolDroppedTransaction(hash common.Hash) *core.DroppedTx {
+	return b.gcc.txPool.Dropped(hash)
+}
+
+func (b *APIBackend) GetPoolReplacementPrice(tx *types.Transaction) *big.Int {
+	return b.gcc.txPool.ReplacementPrice(tx)
+}
+
+func (b *APIBackend) TxReplaceFeeCap() float64 {
+	return b.gcc.config.TxReplaceFeeCap
+}
+
+func (b *APIBackend) SubscribeDroppedTxsEvent(ch chan<- core.DroppedTxsEvent) event.Subscription {
+	return b.gcc.TxPool().SubscribeDroppedTxsEvent(ch)
+}
+
 func (b *APIBackend) Downloader() syncer.Syncer {
 	return b.gcc.Downloader()
 }
@@ -327,6 +343,17 @@ func (b *APIBackend) Validators(blockNr rpc.BlockNumber) ([]common.Address, erro
 	return api[0].Service.(*dpos.API).GetValidators(blockNr)
 }
 
+// SupportPrivateTx returns whether private transactions are accepted in the next block
 func (b *APIBackend) SupportPrivateTx(ctx context.Context) (bool, error) {
-	return types.SupportTxType(types.PrivateTx), nil
+	next := new(big.Int).Add(b.gcc.blockchain.CurrentBlock().Number(), big.NewInt(1))
+	return types.SupportTxType(b.ChainConfig(), next, types.PrivateTx), nil
+}
+
+// AddressIndexHead returns the number of blocks covered by the address index.
+func (b *APIBackend) AddressIndexHead() (uint64, bool) {
+	if b.gcc.addrIndexer == nil {
+		return 0, false
+	}
+	sections, _, _ := b.gcc.addrIndexer.Sections()
+	return sections * addrIndexSize, true
 }
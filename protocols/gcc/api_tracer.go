@@ -623,6 +623,7 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, v
 			Failed:      failed,
 			ReturnValue: fmt.Sprintf("%x", ret),
 			StructLogs:  gccapi.FormatLogs(tracer.StructLogs()),
+			Proxies:     vmenv.ProxyResolutions(),
 		}, nil
 
 	case *tracers.Tracer:
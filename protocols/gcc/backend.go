@@ -13,10 +13,12 @@ import (
 	"github.com/gcchains/chain/accounts"
 	"github.com/gcchains/chain/admission"
 	"github.com/gcchains/chain/api/rpc"
+	"github.com/gcchains/chain/commons/chainmetrics"
 	"github.com/gcchains/chain/commons/log"
 	"github.com/gcchains/chain/configs"
 	"github.com/gcchains/chain/consensus"
 	"github.com/gcchains/chain/consensus/dpos"
+	"github.com/gcchains/chain/consensus/dpos/backend"
 	"github.com/gcchains/chain/contracts/dpos/primitive_backend"
 	"github.com/gcchains/chain/core"
 	"github.com/gcchains/chain/core/bloombits"
@@ -30,6 +32,7 @@ import (
 	"github.com/gcchains/chain/protocols/gcc/filters"
 	"github.com/gcchains/chain/protocols/gcc/gasprice"
 	"github.com/gcchains/chain/protocols/gcc/syncer"
+	"github.com/gcchains/chain/protocols/payload"
 	"github.com/gcchains/chain/types"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/event"
@@ -74,6 +77,9 @@ type gcchainService struct {
 	bloomRequests chan chan *bloombits.Retrieval // Channel receiving bloom data retrieval requests
 	bloomIndexer  *core.ChainIndexer             // LogsBloom indexer operating during block imports
 
+	addrIndexer *core.ChainIndexer // Address indexer operating during block imports, nil if disabled
+	addrIndex   *AddressIndexer    // Backend of the address indexer rolling back side blocks
+
 	// chain service backend
 	APIBackend          *APIBackend
 	AdmissionApiBackend admission.ApiBackend
@@ -88,6 +94,12 @@ type gcchainService struct {
 	lock sync.RWMutex // Protects the variadic fields (e.g. gas price and coinbase)
 
 	remoteDB database.RemoteDatabase // remoteDB represents an remote distributed database.
+
+	payloadManager *payload.Manager // Fetches private payloads from peers for the local remote database
+
+	collector *chainCollector // Collects the stats exported at the /metrics endpoint, nil if not serving
+
+	lbft2Tracer *backend.Tracer // Records the steps of lbft2 fsm of the validator, nil if not traced
 }
 
 func (s *gcchainService) AddLesServer(ls LesServer) {
@@ -99,17 +111,21 @@ func (s *gcchainService) AddLesServer(ls LesServer) {
 // initialisation of the common gcchainService object)
 func New(ctx *node.ServiceContext, config *Config) (*gcchainService, error) {
 
-	chainDb, err := CreateDB(ctx, config, "chaindata")
+	chainDb, err := ctx.OpenDatabaseWithFreezer("chaindata", config.DatabaseCache, config.DatabaseHandles, config.DatabaseFreezer, config.AncientThreshold)
 	if err != nil {
 		return nil, err
 	}
-	chainConfig, genesisHash, genesisErr := core.SetupGenesisBlock(chainDb, config.Genesis)
-	if _, ok := genesisErr.(*configs.ConfigCompatError); genesisErr != nil && !ok {
+	chainConfig, _, genesisErr := core.SetupGenesisBlock(chainDb, config.Genesis)
+	if genesisErr != nil {
+		// refuse to start on a database whose head is past a changed fork
 		return nil, genesisErr
 	}
 	log.Info("Initialised chain configuration", "config", chainConfig)
 
-	var remoteDB database.RemoteDatabase
+	var (
+		remoteDB       database.RemoteDatabase
+		payloadManager *payload.Manager
+	)
 	switch config.PrivateTx.RemoteDBType {
 	case private.IPFS:
 		remoteDB = database.NewIpfsDB(config.PrivateTx.RemoteDBParams)
@@ -117,6 +133,18 @@ func New(ctx *node.ServiceContext, config *Config) (*gcchainService, error) {
 	case private.Dummy:
 		remoteDB = new(database.DummyDatabase)
 		log.Info("Initialize remote database", "database", "Dummy")
+	case private.Local:
+		db, err := CreateDB(ctx, config, "remotedb")
+		if err != nil {
+			return nil, err
+		}
+		localDB := database.NewLocalDatabase(db)
+		if config.PrivateTx.FetchFromPeers {
+			payloadManager = payload.NewManager(localDB)
+			localDB.SetFetcher(payloadManager)
+		}
+		remoteDB = localDB
+		log.Info("Initialize remote database", "database", "Local", "fetchFromPeers", config.PrivateTx.FetchFromPeers)
 	default:
 		remoteDB = database.NewIpfsDB(private.DefaultIpfsUrl)
 		log.Info("Initialize remote database", "database", "IPFS")
@@ -135,6 +163,7 @@ func New(ctx *node.ServiceContext, config *Config) (*gcchainService, error) {
 		bloomRequests:  make(chan chan *bloombits.Retrieval),
 		bloomIndexer:   NewBloomIndexer(chainDb, configs.BloomBitsBlocks),
 		remoteDB:       remoteDB,
+		payloadManager: payloadManager,
 	}
 
 	gcc.engine = gcc.CreateConsensusEngine(ctx, chainConfig, chainDb)
@@ -158,6 +187,9 @@ func New(ctx *node.ServiceContext, config *Config) (*gcchainService, error) {
 		dpos.SetCampaignBackend(contractAddrs[configs.ContractCampaign], primitive_backend.GetChainClient())
 		dpos.SetRptBackend(contractAddrs[configs.ContractRpt], primitive_backend.GetChainClient())
 		dpos.SetRNodeBackend(contractAddrs[configs.ContractRnode], primitive_backend.GetChainClient())
+		if governanceContract, ok := contractAddrs[configs.ContractGovernance]; ok {
+			dpos.SetGovernanceBackend(governanceContract, primitive_backend.GetChainClient())
+		}
 	}
 
 	log.Info("Initialising gcchain protocol", "versions", ProtocolVersions, "network", config.NetworkId)
@@ -172,7 +204,8 @@ func New(ctx *node.ServiceContext, config *Config) (*gcchainService, error) {
 
 	var (
 		vmConfig    = vm.Config{EnablePreimageRecording: config.EnablePreimageRecording}
-		cacheConfig = &core.CacheConfig{Disabled: config.NoPruning, TrieNodeLimit: config.TrieCache, TrieTimeLimit: config.TrieTimeout}
+		cacheConfig = &core.CacheConfig{Disabled: config.NoPruning, TrieNodeLimit: config.TrieCache, TrieTimeLimit: config.TrieTimeout,
+			StateRetention: config.StateRetention}
 	)
 	gcc.blockchain, err = core.NewBlockChain(chainDb, cacheConfig, gcc.chainConfig, gcc.engine, vmConfig, remoteDB, ctx.AccountManager)
 	if err != nil {
@@ -187,20 +220,21 @@ func New(ctx *node.ServiceContext, config *Config) (*gcchainService, error) {
 		contractAddrs[configs.ContractCampaign],
 		contractAddrs[configs.ContractRnode],
 		contractAddrs[configs.ContractNetwork])
+	gcc.AdmissionApiBackend.SetPlotDir(ctx.ResolvePath("plots"))
 
 	if dpos, ok := gcc.engine.(*dpos.Dpos); ok {
 		dpos.SetupAdmission(gcc.AdmissionApiBackend)
 		dpos.SetChain(gcc.blockchain)
 	}
 
-	// Rewind the chain in case of an incompatible config upgrade.
-	if compat, ok := genesisErr.(*configs.ConfigCompatError); ok {
-		log.Warn("Rewinding chain to upgrade configuration", "err", compat)
-		gcc.blockchain.SetHead(compat.RewindTo)
-		rawdb.WriteChainConfig(chainDb, genesisHash, chainConfig)
-	}
 	gcc.bloomIndexer.Start(gcc.blockchain)
 
+	if config.AddressIndex {
+		gcc.addrIndexer, gcc.addrIndex = NewAddressIndexer(chainDb, gcc.chainConfig)
+		gcc.addrIndexer.Start(gcc.blockchain)
+		gcc.addrIndex.Start(gcc.blockchain)
+	}
+
 	if config.TxPool.Journal != "" {
 		config.TxPool.Journal = ctx.ResolvePath(config.TxPool.Journal)
 	}
@@ -239,6 +273,30 @@ func (s *gcchainService) SetAsValidator() {
 	s.engine.(*dpos.Dpos).SetAsValidator(true)
 }
 
+// TraceLBFT2 records the steps of lbft2 fsm of the validator to the trace file.
+func (s *gcchainService) TraceLBFT2(path string) error {
+	dpos, ok := s.engine.(*dpos.Dpos)
+	if !ok {
+		return nil
+	}
+	tracer, err := backend.OpenTracer(path)
+	if err != nil {
+		return err
+	}
+	dpos.SetLBFT2Tracer(tracer)
+	s.lbft2Tracer = tracer
+	return nil
+}
+
+// authorize authorizes dpos to sign with the coinbase held by the wallet, a wallet checking
+// the consensus msgs it signs, e.g. an external signer, signs them rather than their hashes.
+func authorize(d *dpos.Dpos, coinbase common.Address, wallet accounts.Wallet) {
+	d.Authorize(coinbase, wallet.SignHash)
+
+	signer, _ := wallet.(backend.ConsensusSigner)
+	d.SetConsensusSigner(signer)
+}
+
 // CreateConsensusEngine creates the required type of consensus engine instance for an gcchain service
 func (s *gcchainService) CreateConsensusEngine(ctx *node.ServiceContext, chainConfig *configs.ChainConfig,
 	db database.Database) consensus.Engine {
@@ -255,7 +313,7 @@ func (s *gcchainService) CreateConsensusEngine(ctx *node.ServiceContext, chainCo
 				log.Error("Coinbase account unavailable locally", "err", err)
 				return nil
 			}
-			dpos.Authorize(eb, wallet.SignHash)
+			authorize(dpos, eb, wallet)
 		}
 		return dpos
 	}
@@ -377,7 +435,7 @@ func (s *gcchainService) StartMining(local bool) error {
 		if dpos.IsValidator() {
 			return errForbidValidatorMining
 		}
-		if s.AdmissionApiBackend.AdmissionKey() == nil {
+		if s.AdmissionApiBackend.AdmissionKey() == nil && s.AdmissionApiBackend.AdmissionWallet() == nil {
 			return errNotAdmissionKey
 		}
 
@@ -387,7 +445,7 @@ func (s *gcchainService) StartMining(local bool) error {
 				log.Error("Etherbase account unavailable locally", "err", err)
 				return nil
 			}
-			dpos.Authorize(coinbase, wallet.SignHash)
+			authorize(dpos, coinbase, wallet)
 		}
 
 		log.Debug("server.nodeid", "enode", s.server.NodeInfo().Enode)
@@ -472,10 +530,14 @@ func (s *gcchainService) RemoteDB() database.RemoteDatabase { return s.remoteDB
 // Protocols implements node.Service, returning all the currently configured
 // network protocols to start.
 func (s *gcchainService) Protocols() []p2p.Protocol {
-	if s.lesServer == nil {
-		return s.protocolManager.SubProtocols
+	protocols := s.protocolManager.SubProtocols
+	if s.lesServer != nil {
+		protocols = append(protocols, s.lesServer.Protocols()...)
 	}
-	return append(s.protocolManager.SubProtocols, s.lesServer.Protocols()...)
+	if s.payloadManager != nil {
+		protocols = append(protocols, s.payloadManager.Protocols()...)
+	}
+	return protocols
 }
 
 // start implements node.service, starting all internal goroutines needed by the
@@ -501,13 +563,25 @@ func (s *gcchainService) Start(srvr *p2p.Server) error {
 		s.lesServer.Start(srvr)
 	}
 
+	if chainmetrics.Serving() {
+		s.collector = newChainCollector(s)
+		chainmetrics.Register(s.collector)
+	}
+
 	return nil
 }
 
 // Stop implements node.Service, terminating all internal goroutines used by the
 // gcchain protocol.
 func (s *gcchainService) Stop() error {
+	if s.collector != nil {
+		chainmetrics.Unregister(s.collector)
+	}
 	s.bloomIndexer.Close()
+	if s.addrIndexer != nil {
+		s.addrIndex.Close()
+		s.addrIndexer.Close()
+	}
 	s.blockchain.Stop()
 	s.protocolManager.Stop()
 	if s.lesServer != nil {
@@ -517,6 +591,18 @@ func (s *gcchainService) Stop() error {
 	s.miner.Stop()
 	s.eventMux.Stop()
 
+	if s.lbft2Tracer != nil {
+		if err := s.lbft2Tracer.Close(); err != nil {
+			log.Error("Failed to close the lbft trace file", "err", err)
+		}
+	}
+	if s.payloadManager != nil {
+		s.payloadManager.Stop()
+	}
+	if localDB, ok := s.remoteDB.(*database.LocalDatabase); ok {
+		localDB.Close()
+	}
+
 	s.chainDb.Close()
 	close(s.shutdownChan)
 
@@ -0,0 +1,84 @@
+package gcc
+
+import (
+	"github.com/gcchains/chain/consensus/dpos"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	txPoolPendingDesc = prometheus.NewDesc("gcchain_txpool_pending", "pending transactions in txpool.", nil, nil)
+	txPoolQueuedDesc  = prometheus.NewDesc("gcchain_txpool_queued", "queued transactions in txpool.", nil, nil)
+
+	syncStartingBlockDesc = prometheus.NewDesc("gcchain_sync_starting_block", "block number where sync began.", nil, nil)
+	syncCurrentBlockDesc  = prometheus.NewDesc("gcchain_sync_current_block", "current block number where sync is at.", nil, nil)
+	syncHighestBlockDesc  = prometheus.NewDesc("gcchain_sync_highest_block", "highest alleged block number in the chain.", nil, nil)
+	syncSynchronisingDesc = prometheus.NewDesc("gcchain_sync_synchronising", "1 if synchronising now.", nil, nil)
+
+	dposTermDesc               = prometheus.NewDesc("gcchain_dpos_term", "current and future term of dpos.", []string{"term"}, nil)
+	dposConnectedProposersDesc = prometheus.NewDesc("gcchain_dpos_connected_proposers", "connected proposers of the current and future term.",
+		[]string{"term"}, nil)
+	dposConnectedValidatorsDesc = prometheus.NewDesc("gcchain_dpos_connected_validators", "connected validators of the current and future term.",
+		[]string{"term"}, nil)
+)
+
+// chainCollector collects the stats of the txpool, the synchronizer and the dialer of dpos when the /metrics
+// endpoint is scraped.
+type chainCollector struct {
+	gcc *gcchainService
+}
+
+func newChainCollector(gcc *gcchainService) *chainCollector {
+	return &chainCollector{gcc: gcc}
+}
+
+// Describe implements prometheus.Collector
+func (c *chainCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- txPoolPendingDesc
+	ch <- txPoolQueuedDesc
+	ch <- syncStartingBlockDesc
+	ch <- syncCurrentBlockDesc
+	ch <- syncHighestBlockDesc
+	ch <- syncSynchronisingDesc
+	ch <- dposTermDesc
+	ch <- dposConnectedProposersDesc
+	ch <- dposConnectedValidatorsDesc
+}
+
+// Collect implements prometheus.Collector
+func (c *chainCollector) Collect(ch chan<- prometheus.Metric) {
+	pending, queued := c.gcc.txPool.Stats()
+	ch <- prometheus.MustNewConstMetric(txPoolPendingDesc, prometheus.GaugeValue, float64(pending))
+	ch <- prometheus.MustNewConstMetric(txPoolQueuedDesc, prometheus.GaugeValue, float64(queued))
+
+	if syncer := c.gcc.protocolManager.syncer; syncer != nil {
+		progress := syncer.Progress()
+		synchronising := 0.
+		if syncer.Synchronising() {
+			synchronising = 1
+		}
+		ch <- prometheus.MustNewConstMetric(syncStartingBlockDesc, prometheus.GaugeValue, float64(progress.StartingBlock))
+		ch <- prometheus.MustNewConstMetric(syncCurrentBlockDesc, prometheus.GaugeValue, float64(progress.CurrentBlock))
+		ch <- prometheus.MustNewConstMetric(syncHighestBlockDesc, prometheus.GaugeValue, float64(progress.HighestBlock))
+		ch <- prometheus.MustNewConstMetric(syncSynchronisingDesc, prometheus.GaugeValue, synchronising)
+	}
+
+	d, ok := c.gcc.engine.(*dpos.Dpos)
+	if !ok {
+		return
+	}
+	current := d.GetCurrentBlock()
+	dialer := d.Dialer()
+	if current == nil || dialer == nil {
+		return
+	}
+	terms := map[string]uint64{
+		"current": d.TermOf(current.NumberU64()),
+		"future":  d.FutureTermOf(current.NumberU64()),
+	}
+	for label, term := range terms {
+		proposers, validators := dialer.ConnectedOfTerm(term)
+		ch <- prometheus.MustNewConstMetric(dposTermDesc, prometheus.GaugeValue, float64(term), label)
+		ch <- prometheus.MustNewConstMetric(dposConnectedProposersDesc, prometheus.GaugeValue, float64(proposers), label)
+		ch <- prometheus.MustNewConstMetric(dposConnectedValidatorsDesc, prometheus.GaugeValue, float64(validators), label)
+	}
+}
@@ -25,7 +25,8 @@ var DefaultConfig = Config{
 	TrieTimeout:   60 * time.Minute,
 	GasPrice:      big.NewInt(18 * configs.Shannon),
 
-	TxPool: core.DefaultTxPoolConfig,
+	TxPool:          core.DefaultTxPoolConfig,
+	TxReplaceFeeCap: 1,
 	GPO: gasprice.Config{
 		Blocks:     20,
 		Percentile: 60,
@@ -60,6 +61,10 @@ type Config struct {
 	DatabaseCache      int
 	TrieCache          int
 	TrieTimeout        time.Duration
+	StateRetention     uint64 // number of recent blocks whose state is kept, 0 disables state pruning
+	AddressIndex       bool   // maintains the address index of transactions for gcc_getTransactionsByAddress
+	AncientThreshold   uint64 // number of recent blocks kept in the database, older canonical blocks are moved to the freezer, 0 disables it
+	DatabaseFreezer    string `toml:",omitempty"` // directory of the freezer, "ancient" in the chain database if empty
 
 	// Mining-related options
 	Gccbase      common.Address `toml:",omitempty"`
@@ -68,7 +73,8 @@ type Config struct {
 	GasPrice     *big.Int
 
 	// Transaction pool options
-	TxPool core.TxPoolConfig
+	TxPool          core.TxPoolConfig
+	TxReplaceFeeCap float64 // maximum fee in gcc of a transaction replaced by txpool_replace, 0 for no cap
 
 	// Gas Price Oracle options
 	GPO gasprice.Config
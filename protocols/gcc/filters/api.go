@@ -13,6 +13,7 @@ import (
 
 	"/gcchain/chain"
 	"github.com/gcchains/chain/api/rpc"
+	"github.com/gcchains/chain/core/rawdb"
 	"github.com/gcchains/chain/database"
 	"github.com/gcchains/chain/types"
 	"github.com/ethereum/go-ethereum/common"
@@ -154,6 +155,195 @@ func (api *PublicFilterAPI) NewPendingTransactions(ctx context.Context) (*rpc.Su
 	return rpcSub, nil
 }
 
+// PendingTxCriteria represents a request to filter the transactions entering the pending state.
+// Same as gcchain.PendingTxQuery but with UnmarshalJSON() method.
+type PendingTxCriteria gcchain.PendingTxQuery
+
+// RPCPendingTransaction represents a transaction entering the pending state.
+type RPCPendingTransaction struct {
+	From     common.Address  `json:"from"`
+	Gas      hexutil.Uint64  `json:"gas"`
+	GasPrice *hexutil.Big    `json:"gasPrice"`
+	Hash     common.Hash     `json:"hash"`
+	Type     hexutil.Uint64  `json:"type"`
+	Input    hexutil.Bytes   `json:"input"`
+	Nonce    hexutil.Uint64  `json:"nonce"`
+	To       *common.Address `json:"to"`
+	Value    *hexutil.Big    `json:"value"`
+	V        *hexutil.Big    `json:"v"`
+	R        *hexutil.Big    `json:"r"`
+	S        *hexutil.Big    `json:"s"`
+}
+
+func newRPCPendingTransaction(tx *types.Transaction) *RPCPendingTransaction {
+	var signer types.Signer = types.HomesteadSigner{}
+	if tx.Protected() {
+		signer = types.NewCep1Signer(tx.ChainId())
+	}
+	from, _ := types.Sender(signer, tx)
+	v, r, s := tx.RawSignatureValues()
+
+	return &RPCPendingTransaction{
+		From:     from,
+		Gas:      hexutil.Uint64(tx.Gas()),
+		GasPrice: (*hexutil.Big)(tx.GasPrice()),
+		Hash:     tx.Hash(),
+		Type:     hexutil.Uint64(tx.Type()),
+		Input:    hexutil.Bytes(tx.Data()),
+		Nonce:    hexutil.Uint64(tx.Nonce()),
+		To:       tx.To(),
+		Value:    (*hexutil.Big)(tx.Value()),
+		V:        (*hexutil.Big)(v),
+		R:        (*hexutil.Big)(r),
+		S:        (*hexutil.Big)(s),
+	}
+}
+
+// NewFullPendingTransactions creates a subscription that is triggered with the full transaction each time a
+// transaction matching the given criteria enters the transaction pool.
+func (api *PublicFilterAPI) NewFullPendingTransactions(ctx context.Context, crit PendingTxCriteria) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		txs := make(chan []*types.Transaction, 128)
+		pendingTxSub := api.events.SubscribeFullPendingTxs(gcchain.PendingTxQuery(crit), txs)
+
+		for {
+			select {
+			case matchedTxs := <-txs:
+				for _, tx := range matchedTxs {
+					notifier.Notify(rpcSub.ID, newRPCPendingTransaction(tx))
+				}
+			case <-rpcSub.Err():
+				pendingTxSub.Unsubscribe()
+				return
+			case <-notifier.Closed():
+				pendingTxSub.Unsubscribe()
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// IncludedTransaction is a notification of a transaction included in the chain with the required confirmations, or
+// of the removal of the notified inclusion by a chain reorg.
+type IncludedTransaction struct {
+	TxHash           common.Hash    `json:"transactionHash"`
+	BlockHash        common.Hash    `json:"blockHash"`
+	BlockNumber      hexutil.Uint64 `json:"blockNumber"`
+	TransactionIndex hexutil.Uint64 `json:"transactionIndex"`
+	Confirmations    hexutil.Uint64 `json:"confirmations"`
+	Receipt          *types.Receipt `json:"receipt"`
+	Removed          bool           `json:"removed"`
+}
+
+// inclusionTracker tracks the inclusion of a transaction in the canonical chain as new heads arrive.
+type inclusionTracker struct {
+	backend       Backend
+	hash          common.Hash
+	confirmations uint64
+	included      *IncludedTransaction // the notified inclusion, nil if not notified or removed
+}
+
+func newInclusionTracker(backend Backend, hash common.Hash, confirmations uint64) *inclusionTracker {
+	if confirmations == 0 {
+		confirmations = 1
+	}
+	return &inclusionTracker{backend: backend, hash: hash, confirmations: confirmations}
+}
+
+// update returns the notifications of the transaction once the given header is the head of the chain: the removal of
+// the notified inclusion if its block is no longer canonical, and the inclusion once it has the required confirmations.
+func (t *inclusionTracker) update(ctx context.Context, head *types.Header) []*IncludedTransaction {
+	var (
+		notifications []*IncludedTransaction
+		db            = t.backend.ChainDb()
+	)
+	tx, blockHash, number, index := rawdb.ReadTransaction(db, t.hash)
+	confirmed := tx != nil && rawdb.ReadCanonicalHash(db, number) == blockHash && head.Number.Uint64()+1 >= number+t.confirmations
+
+	if t.included != nil && (!confirmed || t.included.BlockHash != blockHash) {
+		removed := *t.included
+		removed.Removed = true
+		notifications = append(notifications, &removed)
+		t.included = nil
+	}
+	if confirmed && t.included == nil {
+		receipts, err := t.backend.GetReceipts(ctx, blockHash)
+		if err != nil || uint64(len(receipts)) <= index {
+			return notifications
+		}
+		t.included = &IncludedTransaction{
+			TxHash:           t.hash,
+			BlockHash:        blockHash,
+			BlockNumber:      hexutil.Uint64(number),
+			TransactionIndex: hexutil.Uint64(index),
+			Confirmations:    hexutil.Uint64(head.Number.Uint64() + 1 - number),
+			Receipt:          receipts[index],
+		}
+		notifications = append(notifications, t.included)
+	}
+	return notifications
+}
+
+// TransactionIncluded creates a subscription that is triggered with the receipt of the transaction of the given hash
+// once its block has the given number of confirmations, 1 by default for the block itself. If the block is removed
+// from the canonical chain by a reorg, the notification is sent again with removed set to true, and the transaction
+// is notified again once it is included and confirmed in the new chain.
+//
+// The transaction is checked against the current head when subscribing and as new blocks are imported, so a
+// transaction already confirmed is notified at once.
+func (api *PublicFilterAPI) TransactionIncluded(ctx context.Context, hash common.Hash, confirmations *hexutil.Uint64) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	var confs uint64
+	if confirmations != nil {
+		confs = uint64(*confirmations)
+	}
+	var (
+		rpcSub  = notifier.CreateSubscription()
+		tracker = newInclusionTracker(api.backend, hash, confs)
+	)
+
+	go func() {
+		headers := make(chan *types.Header)
+		headersSub := api.events.SubscribeNewHeads(headers)
+
+		// subscribed before reading the head, so that no block is missed in between
+		if head, err := api.backend.HeaderByNumber(context.Background(), rpc.LatestBlockNumber); err == nil && head != nil {
+			for _, included := range tracker.update(context.Background(), head) {
+				notifier.Notify(rpcSub.ID, included)
+			}
+		}
+		for {
+			select {
+			case h := <-headers:
+				for _, included := range tracker.update(context.Background(), h) {
+					notifier.Notify(rpcSub.ID, included)
+				}
+			case <-rpcSub.Err():
+				headersSub.Unsubscribe()
+				return
+			case <-notifier.Closed():
+				headersSub.Unsubscribe()
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
 // NewBlockFilter creates a filter that fetches blocks that are imported into the chain.
 // It is part of the filter package since polling goes with eth_getFilterChanges.
 //
@@ -521,6 +711,33 @@ func (args *FilterCriteria) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// UnmarshalJSON sets *args fields with given data.
+func (args *PendingTxCriteria) UnmarshalJSON(data []byte) error {
+	type input struct {
+		From    []common.Address `json:"from"`
+		To      []common.Address `json:"to"`
+		Methods []hexutil.Bytes  `json:"methods"`
+	}
+
+	var raw input
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	args.From = raw.From
+	args.To = raw.To
+	args.Methods = nil
+	for i, method := range raw.Methods {
+		if len(method) != 4 {
+			return fmt.Errorf("invalid method selector at index %d: hex has invalid length %d after decoding", i, len(method))
+		}
+		var selector [4]byte
+		copy(selector[:], method)
+		args.Methods = append(args.Methods, selector)
+	}
+	return nil
+}
+
 func decodeAddress(s string) (common.Address, error) {
 	b, err := hexutil.Decode(s)
 	if err == nil && len(b) != common.AddressLength {
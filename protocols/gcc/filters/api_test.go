@@ -169,3 +169,38 @@ func TestUnmarshalJSONNewFilterArgs(t *testing.T) {
 		t.Fatalf("expected 0 topics, got %d topics", len(test7.Topics[2]))
 	}
 }
+
+func TestUnmarshalJSONPendingTxCriteria(t *testing.T) {
+	var (
+		from = common.HexToAddress("70c87d191324e6712a591f304b4eedef6ad9bb9d")
+		to   = common.HexToAddress("9b2055d370f73ec7d8a03e965129118dc8f5bf83")
+	)
+
+	var test0 PendingTxCriteria
+	if err := json.Unmarshal([]byte("{}"), &test0); err != nil {
+		t.Fatal(err)
+	}
+	if len(test0.From) != 0 || len(test0.To) != 0 || len(test0.Methods) != 0 {
+		t.Fatalf("expected empty criteria, got %v", test0)
+	}
+
+	vector := fmt.Sprintf(`{"from":["0x%x"],"to":["0x%x"],"methods":["0xa9059cbb","0x095ea7b3"]}`, from, to)
+	var test1 PendingTxCriteria
+	if err := json.Unmarshal([]byte(vector), &test1); err != nil {
+		t.Fatal(err)
+	}
+	if len(test1.From) != 1 || test1.From[0] != from {
+		t.Fatalf("expected from %x, got %x", from, test1.From)
+	}
+	if len(test1.To) != 1 || test1.To[0] != to {
+		t.Fatalf("expected to %x, got %x", to, test1.To)
+	}
+	if len(test1.Methods) != 2 || test1.Methods[0] != [4]byte{0xa9, 0x05, 0x9c, 0xbb} || test1.Methods[1] != [4]byte{0x09, 0x5e, 0xa7, 0xb3} {
+		t.Fatalf("invalid methods, got %x", test1.Methods)
+	}
+
+	var test2 PendingTxCriteria
+	if err := json.Unmarshal([]byte(`{"methods":["0xa9059c"]}`), &test2); err == nil {
+		t.Fatal("expected an error for a method selector of 3 bytes")
+	}
+}
@@ -133,9 +133,8 @@ func benchmarkBloomBits(b *testing.B, sectionSize uint64) {
 }
 
 func forEachKey(db database.Database, startPrefix, endPrefix []byte, fn func(key []byte)) {
-	it := db.(*database.LDBDatabase).NewIterator()
-	it.Seek(startPrefix)
-	for it.Valid() {
+	it := db.NewIterator(nil, startPrefix)
+	for it.Next() {
 		key := it.Key()
 		cmpLen := len(key)
 		if len(endPrefix) < cmpLen {
@@ -145,7 +144,6 @@ func forEachKey(db database.Database, startPrefix, endPrefix []byte, fn func(key
 			break
 		}
 		fn(common.CopyBytes(key))
-		it.Next()
 	}
 	it.Release()
 }
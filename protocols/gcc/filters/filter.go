@@ -261,6 +261,45 @@ Logs:
 	return ret
 }
 
+// filterTxs creates a slice of transactions matching the given criteria.
+func filterTxs(txs []*types.Transaction, from, to []common.Address, methods [][4]byte) []*types.Transaction {
+	var ret []*types.Transaction
+	for _, tx := range txs {
+		if len(from) > 0 {
+			var signer types.Signer = types.HomesteadSigner{}
+			if tx.Protected() {
+				signer = types.NewCep1Signer(tx.ChainId())
+			}
+			sender, err := types.Sender(signer, tx)
+			if err != nil || !includes(from, sender) {
+				continue
+			}
+		}
+		if len(to) > 0 && (tx.To() == nil || !includes(to, *tx.To())) {
+			continue
+		}
+		if len(methods) > 0 {
+			if len(tx.Data()) < 4 {
+				continue
+			}
+			var selector [4]byte
+			copy(selector[:], tx.Data()[:4])
+			match := false
+			for _, method := range methods {
+				if method == selector {
+					match = true
+					break
+				}
+			}
+			if !match {
+				continue
+			}
+		}
+		ret = append(ret, tx)
+	}
+	return ret
+}
+
 func bloomFilter(bloom types.Bloom, addresses []common.Address, topics [][]common.Hash) bool {
 	if len(addresses) > 0 {
 		var included bool
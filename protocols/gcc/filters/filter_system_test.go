@@ -4,6 +4,7 @@ package filters
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"fmt"
 	"math/big"
 	"math/rand"
@@ -21,6 +22,7 @@ import (
 	"github.com/gcchains/chain/database"
 	"github.com/gcchains/chain/types"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/event"
 )
 
@@ -568,3 +570,155 @@ func TestPendingLogsSubscription(t *testing.T) {
 		}
 	}
 }
+
+// TestFullPendingTxSubscription tests whether the full pending tx subscriptions return the transactions
+// matching the criteria by sender, recipient and method.
+func TestFullPendingTxSubscription(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mux        = new(event.TypeMux)
+		db         = database.NewMemDatabase()
+		txFeed     = new(event.Feed)
+		rmLogsFeed = new(event.Feed)
+		logsFeed   = new(event.Feed)
+		chainFeed  = new(event.Feed)
+		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
+		api        = NewPublicFilterAPI(backend, false)
+
+		firstKey, _  = crypto.GenerateKey()
+		secondKey, _ = crypto.GenerateKey()
+		firstAddr    = crypto.PubkeyToAddress(firstKey.PublicKey)
+		toAddr       = common.HexToAddress("0x1111111111111111111111111111111111111111")
+		transfer     = [4]byte{0xa9, 0x05, 0x9c, 0xbb}
+		approve      = [4]byte{0x09, 0x5e, 0xa7, 0xb3}
+
+		signedTx = func(tx *types.Transaction, key *ecdsa.PrivateKey) *types.Transaction {
+			signed, _ := types.SignTx(tx, types.HomesteadSigner{}, key)
+			return signed
+		}
+		transactions = []*types.Transaction{
+			signedTx(types.NewTransaction(0, toAddr, new(big.Int), 0, new(big.Int), transfer[:]), firstKey),
+			signedTx(types.NewTransaction(0, toAddr, new(big.Int), 0, new(big.Int), approve[:]), secondKey),
+			signedTx(types.NewContractCreation(1, new(big.Int), 0, new(big.Int), transfer[:]), firstKey),
+			signedTx(types.NewTransaction(1, common.Address{}, new(big.Int), 0, new(big.Int), nil), secondKey),
+		}
+
+		testCases = []struct {
+			crit     gcchain.PendingTxQuery
+			expected []*types.Transaction
+			c        chan []*types.Transaction
+			sub      *Subscription
+		}{
+			// match all
+			{gcchain.PendingTxQuery{}, transactions, nil, nil},
+			// match by sender
+			{gcchain.PendingTxQuery{From: []common.Address{firstAddr}}, []*types.Transaction{transactions[0], transactions[2]}, nil, nil},
+			// match by recipient, excluding contract creations
+			{gcchain.PendingTxQuery{To: []common.Address{toAddr}}, transactions[:2], nil, nil},
+			// match by "or" methods
+			{gcchain.PendingTxQuery{Methods: [][4]byte{transfer, approve}}, transactions[:3], nil, nil},
+			// match by sender, recipient and method
+			{gcchain.PendingTxQuery{From: []common.Address{firstAddr}, To: []common.Address{toAddr}, Methods: [][4]byte{transfer}}, transactions[:1], nil, nil},
+		}
+	)
+
+	for i := range testCases {
+		testCases[i].c = make(chan []*types.Transaction)
+		testCases[i].sub = api.events.SubscribeFullPendingTxs(testCases[i].crit, testCases[i].c)
+	}
+
+	// fetch the transactions of all subscriptions concurrently as they are broadcast in any order
+	errs := make(chan error, len(testCases))
+	for n, test := range testCases {
+		i, tt := n, test
+		go func() {
+			select {
+			case fetched := <-tt.c:
+				if len(fetched) != len(tt.expected) {
+					errs <- fmt.Errorf("invalid number of transactions for case %d, want %d transaction(s), got %d", i, len(tt.expected), len(fetched))
+					return
+				}
+				for j := range fetched {
+					if fetched[j].Hash() != tt.expected[j].Hash() {
+						errs <- fmt.Errorf("invalid transaction on index %d for case %d, want %x, got %x", j, i, tt.expected[j].Hash(), fetched[j].Hash())
+						return
+					}
+				}
+				errs <- nil
+			case <-time.After(2 * time.Second):
+				errs <- fmt.Errorf("transactions not received for case %d", i)
+			}
+		}()
+	}
+
+	time.Sleep(1 * time.Second)
+	txFeed.Send(core.NewTxsEvent{Txs: transactions})
+
+	for range testCases {
+		if err := <-errs; err != nil {
+			t.Error(err)
+		}
+	}
+	for _, tt := range testCases {
+		tt.sub.Unsubscribe()
+	}
+}
+
+// TestInclusionTracker tests whether a transaction is notified once it has the required confirmations
+// and its inclusion is removed by a reorg.
+func TestInclusionTracker(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mux        = new(event.TypeMux)
+		db         = database.NewMemDatabase()
+		txFeed     = new(event.Feed)
+		rmLogsFeed = new(event.Feed)
+		logsFeed   = new(event.Feed)
+		chainFeed  = new(event.Feed)
+		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
+
+		tx       = types.NewTransaction(0, common.Address{}, new(big.Int), 0, new(big.Int), nil)
+		receipt  = &types.Receipt{TxHash: tx.Hash(), GasUsed: 21000}
+		block1   = types.NewBlock(&types.Header{Number: big.NewInt(1)}, []*types.Transaction{tx}, nil)
+		block2   = types.NewBlock(&types.Header{Number: big.NewInt(2), ParentHash: block1.Hash()}, nil, nil)
+		reorged1 = types.NewBlock(&types.Header{Number: big.NewInt(1), Extra: []byte("reorged")}, nil, nil)
+		tracker  = newInclusionTracker(backend, tx.Hash(), 2)
+		ctx      = context.Background()
+	)
+	for _, block := range []*types.Block{block1, block2} {
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+	}
+	rawdb.WriteTxLookupEntries(db, block1)
+	rawdb.WriteReceipts(db, block1.Hash(), 1, types.Receipts{receipt})
+
+	if notifications := tracker.update(ctx, block1.Header()); len(notifications) != 0 {
+		t.Fatalf("transaction notified without enough confirmations: %v", notifications)
+	}
+	notifications := tracker.update(ctx, block2.Header())
+	if len(notifications) != 1 {
+		t.Fatalf("invalid number of notifications for confirmed transaction, want 1, got %d", len(notifications))
+	}
+	if n := notifications[0]; n.Removed || n.BlockHash != block1.Hash() || n.Confirmations != 2 || n.Receipt.TxHash != tx.Hash() {
+		t.Fatalf("invalid notification of confirmed transaction: %+v", n)
+	}
+	if notifications := tracker.update(ctx, block2.Header()); len(notifications) != 0 {
+		t.Fatalf("transaction notified again: %v", notifications)
+	}
+
+	// reorg the block including the transaction
+	rawdb.WriteBlock(db, reorged1)
+	rawdb.WriteCanonicalHash(db, reorged1.Hash(), 1)
+	rawdb.DeleteCanonicalHash(db, 2)
+	rawdb.DeleteTxLookupEntry(db, tx.Hash())
+
+	notifications = tracker.update(ctx, reorged1.Header())
+	if len(notifications) != 1 {
+		t.Fatalf("invalid number of notifications for reorged transaction, want 1, got %d", len(notifications))
+	}
+	if n := notifications[0]; !n.Removed || n.BlockHash != block1.Hash() {
+		t.Fatalf("invalid removal notification: %+v", n)
+	}
+}
@@ -27,11 +27,16 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		DatabaseCache           int
 		TrieCache               int
 		TrieTimeout             time.Duration
+		StateRetention          uint64
+		AddressIndex            bool
+		AncientThreshold        uint64
+		DatabaseFreezer         string         `toml:",omitempty"`
 		Gccbase                 common.Address `toml:",omitempty"`
 		MinerThreads            int            `toml:",omitempty"`
 		ExtraData               hexutil.Bytes  `toml:",omitempty"`
 		GasPrice                *big.Int
 		TxPool                  core.TxPoolConfig
+		TxReplaceFeeCap         float64
 		GPO                     gasprice.Config
 		EnablePreimageRecording bool
 		DocRoot                 string `toml:"-"`
@@ -47,11 +52,16 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.DatabaseCache = c.DatabaseCache
 	enc.TrieCache = c.TrieCache
 	enc.TrieTimeout = c.TrieTimeout
+	enc.StateRetention = c.StateRetention
+	enc.AddressIndex = c.AddressIndex
+	enc.AncientThreshold = c.AncientThreshold
+	enc.DatabaseFreezer = c.DatabaseFreezer
 	enc.Gccbase = c.Gccbase
 	enc.MinerThreads = c.MinerThreads
 	enc.ExtraData = c.ExtraData
 	enc.GasPrice = c.GasPrice
 	enc.TxPool = c.TxPool
+	enc.TxReplaceFeeCap = c.TxReplaceFeeCap
 	enc.GPO = c.GPO
 	enc.EnablePreimageRecording = c.EnablePreimageRecording
 	enc.DocRoot = c.DocRoot
@@ -71,11 +81,16 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		DatabaseCache           *int
 		TrieCache               *int
 		TrieTimeout             *time.Duration
+		StateRetention          *uint64
+		AddressIndex            *bool
+		AncientThreshold        *uint64
+		DatabaseFreezer         *string         `toml:",omitempty"`
 		Gccbase                 *common.Address `toml:",omitempty"`
 		MinerThreads            *int            `toml:",omitempty"`
 		ExtraData               *hexutil.Bytes  `toml:",omitempty"`
 		GasPrice                *big.Int
 		TxPool                  *core.TxPoolConfig
+		TxReplaceFeeCap         *float64
 		GPO                     *gasprice.Config
 		EnablePreimageRecording *bool
 		DocRoot                 *string `toml:"-"`
@@ -110,6 +125,18 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.TrieTimeout != nil {
 		c.TrieTimeout = *dec.TrieTimeout
 	}
+	if dec.StateRetention != nil {
+		c.StateRetention = *dec.StateRetention
+	}
+	if dec.AddressIndex != nil {
+		c.AddressIndex = *dec.AddressIndex
+	}
+	if dec.AncientThreshold != nil {
+		c.AncientThreshold = *dec.AncientThreshold
+	}
+	if dec.DatabaseFreezer != nil {
+		c.DatabaseFreezer = *dec.DatabaseFreezer
+	}
 	if dec.Gccbase != nil {
 		c.Gccbase = *dec.Gccbase
 	}
@@ -125,6 +152,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.TxPool != nil {
 		c.TxPool = *dec.TxPool
 	}
+	if dec.TxReplaceFeeCap != nil {
+		c.TxReplaceFeeCap = *dec.TxReplaceFeeCap
+	}
 	if dec.GPO != nil {
 		c.GPO = *dec.GPO
 	}
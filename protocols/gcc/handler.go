@@ -635,6 +635,34 @@ func (pm *ProtocolManager) handleSyncMsg(msg p2p.Msg, p *peer) error {
 			log.Debug("Failed to deliver node state data", "err", err)
 		}
 
+	case msg.Code == GetSnapshotChunkMsg:
+		var req getSnapshotChunkData
+		if err := msg.Decode(&req); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+
+		log.Debug("received GetSnapshotChunkMsg", "root", req.Root, "origin", req.Origin.Account)
+
+		// an empty chunk tells the peer the snapshot is unavailable, e.g. the state is pruned
+		chunk, err := syncer.BuildSnapshotChunk(pm.blockchain.StateCache().TrieDB(), req.Root, req.Origin, softResponseLimit)
+		if err != nil {
+			log.Debug("Failed to build state snapshot chunk", "root", req.Root, "err", err)
+			chunk = &syncer.SnapshotChunk{Root: req.Root}
+		}
+		return p.SendSnapshotChunk(chunk)
+
+	case msg.Code == SnapshotChunkMsg:
+		var chunk syncer.SnapshotChunk
+		if err := msg.Decode(&chunk); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+
+		log.Debug("received SnapshotChunkMsg", "accounts", len(chunk.Accounts))
+
+		if err := pm.syncer.DeliverSnapshotChunk(p.id, &chunk); err != nil {
+			log.Debug("Failed to deliver state snapshot chunk", "err", err)
+		}
+
 	case msg.Code == GetReceiptsMsg:
 		// Decode the retrieval message
 		msgStream := rlp.NewStream(msg.Payload, uint64(msg.Size))
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/gcchains/chain/commons/log"
+	"github.com/gcchains/chain/protocols/gcc/syncer"
 	"github.com/gcchains/chain/types"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/p2p"
@@ -275,6 +276,11 @@ func (p *peer) SendNodeData(data [][]byte) error {
 	return p2p.Send(p.rw, NodeDataMsg, data)
 }
 
+// SendSnapshotChunk sends a state snapshot chunk to the remote peer.
+func (p *peer) SendSnapshotChunk(chunk *syncer.SnapshotChunk) error {
+	return p2p.Send(p.rw, SnapshotChunkMsg, chunk)
+}
+
 // SendReceiptsRLP sends a batch of transaction receipts, corresponding to the
 // ones requested from an already RLP encoded format.
 func (p *peer) SendReceiptsRLP(receipts []rlp.RawValue) error {
@@ -316,6 +322,12 @@ func (p *peer) RequestNodeData(hashes []common.Hash) error {
 	return p2p.Send(p.rw, GetNodeDataMsg, hashes)
 }
 
+// RequestSnapshotChunk fetches a chunk of the state snapshot with given root starting from origin.
+func (p *peer) RequestSnapshotChunk(root common.Hash, origin syncer.SnapshotCursor) error {
+	p.Log().Debug("Fetching state snapshot chunk", "root", root, "origin", origin.Account)
+	return p2p.Send(p.rw, GetSnapshotChunkMsg, &getSnapshotChunkData{Root: root, Origin: origin})
+}
+
 // RequestReceipts fetches a batch of transaction receipts from a remote node.
 func (p *peer) RequestReceipts(hashes []common.Hash) error {
 	p.Log().Debug("Fetching batch of receipts", "count", len(hashes))
@@ -9,6 +9,7 @@ import (
 
 	"github.com/gcchains/chain/core"
 	"github.com/gcchains/chain/protocols/gcc/configs"
+	"github.com/gcchains/chain/protocols/gcc/syncer"
 	"github.com/gcchains/chain/types"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/event"
@@ -45,6 +46,10 @@ const (
 	NodeDataMsg    = 0x0e
 	GetReceiptsMsg = 0x0f
 	ReceiptsMsg    = 0x10
+
+	// Protocol messages for snapshot sync
+	GetSnapshotChunkMsg = 0x11
+	SnapshotChunkMsg    = 0x12
 )
 
 type errCode int
@@ -167,6 +172,12 @@ func (hn *hashOrNumber) DecodeRLP(s *rlp.Stream) error {
 	return err
 }
 
+// getSnapshotChunkData represents a state snapshot chunk query.
+type getSnapshotChunkData struct {
+	Root   common.Hash           // State root of the checkpoint block the snapshot is taken at
+	Origin syncer.SnapshotCursor // Position in the snapshot the chunk starts from
+}
+
 // newBlockData is the network packet for the block propagation message.
 type newBlockData struct {
 	Block *types.Block
@@ -0,0 +1,343 @@
+package syncer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/gcchains/chain/core/state"
+	"github.com/gcchains/chain/database"
+	"github.com/gcchains/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+var (
+	errSnapshotUnavailable = errors.New("snapshot is unavailable from peer")
+	errSnapshotOrder       = errors.New("snapshot entries are out of order")
+	errSnapshotCode        = errors.New("snapshot code does not match code hash")
+	errSnapshotRoot        = errors.New("rebuilt snapshot state root mismatch")
+)
+
+var emptyCodeHash = crypto.Keccak256(nil)
+
+// SnapshotCursor is a position in a state snapshot, where accounts and storage slots are ordered by hashed keys.
+type SnapshotCursor struct {
+	Account common.Hash // hashed address of the account
+	Storage common.Hash // hashed key of the storage slot in the account, zero for the beginning of the account
+}
+
+// SnapshotSlot is a storage slot in a state snapshot.
+type SnapshotSlot struct {
+	Hash  common.Hash // hashed key of the slot
+	Value []byte      // rlp encoded value as stored in the storage trie
+}
+
+// SnapshotAccount is an account in a state snapshot chunk.
+// An account with large storage is split into several chunks, only the first one carries its code.
+type SnapshotAccount struct {
+	Hash    common.Hash // hashed address of the account
+	Account []byte      // rlp encoded state.Account as stored in the account trie
+	Code    []byte
+	Storage []SnapshotSlot
+}
+
+// SnapshotChunk is a continuous part of the flat state snapshot taken at a state root.
+// Proof contains trie nodes proving every account and storage slot in the chunk against the state root.
+type SnapshotChunk struct {
+	Root     common.Hash
+	Accounts []SnapshotAccount
+	Proof    [][]byte
+	Next     SnapshotCursor // where the next chunk starts
+	Done     bool           // whether it is the last chunk
+}
+
+// BuildSnapshotChunk builds a snapshot chunk of the state with given root starting from origin,
+// the chunk is cut once its size exceeds limit bytes.
+func BuildSnapshotChunk(db *trie.Database, root common.Hash, origin SnapshotCursor, limit int) (*SnapshotChunk, error) {
+	accTrie, err := trie.New(root, db)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		chunk   = &SnapshotChunk{Root: root}
+		proof   = newProofSet()
+		size    = 0
+		storage = origin.Storage
+		it      = trie.NewIterator(accTrie.NodeIterator(origin.Account[:]))
+	)
+	finish := func() *SnapshotChunk {
+		chunk.Proof = proof.nodes
+		return chunk
+	}
+
+	for it.Next() {
+		hash := common.BytesToHash(it.Key)
+		if hash != origin.Account {
+			storage = common.Hash{}
+		}
+		if size+proof.size >= limit {
+			chunk.Next = SnapshotCursor{Account: hash}
+			return finish(), nil
+		}
+
+		var account state.Account
+		if err := rlp.DecodeBytes(it.Value, &account); err != nil {
+			return nil, err
+		}
+		if err := accTrie.Prove(it.Key, 0, proof); err != nil {
+			return nil, err
+		}
+		entry := SnapshotAccount{Hash: hash, Account: common.CopyBytes(it.Value)}
+		if storage == (common.Hash{}) && !bytes.Equal(account.CodeHash, emptyCodeHash) {
+			if entry.Code, err = db.Node(common.BytesToHash(account.CodeHash)); err != nil {
+				return nil, err
+			}
+		}
+		size += len(it.Key) + len(entry.Account) + len(entry.Code)
+
+		if account.Root != types.EmptyRootHash {
+			storageTrie, err := trie.New(account.Root, db)
+			if err != nil {
+				return nil, err
+			}
+			sit := trie.NewIterator(storageTrie.NodeIterator(storage[:]))
+			for sit.Next() {
+				// a chunk resuming an account contains at least one slot of it
+				progressed := len(entry.Storage) > 0 || storage == (common.Hash{}) || len(chunk.Accounts) > 0
+				if size+proof.size >= limit && progressed {
+					chunk.Accounts = append(chunk.Accounts, entry)
+					chunk.Next = SnapshotCursor{Account: hash, Storage: common.BytesToHash(sit.Key)}
+					return finish(), nil
+				}
+				if err := storageTrie.Prove(sit.Key, 0, proof); err != nil {
+					return nil, err
+				}
+				entry.Storage = append(entry.Storage, SnapshotSlot{Hash: common.BytesToHash(sit.Key), Value: common.CopyBytes(sit.Value)})
+				size += len(sit.Key) + len(sit.Value)
+			}
+			if sit.Err != nil {
+				return nil, sit.Err
+			}
+		}
+		chunk.Accounts = append(chunk.Accounts, entry)
+	}
+	if it.Err != nil {
+		return nil, it.Err
+	}
+	chunk.Done = true
+	return finish(), nil
+}
+
+// proofSet collects distinct trie nodes of merkle proofs.
+type proofSet struct {
+	keys  map[string]struct{}
+	nodes [][]byte
+	size  int
+}
+
+func newProofSet() *proofSet {
+	return &proofSet{keys: make(map[string]struct{})}
+}
+
+// Put implements database.Putter.
+func (p *proofSet) Put(key []byte, value []byte) error {
+	if _, ok := p.keys[string(key)]; ok {
+		return nil
+	}
+	p.keys[string(key)] = struct{}{}
+	p.nodes = append(p.nodes, common.CopyBytes(value))
+	p.size += len(value)
+	return nil
+}
+
+// VerifySnapshotChunk verifies a snapshot chunk requested from origin of the state with given root.
+// All accounts and storage slots must be proved by the proof in the chunk and in order.
+// It does not detect missing entries, which is done by comparing roots after the whole state is rebuilt.
+func VerifySnapshotChunk(root common.Hash, origin SnapshotCursor, chunk *SnapshotChunk) error {
+	if chunk.Root != root {
+		return fmt.Errorf("snapshot root mismatch: have %x, want %x", chunk.Root, root)
+	}
+	if len(chunk.Accounts) == 0 && !chunk.Done {
+		return errSnapshotUnavailable
+	}
+
+	proof := database.NewMemDatabase()
+	for _, node := range chunk.Proof {
+		proof.Put(crypto.Keccak256(node), node)
+	}
+
+	last := origin
+	continued := origin.Storage != (common.Hash{})
+	for i, entry := range chunk.Accounts {
+		// the first account continues the one in the previous chunk if the origin is in the middle of its storage
+		resumed := i == 0 && continued
+		switch {
+		case resumed:
+			if entry.Hash != origin.Account {
+				return errSnapshotOrder
+			}
+		case i == 0:
+			if bytes.Compare(entry.Hash[:], origin.Account[:]) < 0 {
+				return errSnapshotOrder
+			}
+		default:
+			if bytes.Compare(entry.Hash[:], last.Account[:]) <= 0 {
+				return errSnapshotOrder
+			}
+		}
+
+		value, _, err := trie.VerifyProof(root, entry.Hash[:], proof)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(value, entry.Account) {
+			return fmt.Errorf("snapshot account %x is not proved", entry.Hash)
+		}
+		var account state.Account
+		if err := rlp.DecodeBytes(entry.Account, &account); err != nil {
+			return err
+		}
+
+		switch {
+		case resumed:
+			if len(entry.Code) != 0 {
+				return errSnapshotCode
+			}
+		case bytes.Equal(account.CodeHash, emptyCodeHash):
+			if len(entry.Code) != 0 {
+				return errSnapshotCode
+			}
+		default:
+			if !bytes.Equal(crypto.Keccak256(entry.Code), account.CodeHash) {
+				return errSnapshotCode
+			}
+		}
+
+		lastSlot := common.Hash{}
+		if resumed {
+			lastSlot = origin.Storage
+		}
+		for j, slot := range entry.Storage {
+			// the first slot of a resumed account is at the origin, others are after the previous one
+			if cmp := bytes.Compare(slot.Hash[:], lastSlot[:]); cmp < 0 || cmp == 0 && (j > 0 || !resumed) {
+				return errSnapshotOrder
+			}
+			value, _, err := trie.VerifyProof(account.Root, slot.Hash[:], proof)
+			if err != nil {
+				return err
+			}
+			if !bytes.Equal(value, slot.Value) {
+				return fmt.Errorf("snapshot storage slot %x of account %x is not proved", slot.Hash, entry.Hash)
+			}
+			lastSlot = slot.Hash
+		}
+		last = SnapshotCursor{Account: entry.Hash, Storage: lastSlot}
+	}
+
+	if !chunk.Done {
+		next := chunk.Next
+		switch {
+		case next.Account == last.Account && next.Storage != (common.Hash{}):
+			if bytes.Compare(next.Storage[:], last.Storage[:]) <= 0 {
+				return errSnapshotOrder
+			}
+		case bytes.Compare(next.Account[:], last.Account[:]) <= 0 || next.Storage != (common.Hash{}):
+			return errSnapshotOrder
+		}
+	}
+	return nil
+}
+
+// snapshotBuilder rebuilds state tries from verified snapshot chunks in order.
+type snapshotBuilder struct {
+	root   common.Hash
+	db     database.Database
+	triedb *trie.Database
+
+	accounts *trie.Trie
+
+	// the account whose storage is being rebuilt
+	pending     bool
+	accountHash common.Hash
+	accountBlob []byte
+	account     state.Account
+	storage     *trie.Trie
+}
+
+func newSnapshotBuilder(root common.Hash, db database.Database) *snapshotBuilder {
+	triedb := trie.NewDatabase(db)
+	accounts, _ := trie.New(common.Hash{}, triedb)
+	return &snapshotBuilder{
+		root:     root,
+		db:       db,
+		triedb:   triedb,
+		accounts: accounts,
+	}
+}
+
+// process applies a verified chunk, the state is committed to database after the last chunk.
+func (b *snapshotBuilder) process(chunk *SnapshotChunk) error {
+	batch := b.db.NewBatch()
+	for _, entry := range chunk.Accounts {
+		if !b.pending || entry.Hash != b.accountHash {
+			if err := b.finishAccount(); err != nil {
+				return err
+			}
+			if err := rlp.DecodeBytes(entry.Account, &b.account); err != nil {
+				return err
+			}
+			if len(entry.Code) != 0 {
+				batch.Put(b.account.CodeHash, entry.Code)
+			}
+			b.pending, b.accountHash, b.accountBlob = true, entry.Hash, entry.Account
+			b.storage, _ = trie.New(common.Hash{}, b.triedb)
+		}
+		for _, slot := range entry.Storage {
+			if err := b.storage.TryUpdate(slot.Hash[:], slot.Value); err != nil {
+				return err
+			}
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	if !chunk.Done {
+		return nil
+	}
+
+	if err := b.finishAccount(); err != nil {
+		return err
+	}
+	root, err := b.accounts.Commit(nil)
+	if err != nil {
+		return err
+	}
+	if root != b.root {
+		return errSnapshotRoot
+	}
+	return b.triedb.Commit(root, false)
+}
+
+// finishAccount writes the storage trie of the pending account and inserts the account into the account trie.
+func (b *snapshotBuilder) finishAccount() error {
+	if !b.pending {
+		return nil
+	}
+	b.pending = false
+
+	root, err := b.storage.Commit(nil)
+	if err != nil {
+		return err
+	}
+	if root != b.account.Root {
+		return fmt.Errorf("rebuilt storage root of account %x mismatch: have %x, want %x", b.accountHash, root, b.account.Root)
+	}
+	if err := b.triedb.Commit(root, false); err != nil {
+		return err
+	}
+	return b.accounts.TryUpdate(b.accountHash[:], b.accountBlob)
+}
@@ -0,0 +1,136 @@
+package syncer
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/gcchains/chain/core/state"
+	"github.com/gcchains/chain/database"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// newSnapshotTestState creates a state with plain accounts and contracts with storage.
+func newSnapshotTestState(t *testing.T) (*database.MemDatabase, common.Hash) {
+	db := database.NewMemDatabase()
+	sdb := state.NewDatabase(db)
+	statedb, _ := state.New(common.Hash{}, sdb)
+	for i := 0; i < 50; i++ {
+		addr := common.BigToAddress(big.NewInt(int64(i + 1)))
+		statedb.SetBalance(addr, big.NewInt(int64(i+1)*1000))
+		statedb.SetNonce(addr, uint64(i))
+		if i%10 == 0 {
+			statedb.SetCode(addr, []byte{byte(i), 0x60, 0x00})
+			for j := 0; j < 40; j++ {
+				statedb.SetState(addr, common.BigToHash(big.NewInt(int64(j))), common.BigToHash(big.NewInt(int64(i*100+j+1))))
+			}
+		}
+	}
+	root, err := statedb.Commit(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sdb.TrieDB().Commit(root, false); err != nil {
+		t.Fatal(err)
+	}
+	return db, root
+}
+
+// Tests that a state is rebuilt from snapshot chunks split in the middle of accounts and storage.
+func TestSnapshotRebuild(t *testing.T) {
+	src, root := newSnapshotTestState(t)
+	srcState, _ := state.New(root, state.NewDatabase(src))
+
+	for _, limit := range []int{1, 500, 4096, 1 << 20} {
+		var (
+			dst     = database.NewMemDatabase()
+			builder = newSnapshotBuilder(root, dst)
+			origin  SnapshotCursor
+			chunks  int
+		)
+		for {
+			chunk, err := BuildSnapshotChunk(trie.NewDatabase(src), root, origin, limit)
+			if err != nil {
+				t.Fatalf("limit %d: BuildSnapshotChunk() error = %v", limit, err)
+			}
+			if err := VerifySnapshotChunk(root, origin, chunk); err != nil {
+				t.Fatalf("limit %d: VerifySnapshotChunk() error = %v", limit, err)
+			}
+			if err := builder.process(chunk); err != nil {
+				t.Fatalf("limit %d: process() error = %v", limit, err)
+			}
+			chunks++
+			if chunk.Done {
+				break
+			}
+			origin = chunk.Next
+		}
+		if limit < 1<<20 && chunks == 1 {
+			t.Errorf("limit %d: snapshot is not split", limit)
+		}
+
+		dstState, err := state.New(root, state.NewDatabase(dst))
+		if err != nil {
+			t.Fatalf("limit %d: rebuilt state is missing: %v", limit, err)
+		}
+		for i := 0; i < 50; i += 7 {
+			addr := common.BigToAddress(big.NewInt(int64(i + 1)))
+			if got, want := dstState.GetBalance(addr), srcState.GetBalance(addr); got.Cmp(want) != 0 {
+				t.Errorf("limit %d: balance of %x = %v, want %v", limit, addr, got, want)
+			}
+		}
+		contract := common.BigToAddress(big.NewInt(21))
+		if got, want := dstState.GetCode(contract), srcState.GetCode(contract); string(got) != string(want) {
+			t.Errorf("limit %d: code = %x, want %x", limit, got, want)
+		}
+		slot := common.BigToHash(big.NewInt(39))
+		if got, want := dstState.GetState(contract, slot), srcState.GetState(contract, slot); got != want {
+			t.Errorf("limit %d: storage = %x, want %x", limit, got, want)
+		}
+	}
+}
+
+// Tests that tampered snapshot chunks are rejected.
+func TestVerifySnapshotChunk(t *testing.T) {
+	src, root := newSnapshotTestState(t)
+	build := func() *SnapshotChunk {
+		chunk, err := BuildSnapshotChunk(trie.NewDatabase(src), root, SnapshotCursor{}, 4096)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return chunk
+	}
+	firstContract := func(chunk *SnapshotChunk) *SnapshotAccount {
+		for i := range chunk.Accounts {
+			if len(chunk.Accounts[i].Storage) > 1 {
+				return &chunk.Accounts[i]
+			}
+		}
+		t.Fatal("no contract in chunk")
+		return nil
+	}
+
+	tests := []struct {
+		name   string
+		tamper func(chunk *SnapshotChunk)
+		want   bool
+	}{
+		{"valid", func(chunk *SnapshotChunk) {}, true},
+		{"wrong root", func(chunk *SnapshotChunk) { chunk.Root = common.Hash{1} }, false},
+		{"tampered account", func(chunk *SnapshotChunk) { chunk.Accounts[0].Account[len(chunk.Accounts[0].Account)-1]++ }, false},
+		{"tampered code", func(chunk *SnapshotChunk) { firstContract(chunk).Code = []byte{0xff} }, false},
+		{"tampered storage", func(chunk *SnapshotChunk) { firstContract(chunk).Storage[0].Value = []byte{0x01} }, false},
+		{"missing proof", func(chunk *SnapshotChunk) { chunk.Proof = chunk.Proof[1:] }, false},
+		{"out of order", func(chunk *SnapshotChunk) {
+			chunk.Accounts[0], chunk.Accounts[1] = chunk.Accounts[1], chunk.Accounts[0]
+		}, false},
+		{"empty", func(chunk *SnapshotChunk) { chunk.Accounts, chunk.Done = nil, false }, false},
+	}
+	for _, tt := range tests {
+		chunk := build()
+		tt.tamper(chunk)
+		if err := VerifySnapshotChunk(root, SnapshotCursor{}, chunk); (err == nil) != tt.want {
+			t.Errorf("%s: VerifySnapshotChunk() error = %v, want valid %v", tt.name, err, tt.want)
+		}
+	}
+}
@@ -86,3 +86,55 @@ func (s *Synchronizer) processFastSyncContent(hash common.Hash) error {
 	}
 	return nil
 }
+
+// snapshotRequest is a request of a state snapshot chunk.
+type snapshotRequest struct {
+	root   common.Hash
+	origin SnapshotCursor
+}
+
+// sync state data from a snapshot chunk by chunk, and rebuild state tries locally
+func (s *Synchronizer) processSnapshotContent(root common.Hash) error {
+	var (
+		builder = newSnapshotBuilder(root, s.blockchain.Database())
+		origin  SnapshotCursor
+		chunks  int
+		start   = time.Now()
+	)
+
+	for {
+		s.syncRequestSnapshotCh <- snapshotRequest{root: root, origin: origin}
+		timer := time.NewTimer(SyncStateTimeout)
+
+		var chunk *SnapshotChunk
+		select {
+		case chunk = <-s.syncSnapshotCh:
+			timer.Stop()
+		case <-timer.C:
+			log.Error("snapshot sync timeout error")
+			return ErrTimeout
+		case <-s.cancelCh:
+			s.processFastSyncContentCh <- struct{}{}
+			return errCanceled
+		case <-s.quitCh:
+			return errQuitSync
+		}
+
+		if err := VerifySnapshotChunk(root, origin, chunk); err != nil {
+			log.Warn("invalid snapshot chunk", "root", root, "origin", origin.Account, "err", err)
+			return err
+		}
+		if err := builder.process(chunk); err != nil {
+			log.Warn("failed to rebuild state from snapshot", "root", root, "err", err)
+			return err
+		}
+		chunks++
+		log.Debug("fetch snapshot chunk", "accounts", len(chunk.Accounts), "next", chunk.Next.Account)
+
+		if chunk.Done {
+			log.Info("state snapshot synced", "root", root, "chunks", chunks, "elapsed", common.PrettyDuration(time.Since(start)))
+			return nil
+		}
+		origin = chunk.Next
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/gcchains/chain/commons/chainmetrics"
 	"github.com/gcchains/chain/commons/log"
 	"github.com/gcchains/chain/configs"
+	"github.com/gcchains/chain/consensus/dpos/backend"
 	"github.com/gcchains/chain/core/state"
 	"github.com/gcchains/chain/database"
 	"github.com/gcchains/chain/types"
@@ -39,13 +40,14 @@ var (
 	MinFullBlocks = configs.DefaultFullSyncPivot
 )
 
-// SyncMode : Full, Fast
+// SyncMode : Full, Fast, Snapshot
 type SyncMode int
 
-// FullSync, FastSync
+// FullSync, FastSync, SnapshotSync
 const (
-	FullSync SyncMode = iota // Synchronise the entire blockchain history from full blocks
-	FastSync                 // Quickly download the headers, full sync only at the chain head
+	FullSync     SyncMode = iota // Synchronise the entire blockchain history from full blocks
+	FastSync                     // Quickly download the headers, full sync only at the chain head
+	SnapshotSync                 // Download the headers and the state snapshot at a term checkpoint, full sync from there
 )
 
 var (
@@ -77,6 +79,9 @@ type SyncPeer interface {
 
 	RequestNodeData(hashes []common.Hash) error
 
+	// RequestSnapshotChunk fetches a chunk of the state snapshot with given root starting from origin.
+	RequestSnapshotChunk(root common.Hash, origin SnapshotCursor) error
+
 	RequestHeadersByNumber(origin uint64, amount int, skip int, reverse bool) error
 
 	RequestBodies([]common.Hash) error
@@ -111,6 +116,9 @@ type Syncer interface {
 	// DeliverNodeData injects a new batch of node state data received from a remote node.
 	DeliverNodeData(id string, data [][]byte) error
 
+	// DeliverSnapshotChunk injects a state snapshot chunk received from a remote node.
+	DeliverSnapshotChunk(id string, chunk *SnapshotChunk) error
+
 	// DeliverHeaders injects a new batch of block headers received from a remote
 	// node into the download schedule.
 	DeliverHeaders(id string, headers []*types.Header) error
@@ -191,6 +199,9 @@ type BlockChain interface {
 	// FastSyncCommitHead sets the current head block to the one defined by the hash
 	// irrelevant what the chain contents were prior.
 	FastSyncCommitHead(hash common.Hash) error
+
+	// Config retrieves the blockchain's chain configuration.
+	Config() *configs.ChainConfig
 }
 
 // Synchronizer is responsible for syncing local chain to latest block
@@ -208,6 +219,8 @@ type Synchronizer struct {
 	syncRequestReceiptsCh  chan []common.Hash
 	syncRequestBodiesCh    chan []common.Hash
 	syncRequestStateDataCh chan []common.Hash
+	syncSnapshotCh         chan *SnapshotChunk
+	syncRequestSnapshotCh  chan snapshotRequest
 	cancelCh               chan struct{}
 	quitCh                 chan struct{}
 
@@ -249,6 +262,8 @@ func New(chain BlockChain, dropPeer DropPeer, mux *event.TypeMux) *Synchronizer
 		syncRequestReceiptsCh:      make(chan []common.Hash, 1),
 		syncRequestBodiesCh:        make(chan []common.Hash, 1),
 		syncRequestStateDataCh:     make(chan []common.Hash, 1),
+		syncSnapshotCh:             make(chan *SnapshotChunk, 1),
+		syncRequestSnapshotCh:      make(chan snapshotRequest, 1),
 		cancelCh:                   make(chan struct{}),
 		quitCh:                     make(chan struct{}),
 		progress:                   &gcchain.SyncProgress{},
@@ -294,12 +309,15 @@ func (s *Synchronizer) Synchronise(p SyncPeer, head common.Hash, height *big.Int
 
 	s.blockchain.SetKnownHead(head, height.Uint64())
 
-	if mode == FastSync {
+	if mode == FastSync || mode == SnapshotSync {
 		current := s.blockchain.CurrentFastBlock().NumberU64()
-		if height.Uint64()-current <= uint64(MinFullBlocks) {
+		pivot := s.selectPivot(height.Uint64())
+		if mode == SnapshotSync {
+			pivot = s.selectCheckpoint(pivot)
+		}
+		if height.Uint64()-current <= uint64(MinFullBlocks) || pivot <= current {
 			err = s.synchronise(p, head, height.Uint64(), FullSync)
 		} else {
-			pivot := s.selectPivot(height.Uint64())
 			err = s.synchronise(p, head, pivot, mode)
 			if err == nil {
 				err = s.synchronise(p, head, height.Uint64(), FullSync)
@@ -340,6 +358,20 @@ func (s *Synchronizer) selectPivot(height uint64) uint64 {
 	return pivot
 }
 
+// selectCheckpoint returns the latest term checkpoint not after pivot, the state snapshot is taken at it.
+func (s *Synchronizer) selectCheckpoint(pivot uint64) uint64 {
+	config := s.blockchain.Config().Dpos
+	if config == nil {
+		return pivot
+	}
+	for number := pivot; number > 0; number-- {
+		if backend.IsCheckPoint(number, config.TermLen, config.ViewLen) {
+			return number
+		}
+	}
+	return 0
+}
+
 func (s *Synchronizer) blocksHandler(errCh chan error, successCh chan struct{}, mode SyncMode) {
 	for {
 		if atomic.LoadInt32(&s.finishFetch) == int32(1) && s.blocksQueue.empty() {
@@ -356,7 +388,7 @@ func (s *Synchronizer) blocksHandler(errCh chan error, successCh chan struct{},
 			return
 		}
 		s.progressLock.Lock()
-		if mode == FastSync {
+		if mode != FullSync {
 			s.progress.CurrentBlock = s.blockchain.CurrentFastBlock().NumberU64()
 		} else {
 			s.progress.CurrentBlock = s.blockchain.CurrentBlock().NumberU64()
@@ -413,7 +445,7 @@ func (s *Synchronizer) synchronise(p SyncPeer, head common.Hash, height uint64,
 		currentNumber = currentHeader.Number.Uint64()
 	)
 
-	if mode == FastSync {
+	if mode != FullSync {
 		currentHeader = s.blockchain.CurrentFastBlock().Header()
 		currentNumber = currentHeader.Number.Uint64()
 	}
@@ -472,7 +504,7 @@ func (s *Synchronizer) synchronise(p SyncPeer, head common.Hash, height uint64,
 
 	// sync state data
 	// get the latest block
-	if mode == FastSync {
+	if mode != FullSync {
 		atomic.StoreInt32(&s.headerIdle, 0)
 		atomic.StoreInt32(&s.blockIdle, 0)
 		if atomic.CompareAndSwapInt32(&s.processFastSyncContentIdle, 0, 1) {
@@ -485,7 +517,12 @@ func (s *Synchronizer) synchronise(p SyncPeer, head common.Hash, height uint64,
 				return ErrTimeout
 			}
 			go func(root common.Hash) {
-				err := s.processFastSyncContent(root)
+				var err error
+				if mode == SnapshotSync {
+					err = s.processSnapshotContent(root)
+				} else {
+					err = s.processFastSyncContent(root)
+				}
 				if err != nil {
 					errCh <- err
 				}
@@ -514,7 +551,7 @@ func (s *Synchronizer) synchronise(p SyncPeer, head common.Hash, height uint64,
 
 		prepare := make(chan bool, 2)
 
-		if mode == FastSync {
+		if mode != FullSync {
 			if MaxBlockFetch < height-i {
 				go s.FetchHeaders(i, MaxBlockFetch)
 			} else {
@@ -548,7 +585,7 @@ func (s *Synchronizer) synchronise(p SyncPeer, head common.Hash, height uint64,
 				elapsed := common.PrettyDuration(time.Since(stats.fetchHeaderStart))
 				log.Debug("fetch headers", "elapsed", elapsed)
 
-				if mode == FastSync {
+				if mode != FullSync {
 					hashes := make([]common.Hash, len(headers))
 					for i, header := range headers {
 						hashes[i] = header.Hash()
@@ -615,7 +652,7 @@ func (s *Synchronizer) synchronise(p SyncPeer, head common.Hash, height uint64,
 	select {
 	case <-successCh:
 		// wait blocks handler be success
-		if mode == FastSync {
+		if mode != FullSync {
 			// wait state sync be finished
 			<-s.stateSyncFinishCh
 			// commit the pivot point
@@ -671,6 +708,8 @@ func (s *Synchronizer) sendRequestLoop() {
 			go s.FetchBodies(hashes)
 		case hashes := <-s.syncRequestStateDataCh:
 			go s.currentPeer.RequestNodeData(hashes)
+		case req := <-s.syncRequestSnapshotCh:
+			go s.currentPeer.RequestSnapshotChunk(req.root, req.origin)
 		case <-s.cancelCh:
 			s.sendRequestLoopFinishCh <- struct{}{}
 			return
@@ -743,6 +782,20 @@ func (s *Synchronizer) DeliverNodeData(id string, data [][]byte) error {
 	return errCanceled
 }
 
+// DeliverSnapshotChunk injects a state snapshot chunk received from a remote node.
+func (s *Synchronizer) DeliverSnapshotChunk(id string, chunk *SnapshotChunk) error {
+	if s.Synchronising() {
+		s.currentPeerMutex.Lock()
+		defer s.currentPeerMutex.Unlock()
+		if s.currentPeer.IDString() != id {
+			return ErrUnknownPeer
+		}
+		s.syncSnapshotCh <- chunk
+		return nil
+	}
+	return errCanceled
+}
+
 // Progress report the progress
 func (s *Synchronizer) Progress() gcchain.SyncProgress {
 	s.progressLock.RLock()
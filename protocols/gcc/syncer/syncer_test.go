@@ -26,6 +26,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/trie"
 )
 
 var (
@@ -691,6 +692,74 @@ func TestGetBalanceAtMiddleBlock(t *testing.T) {
 	}
 }
 
+func TestSnapshotSync(t *testing.T) {
+	syncer.MinFullBlocks = 32
+	defer func() { syncer.MinFullBlocks = 1024 }()
+
+	var (
+		p            = NewFakePeer(200, 0, true)
+		head, height = p.Head()
+	)
+
+	localchain, _ := newBlockchainWithDB(0, false)
+	localchain.SetSyncMode(syncer.SnapshotSync)
+	localSyncer := newSyncer(localchain)
+	localSyncer.AddPeer(p)
+	defer localSyncer.Terminate()
+
+	go p.returnBlocksLoop()
+	defer p.quit()
+	go func() {
+		for {
+			select {
+			case blocks := <-p.returnCh:
+				localSyncer.DeliverBlocks(p.IDString(), blocks)
+			case receipts := <-p.returnReceiptsCh:
+				localSyncer.DeliverReceipts(p.IDString(), receipts)
+			case data := <-p.returnStateDataCh:
+				t.Errorf("state is downloaded node by node in snapshot sync")
+				localSyncer.DeliverNodeData(p.IDString(), data)
+			case chunk := <-p.returnSnapshotCh:
+				localSyncer.DeliverSnapshotChunk(p.IDString(), chunk)
+			case headers := <-p.returnHeadersCh:
+				localSyncer.DeliverHeaders(p.IDString(), headers)
+			case bodies := <-p.returnBodiesCh:
+				localSyncer.DeliverBodies(p.IDString(), bodies)
+			case <-p.quitCh:
+				return
+			}
+		}
+	}()
+
+	if err := localSyncer.Synchronise(p, head, height, syncer.SnapshotSync); err != nil {
+		t.Fatalf("Synchronise() error = %v", err)
+	}
+	if got := localchain.CurrentBlock().NumberU64(); got != height.Uint64() {
+		t.Fatalf("head = %d, want %d", got, height)
+	}
+
+	pivot := height.Uint64() - uint64(syncer.MinFullBlocks)
+	checkpoint := localchain.GetBlockByNumber(pivot - pivot%12)
+	// states before the checkpoint are not downloaded or executed
+	if _, err := localchain.StateAt(localchain.GetBlockByNumber(1).StateRoot()); err == nil {
+		t.Errorf("state before the checkpoint exists")
+	}
+	// the checkpoint state is rebuilt from the snapshot, and later ones are executed
+	for _, block := range []*types.Block{checkpoint, localchain.CurrentBlock()} {
+		local, err := localchain.StateAt(block.StateRoot())
+		if err != nil {
+			t.Fatalf("state at block %d is missing: %v", block.NumberU64(), err)
+		}
+		remote, _ := p.blockchain.StateAt(block.StateRoot())
+		if got, want := local.GetBalance(testBank), remote.GetBalance(testBank); got.Cmp(want) != 0 {
+			t.Errorf("bank balance at block %d = %v, want %v", block.NumberU64(), got, want)
+		}
+		if got, want := len(local.GetCode(rewardAddr)), len(remote.GetCode(rewardAddr)); got != want {
+			t.Errorf("reward code length at block %d = %d, want %d", block.NumberU64(), got, want)
+		}
+	}
+}
+
 func newBlockchainWithDB(n int, deployContract bool) (syncer.BlockChain, *database.MemDatabase) {
 	db := database.NewMemDatabase()
 	remoteDB := database.NewIpfsDbWithAdapter(database.NewFakeIpfsAdapter())
@@ -806,6 +875,7 @@ type FakePeer struct {
 	returnStateDataCh chan [][]byte
 	returnHeadersCh   chan []*types.Header
 	returnBodiesCh    chan [][]*types.Transaction
+	returnSnapshotCh  chan *syncer.SnapshotChunk
 	quitCh            chan struct{}
 	id                int
 	errorPeer         bool
@@ -832,6 +902,7 @@ func NewFakePeer(n int, badIdx int, deployContract bool) *FakePeer {
 		returnStateDataCh: make(chan [][]byte),
 		returnHeadersCh:   make(chan []*types.Header),
 		returnBodiesCh:    make(chan [][]*types.Transaction),
+		returnSnapshotCh:  make(chan *syncer.SnapshotChunk),
 		quitCh:            make(chan struct{}),
 		id:                0,
 	}
@@ -876,6 +947,15 @@ func (fp *FakePeer) RequestNodeData(hashes []common.Hash) error {
 	return nil
 }
 
+func (fp *FakePeer) RequestSnapshotChunk(root common.Hash, origin syncer.SnapshotCursor) error {
+	chunk, err := syncer.BuildSnapshotChunk(trie.NewDatabase(fp.db), root, origin, 4096)
+	if err != nil {
+		chunk = &syncer.SnapshotChunk{Root: root}
+	}
+	fp.returnSnapshotCh <- chunk
+	return nil
+}
+
 func (fp *FakePeer) returnBlocksLoop() {
 	for {
 		select {
@@ -0,0 +1,98 @@
+// Copyright 2018 The gcchain authors
+
+package les
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gcchains/chain/api/rpc"
+	"github.com/gcchains/chain/internal/gccapi"
+	"github.com/gcchains/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// PublicLightAPI provides the chain APIs a light client is able to serve, states are retrieved on demand.
+type PublicLightAPI struct {
+	chain  *LightChain
+	client *client
+}
+
+// newPublicLightAPI creates a new light chain API.
+func newPublicLightAPI(chain *LightChain, client *client) *PublicLightAPI {
+	return &PublicLightAPI{chain: chain, client: client}
+}
+
+func (api *PublicLightAPI) header(blockNr rpc.BlockNumber) (*types.Header, error) {
+	if blockNr == rpc.LatestBlockNumber || blockNr == rpc.PendingBlockNumber {
+		return api.chain.CurrentHeader(), nil
+	}
+	header := api.chain.GetHeaderByNumber(uint64(blockNr))
+	if header == nil {
+		return nil, fmt.Errorf("header #%d not found", blockNr)
+	}
+	return header, nil
+}
+
+// BlockNumber returns the block number of the light chain head.
+func (api *PublicLightAPI) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(api.chain.CurrentHeader().Number.Uint64())
+}
+
+// GetBlockByNumber returns the header of the requested block, the light chain has no transactions.
+func (api *PublicLightAPI) GetBlockByNumber(ctx context.Context, blockNr rpc.BlockNumber, fullTx bool) (map[string]interface{}, error) {
+	header, err := api.header(blockNr)
+	if err != nil {
+		return nil, err
+	}
+	return gccapi.RPCMarshalBlock(types.NewBlockWithHeader(header), false, false)
+}
+
+// GetBalance returns the balance of the account in the state of the given block number.
+func (api *PublicLightAPI) GetBalance(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (*hexutil.Big, error) {
+	header, err := api.header(blockNr)
+	if err != nil {
+		return nil, err
+	}
+	account, err := api.client.GetAccount(ctx, header, address)
+	if err != nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(account.Balance), nil
+}
+
+// GetTransactionCount returns the nonce of the account in the state of the given block number.
+func (api *PublicLightAPI) GetTransactionCount(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (*hexutil.Uint64, error) {
+	header, err := api.header(blockNr)
+	if err != nil {
+		return nil, err
+	}
+	account, err := api.client.GetAccount(ctx, header, address)
+	if err != nil {
+		return nil, err
+	}
+	return (*hexutil.Uint64)(&account.Nonce), nil
+}
+
+// GetCode returns the code of the contract in the state of the given block number.
+func (api *PublicLightAPI) GetCode(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (hexutil.Bytes, error) {
+	header, err := api.header(blockNr)
+	if err != nil {
+		return nil, err
+	}
+	return api.client.GetCode(ctx, header, address)
+}
+
+// GetStorageAt returns the storage at the given address and key in the state of the given block number.
+func (api *PublicLightAPI) GetStorageAt(ctx context.Context, address common.Address, key string, blockNr rpc.BlockNumber) (hexutil.Bytes, error) {
+	header, err := api.header(blockNr)
+	if err != nil {
+		return nil, err
+	}
+	value, err := api.client.GetStorage(ctx, header, address, common.HexToHash(key))
+	if err != nil {
+		return nil, err
+	}
+	return value[:], nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2018 The gcchain authors
+
+package les
+
+import (
+	"errors"
+
+	"github.com/gcchains/chain/api/rpc"
+	"github.com/gcchains/chain/commons/log"
+	"github.com/gcchains/chain/consensus/dpos"
+	"github.com/gcchains/chain/core"
+	"github.com/gcchains/chain/database"
+	"github.com/gcchains/chain/node"
+	"github.com/ethereum/go-ethereum/p2p"
+)
+
+var errLightWithoutDpos = errors.New("light client requires the dpos consensus")
+
+// Config contains configurations of a light client.
+type Config struct {
+	Genesis   *core.Genesis
+	NetworkId uint64
+
+	DatabaseCache   int
+	DatabaseHandles int
+}
+
+// LightService implements the light client service, which syncs verified headers only
+// and retrieves state on demand from light servers.
+type LightService struct {
+	chainDb database.Database
+	engine  *dpos.Dpos
+	chain   *LightChain
+	client  *client
+}
+
+// New creates a light client service.
+func New(ctx *node.ServiceContext, config *Config) (*LightService, error) {
+	chainDb, err := ctx.OpenDatabase("lightchaindata", config.DatabaseCache, config.DatabaseHandles)
+	if err != nil {
+		return nil, err
+	}
+	chainConfig, _, err := core.SetupGenesisBlock(chainDb, config.Genesis)
+	if err != nil {
+		return nil, err
+	}
+	if chainConfig.Dpos == nil {
+		return nil, errLightWithoutDpos
+	}
+	log.Info("Initialised light chain configuration", "config", chainConfig)
+
+	engine := dpos.New(chainConfig.Dpos, chainDb)
+	chain, err := NewLightChain(chainDb, chainConfig, engine)
+	if err != nil {
+		return nil, err
+	}
+	return &LightService{
+		chainDb: chainDb,
+		engine:  engine,
+		chain:   chain,
+		client:  newClient(chain, config.NetworkId),
+	}, nil
+}
+
+// LightChain returns the light chain.
+func (s *LightService) LightChain() *LightChain { return s.chain }
+
+// Protocols implements node.Service, returning the light protocol.
+func (s *LightService) Protocols() []p2p.Protocol {
+	return s.client.Protocols()
+}
+
+// APIs implements node.Service, returning the light client APIs.
+func (s *LightService) APIs() []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "eth",
+			Version:   "1.0",
+			Service:   newPublicLightAPI(s.chain, s.client),
+			Public:    true,
+		},
+	}
+}
+
+// Start implements node.Service.
+func (s *LightService) Start(srvr *p2p.Server) error {
+	log.Info("Light client service started", "head", s.chain.CurrentHeader().Number)
+	return nil
+}
+
+// Stop implements node.Service, terminating syncing and pending requests.
+func (s *LightService) Stop() error {
+	s.chain.Stop()
+	s.client.Stop()
+	s.chainDb.Close()
+	return nil
+}
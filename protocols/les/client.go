@@ -0,0 +1,281 @@
+// Copyright 2018 The gcchain authors
+
+package les
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gcchains/chain/commons/log"
+	"github.com/ethereum/go-ethereum/p2p"
+)
+
+// requestTimeout is the time waiting for a server to respond a request before asking the next one.
+const requestTimeout = 3 * time.Second
+
+var (
+	errNoServers         = errors.New("no light servers available")
+	errRequestTimeout    = errors.New("light request timeout")
+	errClientStopped     = errors.New("light client stopped")
+	errLightChainStopped = errors.New("light chain stopped")
+)
+
+// request is a request waiting for the response.
+type request struct {
+	peer string
+	resp chan interface{}
+}
+
+// client runs the light protocol with servers. It keeps the light chain in sync with the heads
+// announced by servers and sends on demand requests to them.
+type client struct {
+	chain     *LightChain
+	networkID uint64
+
+	peers   map[string]*peer
+	pending map[uint64]*request
+	reqID   uint64
+	lock    sync.Mutex
+
+	syncCh   chan *peer
+	quit     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+func newClient(chain *LightChain, networkID uint64) *client {
+	c := &client{
+		chain:     chain,
+		networkID: networkID,
+		peers:     make(map[string]*peer),
+		pending:   make(map[uint64]*request),
+		syncCh:    make(chan *peer, 1),
+		quit:      make(chan struct{}),
+	}
+	c.wg.Add(1)
+	go c.syncLoop()
+	return c
+}
+
+// Protocols returns the light sub protocol.
+func (c *client) Protocols() []p2p.Protocol {
+	return []p2p.Protocol{{
+		Name:    ProtocolName,
+		Version: ProtocolVersion,
+		Length:  ProtocolLength,
+		Run: func(p *p2p.Peer, rw p2p.MsgReadWriter) error {
+			id := p.ID()
+			return c.handle(fmt.Sprintf("%x", id[:8]), rw, func() { p.Disconnect(p2p.DiscUselessPeer) })
+		},
+	}}
+}
+
+// Stop terminates syncing and all pending requests.
+func (c *client) Stop() {
+	c.stopOnce.Do(func() {
+		close(c.quit)
+	})
+	c.wg.Wait()
+}
+
+// handle is called when a light server connects, it returns when the server disconnects.
+func (c *client) handle(id string, rw p2p.MsgReadWriter, disconnect func()) error {
+	p := newPeer(id, rw, disconnect)
+	head := c.chain.CurrentHeader()
+	if err := p.handshake(c.networkID, c.chain.Genesis().Hash(), head.Hash(), head.Number.Uint64()); err != nil {
+		log.Debug("light server handshake failed", "peer", id, "err", err)
+		return err
+	}
+
+	c.lock.Lock()
+	c.peers[id] = p
+	c.lock.Unlock()
+
+	defer func() {
+		c.lock.Lock()
+		delete(c.peers, id)
+		c.lock.Unlock()
+	}()
+
+	c.triggerSync(p)
+	for {
+		if err := c.handleMsg(p); err != nil {
+			log.Debug("light server handling failed", "peer", id, "err", err)
+			return err
+		}
+	}
+}
+
+func (c *client) handleMsg(p *peer) error {
+	msg, err := p.rw.ReadMsg()
+	if err != nil {
+		return err
+	}
+	if msg.Size > ProtocolMaxMsgSize {
+		return fmt.Errorf("message too large: %v > %v", msg.Size, ProtocolMaxMsgSize)
+	}
+	defer msg.Discard()
+
+	var (
+		reqID uint64
+		resp  interface{}
+	)
+	switch msg.Code {
+	case AnnounceMsg:
+		var announce announceData
+		if err := msg.Decode(&announce); err != nil {
+			return fmt.Errorf("invalid announcement: %v", err)
+		}
+		p.setHead(announce.Hash, announce.Number)
+		c.triggerSync(p)
+		return nil
+
+	case HeadersMsg:
+		var data headersData
+		if err := msg.Decode(&data); err != nil {
+			return fmt.Errorf("invalid headers response: %v", err)
+		}
+		reqID, resp = data.ReqID, &data
+
+	case ProofMsg, CodeMsg:
+		var data nodesData
+		if err := msg.Decode(&data); err != nil {
+			return fmt.Errorf("invalid nodes response: %v", err)
+		}
+		reqID, resp = data.ReqID, &data
+
+	case GetHeadersMsg, GetProofMsg, GetCodeMsg:
+		// light clients serve nothing
+		return nil
+
+	default:
+		return fmt.Errorf("invalid message code %v", msg.Code)
+	}
+
+	c.lock.Lock()
+	req, ok := c.pending[reqID]
+	c.lock.Unlock()
+
+	// ignore responses nobody waits for or from a peer not asked
+	if ok && req.peer == p.id {
+		select {
+		case req.resp <- resp:
+		default:
+		}
+	}
+	return nil
+}
+
+// request sends a request built with a fresh request id to the peer and waits for the response.
+func (c *client) request(ctx context.Context, p *peer, code uint64, build func(reqID uint64) interface{}) (interface{}, error) {
+	req := &request{peer: p.id, resp: make(chan interface{}, 1)}
+
+	c.lock.Lock()
+	c.reqID++
+	id := c.reqID
+	c.pending[id] = req
+	c.lock.Unlock()
+
+	defer func() {
+		c.lock.Lock()
+		delete(c.pending, id)
+		c.lock.Unlock()
+	}()
+
+	if err := p2p.Send(p.rw, code, build(id)); err != nil {
+		return nil, err
+	}
+
+	timeout := time.NewTimer(requestTimeout)
+	defer timeout.Stop()
+
+	select {
+	case resp := <-req.resp:
+		return resp, nil
+	case <-timeout.C:
+		return nil, errRequestTimeout
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-c.quit:
+		return nil, errClientStopped
+	}
+}
+
+// retrieve calls fn with connected servers one by one until it succeeds.
+func (c *client) retrieve(ctx context.Context, fn func(p *peer) error) error {
+	c.lock.Lock()
+	peers := make([]*peer, 0, len(c.peers))
+	for _, p := range c.peers {
+		peers = append(peers, p)
+	}
+	c.lock.Unlock()
+
+	for _, p := range peers {
+		err := fn(p)
+		if err == nil {
+			return nil
+		}
+		if err == errClientStopped || err == ctx.Err() {
+			return err
+		}
+		log.Debug("light request to server failed", "peer", p.id, "err", err)
+	}
+	return errNoServers
+}
+
+// triggerSync asks the sync loop to sync with the peer if it is not busy.
+func (c *client) triggerSync(p *peer) {
+	select {
+	case c.syncCh <- p:
+	default:
+	}
+}
+
+func (c *client) syncLoop() {
+	defer c.wg.Done()
+
+	for {
+		select {
+		case p := <-c.syncCh:
+			if err := c.synchronise(p); err != nil {
+				log.Debug("light chain sync failed", "peer", p.id, "err", err)
+			}
+		case <-c.quit:
+			return
+		}
+	}
+}
+
+// synchronise downloads and inserts headers from the peer until the light chain reaches the head of the peer.
+func (c *client) synchronise(p *peer) error {
+	for {
+		_, number := p.Head()
+		origin := c.chain.CurrentHeader().Number.Uint64() + 1
+		if number < origin {
+			return nil
+		}
+
+		resp, err := c.request(context.Background(), p, GetHeadersMsg, func(reqID uint64) interface{} {
+			return &getHeadersData{ReqID: reqID, Origin: origin, Amount: maxHeadersServe}
+		})
+		if err != nil {
+			return err
+		}
+		headers := resp.(*headersData).Headers
+		if len(headers) == 0 || headers[0].Number.Uint64() != origin {
+			return fmt.Errorf("unexpected headers from #%d", origin)
+		}
+		if i, err := c.chain.InsertHeaderChain(headers); err != nil {
+			if err == errLightChainStopped {
+				return err
+			}
+			// the server sent headers failing verification, drop it
+			log.Warn("invalid header from light server", "peer", p.id, "number", headers[i].Number, "hash", headers[i].Hash(), "err", err)
+			p.disconnect()
+			return err
+		}
+	}
+}
@@ -0,0 +1,227 @@
+// Copyright 2018 The gcchain authors
+
+package les
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/gcchains/chain/configs"
+	"github.com/gcchains/chain/consensus/dpos"
+	"github.com/gcchains/chain/core"
+	"github.com/gcchains/chain/core/vm"
+	"github.com/gcchains/chain/database"
+	"github.com/gcchains/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p"
+)
+
+var (
+	testKey, _   = crypto.GenerateKey()
+	testBank     = crypto.PubkeyToAddress(testKey.PublicKey)
+	testContract = common.HexToAddress("0x0100")
+	testCode     = []byte{0x60, 0x00, 0x60, 0x00, 0xf3}
+	testSlot     = common.HexToHash("0x01")
+	testValue    = common.HexToHash("0xc0ffee")
+)
+
+const testNetworkID = 7
+
+func newTestGenesis() *core.Genesis {
+	config := *configs.TestChainConfig
+	config.Dpos = configs.ChainConfigInfo().Dpos
+	return &core.Genesis{
+		Config: &config,
+		Alloc: core.GenesisAlloc{
+			testBank:     {Balance: big.NewInt(1000000000)},
+			testContract: {Balance: big.NewInt(1), Code: testCode, Storage: map[common.Hash]common.Hash{testSlot: testValue}},
+		},
+	}
+}
+
+// newTestServer creates a light server of a chain with blocks transferring to distinct accounts.
+func newTestServer(t *testing.T, gspec *core.Genesis, blocks int) (*Server, *core.BlockChain) {
+	db := database.NewMemDatabase()
+	genesis := gspec.MustCommit(db)
+	engine := dpos.NewFaker(gspec.Config.Dpos, db)
+	signer := types.NewCep1Signer(gspec.Config.ChainID)
+
+	chain, _ := core.GenerateChain(gspec.Config, genesis, engine, db, nil, blocks, func(i int, gen *core.BlockGen) {
+		to := common.BigToAddress(big.NewInt(int64(i + 1)))
+		tx, _ := types.SignTx(types.NewTransaction(gen.TxNonce(testBank), to, big.NewInt(1000), configs.TxGas, new(big.Int), nil), signer, testKey)
+		gen.AddTx(tx)
+	})
+	blockchain, err := core.NewBlockChain(db, nil, gspec.Config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	if _, err := blockchain.InsertChain(chain); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	return NewServer(blockchain, testNetworkID), blockchain
+}
+
+// newTestClient creates a light client whose chain is verified by engine.
+func newTestClient(t *testing.T, gspec *core.Genesis, newEngine func(db database.Database) LightEngine) *client {
+	db := database.NewMemDatabase()
+	gspec.MustCommit(db)
+	chain, err := NewLightChain(db, gspec.Config, newEngine(db))
+	if err != nil {
+		t.Fatalf("failed to create light chain: %v", err)
+	}
+	return newClient(chain, testNetworkID)
+}
+
+func fakeEngine(config *configs.DposConfig) func(db database.Database) LightEngine {
+	return func(db database.Database) LightEngine { return dpos.NewFaker(config, db) }
+}
+
+// connect runs the light protocol between a server and a client over an in-memory pipe
+func connect(s *Server, c *client) func() {
+	rwS, rwC := p2p.MsgPipe()
+	go s.handle("client", rwS, func() { rwS.Close() })
+	go c.handle("server", rwC, func() { rwC.Close() })
+	return func() {
+		rwS.Close()
+		rwC.Close()
+	}
+}
+
+func waitHead(t *testing.T, c *client, number uint64) {
+	for i := 0; i < 500; i++ {
+		if c.chain.CurrentHeader().Number.Uint64() == number {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("light chain head = %v, want %v", c.chain.CurrentHeader().Number, number)
+}
+
+// Tests that a light client syncs all headers of the server in batches and retrieves proved state.
+func TestLightSync(t *testing.T) {
+	gspec := newTestGenesis()
+	server, blockchain := newTestServer(t, gspec, maxHeadersServe+10)
+	c := newTestClient(t, gspec, fakeEngine(gspec.Config.Dpos))
+	defer c.Stop()
+
+	disconnect := connect(server, c)
+	defer disconnect()
+
+	head := blockchain.CurrentBlock()
+	waitHead(t, c, head.NumberU64())
+	if got := c.chain.CurrentHeader().Hash(); got != head.Hash() {
+		t.Fatalf("light chain head = %x, want %x", got, head.Hash())
+	}
+
+	ctx := context.Background()
+	statedb, _ := blockchain.State()
+	for _, addr := range []common.Address{testBank, common.BigToAddress(big.NewInt(5)), common.HexToAddress("0xdead")} {
+		account, err := c.GetAccount(ctx, c.chain.CurrentHeader(), addr)
+		if err != nil {
+			t.Fatalf("GetAccount(%x) error = %v", addr, err)
+		}
+		if got, want := account.Balance, statedb.GetBalance(addr); got.Cmp(want) != 0 {
+			t.Errorf("balance of %x = %v, want %v", addr, got, want)
+		}
+		if got, want := account.Nonce, statedb.GetNonce(addr); got != want {
+			t.Errorf("nonce of %x = %v, want %v", addr, got, want)
+		}
+	}
+
+	// the state of an old block is retrieved as well
+	old := c.chain.GetHeaderByNumber(3)
+	account, err := c.GetAccount(ctx, old, testBank)
+	if err != nil {
+		t.Fatalf("GetAccount() of old block error = %v", err)
+	}
+	if account.Nonce != 3 {
+		t.Errorf("nonce at block 3 = %v, want 3", account.Nonce)
+	}
+
+	code, err := c.GetCode(ctx, c.chain.CurrentHeader(), testContract)
+	if err != nil {
+		t.Fatalf("GetCode() error = %v", err)
+	}
+	if !bytes.Equal(code, testCode) {
+		t.Errorf("GetCode() = %x, want %x", code, testCode)
+	}
+
+	for _, tt := range []struct {
+		addr common.Address
+		slot common.Hash
+		want common.Hash
+	}{
+		{testContract, testSlot, testValue},
+		{testContract, common.HexToHash("0x02"), common.Hash{}},
+		{testBank, testSlot, common.Hash{}},
+	} {
+		value, err := c.GetStorage(ctx, c.chain.CurrentHeader(), tt.addr, tt.slot)
+		if err != nil {
+			t.Fatalf("GetStorage(%x, %x) error = %v", tt.addr, tt.slot, err)
+		}
+		if value != tt.want {
+			t.Errorf("GetStorage(%x, %x) = %x, want %x", tt.addr, tt.slot, value, tt.want)
+		}
+	}
+}
+
+// Tests that a light client stops syncing at a header failing verification and drops the server.
+func TestLightSyncInvalidHeader(t *testing.T) {
+	gspec := newTestGenesis()
+	server, _ := newTestServer(t, gspec, 20)
+	c := newTestClient(t, gspec, func(db database.Database) LightEngine {
+		return dpos.NewFakeFailer(gspec.Config.Dpos, db, 11)
+	})
+	defer c.Stop()
+
+	disconnect := connect(server, c)
+	defer disconnect()
+
+	time.Sleep(200 * time.Millisecond)
+	if got := c.chain.CurrentHeader().Number.Uint64(); got != 0 {
+		t.Errorf("light chain head = %v, want 0 as the batch contains an invalid header", got)
+	}
+	c.lock.Lock()
+	peers := len(c.peers)
+	c.lock.Unlock()
+	server.lock.RLock()
+	clients := len(server.peers)
+	server.lock.RUnlock()
+	if peers != 0 || clients != 0 {
+		t.Errorf("connected servers = %d, clients = %d, want the server dropped", peers, clients)
+	}
+}
+
+// Tests that tampered state proofs are rejected.
+func TestVerifyProof(t *testing.T) {
+	gspec := newTestGenesis()
+	server, blockchain := newTestServer(t, gspec, 5)
+	head := blockchain.CurrentBlock()
+	key := crypto.Keccak256Hash(testBank[:])
+
+	nodes, err := server.prove(&getProofData{BlockHash: head.Hash(), Account: key})
+	if err != nil {
+		t.Fatalf("prove() error = %v", err)
+	}
+	if _, err := verifyProof(head.StateRoot(), key[:], nodes); err != nil {
+		t.Fatalf("verifyProof() error = %v", err)
+	}
+
+	tampered := make([][]byte, len(nodes))
+	for i := range nodes {
+		tampered[i] = common.CopyBytes(nodes[i])
+	}
+	last := tampered[len(tampered)-1]
+	last[len(last)-1]++
+	if value, err := verifyProof(head.StateRoot(), key[:], tampered); err == nil {
+		t.Errorf("verifyProof() of tampered proof = %x, want error", value)
+	}
+
+	if _, err := server.prove(&getProofData{BlockHash: common.Hash{1}, Account: key}); err == nil {
+		t.Errorf("prove() of unknown block succeeded")
+	}
+}
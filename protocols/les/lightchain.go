@@ -0,0 +1,120 @@
+// Copyright 2018 The gcchain authors
+
+package les
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gcchains/chain/configs"
+	"github.com/gcchains/chain/consensus"
+	"github.com/gcchains/chain/core"
+	"github.com/gcchains/chain/core/rawdb"
+	"github.com/gcchains/chain/database"
+	"github.com/gcchains/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// LightEngine is a consensus engine able to verify headers without executing blocks.
+type LightEngine interface {
+	consensus.Engine
+
+	// VerifyLightHeaders verifies a batch of headers, the results are sent in the order of the headers.
+	VerifyLightHeaders(chain consensus.ChainReader, headers []*types.Header) (chan<- struct{}, <-chan error)
+}
+
+// LightChain is a chain of headers verified by the seals and signatures of the dpos committee,
+// it is all a light client keeps locally.
+type LightChain struct {
+	hc     *core.HeaderChain
+	engine LightEngine
+
+	mu      sync.Mutex // protects header insertion
+	running int32      // 0 if the chain is stopped
+}
+
+// NewLightChain creates a light chain on db, which must contain the genesis block.
+func NewLightChain(db database.Database, config *configs.ChainConfig, engine LightEngine) (*LightChain, error) {
+	lc := &LightChain{engine: engine, running: 1}
+
+	hc, err := core.NewHeaderChain(db, config, engine, func() bool { return atomic.LoadInt32(&lc.running) == 0 })
+	if err != nil {
+		return nil, err
+	}
+	// a header chain starts from the head block, which a light chain never has
+	if head := rawdb.ReadHeadHeaderHash(db); head != (common.Hash{}) {
+		if header := hc.GetHeaderByHash(head); header != nil {
+			hc.SetCurrentHeader(header)
+			hc.SetKnownHead(head, header.Number.Uint64())
+		}
+	}
+	lc.hc = hc
+	return lc, nil
+}
+
+// InsertHeaderChain verifies and inserts headers, which must be continuous. It returns the index of
+// the failing header if an error is returned.
+func (lc *LightChain) InsertHeaderChain(headers []*types.Header) (int, error) {
+	if len(headers) == 0 {
+		return 0, nil
+	}
+	for i := 1; i < len(headers); i++ {
+		if headers[i].Number.Uint64() != headers[i-1].Number.Uint64()+1 || headers[i].ParentHash != headers[i-1].Hash() {
+			return i, fmt.Errorf("non contiguous insert: item %d is #%d, item %d is #%d", i-1, headers[i-1].Number, i, headers[i].Number)
+		}
+	}
+
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	abort, results := lc.engine.VerifyLightHeaders(lc.hc, headers)
+	defer close(abort)
+
+	for i := range headers {
+		if atomic.LoadInt32(&lc.running) == 0 {
+			return i, errLightChainStopped
+		}
+		if err := <-results; err != nil {
+			return i, err
+		}
+	}
+
+	last := headers[len(headers)-1]
+	lc.hc.SetKnownHead(last.Hash(), last.Number.Uint64())
+	return lc.hc.InsertHeaderChain(headers, func(header *types.Header) error {
+		_, err := lc.hc.WriteHeader(header)
+		return err
+	}, time.Now())
+}
+
+// CurrentHeader returns the head of the light chain.
+func (lc *LightChain) CurrentHeader() *types.Header {
+	return lc.hc.CurrentHeader()
+}
+
+// GetHeaderByNumber returns the canonical header with given number.
+func (lc *LightChain) GetHeaderByNumber(number uint64) *types.Header {
+	return lc.hc.GetHeaderByNumber(number)
+}
+
+// GetHeaderByHash returns the header with given hash.
+func (lc *LightChain) GetHeaderByHash(hash common.Hash) *types.Header {
+	return lc.hc.GetHeaderByHash(hash)
+}
+
+// Genesis returns the genesis header.
+func (lc *LightChain) Genesis() *types.Header {
+	return lc.hc.GetHeaderByNumber(0)
+}
+
+// Config returns the chain configuration.
+func (lc *LightChain) Config() *configs.ChainConfig {
+	return lc.hc.Config()
+}
+
+// Stop interrupts header insertion.
+func (lc *LightChain) Stop() {
+	atomic.StoreInt32(&lc.running, 0)
+}
@@ -0,0 +1,131 @@
+// Copyright 2018 The gcchain authors
+
+package les
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/gcchains/chain/core/state"
+	"github.com/gcchains/chain/database"
+	"github.com/gcchains/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+var (
+	errProofUnavailable = errors.New("state proof is unavailable from server")
+	errCodeMismatch     = errors.New("code does not match code hash")
+)
+
+var emptyCodeHash = crypto.Keccak256(nil)
+
+// verifyProof verifies the merkle proof of key in the trie with given root, it returns nil if key is proved absent.
+func verifyProof(root common.Hash, key []byte, nodes [][]byte) ([]byte, error) {
+	proof := database.NewMemDatabase()
+	for _, node := range nodes {
+		proof.Put(crypto.Keccak256(node), node)
+	}
+	value, _, err := trie.VerifyProof(root, key, proof)
+	return value, err
+}
+
+// proveAccount retrieves the proof of an account from the peer, and the proof of a storage slot of it
+// if storage is not nil. It returns the account verified against the state root of header.
+func (c *client) proveAccount(ctx context.Context, p *peer, header *types.Header, addr common.Address, storage []byte) (*state.Account, [][]byte, error) {
+	accKey := crypto.Keccak256Hash(addr[:])
+	resp, err := c.request(ctx, p, GetProofMsg, func(reqID uint64) interface{} {
+		return &getProofData{ReqID: reqID, BlockHash: header.Hash(), Account: accKey, Storage: storage}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	nodes := resp.(*nodesData).Nodes
+	if len(nodes) == 0 {
+		return nil, nil, errProofUnavailable
+	}
+
+	blob, err := verifyProof(header.StateRoot, accKey[:], nodes)
+	if err != nil {
+		return nil, nil, err
+	}
+	if blob == nil {
+		return &state.Account{Balance: new(big.Int), Root: types.EmptyRootHash, CodeHash: emptyCodeHash}, nodes, nil
+	}
+	var account state.Account
+	if err := rlp.DecodeBytes(blob, &account); err != nil {
+		return nil, nil, err
+	}
+	return &account, nodes, nil
+}
+
+// GetAccount retrieves the account at addr in the state of header, proved by a server.
+func (c *client) GetAccount(ctx context.Context, header *types.Header, addr common.Address) (*state.Account, error) {
+	var account *state.Account
+	err := c.retrieve(ctx, func(p *peer) (err error) {
+		account, _, err = c.proveAccount(ctx, p, header, addr, nil)
+		return err
+	})
+	return account, err
+}
+
+// GetStorage retrieves the storage slot at key of addr in the state of header, proved by a server.
+func (c *client) GetStorage(ctx context.Context, header *types.Header, addr common.Address, key common.Hash) (common.Hash, error) {
+	var value common.Hash
+	slotKey := crypto.Keccak256(key[:])
+	err := c.retrieve(ctx, func(p *peer) error {
+		account, nodes, err := c.proveAccount(ctx, p, header, addr, slotKey)
+		if err != nil {
+			return err
+		}
+		if account.Root == types.EmptyRootHash {
+			value = common.Hash{}
+			return nil
+		}
+		enc, err := verifyProof(account.Root, slotKey, nodes)
+		if err != nil {
+			return err
+		}
+		if len(enc) > 0 {
+			_, content, _, err := rlp.Split(enc)
+			if err != nil {
+				return err
+			}
+			value.SetBytes(content)
+		}
+		return nil
+	})
+	return value, err
+}
+
+// GetCode retrieves the code of the contract at addr in the state of header, proved by a server.
+func (c *client) GetCode(ctx context.Context, header *types.Header, addr common.Address) ([]byte, error) {
+	account, err := c.GetAccount(ctx, header, addr)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(account.CodeHash, emptyCodeHash) {
+		return nil, nil
+	}
+
+	var code []byte
+	err = c.retrieve(ctx, func(p *peer) error {
+		resp, err := c.request(ctx, p, GetCodeMsg, func(reqID uint64) interface{} {
+			return &getCodeData{ReqID: reqID, Hash: common.BytesToHash(account.CodeHash)}
+		})
+		if err != nil {
+			return err
+		}
+		nodes := resp.(*nodesData).Nodes
+		if len(nodes) != 1 || !bytes.Equal(crypto.Keccak256(nodes[0]), account.CodeHash) {
+			return errCodeMismatch
+		}
+		code = nodes[0]
+		return nil
+	})
+	return code, err
+}
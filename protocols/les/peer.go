@@ -0,0 +1,107 @@
+// Copyright 2018 The gcchain authors
+
+package les
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/p2p"
+)
+
+const handshakeTimeout = 5 * time.Second
+
+// peer is a remote node running the light protocol.
+type peer struct {
+	id         string
+	rw         p2p.MsgReadWriter
+	disconnect func() // disconnects the peer, its handler returns
+
+	head   common.Hash
+	number uint64
+	lock   sync.RWMutex
+}
+
+func newPeer(id string, rw p2p.MsgReadWriter, disconnect func()) *peer {
+	return &peer{id: id, rw: rw, disconnect: disconnect}
+}
+
+// Head returns the hash and number of the latest head known of the peer.
+func (p *peer) Head() (common.Hash, uint64) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	return p.head, p.number
+}
+
+// setHead updates the head of the peer if it is higher than the known one.
+func (p *peer) setHead(hash common.Hash, number uint64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if number >= p.number {
+		p.head, p.number = hash, number
+	}
+}
+
+// handshake exchanges status with the peer and checks they are on the same chain.
+func (p *peer) handshake(networkID uint64, genesis, head common.Hash, number uint64) error {
+	errc := make(chan error, 2)
+	var status statusData // safe to read after two values have been received from errc
+
+	go func() {
+		errc <- p2p.Send(p.rw, StatusMsg, &statusData{
+			ProtocolVersion: ProtocolVersion,
+			NetworkId:       networkID,
+			Genesis:         genesis,
+			Head:            head,
+			Number:          number,
+		})
+	}()
+	go func() {
+		errc <- p.readStatus(&status)
+	}()
+
+	timeout := time.NewTimer(handshakeTimeout)
+	defer timeout.Stop()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-errc:
+			if err != nil {
+				return err
+			}
+		case <-timeout.C:
+			return p2p.DiscReadTimeout
+		}
+	}
+
+	switch {
+	case status.ProtocolVersion != ProtocolVersion:
+		return errVersionMismatch
+	case status.NetworkId != networkID:
+		return errNetworkIDMismatch
+	case status.Genesis != genesis:
+		return errGenesisMismatch
+	}
+	p.setHead(status.Head, status.Number)
+	return nil
+}
+
+func (p *peer) readStatus(status *statusData) error {
+	msg, err := p.rw.ReadMsg()
+	if err != nil {
+		return err
+	}
+	defer msg.Discard()
+
+	if msg.Code != StatusMsg {
+		return errNoStatus
+	}
+	if msg.Size > ProtocolMaxMsgSize {
+		return fmt.Errorf("message too large: %v > %v", msg.Size, ProtocolMaxMsgSize)
+	}
+	return msg.Decode(status)
+}
@@ -0,0 +1,93 @@
+// Copyright 2018 The gcchain authors
+
+// Package les implements the light client sub protocol, with which light clients follow the chain by headers
+// verified with dpos committee signatures and retrieve state on demand with merkle proofs from full nodes.
+package les
+
+import (
+	"errors"
+
+	"github.com/gcchains/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ProtocolName is the official short name of the protocol used during capability negotiation.
+const ProtocolName = "gccles"
+
+// ProtocolVersion is the version of the light protocol.
+const ProtocolVersion = 1
+
+// ProtocolLength is the number of implemented messages.
+const ProtocolLength = 8
+
+const ProtocolMaxMsgSize = 10 * 1024 * 1024 // Maximum cap on the size of a protocol message
+
+// light protocol message codes
+const (
+	StatusMsg     = 0x00
+	AnnounceMsg   = 0x01
+	GetHeadersMsg = 0x02
+	HeadersMsg    = 0x03
+	GetProofMsg   = 0x04
+	ProofMsg      = 0x05
+	GetCodeMsg    = 0x06
+	CodeMsg       = 0x07
+)
+
+const maxHeadersServe = 192 // Amount of block headers to be served in one response
+
+var (
+	errNetworkIDMismatch = errors.New("network id mismatch")
+	errGenesisMismatch   = errors.New("genesis block mismatch")
+	errVersionMismatch   = errors.New("protocol version mismatch")
+	errNoStatus          = errors.New("no status message from peer")
+)
+
+// statusData is the handshake message announcing the chain of a peer.
+type statusData struct {
+	ProtocolVersion uint32
+	NetworkId       uint64
+	Genesis         common.Hash
+	Head            common.Hash
+	Number          uint64
+}
+
+// announceData is a new chain head announced by a server.
+type announceData struct {
+	Hash   common.Hash
+	Number uint64
+}
+
+// getHeadersData is the request of continuous canonical headers from origin.
+type getHeadersData struct {
+	ReqID  uint64
+	Origin uint64
+	Amount uint64
+}
+
+// headersData is the response to a headers request.
+type headersData struct {
+	ReqID   uint64
+	Headers []*types.Header
+}
+
+// getProofData is the request of a merkle proof of an account in the state of a block,
+// with a proof of a storage slot in the account if Storage is not empty.
+type getProofData struct {
+	ReqID     uint64
+	BlockHash common.Hash
+	Account   common.Hash // hashed address of the account
+	Storage   []byte      // hashed key of the storage slot
+}
+
+// getCodeData is the request of a contract code by its hash.
+type getCodeData struct {
+	ReqID uint64
+	Hash  common.Hash
+}
+
+// nodesData is the response to a proof or code request, it is empty if the server does not have them.
+type nodesData struct {
+	ReqID uint64
+	Nodes [][]byte
+}
@@ -0,0 +1,252 @@
+// Copyright 2018 The gcchain authors
+
+package les
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/gcchains/chain/commons/log"
+	"github.com/gcchains/chain/core"
+	"github.com/gcchains/chain/core/state"
+	"github.com/gcchains/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// Server serves headers, state proofs and codes of a full node to light clients.
+// It implements gcc.LesServer.
+type Server struct {
+	chain     *core.BlockChain
+	networkID uint64
+
+	peers map[string]*peer
+	lock  sync.RWMutex
+
+	headCh  chan core.ChainHeadEvent
+	headSub event.Subscription
+	wg      sync.WaitGroup
+}
+
+// NewServer creates a light server serving the chain.
+func NewServer(chain *core.BlockChain, networkID uint64) *Server {
+	return &Server{
+		chain:     chain,
+		networkID: networkID,
+		peers:     make(map[string]*peer),
+	}
+}
+
+// Protocols returns the light sub protocol.
+func (s *Server) Protocols() []p2p.Protocol {
+	return []p2p.Protocol{{
+		Name:    ProtocolName,
+		Version: ProtocolVersion,
+		Length:  ProtocolLength,
+		Run: func(p *p2p.Peer, rw p2p.MsgReadWriter) error {
+			id := p.ID()
+			return s.handle(fmt.Sprintf("%x", id[:8]), rw, func() { p.Disconnect(p2p.DiscUselessPeer) })
+		},
+	}}
+}
+
+// Start starts announcing new chain heads to light clients.
+func (s *Server) Start(srvr *p2p.Server) {
+	s.headCh = make(chan core.ChainHeadEvent, 10)
+	s.headSub = s.chain.SubscribeChainHeadEvent(s.headCh)
+
+	s.wg.Add(1)
+	go s.announceLoop()
+}
+
+// Stop stops announcing chain heads.
+func (s *Server) Stop() {
+	if s.headSub != nil {
+		s.headSub.Unsubscribe()
+	}
+	s.wg.Wait()
+}
+
+// SetBloomBitsIndexer does nothing, as log filtering is not served to light clients yet.
+func (s *Server) SetBloomBitsIndexer(bbIndexer *core.ChainIndexer) {}
+
+func (s *Server) announceLoop() {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case ev := <-s.headCh:
+			announce := &announceData{Hash: ev.Block.Hash(), Number: ev.Block.NumberU64()}
+			// send outside the lock, a slow peer must not block clients connecting and leaving
+			s.lock.RLock()
+			peers := make([]*peer, 0, len(s.peers))
+			for _, p := range s.peers {
+				peers = append(peers, p)
+			}
+			s.lock.RUnlock()
+
+			for _, p := range peers {
+				if err := p2p.Send(p.rw, AnnounceMsg, announce); err != nil {
+					log.Debug("failed to announce head to light peer", "peer", p.id, "err", err)
+				}
+			}
+
+		case <-s.headSub.Err():
+			return
+		}
+	}
+}
+
+// handle is called when a light client connects, it returns when the client disconnects.
+func (s *Server) handle(id string, rw p2p.MsgReadWriter, disconnect func()) error {
+	p := newPeer(id, rw, disconnect)
+	head := s.chain.CurrentBlock()
+	if err := p.handshake(s.networkID, s.chain.Genesis().Hash(), head.Hash(), head.NumberU64()); err != nil {
+		log.Debug("light client handshake failed", "peer", id, "err", err)
+		return err
+	}
+
+	s.lock.Lock()
+	s.peers[id] = p
+	s.lock.Unlock()
+
+	defer func() {
+		s.lock.Lock()
+		delete(s.peers, id)
+		s.lock.Unlock()
+	}()
+
+	for {
+		if err := s.handleMsg(p); err != nil {
+			log.Debug("light client handling failed", "peer", id, "err", err)
+			return err
+		}
+	}
+}
+
+func (s *Server) handleMsg(p *peer) error {
+	msg, err := p.rw.ReadMsg()
+	if err != nil {
+		return err
+	}
+	if msg.Size > ProtocolMaxMsgSize {
+		return fmt.Errorf("message too large: %v > %v", msg.Size, ProtocolMaxMsgSize)
+	}
+	defer msg.Discard()
+
+	switch msg.Code {
+	case GetHeadersMsg:
+		var req getHeadersData
+		if err := msg.Decode(&req); err != nil {
+			return fmt.Errorf("invalid headers request: %v", err)
+		}
+		if req.Amount > maxHeadersServe {
+			req.Amount = maxHeadersServe
+		}
+		var headers []*types.Header
+		for number := req.Origin; number < req.Origin+req.Amount; number++ {
+			header := s.chain.GetHeaderByNumber(number)
+			if header == nil {
+				break
+			}
+			headers = append(headers, header)
+		}
+		return p2p.Send(p.rw, HeadersMsg, &headersData{ReqID: req.ReqID, Headers: headers})
+
+	case GetProofMsg:
+		var req getProofData
+		if err := msg.Decode(&req); err != nil {
+			return fmt.Errorf("invalid proof request: %v", err)
+		}
+		nodes, err := s.prove(&req)
+		if err != nil {
+			log.Debug("failed to prove state for light client", "peer", p.id, "block", req.BlockHash, "err", err)
+		}
+		return p2p.Send(p.rw, ProofMsg, &nodesData{ReqID: req.ReqID, Nodes: nodes})
+
+	case GetCodeMsg:
+		var req getCodeData
+		if err := msg.Decode(&req); err != nil {
+			return fmt.Errorf("invalid code request: %v", err)
+		}
+		var nodes [][]byte
+		if code, err := s.chain.StateCache().TrieDB().Node(req.Hash); err == nil {
+			nodes = append(nodes, code)
+		}
+		return p2p.Send(p.rw, CodeMsg, &nodesData{ReqID: req.ReqID, Nodes: nodes})
+
+	case AnnounceMsg, HeadersMsg, ProofMsg, CodeMsg:
+		// light clients do not announce or respond anything
+		return nil
+
+	default:
+		return fmt.Errorf("invalid message code %v", msg.Code)
+	}
+}
+
+// prove proves an account and optionally a storage slot of it in the public state of a block.
+// Requests name blocks instead of state roots, so that private states are never served.
+func (s *Server) prove(req *getProofData) ([][]byte, error) {
+	header := s.chain.GetHeaderByHash(req.BlockHash)
+	if header == nil {
+		return nil, fmt.Errorf("unknown block %x", req.BlockHash)
+	}
+
+	triedb := s.chain.StateCache().TrieDB()
+	accTrie, err := trie.New(header.StateRoot, triedb)
+	if err != nil {
+		return nil, err
+	}
+	proof := newNodeSet()
+	if err := accTrie.Prove(req.Account[:], 0, proof); err != nil {
+		return nil, err
+	}
+	if len(req.Storage) == 0 {
+		return proof.nodes, nil
+	}
+
+	blob, err := accTrie.TryGet(req.Account[:])
+	if err != nil || blob == nil {
+		// absence of the account is proved already
+		return proof.nodes, err
+	}
+	var account state.Account
+	if err := rlp.DecodeBytes(blob, &account); err != nil {
+		return nil, err
+	}
+	if account.Root == types.EmptyRootHash {
+		return proof.nodes, nil
+	}
+	storageTrie, err := trie.New(account.Root, triedb)
+	if err != nil {
+		return nil, err
+	}
+	if err := storageTrie.Prove(req.Storage, 0, proof); err != nil {
+		return nil, err
+	}
+	return proof.nodes, nil
+}
+
+// nodeSet collects distinct trie nodes of merkle proofs.
+type nodeSet struct {
+	keys  map[common.Hash]struct{}
+	nodes [][]byte
+}
+
+func newNodeSet() *nodeSet {
+	return &nodeSet{keys: make(map[common.Hash]struct{})}
+}
+
+// Put implements database.Putter.
+func (n *nodeSet) Put(key []byte, value []byte) error {
+	hash := common.BytesToHash(key)
+	if _, ok := n.keys[hash]; ok {
+		return nil
+	}
+	n.keys[hash] = struct{}{}
+	n.nodes = append(n.nodes, common.CopyBytes(value))
+	return nil
+}
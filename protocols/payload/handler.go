@@ -0,0 +1,211 @@
+// Copyright 2018 The gcchain authors
+
+package payload
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gcchains/chain/commons/log"
+	"github.com/gcchains/chain/database"
+	"github.com/ethereum/go-ethereum/p2p"
+)
+
+// fetchTimeout is the time waiting for a peer to respond a payload request before asking the next one.
+const fetchTimeout = 3 * time.Second
+
+var (
+	ErrPayloadNotFound = errors.New("payload not found in peers")
+	errManagerStopped  = errors.New("payload manager stopped")
+)
+
+// Store is the local storage payloads are served from.
+type Store interface {
+	// GetLocal retrieves a payload from local storage only.
+	GetLocal(key []byte) ([]byte, error)
+}
+
+// peer is a remote node running the payload protocol.
+type peer struct {
+	id string
+	rw p2p.MsgReadWriter
+}
+
+// request is a payload request waiting for the response.
+type request struct {
+	peer string
+	resp chan *payloadData
+}
+
+// Manager serves payloads in local store to peers and fetches missing payloads from peers.
+// It implements database.Fetcher.
+type Manager struct {
+	store Store
+
+	peers   map[string]*peer
+	pending map[uint64]*request
+	reqID   uint64
+	lock    sync.Mutex
+
+	quit     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewManager creates a new payload manager serving payloads from store.
+func NewManager(store Store) *Manager {
+	return &Manager{
+		store:   store,
+		peers:   make(map[string]*peer),
+		pending: make(map[uint64]*request),
+		quit:    make(chan struct{}),
+	}
+}
+
+// Protocols returns the payload sub protocol.
+func (m *Manager) Protocols() []p2p.Protocol {
+	return []p2p.Protocol{{
+		Name:    ProtocolName,
+		Version: ProtocolVersion,
+		Length:  ProtocolLength,
+		Run: func(p *p2p.Peer, rw p2p.MsgReadWriter) error {
+			id := p.ID()
+			return m.handle(fmt.Sprintf("%x", id[:8]), rw)
+		},
+	}}
+}
+
+// Stop terminates all pending fetches.
+func (m *Manager) Stop() {
+	m.stopOnce.Do(func() {
+		close(m.quit)
+	})
+}
+
+// handle is called when a peer running the payload protocol connects, it returns when the peer disconnects.
+func (m *Manager) handle(id string, rw p2p.MsgReadWriter) error {
+	p := &peer{id: id, rw: rw}
+
+	m.lock.Lock()
+	m.peers[id] = p
+	m.lock.Unlock()
+
+	defer func() {
+		m.lock.Lock()
+		delete(m.peers, id)
+		m.lock.Unlock()
+	}()
+
+	for {
+		if err := m.handleMsg(p); err != nil {
+			log.Debug("payload peer handling failed", "peer", id, "err", err)
+			return err
+		}
+	}
+}
+
+func (m *Manager) handleMsg(p *peer) error {
+	msg, err := p.rw.ReadMsg()
+	if err != nil {
+		return err
+	}
+	if msg.Size > ProtocolMaxMsgSize {
+		return fmt.Errorf("message too large: %v > %v", msg.Size, ProtocolMaxMsgSize)
+	}
+	defer msg.Discard()
+
+	switch msg.Code {
+	case GetPayloadMsg:
+		var req getPayloadData
+		if err := msg.Decode(&req); err != nil {
+			return fmt.Errorf("invalid payload request: %v", err)
+		}
+		data, err := m.store.GetLocal(req.Key)
+		return p2p.Send(p.rw, PayloadMsg, &payloadData{ReqID: req.ReqID, Found: err == nil, Data: data})
+
+	case PayloadMsg:
+		var resp payloadData
+		if err := msg.Decode(&resp); err != nil {
+			return fmt.Errorf("invalid payload response: %v", err)
+		}
+		m.lock.Lock()
+		req, ok := m.pending[resp.ReqID]
+		m.lock.Unlock()
+
+		// ignore responses nobody waits for or from a peer not asked
+		if ok && req.peer == p.id {
+			select {
+			case req.resp <- &resp:
+			default:
+			}
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("invalid message code %v", msg.Code)
+	}
+}
+
+// Fetch asks connected peers one by one for the payload with given content key.
+func (m *Manager) Fetch(key []byte) ([]byte, error) {
+	m.lock.Lock()
+	peers := make([]*peer, 0, len(m.peers))
+	for _, p := range m.peers {
+		peers = append(peers, p)
+	}
+	m.lock.Unlock()
+
+	for _, p := range peers {
+		data, err := m.fetchFrom(p, key)
+		if err == errManagerStopped {
+			return nil, err
+		}
+		if err != nil {
+			log.Debug("failed to fetch payload from peer", "peer", p.id, "err", err)
+			continue
+		}
+		return data, nil
+	}
+	return nil, ErrPayloadNotFound
+}
+
+// fetchFrom requests the payload from a peer and waits for a valid response.
+func (m *Manager) fetchFrom(p *peer, key []byte) ([]byte, error) {
+	req := &request{peer: p.id, resp: make(chan *payloadData, 1)}
+
+	m.lock.Lock()
+	m.reqID++
+	id := m.reqID
+	m.pending[id] = req
+	m.lock.Unlock()
+
+	defer func() {
+		m.lock.Lock()
+		delete(m.pending, id)
+		m.lock.Unlock()
+	}()
+
+	if err := p2p.Send(p.rw, GetPayloadMsg, &getPayloadData{ReqID: id, Key: key}); err != nil {
+		return nil, err
+	}
+
+	timeout := time.NewTimer(fetchTimeout)
+	defer timeout.Stop()
+
+	select {
+	case resp := <-req.resp:
+		if !resp.Found {
+			return nil, ErrPayloadNotFound
+		}
+		if !bytes.Equal(database.ContentKey(resp.Data), key) {
+			return nil, database.ErrContentMismatch
+		}
+		return resp.Data, nil
+	case <-timeout.C:
+		return nil, errors.New("payload request timeout")
+	case <-m.quit:
+		return nil, errManagerStopped
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2018 The gcchain authors
+
+package payload
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/gcchains/chain/database"
+	"github.com/ethereum/go-ethereum/p2p"
+)
+
+// connect runs the payload protocol between two managers over an in-memory pipe
+func connect(a, b *Manager) func() {
+	rwA, rwB := p2p.MsgPipe()
+	go a.handle("b", rwA)
+	go b.handle("a", rwB)
+	return func() {
+		rwA.Close()
+		rwB.Close()
+	}
+}
+
+func waitPeers(t *testing.T, m *Manager, n int) {
+	for i := 0; i < 1000; i++ {
+		m.lock.Lock()
+		connected := len(m.peers)
+		m.lock.Unlock()
+		if connected == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("peers are not connected")
+}
+
+func TestFetchPayload(t *testing.T) {
+	var (
+		content    = []byte("sealed private payload")
+		localA     = database.NewLocalDatabase(database.NewMemDatabase())
+		localB     = database.NewLocalDatabase(database.NewMemDatabase())
+		managerA   = NewManager(localA)
+		managerB   = NewManager(localB)
+		key, _     = localA.Put(content)
+		disconnect = connect(managerA, managerB)
+	)
+	defer disconnect()
+	waitPeers(t, managerB, 1)
+
+	localB.SetFetcher(managerB)
+	got, err := localB.Get(key)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Errorf("Get() = %s, want %s", got, content)
+	}
+	if !localB.Has(key) {
+		t.Errorf("fetched payload is not kept locally")
+	}
+
+	if _, err := managerB.Fetch(database.ContentKey([]byte("missing"))); err != ErrPayloadNotFound {
+		t.Errorf("Fetch() on missing payload error = %v, want %v", err, ErrPayloadNotFound)
+	}
+}
+
+func TestFetchWithoutPeers(t *testing.T) {
+	m := NewManager(database.NewLocalDatabase(database.NewMemDatabase()))
+	if _, err := m.Fetch(database.ContentKey([]byte("payload"))); err != ErrPayloadNotFound {
+		t.Errorf("Fetch() error = %v, want %v", err, ErrPayloadNotFound)
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright 2018 The gcchain authors
+
+// Package payload implements the payload sub protocol, with which participants of private transactions
+// retrieve sealed payloads from each other instead of a distributed storage like IPFS.
+package payload
+
+// ProtocolName is the official short name of the protocol used during capability negotiation.
+const ProtocolName = "gccpayload"
+
+// ProtocolVersion is the version of the payload protocol.
+const ProtocolVersion = 1
+
+// ProtocolLength is the number of implemented messages.
+const ProtocolLength = 2
+
+const ProtocolMaxMsgSize = 10 * 1024 * 1024 // Maximum cap on the size of a protocol message
+
+// payload protocol message codes
+const (
+	GetPayloadMsg = 0x00
+	PayloadMsg    = 0x01
+)
+
+// getPayloadData is the request of a payload by its content key.
+type getPayloadData struct {
+	ReqID uint64
+	Key   []byte
+}
+
+// payloadData is the response to a payload request, Found is false if the peer does not have the payload.
+type payloadData struct {
+	ReqID uint64
+	Found bool
+	Data  []byte
+}
@@ -0,0 +1,73 @@
+// Copyright 2018 The gcchain authors
+
+package signer
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// AuditEntry is a signing request handled by the signer, approved or not.
+type AuditEntry struct {
+	Time    time.Time      `json:"time"`
+	Method  string         `json:"method"`
+	Account common.Address `json:"account"`
+
+	Number *uint64         `json:"number,omitempty"` // number of the header signed
+	State  string          `json:"state,omitempty"`  // consensus state the header is signed in
+	Hash   *common.Hash    `json:"hash,omitempty"`   // hash of the header or transaction signed
+	To     *common.Address `json:"to,omitempty"`     // recipient of the transaction signed
+	Mac    string          `json:"mac,omitempty"`    // handshake mac signed
+
+	Signed bool   `json:"signed"`
+	Error  string `json:"error,omitempty"` // reason of a refusal
+}
+
+// AuditLog records the requests handled by the signer, one json object per line.
+type AuditLog struct {
+	lock   sync.Mutex
+	enc    *json.Encoder
+	closer io.Closer
+}
+
+// NewAuditLog returns an audit log writing the entries to w.
+func NewAuditLog(w io.Writer) *AuditLog {
+	return &AuditLog{
+		enc: json.NewEncoder(w),
+	}
+}
+
+// OpenAuditLog returns an audit log appending the entries to the file.
+func OpenAuditLog(path string) (*AuditLog, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	l := NewAuditLog(f)
+	l.closer = f
+	return l, nil
+}
+
+// Log writes an entry.
+func (l *AuditLog) Log(entry *AuditEntry) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.enc.Encode(entry)
+}
+
+// Close closes the file of the audit log, if it is opened by OpenAuditLog.
+func (l *AuditLog) Close() error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.closer == nil {
+		return nil
+	}
+	return l.closer.Close()
+}
@@ -0,0 +1,139 @@
+// Copyright 2018 The gcchain authors
+
+package signer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gcchains/chain/consensus"
+	"github.com/gcchains/chain/database"
+	"github.com/gcchains/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultMacTimeGap is the gap allowed between the time in a handshake mac and the local time.
+const DefaultMacTimeGap = time.Minute
+
+var (
+	errInvalidState       = errors.New("invalid consensus state to sign a header in")
+	errDoubleSign         = errors.New("another header is signed at the height in the state")
+	errContractCreation   = errors.New("contract creation is not allowed")
+	errContractNotAllowed = errors.New("contract is not in the whitelist")
+	errInvalidMac         = errors.New("invalid handshake mac")
+	errHashRefused        = errors.New("signing a bare hash is refused, its content cannot be checked")
+)
+
+// signedHeaderPrefix prefixes the keys of the headers signed, followed by the account, the
+// height and the state, to the hash signed for the header
+var signedHeaderPrefix = []byte("signed-header-")
+
+// Rules decides what the signer signs. It never signs two different headers at the same
+// height and consensus state for an account, remembering the headers signed in a database
+// surviving restarts, and only signs transactions to the whitelisted contracts.
+type Rules struct {
+	db         database.Database
+	contracts  map[common.Address]bool
+	macTimeGap time.Duration
+
+	lock sync.Mutex // serializes the check and the record of headers
+}
+
+// NewRules returns rules recording the headers signed in db, allowing transactions to the
+// contracts.
+func NewRules(db database.Database, contracts []common.Address) *Rules {
+	r := &Rules{
+		db:         db,
+		contracts:  make(map[common.Address]bool),
+		macTimeGap: DefaultMacTimeGap,
+	}
+	for _, c := range contracts {
+		r.contracts[c] = true
+	}
+	return r
+}
+
+// Contracts returns the whitelisted contracts.
+func (r *Rules) Contracts() []common.Address {
+	contracts := make([]common.Address, 0, len(r.contracts))
+	for c := range r.contracts {
+		contracts = append(contracts, c)
+	}
+	return contracts
+}
+
+// ApproveHeader approves signing the header in the state, recording the hash signed for it.
+// It fails if another header is already signed at the height in the state, signing the
+// same one again is allowed.
+func (r *Rules) ApproveHeader(account common.Address, header *types.Header, state consensus.State, hash common.Hash) error {
+	switch state {
+	case consensus.Idle, consensus.Prepare, consensus.Commit, consensus.ImpeachPrepare, consensus.ImpeachCommit:
+	default:
+		return errInvalidState
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	key := signedHeaderKey(account, header.Number.Uint64(), state)
+	ok, err := r.db.Has(key)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return r.db.Put(key, hash.Bytes())
+	}
+
+	signed, err := r.db.Get(key)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(signed, hash.Bytes()) {
+		return errDoubleSign
+	}
+	return nil
+}
+
+// ApproveTransaction approves signing the transaction if it is sent to a whitelisted
+// contract.
+func (r *Rules) ApproveTransaction(tx *types.Transaction) error {
+	to := tx.To()
+	if to == nil {
+		return errContractCreation
+	}
+	if !r.contracts[*to] {
+		return errContractNotAllowed
+	}
+	return nil
+}
+
+// ApproveMac approves signing a handshake mac, i.e. "gcchain|<RFC3339 time>" with a time
+// close to the local one.
+func (r *Rules) ApproveMac(mac string) error {
+	s := strings.Split(mac, "|")
+	if len(s) != 2 || s[0] != "gcchain" {
+		return errInvalidMac
+	}
+	t, err := time.Parse(time.RFC3339, s[1])
+	if err != nil {
+		return errInvalidMac
+	}
+	if gap := time.Since(t); gap > r.macTimeGap || gap < -r.macTimeGap {
+		return fmt.Errorf("%v: time %v is off by %v", errInvalidMac, s[1], gap)
+	}
+	return nil
+}
+
+func signedHeaderKey(account common.Address, number uint64, state consensus.State) []byte {
+	key := make([]byte, 0, len(signedHeaderPrefix)+common.AddressLength+9)
+	key = append(key, signedHeaderPrefix...)
+	key = append(key, account.Bytes()...)
+	key = append(key, make([]byte, 8)...)
+	binary.BigEndian.PutUint64(key[len(key)-8:], number)
+	return append(key, byte(state))
+}
@@ -0,0 +1,197 @@
+// Copyright 2018 The gcchain authors
+
+// Package signer implements a signer daemon holding the keys of validators and proposers
+// out of their nodes. The nodes send it the consensus msgs and transactions to sign, by
+// the accounts/external backend, and it signs them by its rules, auditing every request.
+package signer
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/gcchains/chain/accounts"
+	"github.com/gcchains/chain/accounts/keystore"
+	"github.com/gcchains/chain/api/rpc"
+	"github.com/gcchains/chain/commons/log"
+	"github.com/gcchains/chain/consensus"
+	"github.com/gcchains/chain/consensus/dpos"
+	"github.com/gcchains/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Namespace is the RPC namespace of the signer API.
+const Namespace = "signer"
+
+// Signer signs with the unlocked accounts of a keystore what its rules approve.
+type Signer struct {
+	keystore *keystore.KeyStore
+	rules    *Rules
+	audit    *AuditLog
+}
+
+// New returns a signer of the accounts unlocked in ks, recording the requests to audit.
+func New(ks *keystore.KeyStore, rules *Rules, audit *AuditLog) *Signer {
+	return &Signer{
+		keystore: ks,
+		rules:    rules,
+		audit:    audit,
+	}
+}
+
+// APIs returns the RPC services of the signer.
+func (s *Signer) APIs() []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: Namespace,
+			Version:   "1.0",
+			Service:   &API{s},
+			Public:    true,
+		},
+	}
+}
+
+// Accounts returns the accounts the signer signs with, i.e. the unlocked ones.
+func (s *Signer) Accounts() []common.Address {
+	var addresses []common.Address
+	for _, wallet := range s.keystore.Wallets() {
+		if status, _ := wallet.Status(); status != "Unlocked" {
+			continue
+		}
+		for _, a := range wallet.Accounts() {
+			addresses = append(addresses, a.Address)
+		}
+	}
+	return addresses
+}
+
+// SignHeader signs the header in the state, a proposer sealing it in idle state.
+func (s *Signer) SignHeader(account common.Address, header *types.Header, state consensus.State) ([]byte, error) {
+	number := header.Number.Uint64()
+	entry := &AuditEntry{Method: "signHeader", Account: account, Number: &number, State: state.String()}
+
+	hash, err := dpos.HeaderHashToSign(header, state)
+	if err != nil {
+		return nil, s.refuse(entry, err)
+	}
+	h := common.BytesToHash(hash)
+	entry.Hash = &h
+
+	if err := s.rules.ApproveHeader(account, header, state, h); err != nil {
+		return nil, s.refuse(entry, err)
+	}
+	return s.sign(entry, hash)
+}
+
+// SignMac signs the mac of a handshake with remote validators.
+func (s *Signer) SignMac(account common.Address, mac string) ([]byte, error) {
+	entry := &AuditEntry{Method: "signMac", Account: account, Mac: mac}
+
+	if err := s.rules.ApproveMac(mac); err != nil {
+		return nil, s.refuse(entry, err)
+	}
+	return s.sign(entry, crypto.Keccak256([]byte(mac)))
+}
+
+// SignTransaction signs the transaction to a whitelisted contract.
+func (s *Signer) SignTransaction(account common.Address, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	h := tx.Hash()
+	entry := &AuditEntry{Method: "signTransaction", Account: account, Hash: &h, To: tx.To()}
+
+	if err := s.rules.ApproveTransaction(tx); err != nil {
+		return nil, s.refuse(entry, err)
+	}
+	signed, err := s.keystore.SignTx(accounts.Account{Address: account}, tx, chainID)
+	if err != nil {
+		return nil, s.refuse(entry, err)
+	}
+	return signed, s.approve(entry)
+}
+
+// SignHash refuses to sign the hash, whose content cannot be checked by the rules.
+func (s *Signer) SignHash(account common.Address, hash []byte) ([]byte, error) {
+	h := common.BytesToHash(hash)
+	return nil, s.refuse(&AuditEntry{Method: "signHash", Account: account, Hash: &h}, errHashRefused)
+}
+
+// PublicKey returns the public key of the account.
+func (s *Signer) PublicKey(account common.Address) ([]byte, error) {
+	return s.keystore.EcdsaPublicKey(accounts.Account{Address: account})
+}
+
+// sign signs the hash approved, failing if the request cannot be audited.
+func (s *Signer) sign(entry *AuditEntry, hash []byte) ([]byte, error) {
+	sig, err := s.keystore.SignHash(accounts.Account{Address: entry.Account}, hash)
+	if err != nil {
+		return nil, s.refuse(entry, err)
+	}
+	return sig, s.approve(entry)
+}
+
+func (s *Signer) approve(entry *AuditEntry) error {
+	entry.Time, entry.Signed = time.Now(), true
+	if err := s.audit.Log(entry); err != nil {
+		log.Error("failed to audit signing request, refusing it", "method", entry.Method, "account", entry.Account, "err", err)
+		return err
+	}
+	log.Info("signed", "method", entry.Method, "account", entry.Account)
+	return nil
+}
+
+func (s *Signer) refuse(entry *AuditEntry, reason error) error {
+	entry.Time, entry.Error = time.Now(), reason.Error()
+	if err := s.audit.Log(entry); err != nil {
+		log.Error("failed to audit signing request", "method", entry.Method, "account", entry.Account, "err", err)
+	}
+	log.Warn("refused to sign", "method", entry.Method, "account", entry.Account, "reason", reason)
+	return reason
+}
+
+// API is the RPC API of the signer, headers and transactions are RLP encoded.
+type API struct {
+	s *Signer
+}
+
+// Accounts returns the accounts the signer signs with.
+func (api *API) Accounts() []common.Address {
+	return api.s.Accounts()
+}
+
+// SignHeader signs the header in the state.
+func (api *API) SignHeader(account common.Address, header hexutil.Bytes, state consensus.State) (hexutil.Bytes, error) {
+	h := new(types.Header)
+	if err := rlp.DecodeBytes(header, h); err != nil {
+		return nil, err
+	}
+	return api.s.SignHeader(account, h, state)
+}
+
+// SignMac signs the mac of a handshake.
+func (api *API) SignMac(account common.Address, mac string) (hexutil.Bytes, error) {
+	return api.s.SignMac(account, mac)
+}
+
+// SignTransaction signs the transaction, returning the signed one.
+func (api *API) SignTransaction(account common.Address, tx hexutil.Bytes, chainID *hexutil.Big) (hexutil.Bytes, error) {
+	t := new(types.Transaction)
+	if err := rlp.DecodeBytes(tx, t); err != nil {
+		return nil, err
+	}
+	signed, err := api.s.SignTransaction(account, t, (*big.Int)(chainID))
+	if err != nil {
+		return nil, err
+	}
+	return rlp.EncodeToBytes(signed)
+}
+
+// SignHash refuses to sign the hash.
+func (api *API) SignHash(account common.Address, hash hexutil.Bytes) (hexutil.Bytes, error) {
+	return api.s.SignHash(account, hash)
+}
+
+// PublicKey returns the public key of the account.
+func (api *API) PublicKey(account common.Address) (hexutil.Bytes, error) {
+	return api.s.PublicKey(account)
+}
@@ -0,0 +1,221 @@
+package signer
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gcchains/chain/accounts"
+	"github.com/gcchains/chain/accounts/external"
+	"github.com/gcchains/chain/accounts/keystore"
+	"github.com/gcchains/chain/api/rpc"
+	"github.com/gcchains/chain/consensus"
+	"github.com/gcchains/chain/consensus/dpos"
+	"github.com/gcchains/chain/database"
+	"github.com/gcchains/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	campaignContract = common.HexToAddress("0xf26b6164749cde15a29afea57ffeae115b24b505")
+	otherContract    = common.HexToAddress("0x7e9915bea4af2ebea96dd8ba9814d4503e6c0218")
+)
+
+// newTestSigner returns a signer of an unlocked account, with its audit log.
+func newTestSigner(t *testing.T) (*Signer, accounts.Account, *bytes.Buffer, func()) {
+	dir, err := ioutil.TempDir("", "signer-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	account, err := ks.NewAccount("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.Unlock(account, "password"); err != nil {
+		t.Fatal(err)
+	}
+
+	audit := new(bytes.Buffer)
+	rules := NewRules(database.NewMemDatabase(), []common.Address{campaignContract})
+	return New(ks, rules, NewAuditLog(audit)), account, audit, func() { os.RemoveAll(dir) }
+}
+
+func newTestHeader(number int64, extra string) *types.Header {
+	return &types.Header{
+		Number: big.NewInt(number),
+		Time:   big.NewInt(time.Now().Unix()),
+		Extra:  []byte(extra),
+	}
+}
+
+func recoverSigner(t *testing.T, hash []byte, sig []byte) common.Address {
+	pubkey, err := crypto.Ecrecover(hash, sig)
+	if err != nil {
+		t.Fatalf("Ecrecover() error = %v", err)
+	}
+	return common.BytesToAddress(crypto.Keccak256(pubkey[1:])[12:])
+}
+
+func TestSignHeader(t *testing.T) {
+	s, account, audit, cleanup := newTestSigner(t)
+	defer cleanup()
+
+	header, other := newTestHeader(1, "a"), newTestHeader(1, "b")
+	for _, state := range []consensus.State{consensus.Idle, consensus.Prepare, consensus.Commit} {
+		sig, err := s.SignHeader(account.Address, header, state)
+		if err != nil {
+			t.Fatalf("SignHeader(%v) error = %v", state, err)
+		}
+		hash, _ := dpos.HeaderHashToSign(header, state)
+		if signer := recoverSigner(t, hash, sig); signer != account.Address {
+			t.Errorf("SignHeader(%v) signed by %v, want %v", state, signer.Hex(), account.Address.Hex())
+		}
+
+		// signing the same header again is allowed, another one is not
+		if _, err := s.SignHeader(account.Address, header, state); err != nil {
+			t.Errorf("SignHeader(%v) the same header error = %v", state, err)
+		}
+		if _, err := s.SignHeader(account.Address, other, state); err != errDoubleSign {
+			t.Errorf("SignHeader(%v) another header error = %v, want %v", state, err, errDoubleSign)
+		}
+	}
+
+	// another header is signed at another height, or in impeach states
+	if _, err := s.SignHeader(account.Address, newTestHeader(2, "b"), consensus.Prepare); err != nil {
+		t.Errorf("SignHeader() at another height error = %v", err)
+	}
+	if _, err := s.SignHeader(account.Address, other, consensus.ImpeachPrepare); err != nil {
+		t.Errorf("SignHeader() in impeach prepare error = %v", err)
+	}
+	if _, err := s.SignHeader(account.Address, header, consensus.Validate); err != errInvalidState {
+		t.Errorf("SignHeader() in validate error = %v, want %v", err, errInvalidState)
+	}
+
+	// every request is audited
+	var entries []*AuditEntry
+	dec := json.NewDecoder(audit)
+	for dec.More() {
+		entry := new(AuditEntry)
+		if err := dec.Decode(entry); err != nil {
+			t.Fatalf("audit log error = %v", err)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 12 {
+		t.Fatalf("audit log has %d entries, want 12", len(entries))
+	}
+	if e := entries[2]; e.Signed || e.Error != errDoubleSign.Error() || *e.Number != 1 || e.State != consensus.Idle.String() {
+		t.Errorf("audit entry of a double sign = %+v", e)
+	}
+}
+
+func TestSignTransaction(t *testing.T) {
+	s, account, _, cleanup := newTestSigner(t)
+	defer cleanup()
+
+	chainID := big.NewInt(42)
+	tx := types.NewTransaction(0, campaignContract, big.NewInt(0), 100000, big.NewInt(1), nil)
+	signed, err := s.SignTransaction(account.Address, tx, chainID)
+	if err != nil {
+		t.Fatalf("SignTransaction() error = %v", err)
+	}
+	if sender, err := types.Sender(types.NewCep1Signer(chainID), signed); err != nil || sender != account.Address {
+		t.Errorf("SignTransaction() sender = %v, %v, want %v", sender.Hex(), err, account.Address.Hex())
+	}
+
+	tx = types.NewTransaction(0, otherContract, big.NewInt(0), 100000, big.NewInt(1), nil)
+	if _, err := s.SignTransaction(account.Address, tx, chainID); err != errContractNotAllowed {
+		t.Errorf("SignTransaction() to another contract error = %v, want %v", err, errContractNotAllowed)
+	}
+	tx = types.NewContractCreation(0, big.NewInt(0), 100000, big.NewInt(1), nil)
+	if _, err := s.SignTransaction(account.Address, tx, chainID); err != errContractCreation {
+		t.Errorf("SignTransaction() creating a contract error = %v, want %v", err, errContractCreation)
+	}
+}
+
+func TestSignMac(t *testing.T) {
+	s, account, _, cleanup := newTestSigner(t)
+	defer cleanup()
+
+	mac := "gcchain|" + time.Now().Format(time.RFC3339)
+	sig, err := s.SignMac(account.Address, mac)
+	if err != nil {
+		t.Fatalf("SignMac() error = %v", err)
+	}
+	if signer := recoverSigner(t, crypto.Keccak256([]byte(mac)), sig); signer != account.Address {
+		t.Errorf("SignMac() signed by %v, want %v", signer.Hex(), account.Address.Hex())
+	}
+
+	for _, mac := range []string{
+		"gcchain|" + time.Now().Add(-time.Hour).Format(time.RFC3339),
+		"other|" + time.Now().Format(time.RFC3339),
+		"gcchain",
+	} {
+		if _, err := s.SignMac(account.Address, mac); err == nil {
+			t.Errorf("SignMac(%q) signed", mac)
+		}
+	}
+
+	if _, err := s.SignHash(account.Address, crypto.Keccak256([]byte(mac))); err != errHashRefused {
+		t.Errorf("SignHash() error = %v, want %v", err, errHashRefused)
+	}
+}
+
+// TestExternalSigner tests the external wallet of a node against a signer over ipc.
+func TestExternalSigner(t *testing.T) {
+	s, account, _, cleanup := newTestSigner(t)
+	defer cleanup()
+
+	endpoint := filepath.Join(os.TempDir(), "signer-test.ipc")
+	listener, _, err := rpc.StartIPCEndpoint(endpoint, s.APIs())
+	if err != nil {
+		t.Fatalf("StartIPCEndpoint() error = %v", err)
+	}
+	defer listener.Close()
+
+	wallet, err := external.NewExternalSigner(endpoint, nil)
+	if err != nil {
+		t.Fatalf("NewExternalSigner() error = %v", err)
+	}
+	if !wallet.Contains(accounts.Account{Address: account.Address}) {
+		t.Fatalf("external signer has accounts %v, want %v", wallet.Accounts(), account.Address.Hex())
+	}
+	acc := accounts.Account{Address: account.Address}
+
+	header := newTestHeader(1, "a")
+	sig, err := wallet.SignHeader(acc, header, consensus.Commit)
+	if err != nil {
+		t.Fatalf("SignHeader() error = %v", err)
+	}
+	hash, _ := dpos.HeaderHashToSign(header, consensus.Commit)
+	if signer := recoverSigner(t, hash, sig); signer != account.Address {
+		t.Errorf("SignHeader() signed by %v, want %v", signer.Hex(), account.Address.Hex())
+	}
+	if _, err := wallet.SignHeader(acc, newTestHeader(1, "b"), consensus.Commit); err == nil {
+		t.Error("SignHeader() signed another header at the height")
+	}
+
+	if _, err := wallet.SignMac(acc, "gcchain|"+time.Now().Format(time.RFC3339)); err != nil {
+		t.Errorf("SignMac() error = %v", err)
+	}
+	if _, err := wallet.SignHash(acc, hash); err == nil {
+		t.Error("SignHash() signed a bare hash")
+	}
+
+	chainID := big.NewInt(42)
+	tx := types.NewTransaction(0, campaignContract, big.NewInt(0), 100000, big.NewInt(1), nil)
+	signed, err := wallet.SignTx(acc, tx, chainID)
+	if err != nil {
+		t.Fatalf("SignTx() error = %v", err)
+	}
+	if sender, err := types.Sender(types.NewCep1Signer(chainID), signed); err != nil || sender != account.Address {
+		t.Errorf("SignTx() sender = %v, %v, want %v", sender.Hex(), err, account.Address.Hex())
+	}
+}
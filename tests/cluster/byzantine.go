@@ -0,0 +1,124 @@
+// Copyright 2018 The gcchain authors
+
+package cluster
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/gcchains/chain/commons/log"
+	"github.com/gcchains/chain/consensus/dpos"
+	"github.com/gcchains/chain/consensus/dpos/backend"
+	"github.com/gcchains/chain/core"
+	"github.com/gcchains/chain/types"
+	"github.com/ethereum/go-ethereum/p2p"
+)
+
+var errNotDpos = errors.New("the engine of the node is not dpos")
+
+// startByzantine starts the dpos handler of a byzantine proposer without the miner,
+// the proposer equivocates in its turns instead of proposing a block.
+func (n *Node) startByzantine(server *p2p.Server, chainService service) error {
+	engine, ok := chainService.Engine().(*dpos.Dpos)
+	if !ok {
+		return errNotDpos
+	}
+	chain := chainService.BlockChain()
+
+	engine.SetAsMiner(true)
+	engine.StartMining(chain, server, func(*types.Block, bool) {}, func(*p2p.Peer) {}, func() {})
+
+	n.quitCh = make(chan struct{})
+	go equivocateLoop(n, chain, engine, n.quitCh)
+	return nil
+}
+
+// equivocateLoop proposes two conflicting blocks in each turn of the proposer, sending
+// each of them to a half of the validators, so that none of them can be prepared and
+// the validators have to impeach the proposer.
+func equivocateLoop(n *Node, chain *core.BlockChain, engine *dpos.Dpos, quitCh chan struct{}) {
+	headCh := make(chan core.ChainHeadEvent, 16)
+	sub := chain.SubscribeChainHeadEvent(headCh)
+	defer sub.Unsubscribe()
+
+	parent := chain.CurrentBlock()
+	for {
+		if ok, _ := engine.IsProposerOf(n.Address, parent.NumberU64()+1); ok {
+			if err := equivocate(n, chain, engine, parent, quitCh); err != nil {
+				log.Warn("byzantine proposer failed to equivocate", "number", parent.NumberU64()+1, "err", err)
+			}
+		}
+
+		select {
+		case ev := <-headCh:
+			parent = ev.Block
+		case <-sub.Err():
+			return
+		case <-quitCh:
+			return
+		}
+	}
+}
+
+func equivocate(n *Node, chain *core.BlockChain, engine *dpos.Dpos, parent *types.Block, quitCh chan struct{}) error {
+	first, err := proposeBlock(n, chain, engine, parent, 1, quitCh)
+	if err != nil || first == nil {
+		return err
+	}
+	second, err := proposeBlock(n, chain, engine, parent, 2, quitCh)
+	if err != nil || second == nil {
+		return err
+	}
+
+	handler, ok := engine.Protocol().(*backend.Handler)
+	if !ok {
+		return errNotDpos
+	}
+	validators := handler.Dialer().ValidatorsOfTerm(engine.TermOf(first.NumberU64()))
+
+	var remotes []*backend.RemoteValidator
+	for _, validator := range validators {
+		remotes = append(remotes, validator)
+	}
+	sort.Slice(remotes, func(i, j int) bool {
+		return bytes.Compare(remotes[i].Coinbase().Bytes(), remotes[j].Coinbase().Bytes()) < 0
+	})
+
+	log.Debug("byzantine proposer equivocating", "number", first.NumberU64(), "first", first.Hash().Hex(), "second", second.Hash().Hex(), "validators", len(remotes))
+
+	for i, remote := range remotes {
+		block := first
+		if i >= len(remotes)/2 {
+			block = second
+		}
+		remote.AsyncSendPreprepareBlock(block)
+	}
+	return nil
+}
+
+// proposeBlock seals an empty block on top of parent, the vanity distinguishes the blocks
+// of the same turn.
+func proposeBlock(n *Node, chain *core.BlockChain, engine *dpos.Dpos, parent *types.Block, vanity byte, quitCh chan struct{}) (*types.Block, error) {
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     new(big.Int).Add(parent.Number(), big.NewInt(1)),
+		GasLimit:   core.CalcGasLimit(parent),
+		Extra:      []byte{vanity},
+		Coinbase:   n.Address,
+	}
+	if err := engine.PrepareBlock(chain, header); err != nil {
+		return nil, err
+	}
+
+	state, err := chain.StateAt(parent.StateRoot())
+	if err != nil {
+		return nil, err
+	}
+	block, err := engine.Finalize(chain, header, state, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return engine.Seal(chain, block, quitCh)
+}
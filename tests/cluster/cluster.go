@@ -0,0 +1,417 @@
+// Copyright 2018 The gcchain authors
+
+// Package cluster runs a committee of full nodes in process for consensus integration tests.
+//
+// The nodes run the real chain service, i.e. the protocol manager, the dpos handler and its
+// dialer, and are connected by in-memory pipes as the inproc adapter of p2p/simulations
+// does. The committee is defined by the genesis block, and the term is long enough for the
+// tests to run in the first one, so no election takes place. Faults are injected by
+// crashing nodes, partitioning or slowing down the network, and by byzantine proposers
+// equivocating in their turns.
+package cluster
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gcchains/chain/configs"
+	"github.com/gcchains/chain/consensus/dpos/backend"
+	"github.com/gcchains/chain/core"
+	"github.com/gcchains/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	networkID = 7331
+
+	// viewLen is long enough to keep the cluster in the first term, which committee is
+	// the one of the genesis block.
+	viewLen = 1 << 20
+
+	// bootTime is the time the nodes have to boot and connect before the first block.
+	bootTime = 5 * time.Second
+)
+
+// Config is the configuration of a cluster.
+type Config struct {
+	Proposers      int           // number of proposers, taking turns to propose blocks
+	Faulty         uint64        // number of faulty validators tolerated, there are 3f+1 validators
+	Period         time.Duration // period of block generation
+	ImpeachTimeout time.Duration // time to wait for a block before impeaching the proposer
+	Byzantine      []int         // indexes of the proposers equivocating instead of proposing blocks
+	Trace          bool          // records the steps of the lbft2 fsm of the validators, see Trace
+}
+
+// DefaultConfig is a committee of 3 proposers and 4 validators.
+var DefaultConfig = Config{
+	Proposers:      3,
+	Faulty:         1,
+	Period:         time.Second,
+	ImpeachTimeout: 4 * time.Second,
+}
+
+// Cluster is a committee of nodes, the proposers are indexed before the validators.
+type Cluster struct {
+	config  Config
+	genesis *core.Genesis
+	nodes   []*Node
+	network *network
+}
+
+// New creates a cluster keeping the data of its nodes in dir, the nodes are not started.
+func New(dir string, config Config) (*Cluster, error) {
+	var (
+		validators = int(3*config.Faulty + 1)
+		nodes      = make([]*Node, 0, config.Proposers+validators)
+		genesis    = newGenesis(config)
+	)
+
+	for i := 0; i < config.Proposers+validators; i++ {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			return nil, err
+		}
+		nodeKey, err := crypto.GenerateKey()
+		if err != nil {
+			return nil, err
+		}
+
+		role := Proposer
+		if i >= config.Proposers {
+			role = Validator
+		}
+		nodes = append(nodes, &Node{
+			Index:     i,
+			Role:      role,
+			Address:   crypto.PubkeyToAddress(key.PublicKey),
+			key:       key,
+			nodeKey:   nodeKey,
+			dataDir:   filepath.Join(dir, fmt.Sprintf("node%d", i)),
+			genesis:   genesis,
+			networkID: networkID,
+			trace:     config.Trace && role == Validator,
+		})
+	}
+	for _, i := range config.Byzantine {
+		if i < 0 || i >= config.Proposers {
+			return nil, fmt.Errorf("byzantine node %d is not a proposer", i)
+		}
+		nodes[i].byzantine = true
+	}
+
+	for _, n := range nodes {
+		if n.Role == Proposer {
+			genesis.Dpos.Proposers = append(genesis.Dpos.Proposers, n.Address)
+		} else {
+			genesis.Dpos.Validators = append(genesis.Dpos.Validators, n.Address)
+		}
+		genesis.Alloc[n.Address] = core.GenesisAccount{Balance: new(big.Int).Mul(big.NewInt(1000), big.NewInt(configs.Gcc))}
+	}
+
+	return &Cluster{
+		config:  config,
+		genesis: genesis,
+		nodes:   nodes,
+		network: newNetwork(),
+	}, nil
+}
+
+// newGenesis returns a genesis block with the dpos configuration of the cluster,
+// the committee is filled in by the caller and the timestamp when it starts.
+func newGenesis(config Config) *core.Genesis {
+	chainConfig := *configs.ChainConfigInfo()
+	chainConfig.Dpos = &configs.DposConfig{
+		Period:             uint64(config.Period / time.Millisecond),
+		TermLen:            uint64(config.Proposers),
+		ViewLen:            viewLen,
+		FaultyNumber:       config.Faulty,
+		MaxInitBlockNumber: uint64(config.Proposers) * viewLen,
+		ImpeachTimeout:     config.ImpeachTimeout,
+	}
+
+	return &core.Genesis{
+		Config:     &chainConfig,
+		ExtraData:  make([]byte, 32),
+		GasLimit:   configs.DefaultGasLimitPerBlock,
+		Difficulty: big.NewInt(1),
+		Alloc:      make(core.GenesisAlloc),
+		Dpos: types.DposSnap{
+			Sigs: make([]types.DposSignature, 3*config.Faulty+1),
+		},
+	}
+}
+
+// Start starts all the nodes and connects them. The genesis block is timestamped
+// so that the first block is not proposed to a committee still booting.
+func (c *Cluster) Start() error {
+	c.genesis.Timestamp = uint64(time.Now().Add(bootTime).UnixNano() / int64(time.Millisecond))
+
+	for _, n := range c.nodes {
+		if err := n.start(); err != nil {
+			c.Stop()
+			return fmt.Errorf("failed to start node %d: %v", n.Index, err)
+		}
+	}
+	for _, role := range []Role{Validator, Proposer} {
+		for _, i := range c.indexesOf(role) {
+			if err := c.nodes[i].run(); err != nil {
+				c.Stop()
+				return fmt.Errorf("failed to run node %d: %v", i, err)
+			}
+		}
+	}
+	c.connect()
+	return nil
+}
+
+// Stop stops all the running nodes.
+func (c *Cluster) Stop() {
+	for _, n := range c.nodes {
+		if n.Running() {
+			c.network.isolate(n.Index)
+			n.stop()
+		}
+	}
+}
+
+// connect links all the running nodes which are reachable and not linked yet.
+func (c *Cluster) connect() {
+	for _, a := range c.nodes {
+		for _, b := range c.nodes[a.Index+1:] {
+			srvA, srvB := a.server(), b.server()
+			if srvA != nil && srvB != nil {
+				c.network.connect(a.Index, b.Index, srvA, srvB)
+			}
+		}
+	}
+}
+
+// Nodes returns all the nodes of the cluster.
+func (c *Cluster) Nodes() []*Node {
+	return c.nodes
+}
+
+// Node returns the node with the index.
+func (c *Cluster) Node(index int) *Node {
+	return c.nodes[index]
+}
+
+// Proposers returns the indexes of the proposers.
+func (c *Cluster) Proposers() []int {
+	return c.indexesOf(Proposer)
+}
+
+// Validators returns the indexes of the validators.
+func (c *Cluster) Validators() []int {
+	return c.indexesOf(Validator)
+}
+
+func (c *Cluster) indexesOf(role Role) []int {
+	var indexes []int
+	for _, n := range c.nodes {
+		if n.Role == role {
+			indexes = append(indexes, n.Index)
+		}
+	}
+	return indexes
+}
+
+// ProposerOf returns the index of the proposer in charge of the block number.
+func (c *Cluster) ProposerOf(number uint64) int {
+	return int((number - 1) % uint64(c.config.Proposers))
+}
+
+// Crash stops a node, its links are closed.
+func (c *Cluster) Crash(index int) error {
+	c.network.isolate(index)
+	return c.nodes[index].stop()
+}
+
+// Restart starts a crashed node again from its data directory, and connects it.
+func (c *Cluster) Restart(index int) error {
+	if err := c.nodes[index].start(); err != nil {
+		return err
+	}
+	if err := c.nodes[index].run(); err != nil {
+		return err
+	}
+	c.connect()
+	return nil
+}
+
+// Partition splits the nodes into groups which can not reach each other, the nodes not
+// in any group are isolated.
+func (c *Cluster) Partition(groups ...[]int) {
+	c.network.partition(groups)
+}
+
+// Heal removes the partition and connects the nodes again.
+func (c *Cluster) Heal() {
+	c.network.heal()
+	c.connect()
+}
+
+// Delay delays the msgs sent between two nodes, a delay of 0 removes it.
+func (c *Cluster) Delay(a, b int, delay time.Duration) {
+	c.network.setDelay(a, b, delay)
+}
+
+// DelayNode delays the msgs sent between a node and all the others.
+func (c *Cluster) DelayNode(index int, delay time.Duration) {
+	for _, n := range c.nodes {
+		if n.Index != index {
+			c.network.setDelay(index, n.Index, delay)
+		}
+	}
+}
+
+// WaitHeight waits until the nodes, all the running ones if none is given, reach the
+// block number.
+func (c *Cluster) WaitHeight(number uint64, timeout time.Duration, indexes ...int) error {
+	nodes := c.running(indexes)
+	deadline := time.Now().Add(timeout)
+	for {
+		var lagging *Node
+		for _, n := range nodes {
+			if n.Height() < number {
+				lagging = n
+				break
+			}
+		}
+		if lagging == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("node %d is at block %d after %v, want %d", lagging.Index, lagging.Height(), timeout, number)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// WaitProposed waits until all the running nodes commit a block proposed in turn, i.e.
+// not an impeachment block, above the current lowest head.
+func (c *Cluster) WaitProposed(timeout time.Duration) error {
+	var (
+		from     = c.Height() + 1
+		deadline = time.Now().Add(timeout)
+	)
+	for number := from; ; {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("no proposed block from %d to %d after %v", from, number, timeout)
+		}
+		if err := c.WaitHeight(number, time.Until(deadline)); err != nil {
+			return err
+		}
+		block := c.running(nil)[0].BlockChain().GetBlockByNumber(number)
+		if block != nil && !block.Impeachment() {
+			return nil
+		}
+		number++
+	}
+}
+
+// Stalled checks that no node, all the running ones if none is given, goes past the
+// block number during the duration.
+func (c *Cluster) Stalled(number uint64, duration time.Duration, indexes ...int) error {
+	nodes := c.running(indexes)
+	deadline := time.Now().Add(duration)
+	for time.Now().Before(deadline) {
+		for _, n := range nodes {
+			if height := n.Height(); height > number {
+				return fmt.Errorf("node %d reached block %d, want at most %d", n.Index, height, number)
+			}
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return nil
+}
+
+// Height returns the lowest head block number of the running nodes.
+func (c *Cluster) Height() uint64 {
+	var height uint64
+	for i, n := range c.running(nil) {
+		if h := n.Height(); i == 0 || h < height {
+			height = h
+		}
+	}
+	return height
+}
+
+// CheckSafety checks that the running nodes agree on the blocks up to the lowest head,
+// i.e. no two different blocks are committed at the same height.
+func (c *Cluster) CheckSafety() error {
+	nodes := c.running(nil)
+	if len(nodes) == 0 {
+		return nil
+	}
+	for number := uint64(1); number <= c.Height(); number++ {
+		var (
+			hash  common.Hash
+			first *Node
+		)
+		for _, n := range nodes {
+			block := n.BlockChain().GetBlockByNumber(number)
+			if block == nil {
+				return fmt.Errorf("node %d has no block %d", n.Index, number)
+			}
+			if first == nil {
+				hash, first = block.Hash(), n
+				continue
+			}
+			if block.Hash() != hash {
+				return fmt.Errorf("conflicting blocks %d, node %d has %s, node %d has %s", number, first.Index, hash.Hex(), n.Index, block.Hash().Hex())
+			}
+		}
+	}
+	return nil
+}
+
+// Impeachments returns the numbers of the impeachment blocks in the chain of a node.
+func (c *Cluster) Impeachments(index int) []uint64 {
+	chain := c.nodes[index].BlockChain()
+	if chain == nil {
+		return nil
+	}
+
+	var numbers []uint64
+	for number := uint64(1); number <= chain.CurrentBlock().NumberU64(); number++ {
+		if block := chain.GetBlockByNumber(number); block != nil && block.Impeachment() {
+			numbers = append(numbers, number)
+		}
+	}
+	return numbers
+}
+
+// Trace returns the steps of the lbft2 fsm recorded by a validator, in all its runs.
+func (c *Cluster) Trace(index int) ([]*backend.TraceStep, error) {
+	f, err := os.Open(c.nodes[index].tracePath())
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return backend.ReadTrace(f)
+}
+
+// running returns the running nodes with the indexes, all the running ones if none is given.
+func (c *Cluster) running(indexes []int) []*Node {
+	var nodes []*Node
+	if len(indexes) == 0 {
+		for _, n := range c.nodes {
+			if n.Running() {
+				nodes = append(nodes, n)
+			}
+		}
+		return nodes
+	}
+	for _, i := range indexes {
+		if c.nodes[i].Running() {
+			nodes = append(nodes, c.nodes[i])
+		}
+	}
+	return nodes
+}
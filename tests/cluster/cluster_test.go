@@ -0,0 +1,252 @@
+package cluster
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gcchains/chain/consensus/dpos/backend"
+)
+
+// The scenarios follow the committee of tlaplus/lbft.tla, 4 validators where a
+// prepare or commit certificate takes 3 signatures and an impeachment certificate 2.
+
+const timeout = 2 * time.Minute
+
+func newCluster(t *testing.T, config Config) (*Cluster, func()) {
+	if testing.Short() {
+		t.Skip("skipping cluster test in short mode")
+	}
+	dir, err := ioutil.TempDir("", "cluster")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := New(dir, config)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	if err := c.Start(); err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return c, func() {
+		c.Stop()
+		os.RemoveAll(dir)
+	}
+}
+
+func checkSafety(t *testing.T, c *Cluster) {
+	if err := c.CheckSafety(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// checkImpeachments checks that node index has impeached blocks, all of them at the
+// turns of the proposer.
+func checkImpeachments(t *testing.T, c *Cluster, index int, proposer int) {
+	impeached := c.Impeachments(index)
+	if len(impeached) == 0 {
+		t.Fatalf("no impeachment block in chain of node %d", index)
+	}
+	for _, number := range impeached {
+		if p := c.ProposerOf(number); p != proposer {
+			t.Errorf("impeachment block %d at turn of proposer %d, want %d", number, p, proposer)
+		}
+	}
+}
+
+// checkImpeachedSince checks that the blocks of node index from the number on are all
+// impeachment blocks.
+func checkImpeachedSince(t *testing.T, c *Cluster, index int, number uint64) {
+	impeached := make(map[uint64]bool)
+	for _, n := range c.Impeachments(index) {
+		impeached[n] = true
+	}
+	for ; number <= c.Node(index).Height(); number++ {
+		if !impeached[number] {
+			t.Fatalf("block %d of node %d is not an impeachment block", number, index)
+		}
+	}
+}
+
+// All the validators reach the commit certificate, every block is proposed in turn.
+func TestLiveness(t *testing.T) {
+	c, cleanup := newCluster(t, DefaultConfig)
+	defer cleanup()
+
+	if err := c.WaitHeight(6, timeout); err != nil {
+		t.Fatal(err)
+	}
+	checkSafety(t, c)
+	if impeached := c.Impeachments(0); len(impeached) != 0 {
+		t.Errorf("impeachment blocks %v, want none", impeached)
+	}
+}
+
+// The 3 validators left still reach the certificates, the crashed one catches up once
+// it is restarted.
+func TestCrashedValidator(t *testing.T) {
+	c, cleanup := newCluster(t, DefaultConfig)
+	defer cleanup()
+
+	validator := c.Validators()[0]
+	if err := c.WaitHeight(2, timeout); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Crash(validator); err != nil {
+		t.Fatal(err)
+	}
+	height := c.Height()
+	if err := c.WaitHeight(height+4, timeout); err != nil {
+		t.Fatal(err)
+	}
+	checkSafety(t, c)
+
+	if err := c.Restart(validator); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.WaitHeight(c.Height()+2, timeout, validator); err != nil {
+		t.Fatal(err)
+	}
+	checkSafety(t, c)
+}
+
+// With 2 of 4 validators crashed no prepare certificate can be reached, though the
+// impeachment certificate still can, so the chain only goes on with impeachment
+// blocks until they are back.
+func TestCrashedValidators(t *testing.T) {
+	c, cleanup := newCluster(t, DefaultConfig)
+	defer cleanup()
+
+	validators := c.Validators()
+	if err := c.WaitHeight(2, timeout); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range validators[:2] {
+		if err := c.Crash(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// a block being committed meanwhile may still reach the proposers
+	height := c.Height() + 1
+	if err := c.WaitHeight(height+3, timeout); err != nil {
+		t.Fatal(err)
+	}
+	checkSafety(t, c)
+	checkImpeachedSince(t, c, validators[2], height+1)
+
+	for _, v := range validators[:2] {
+		if err := c.Restart(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := c.WaitProposed(timeout); err != nil {
+		t.Fatal(err)
+	}
+	checkSafety(t, c)
+}
+
+// The validators impeach a crashed proposer in each of its turns, with the
+// impeachment certificate of 2 signatures.
+func TestCrashedProposer(t *testing.T) {
+	c, cleanup := newCluster(t, DefaultConfig)
+	defer cleanup()
+
+	proposer := c.Proposers()[1]
+	if err := c.WaitHeight(1, timeout); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Crash(proposer); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.WaitHeight(7, timeout); err != nil {
+		t.Fatal(err)
+	}
+	checkSafety(t, c)
+	checkImpeachments(t, c, c.Validators()[0], proposer)
+}
+
+// A partition splitting the validators in halves leaves no side with a prepare
+// certificate, only impeachment blocks are committed until the partition heals.
+func TestPartition(t *testing.T) {
+	c, cleanup := newCluster(t, DefaultConfig)
+	defer cleanup()
+
+	var (
+		proposers  = c.Proposers()
+		validators = c.Validators()
+	)
+	if err := c.WaitHeight(2, timeout); err != nil {
+		t.Fatal(err)
+	}
+	c.Partition(append(proposers, validators[:2]...), validators[2:])
+
+	height := c.Height() + 1
+	if err := c.WaitHeight(height+3, timeout, proposers...); err != nil {
+		t.Fatal(err)
+	}
+	checkImpeachedSince(t, c, proposers[0], height+1)
+
+	c.Heal()
+	if err := c.WaitProposed(timeout); err != nil {
+		t.Fatal(err)
+	}
+	checkSafety(t, c)
+}
+
+// A validator with a slow link does not hold the others up.
+func TestDelayedValidator(t *testing.T) {
+	c, cleanup := newCluster(t, DefaultConfig)
+	defer cleanup()
+
+	c.DelayNode(c.Validators()[0], 200*time.Millisecond)
+	if err := c.WaitHeight(6, timeout); err != nil {
+		t.Fatal(err)
+	}
+	checkSafety(t, c)
+}
+
+// A byzantine proposer sending each half of the validators a different block leaves
+// none of them with a prepare certificate, it is impeached in each of its turns.
+func TestByzantineProposer(t *testing.T) {
+	config := DefaultConfig
+	config.Byzantine = []int{1}
+
+	c, cleanup := newCluster(t, config)
+	defer cleanup()
+
+	if err := c.WaitHeight(7, timeout); err != nil {
+		t.Fatal(err)
+	}
+	checkSafety(t, c)
+	checkImpeachments(t, c, c.Validators()[0], 1)
+}
+
+// The steps of the lbft2 fsm of the validators are checked against tlaplus/lbft.tla.
+// The fsm collects certificates before the block, which the spec does not allow, so the
+// divergences are reported rather than failing the test.
+func TestTrace(t *testing.T) {
+	config := DefaultConfig
+	config.Trace = true
+
+	c, cleanup := newCluster(t, config)
+	defer cleanup()
+
+	if err := c.WaitHeight(6, timeout); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range c.Validators() {
+		steps, err := c.Trace(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(steps) == 0 || steps[len(steps)-1].Height < 6 {
+			t.Fatalf("node %d traced %d steps, want the steps up to block 6", v, len(steps))
+		}
+		for _, d := range backend.CheckTrace(steps) {
+			t.Logf("node %d: %v", v, d)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright 2018 The gcchain authors
+
+package cluster
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p"
+)
+
+// linkID identifies the link between two nodes, the lower index first.
+type linkID struct {
+	a, b int
+}
+
+func newLinkID(a, b int) linkID {
+	if a > b {
+		a, b = b, a
+	}
+	return linkID{a: a, b: b}
+}
+
+// link is an in-memory pipe connecting the p2p servers of two nodes.
+type link struct {
+	conns [2]*faultConn
+}
+
+// network connects the nodes of a cluster with in-memory pipes, the way the inproc
+// adapter of p2p/simulations does. The links can be cut by partitions and slowed
+// down by delays.
+type network struct {
+	lock   sync.Mutex
+	links  map[linkID]*link
+	delays map[linkID]time.Duration
+	groups map[int]int // partition group of the nodes, nil if the network is not partitioned
+}
+
+func newNetwork() *network {
+	return &network{
+		links:  make(map[linkID]*link),
+		delays: make(map[linkID]time.Duration),
+	}
+}
+
+// reachable returns if the nodes a and b are in the same partition, the lock must be held.
+func (nw *network) reachable(a, b int) bool {
+	if nw.groups == nil {
+		return true
+	}
+	groupA, okA := nw.groups[a]
+	groupB, okB := nw.groups[b]
+	return okA && okB && groupA == groupB
+}
+
+// connect links the p2p servers of the nodes, if they are reachable and not linked yet.
+func (nw *network) connect(a, b int, srvA, srvB *p2p.Server) {
+	id := newLinkID(a, b)
+
+	nw.lock.Lock()
+	if _, ok := nw.links[id]; ok || !nw.reachable(a, b) {
+		nw.lock.Unlock()
+		return
+	}
+	pipeA, pipeB := net.Pipe()
+	l := new(link)
+	l.conns[0] = &faultConn{Conn: pipeA, network: nw, id: id, link: l}
+	l.conns[1] = &faultConn{Conn: pipeB, network: nw, id: id, link: l}
+	nw.links[id] = l
+	nw.lock.Unlock()
+
+	go srvB.SetupConn(l.conns[1], 0, nil)
+	go srvA.SetupConn(l.conns[0], 0, srvB.Self())
+}
+
+// cut closes the links matching the given function, which is called with the lock held.
+func (nw *network) cut(match func(id linkID) bool) {
+	var links []*link
+
+	nw.lock.Lock()
+	for id, l := range nw.links {
+		if match(id) {
+			links = append(links, l)
+		}
+	}
+	nw.lock.Unlock()
+
+	for _, l := range links {
+		l.conns[0].Close()
+		l.conns[1].Close()
+	}
+}
+
+// isolate closes all the links of a node.
+func (nw *network) isolate(index int) {
+	nw.cut(func(id linkID) bool { return id.a == index || id.b == index })
+}
+
+// partition splits the nodes into groups, cutting the links between the groups.
+// The nodes not in any group are isolated.
+func (nw *network) partition(groups [][]int) {
+	nw.lock.Lock()
+	nw.groups = make(map[int]int)
+	for group, nodes := range groups {
+		for _, index := range nodes {
+			nw.groups[index] = group
+		}
+	}
+	nw.lock.Unlock()
+
+	nw.cut(func(id linkID) bool { return !nw.reachable(id.a, id.b) })
+}
+
+// heal removes the partition, the nodes have to be connected again.
+func (nw *network) heal() {
+	nw.lock.Lock()
+	defer nw.lock.Unlock()
+
+	nw.groups = nil
+}
+
+// setDelay sets the delay of the msgs sent on the link of the nodes.
+func (nw *network) setDelay(a, b int, delay time.Duration) {
+	nw.lock.Lock()
+	defer nw.lock.Unlock()
+
+	if delay <= 0 {
+		delete(nw.delays, newLinkID(a, b))
+		return
+	}
+	nw.delays[newLinkID(a, b)] = delay
+}
+
+func (nw *network) delayOf(id linkID) time.Duration {
+	nw.lock.Lock()
+	defer nw.lock.Unlock()
+
+	return nw.delays[id]
+}
+
+// remove forgets a closed link, unless the nodes were linked again meanwhile.
+func (nw *network) remove(id linkID, l *link) {
+	nw.lock.Lock()
+	defer nw.lock.Unlock()
+
+	if nw.links[id] == l {
+		delete(nw.links, id)
+	}
+}
+
+// faultConn is an end of a link, it delays the writes by the delay of the link.
+type faultConn struct {
+	net.Conn
+
+	network *network
+	id      linkID
+	link    *link
+}
+
+func (c *faultConn) Write(b []byte) (int, error) {
+	if delay := c.network.delayOf(c.id); delay > 0 {
+		time.Sleep(delay)
+	}
+	return c.Conn.Write(b)
+}
+
+func (c *faultConn) Close() error {
+	c.network.remove(c.id, c.link)
+	return c.Conn.Close()
+}
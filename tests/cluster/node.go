@@ -0,0 +1,255 @@
+// Copyright 2018 The gcchain authors
+
+package cluster
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/gcchains/chain/accounts"
+	"github.com/gcchains/chain/accounts/keystore"
+	"github.com/gcchains/chain/consensus"
+	"github.com/gcchains/chain/consensus/dpos"
+	"github.com/gcchains/chain/core"
+	"github.com/gcchains/chain/node"
+	"github.com/gcchains/chain/protocols/gcc"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/p2p"
+)
+
+const passphrase = "cluster"
+
+var errNodeNotRunning = errors.New("node is not running")
+
+// Role is the role of a node in the committee.
+type Role int
+
+const (
+	// Proposer proposes blocks in its turn.
+	Proposer Role = iota
+
+	// Validator validates the proposed blocks and impeaches the faulty proposers.
+	Validator
+)
+
+func (r Role) String() string {
+	switch r {
+	case Proposer:
+		return "proposer"
+	case Validator:
+		return "validator"
+	default:
+		return "unknown"
+	}
+}
+
+// service is the part of the chain service of a node driven by the cluster.
+type service interface {
+	node.Service
+	BlockChain() *core.BlockChain
+	Engine() consensus.Engine
+	StartMining(local bool) error
+	SetupValidator() error
+}
+
+// Node is a member of the committee of a cluster, running a full node.
+type Node struct {
+	Index   int
+	Role    Role
+	Address common.Address
+
+	key       *ecdsa.PrivateKey // key of the coinbase account
+	nodeKey   *ecdsa.PrivateKey // key of the p2p server
+	dataDir   string
+	genesis   *core.Genesis
+	networkID uint64
+	byzantine bool
+	trace     bool // records the steps of the lbft2 fsm
+
+	lock    sync.RWMutex
+	stack   *node.Node
+	service service
+	quitCh  chan struct{}
+}
+
+// start boots the node from its data directory, the node does not work in its role
+// until it runs.
+func (n *Node) start() error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.stack != nil {
+		return node.ErrNodeRunning
+	}
+
+	stack, err := node.New(&node.Config{
+		Name:              fmt.Sprintf("%s%d", n.Role, n.Index),
+		DataDir:           n.dataDir,
+		UseLightweightKDF: true,
+		NoUSB:             true,
+		P2P: p2p.Config{
+			PrivateKey:  n.nodeKey,
+			MaxPeers:    64,
+			NoDiscovery: true,
+			NoDial:      true,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	key, err := n.unlock(stack)
+	if err != nil {
+		return err
+	}
+
+	var chainService service
+	err = stack.Register(func(ctx *node.ServiceContext) (node.Service, error) {
+		config := gcc.DefaultConfig
+		config.Genesis = n.genesis
+		config.NetworkId = n.networkID
+		config.Gccbase = n.Address
+
+		fullNode, err := gcc.New(ctx, &config)
+		if err != nil {
+			return nil, err
+		}
+		if n.Role == Validator {
+			fullNode.SetAsValidator()
+			if n.trace {
+				if err := fullNode.TraceLBFT2(n.tracePath()); err != nil {
+					return nil, err
+				}
+			}
+		} else {
+			fullNode.SetAsMiner(true)
+			fullNode.AdmissionApiBackend.SetAdmissionKey(key)
+		}
+		chainService = fullNode
+		return fullNode, nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := stack.Start(); err != nil {
+		return err
+	}
+
+	n.stack, n.service = stack, chainService
+	return nil
+}
+
+// run starts the node working in its role, a validator validating blocks and a
+// proposer proposing them.
+func (n *Node) run() error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.stack == nil {
+		return errNodeNotRunning
+	}
+
+	switch {
+	case n.Role == Validator:
+		return n.service.SetupValidator()
+	case n.byzantine:
+		return n.startByzantine(n.stack.Server(), n.service)
+	default:
+		return n.service.StartMining(true)
+	}
+}
+
+// unlock imports the coinbase key into the keystore of the node and unlocks it.
+func (n *Node) unlock(stack *node.Node) (*keystore.Key, error) {
+	ks := stack.AccountManager().Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
+
+	account := accounts.Account{Address: n.Address}
+	if !ks.HasAddress(n.Address) {
+		imported, err := ks.ImportECDSA(n.key, passphrase)
+		if err != nil {
+			return nil, err
+		}
+		account = imported
+	}
+	if err := ks.Unlock(account, passphrase); err != nil {
+		return nil, err
+	}
+	_, key, err := ks.GetDecryptedKey(account, passphrase)
+	return key, err
+}
+
+// tracePath returns the path of the lbft2 trace file, kept along with the data.
+func (n *Node) tracePath() string {
+	return filepath.Join(n.dataDir, "lbft2.trace")
+}
+
+// stop shuts the node down, keeping its data directory.
+func (n *Node) stop() error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.stack == nil {
+		return errNodeNotRunning
+	}
+	if n.quitCh != nil {
+		close(n.quitCh)
+		n.quitCh = nil
+	}
+	err := n.stack.Stop()
+	n.stack, n.service = nil, nil
+	return err
+}
+
+// server returns the p2p server of the node, nil if it is not running.
+func (n *Node) server() *p2p.Server {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+
+	if n.stack == nil {
+		return nil
+	}
+	return n.stack.Server()
+}
+
+// Running returns if the node is running.
+func (n *Node) Running() bool {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+
+	return n.stack != nil
+}
+
+// BlockChain returns the chain of the node, nil if it is not running.
+func (n *Node) BlockChain() *core.BlockChain {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+
+	if n.service == nil {
+		return nil
+	}
+	return n.service.BlockChain()
+}
+
+// Engine returns the dpos engine of the node, nil if it is not running.
+func (n *Node) Engine() *dpos.Dpos {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+
+	if n.service == nil {
+		return nil
+	}
+	engine, _ := n.service.Engine().(*dpos.Dpos)
+	return engine
+}
+
+// Height returns the number of the head block of the node, 0 if it is not running.
+func (n *Node) Height() uint64 {
+	chain := n.BlockChain()
+	if chain == nil {
+		return 0
+	}
+	return chain.CurrentBlock().NumberU64()
+}
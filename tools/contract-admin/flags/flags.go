@@ -22,6 +22,7 @@ func init() {
 	}
 
 	Register(GeneralFlags...)
+	Register(ProposalFlags...)
 }
 
 func Register(flags ...cli.Flag) {
@@ -64,6 +65,23 @@ var GeneralFlags = []cli.Flag{
 	},
 }
 
+const (
+	Target      = "target"
+	Description = "description"
+)
+
+// ProposalFlags are the flags of governance proposals besides the general ones
+var ProposalFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  Target,
+		Usage: "Contract called by the proposal: admission, campaign, network, rnode, rpt, governance or an address",
+	},
+	cli.StringFlag{
+		Name:  Description,
+		Usage: "Description of the proposal",
+	},
+}
+
 func GetContractAddress(ctx *cli.Context) common.Address {
 	if !ctx.IsSet(ContractAddr) {
 		log.Fatal("contract address must be provided!")
@@ -90,3 +108,12 @@ func GetKeystorePath(ctx *cli.Context) string {
 	keystorePath := ctx.String(KeystorePath)
 	return keystorePath
 }
+
+func GetTarget(ctx *cli.Context) string {
+	if !ctx.IsSet(Target) {
+		log.Fatal("target must be provided!")
+	}
+
+	target := ctx.String(Target)
+	return target
+}
@@ -0,0 +1,309 @@
+package governance
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gcchains/chain/accounts/abi"
+	"github.com/gcchains/chain/accounts/abi/bind"
+	"github.com/gcchains/chain/api/gcclient"
+	"github.com/gcchains/chain/commons/log"
+	"github.com/gcchains/chain/configs"
+	dposGovernance "github.com/gcchains/chain/consensus/dpos/governance"
+	"github.com/gcchains/chain/contracts/dpos/admission"
+	"github.com/gcchains/chain/contracts/dpos/campaign"
+	"github.com/gcchains/chain/contracts/dpos/governance"
+	"github.com/gcchains/chain/contracts/dpos/network"
+	"github.com/gcchains/chain/contracts/dpos/rnode"
+	rptContract "github.com/gcchains/chain/contracts/dpos/rpt"
+	"github.com/gcchains/chain/tools/contract-admin/flags"
+	"github.com/gcchains/chain/tools/contract-admin/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/urfave/cli"
+)
+
+const targetGovernance = "governance"
+
+// abis of the contracts a proposal calls by name
+var targetABIs = map[string]string{
+	configs.ContractAdmission: admission.AdmissionABI,
+	configs.ContractCampaign:  campaign.CampaignABI,
+	configs.ContractNetwork:   network.NetworkABI,
+	configs.ContractRnode:     rnode.RnodeABI,
+	configs.ContractRpt:       rptContract.RptABI,
+	targetGovernance:          governance.GovernanceABI,
+}
+
+var (
+	GovernanceCommand = cli.Command{
+		Name:  "governance",
+		Usage: "Manage Governance Contract",
+		Description: `
+		Manage Governance Contract, rnodes propose the calls to system contracts and vote them,
+		an adopted proposal is executed after the timelock
+		`,
+		Flags: flags.GeneralFlags,
+		Subcommands: []cli.Command{
+			{
+				Name:      "propose",
+				Usage:     "propose a call to a contract",
+				Action:    propose,
+				Flags:     append(append([]cli.Flag{}, flags.GeneralFlags...), flags.ProposalFlags...),
+				ArgsUsage: "method [args...]",
+				Description: `propose a call of the method with the args to the target contract, e.g.
+		--target rpt updateTotalSeats 6
+		--target governance setParam totalSeats 6
+		a target given by its address is called with the hex data as the only argument.
+		Parameters read by dpos are adopted by setParam of governance contract, other contracts
+		are only governed if they are owned by the governance contract`,
+			},
+			{
+				Name:        "vote",
+				Usage:       "vote a proposal",
+				Action:      vote,
+				Flags:       flags.GeneralFlags,
+				ArgsUsage:   "id true|false",
+				Description: `approve or reject a proposal`,
+			},
+			{
+				Name:        "execute",
+				Usage:       "execute a proposal",
+				Action:      execute,
+				Flags:       flags.GeneralFlags,
+				ArgsUsage:   "id",
+				Description: `execute an adopted proposal after the timelock`,
+			},
+			{
+				Name:        "list",
+				Usage:       "list proposals and params",
+				Action:      list,
+				Flags:       flags.GeneralFlags,
+				Description: `list proposals, configs and params adopted in contract`,
+			},
+		},
+	}
+)
+
+func propose(ctx *cli.Context) error {
+	gov, opts, client := createContractInstanceAndTransactor(ctx, true)
+	target, data, err := proposalCall(flags.GetTarget(ctx), flags.GetContractAddress(ctx), ctx.Args())
+	if err != nil {
+		log.Fatal("Failed to encode the call of the proposal", "err", err)
+	}
+
+	tx, err := gov.Propose(opts, target, data, ctx.String(flags.Description))
+	utils.WaitMined(client, tx, err)
+	return nil
+}
+
+func vote(ctx *cli.Context) error {
+	gov, opts, client := createContractInstanceAndTransactor(ctx, true)
+	if len(ctx.Args()) != 2 {
+		log.Fatal("Invalid length of arguments", "want", 2, "got", len(ctx.Args()))
+	}
+	id, err := strconv.ParseInt(ctx.Args().Get(0), 10, 64)
+	if err != nil {
+		log.Fatal("Failed to parse value", "value", ctx.Args().Get(0), "err", err)
+	}
+	approve, err := strconv.ParseBool(ctx.Args().Get(1))
+	if err != nil {
+		log.Fatal("Invalid argument", "want", "bool", "got", ctx.Args().Get(1))
+	}
+
+	tx, err := gov.Vote(opts, big.NewInt(id), approve)
+	utils.WaitMined(client, tx, err)
+	return nil
+}
+
+func execute(ctx *cli.Context) error {
+	gov, opts, client := createContractInstanceAndTransactor(ctx, true)
+	id := utils.GetFirstIntArgument(ctx)
+	tx, err := gov.Execute(opts, big.NewInt(id))
+	utils.WaitMined(client, tx, err)
+	return nil
+}
+
+func list(ctx *cli.Context) error {
+	gov, _, _ := createContractInstanceAndTransactor(ctx, false)
+
+	quorum, err := gov.QuorumPercentage(nil)
+	if err != nil {
+		log.Fatal("Failed to get quorum percentage", "err", err)
+	}
+	votingPeriod, err := gov.VotingPeriod(nil)
+	if err != nil {
+		log.Fatal("Failed to get voting period", "err", err)
+	}
+	timelock, err := gov.Timelock(nil)
+	if err != nil {
+		log.Fatal("Failed to get timelock", "err", err)
+	}
+	log.Info("configs", "quorum percentage", quorum, "voting period", votingPeriod, "timelock", timelock)
+
+	keys, err := gov.GetParamKeys(nil)
+	if err != nil {
+		log.Fatal("Failed to get param keys", "err", err)
+	}
+	for _, key := range keys {
+		param, err := gov.GetParam(nil, key)
+		if err != nil {
+			log.Fatal("Failed to get param", "key", dposGovernance.ParamName(key), "err", err)
+		}
+		log.Info("param", "name", dposGovernance.ParamName(key), "value", param.Value)
+	}
+
+	count, err := gov.ProposalCount(nil)
+	if err != nil {
+		log.Fatal("Failed to get proposal count", "err", err)
+	}
+	log.Info("proposal len", "value", count)
+
+	for id := int64(0); id < count.Int64(); id++ {
+		p, err := gov.ProposalOf(nil, big.NewInt(id))
+		if err != nil {
+			log.Fatal("Failed to get proposal", "id", id, "err", err)
+		}
+		status, err := gov.ProposalStatusOf(nil, big.NewInt(id))
+		if err != nil {
+			log.Fatal("Failed to get proposal status", "id", id, "err", err)
+		}
+		log.Info("proposal", "id", id, "proposer", p.Proposer.Hex(), "target", p.Target.Hex(), "data", hexutil.Encode(p.Data),
+			"description", p.Description, "created", time.Unix(p.CreatedTime.Int64(), 0))
+
+		state := "voting"
+		switch {
+		case status.Executed:
+			state = "executed"
+		case status.AdoptedTime.Sign() > 0:
+			executable := new(big.Int).Add(status.AdoptedTime, timelock)
+			state = fmt.Sprintf("adopted, executable from %v", time.Unix(executable.Int64(), 0))
+		case time.Now().Unix() >= new(big.Int).Add(p.CreatedTime, votingPeriod).Int64():
+			state = "rejected"
+		}
+		log.Info("proposal status", "id", id, "approvals", status.Approvals, "rejections", status.Rejections,
+			"quorum", status.Quorum, "state", state)
+	}
+
+	return nil
+}
+
+// proposalCall returns the target contract and the data of the call of a proposal, encoding the call of the method
+// with the args by the abi of the target contract.
+func proposalCall(target string, governanceAddr common.Address, args cli.Args) (common.Address, []byte, error) {
+	if common.IsHexAddress(target) {
+		if len(args) != 1 {
+			return common.Address{}, nil, fmt.Errorf("want the hex data of the call, got %d arguments", len(args))
+		}
+		data, err := hexutil.Decode(args.Get(0))
+		return common.HexToAddress(target), data, err
+	}
+
+	abiJSON, ok := targetABIs[target]
+	if !ok {
+		return common.Address{}, nil, fmt.Errorf("unknown target contract %v", target)
+	}
+	addr := governanceAddr
+	if target != targetGovernance {
+		if addr, ok = configs.ChainConfigInfo().Dpos.Contracts[target]; !ok {
+			return common.Address{}, nil, fmt.Errorf("no address of target contract %v", target)
+		}
+	}
+	if len(args) == 0 {
+		return common.Address{}, nil, fmt.Errorf("method of target contract must be provided")
+	}
+
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	data, err := packCall(parsed, args.First(), args.Tail())
+	return addr, data, err
+}
+
+// packCall encodes the call of the method with the args parsed by the types of its inputs.
+func packCall(parsed abi.ABI, name string, args []string) ([]byte, error) {
+	method, ok := parsed.Methods[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown method %v", name)
+	}
+	if len(args) != len(method.Inputs) {
+		return nil, fmt.Errorf("method %v wants %d arguments, got %d", name, len(method.Inputs), len(args))
+	}
+
+	values := make([]interface{}, len(args))
+	for i, input := range method.Inputs {
+		v, err := parseArg(input.Type, args[i])
+		if err != nil {
+			return nil, fmt.Errorf("argument %v of method %v: %v", input.Name, name, err)
+		}
+		values[i] = v
+	}
+	return parsed.Pack(name, values...)
+}
+
+func parseArg(t abi.Type, arg string) (interface{}, error) {
+	switch t.T {
+	case abi.IntTy, abi.UintTy:
+		n, ok := new(big.Int).SetString(arg, 0)
+		if !ok {
+			return nil, fmt.Errorf("invalid integer %v", arg)
+		}
+		if t.Type == reflect.TypeOf(n) {
+			return n, nil
+		}
+		if t.T == abi.UintTy {
+			return reflect.ValueOf(n.Uint64()).Convert(t.Type).Interface(), nil
+		}
+		return reflect.ValueOf(n.Int64()).Convert(t.Type).Interface(), nil
+	case abi.BoolTy:
+		return strconv.ParseBool(arg)
+	case abi.AddressTy:
+		if !common.IsHexAddress(arg) {
+			return nil, fmt.Errorf("invalid address %v", arg)
+		}
+		return common.HexToAddress(arg), nil
+	case abi.StringTy:
+		return arg, nil
+	case abi.BytesTy:
+		return hexutil.Decode(arg)
+	case abi.FixedBytesTy:
+		if t.Size != 32 {
+			break
+		}
+		// a bytes32 is hex, or else a name as the params of governance contract
+		if strings.HasPrefix(arg, "0x") {
+			b, err := hexutil.Decode(arg)
+			if err != nil || len(b) != 32 {
+				return nil, fmt.Errorf("invalid bytes32 %v", arg)
+			}
+			var key [32]byte
+			copy(key[:], b)
+			return key, nil
+		}
+		if len(arg) > 32 {
+			return nil, fmt.Errorf("name %v is longer than 32 bytes", arg)
+		}
+		return dposGovernance.ParamKey(arg), nil
+	}
+	return nil, fmt.Errorf("unsupported type %v", t)
+}
+
+func createContractInstanceAndTransactor(ctx *cli.Context, withTransactor bool) (contract *governance.Governance, opts *bind.TransactOpts, client *gcclient.Client) {
+	contractAddr, client, key := utils.PrepareAll(ctx, withTransactor)
+
+	if withTransactor {
+		opts = bind.NewKeyedTransactor(key.PrivateKey)
+	}
+
+	contract, err := governance.NewGovernance(contractAddr, client)
+	if err != nil {
+		log.Fatal("Failed to create new contract instance", "err", err)
+	}
+
+	return contract, opts, client
+}
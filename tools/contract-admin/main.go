@@ -9,6 +9,7 @@ import (
 	"github.com/gcchains/chain/configs"
 	"github.com/gcchains/chain/tools/contract-admin/admission"
 	"github.com/gcchains/chain/tools/contract-admin/campaign"
+	"github.com/gcchains/chain/tools/contract-admin/governance"
 	"github.com/gcchains/chain/tools/contract-admin/network"
 	"github.com/gcchains/chain/tools/contract-admin/rnode"
 	"github.com/gcchains/chain/tools/contract-admin/rpt"
@@ -33,6 +34,7 @@ func newApp() *cli.App {
 	app.Commands = []cli.Command{
 		admission.AdmissionCommand,
 		campaign.CampaignCommand,
+		governance.GovernanceCommand,
 		network.NetworkCommand,
 		rnode.RnodeCommand,
 		rpt.RptCommand,
@@ -0,0 +1,53 @@
+// Copyright 2018 The gcchain authors
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gcchains/chain/consensus/dpos/backend"
+)
+
+// check the traces recorded by validators running with --lbfttrace against the spec in
+// tlaplus/lbft.tla, reporting the steps diverging from it
+// usage:
+// ./lbfttrace trace1.jsonl [trace2.jsonl ...]
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintf(os.Stderr, "usage: %s <trace file> [trace file ...]\n", os.Args[0])
+		os.Exit(2)
+	}
+
+	diverged := false
+	for _, path := range os.Args[1:] {
+		steps, err := readTrace(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+			os.Exit(2)
+		}
+
+		divergences := backend.CheckTrace(steps)
+		for _, d := range divergences {
+			fmt.Printf("%s: %v\n", path, d)
+		}
+		fmt.Printf("%s: %d steps, %d divergences\n", path, len(steps), len(divergences))
+
+		diverged = diverged || len(divergences) > 0
+	}
+
+	if diverged {
+		os.Exit(1)
+	}
+}
+
+func readTrace(path string) ([]*backend.TraceStep, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return backend.ReadTrace(f)
+}
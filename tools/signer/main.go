@@ -0,0 +1,166 @@
+// Copyright 2018 The gcchain authors
+
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/gcchains/chain/accounts"
+	"github.com/gcchains/chain/accounts/keystore"
+	"github.com/gcchains/chain/api/rpc"
+	"github.com/gcchains/chain/commons/log"
+	"github.com/gcchains/chain/configs"
+	"github.com/gcchains/chain/database"
+	"github.com/gcchains/chain/signer"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// signer holds the keys of validators and proposers out of their nodes, which run with
+// --signer to have their consensus msgs and campaign txs signed by it. It never signs two
+// different headers at a height in a consensus state, only signs txs to the campaign and
+// rnode contracts and those given by -contracts, and records every request in the audit log.
+// usage:
+// ./signer -keystore ./keystore -unlock 0x... -password ./password -runmode dev
+// gcchain run --signer ./signer/signer.ipc --account 0x... --validator
+
+func main() {
+	var (
+		keystoreDir  = flag.String("keystore", "", "keystore directory of the accounts to sign with")
+		unlock       = flag.String("unlock", "", "comma separated list of accounts to sign with, all of the keystore if empty")
+		passwordFile = flag.String("password", "", "password file of the accounts, one per line in their order")
+		dataDir      = flag.String("datadir", "signer", "directory of the headers signed, the audit log and the ipc endpoint")
+		auditLog     = flag.String("auditlog", "", "audit log file (default = <datadir>/audit.log)")
+		runMode      = flag.String("runmode", string(configs.Mainnet), "run mode of the chain whose campaign and rnode contracts txs may be sent to, eg:dev|testnet|mainnet")
+		contracts    = flag.String("contracts", "", "comma separated list of more contracts txs may be sent to")
+		ipcPath      = flag.String("ipc", "", "ipc endpoint path (default = <datadir>/signer.ipc)")
+		httpAddr     = flag.String("http", "", "HTTP endpoint address <host:port>, disabled if empty")
+		authSecret   = flag.String("authsecret", "", "shared secret file authenticating the HTTP clients, required by -http")
+	)
+	flag.Parse()
+
+	if *keystoreDir == "" {
+		log.Fatalf("-keystore is required")
+	}
+	if *httpAddr != "" && *authSecret == "" {
+		log.Fatalf("-http requires -authsecret")
+	}
+	if err := configs.SetRunMode(configs.RunMode(*runMode)); err != nil {
+		log.Fatalf("%v", err)
+	}
+	if err := os.MkdirAll(*dataDir, 0700); err != nil {
+		log.Fatalf("Failed to create data directory: %v", err)
+	}
+
+	ks := keystore.NewKeyStore(*keystoreDir, keystore.StandardScryptN, keystore.StandardScryptP)
+	unlockAccounts(ks, *unlock, *passwordFile)
+
+	db, err := database.NewLDBDatabase(filepath.Join(*dataDir, "signed"), 16, 16)
+	if err != nil {
+		log.Fatalf("Failed to open the database of the headers signed: %v", err)
+	}
+	defer db.Close()
+
+	if *auditLog == "" {
+		*auditLog = filepath.Join(*dataDir, "audit.log")
+	}
+	audit, err := signer.OpenAuditLog(*auditLog)
+	if err != nil {
+		log.Fatalf("Failed to open audit log: %v", err)
+	}
+	defer audit.Close()
+
+	whitelist := []common.Address{
+		configs.ChainConfigInfo().Dpos.Contracts[configs.ContractCampaign],
+		configs.ChainConfigInfo().Dpos.Contracts[configs.ContractRnode],
+	}
+	for _, c := range strings.FieldsFunc(*contracts, func(c rune) bool { return c == ',' }) {
+		if !common.IsHexAddress(c) {
+			log.Fatalf("Invalid contract address: %v", c)
+		}
+		whitelist = append(whitelist, common.HexToAddress(c))
+	}
+	rules := signer.NewRules(db, whitelist)
+	s := signer.New(ks, rules, audit)
+
+	if *ipcPath == "" {
+		*ipcPath = filepath.Join(*dataDir, "signer.ipc")
+	}
+	ipcListener, _, err := rpc.StartIPCEndpoint(*ipcPath, s.APIs())
+	if err != nil {
+		log.Fatalf("Failed to start ipc endpoint: %v", err)
+	}
+	defer ipcListener.Close()
+	log.Info("IPC endpoint opened", "path", *ipcPath)
+
+	if *httpAddr != "" {
+		auth, err := rpc.NewAuthenticator(rpc.AuthConfig{SecretFile: *authSecret})
+		if err != nil {
+			log.Fatalf("Failed to read auth secret: %v", err)
+		}
+		// the clients authenticate, any virtual host is allowed
+		httpListener, _, err := rpc.StartHTTPEndpoint(*httpAddr, s.APIs(), nil, []string{signer.Namespace}, nil, []string{"*"}, nil, auth)
+		if err != nil {
+			log.Fatalf("Failed to start HTTP endpoint: %v", err)
+		}
+		defer httpListener.Close()
+		log.Info("HTTP endpoint opened", "url", "http://"+*httpAddr)
+	}
+
+	log.Info("Signer started", "accounts", s.Accounts(), "contracts", rules.Contracts(), "auditlog", *auditLog)
+
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigc)
+	<-sigc
+	log.Info("Got interrupt, shutting down...")
+}
+
+// unlockAccounts unlocks the accounts to sign with, the keystore only signs with those.
+func unlockAccounts(ks *keystore.KeyStore, unlock string, passwordFile string) {
+	if passwordFile == "" {
+		log.Fatalf("-password is required")
+	}
+	text, err := ioutil.ReadFile(passwordFile)
+	if err != nil {
+		log.Fatalf("Failed to read password file: %v", err)
+	}
+	passwords := strings.Split(string(text), "\n")
+	// Sanitise DOS line endings.
+	for i := range passwords {
+		passwords[i] = strings.TrimRight(passwords[i], "\r")
+	}
+
+	var accs []accounts.Account
+	if unlock == "" {
+		accs = ks.Accounts()
+	} else {
+		for _, addr := range strings.FieldsFunc(unlock, func(c rune) bool { return c == ',' }) {
+			if !common.IsHexAddress(addr) {
+				log.Fatalf("Invalid account address: %v", addr)
+			}
+			account, err := ks.Find(accounts.Account{Address: common.HexToAddress(addr)})
+			if err != nil {
+				log.Fatalf("Failed to find account %v: %v", addr, err)
+			}
+			accs = append(accs, account)
+		}
+	}
+	if len(accs) == 0 {
+		log.Fatalf("No account to sign with")
+	}
+
+	for i, account := range accs {
+		if i >= len(passwords) {
+			log.Fatalf("No password for account %v", account.Address.Hex())
+		}
+		if err := ks.Unlock(account, passwords[i]); err != nil {
+			log.Fatalf("Failed to unlock account %v: %v", account.Address.Hex(), err)
+		}
+	}
+}
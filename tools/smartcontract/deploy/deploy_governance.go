@@ -0,0 +1,24 @@
+package deploy
+
+import (
+	"math/big"
+
+	"github.com/gcchains/chain/commons/log"
+	"github.com/gcchains/chain/contracts/dpos/governance"
+	"github.com/gcchains/chain/tools/smartcontract/config"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DeployGovernance deploy governance contract, voted by the rnodes of rnodeAddr
+func DeployGovernance(rnodeAddr common.Address, password string, nonce uint64) common.Address {
+	client, err, privateKey, _, fromAddress := config.Connect(password)
+	printBalance(client, fromAddress)
+	// Launch contract deploy transaction.
+	auth := newTransactor(privateKey, new(big.Int).SetUint64(nonce))
+	contractAddress, tx, _, err := governance.DeployGovernance(auth, client, rnodeAddr)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	printTx(tx, err, client, contractAddress)
+	return contractAddress
+}
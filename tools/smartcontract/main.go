@@ -15,6 +15,7 @@ import (
 
 var (
 	proxyCampaignContractAddress common.Address
+	rnodeContractAddress         common.Address
 )
 
 func main() {
@@ -46,6 +47,11 @@ func main() {
 
 	deploy.UpdateCampaignParameters(password, proxyCampaignContractAddress, 5, 6)
 
+	// 6
+	title := "[6.DeployGovernance]"
+	governanceAddress := deploy.DeployGovernance(rnodeContractAddress, password, 6)
+	deploy.PrintContract(title, governanceAddress)
+
 	fmt.Println("======== init contract deploy completed=========")
 }
 
@@ -54,6 +60,7 @@ func deployRNodeAdmissionAndCampaign(password string, wg *sync.WaitGroup) {
 	title := "[1.DeployRNode]"
 	rnodeAddress := deploy.DeployRNode(password, 0)
 	deploy.PrintContract(title, rnodeAddress)
+	rnodeContractAddress = rnodeAddress
 
 	// 2
 	title = "[2.DeployAdmission]"
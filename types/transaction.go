@@ -10,6 +10,7 @@ import (
 	"math/big"
 	"sync/atomic"
 
+	"github.com/gcchains/chain/configs"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -442,16 +443,15 @@ func (m Message) Data() []byte            { return m.data }
 func (m *Message) SetData(newData []byte) { m.data = newData }
 func (m Message) CheckNonce() bool        { return m.checkNonce }
 
-// SupportTxType returns if a transaction type is supported.
-func SupportTxType(txType uint64) bool {
-	return txType == BasicTx
-
-	// supportPrivate := private.SupportPrivateTx == "true"
-	// eng := bc.Engine()
-	// if eng != nil {
-	// 	if d, ok := eng.(*dpos.Dpos); ok {
-	// 		return (!d.IsValidator()) && supportPrivate // validator node cannot handle private tx
-	// 	}
-	// }
-	// return supportPrivate
+// SupportTxType returns if a transaction type is supported in the block with given number.
+// Private transactions are only supported after the PrivateTx fork.
+func SupportTxType(config *configs.ChainConfig, num *big.Int, txType uint64) bool {
+	switch txType {
+	case BasicTx:
+		return true
+	case PrivateTx:
+		return config.IsPrivateTx(num)
+	default:
+		return false
+	}
 }